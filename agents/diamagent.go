@@ -293,6 +293,7 @@ func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
 			reqProcessor.Flags.HasKey(utils.MetaAccounts),
 			reqProcessor.Flags.HasKey(utils.MetaThresholds),
 			reqProcessor.Flags.HasKey(utils.MetaStats), *cgrEv)
+		initArgs.GetSessionCosts = reqProcessor.Flags.HasKey(utils.MetaSessionCosts)
 		var initReply sessions.V1InitSessionReply
 		err = da.sS.Call(utils.SessionSv1InitiateSession,
 			initArgs, &initReply)
@@ -303,6 +304,7 @@ func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
 		updateArgs := sessions.NewV1UpdateSessionArgs(
 			reqProcessor.Flags.HasKey(utils.MetaAttributes),
 			reqProcessor.Flags.HasKey(utils.MetaAccounts), *cgrEv)
+		updateArgs.GetSessionCosts = reqProcessor.Flags.HasKey(utils.MetaSessionCosts)
 		var updateReply sessions.V1UpdateSessionReply
 		err = da.sS.Call(utils.SessionSv1UpdateSession,
 			updateArgs, &updateReply)
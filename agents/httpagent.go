@@ -151,6 +151,7 @@ func (ha *HTTPAgent) processRequest(reqProcessor *config.HttpAgntProcCfg,
 			reqProcessor.Flags.HasKey(utils.MetaAccounts),
 			reqProcessor.Flags.HasKey(utils.MetaThresholds),
 			reqProcessor.Flags.HasKey(utils.MetaStats), *cgrEv)
+		initArgs.GetSessionCosts = reqProcessor.Flags.HasKey(utils.MetaSessionCosts)
 		var initReply sessions.V1InitSessionReply
 		err = ha.sessionS.Call(utils.SessionSv1InitiateSession,
 			initArgs, &initReply)
@@ -161,6 +162,7 @@ func (ha *HTTPAgent) processRequest(reqProcessor *config.HttpAgntProcCfg,
 		updateArgs := sessions.NewV1UpdateSessionArgs(
 			reqProcessor.Flags.HasKey(utils.MetaAttributes),
 			reqProcessor.Flags.HasKey(utils.MetaAccounts), *cgrEv)
+		updateArgs.GetSessionCosts = reqProcessor.Flags.HasKey(utils.MetaSessionCosts)
 		var updateReply sessions.V1UpdateSessionReply
 		err = ha.sessionS.Call(utils.SessionSv1UpdateSession,
 			updateArgs, &updateReply)
@@ -195,6 +195,7 @@ func (ra *RadiusAgent) processRequest(reqProcessor *config.RARequestProcessor,
 			reqProcessor.Flags.HasKey(utils.MetaAccounts),
 			reqProcessor.Flags.HasKey(utils.MetaThresholds),
 			reqProcessor.Flags.HasKey(utils.MetaStats), *cgrEv)
+		initArgs.GetSessionCosts = reqProcessor.Flags.HasKey(utils.MetaSessionCosts)
 		var initReply sessions.V1InitSessionReply
 		err = ra.sessionS.Call(utils.SessionSv1InitiateSession,
 			initArgs, &initReply)
@@ -205,6 +206,7 @@ func (ra *RadiusAgent) processRequest(reqProcessor *config.RARequestProcessor,
 		updateArgs := sessions.NewV1UpdateSessionArgs(
 			reqProcessor.Flags.HasKey(utils.MetaAttributes),
 			reqProcessor.Flags.HasKey(utils.MetaAccounts), *cgrEv)
+		updateArgs.GetSessionCosts = reqProcessor.Flags.HasKey(utils.MetaSessionCosts)
 		var updateReply sessions.V1UpdateSessionReply
 		err = ra.sessionS.Call(utils.SessionSv1UpdateSession,
 			updateArgs, &updateReply)
@@ -172,10 +172,19 @@ func (self *ApierV1) RemActionTiming(attrs AttrRemActionTiming, reply *string) (
 }
 
 // Ads a new account into dataDb. If already defined, returns success.
-func (self *ApierV1) SetAccount(attr utils.AttrSetAccount, reply *string) (err error) {
+func (self *ApierV1) SetAccount(attr utils.AttrSetAccount, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV1SetAccount, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.setAccount(attr, reply) })
+}
+
+func (self *ApierV1) setAccount(attr utils.AttrSetAccount, reply *string) (err error) {
 	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
+	if attr.DebitPolicy != nil && !engine.IsDebitPolicy(*attr.DebitPolicy) {
+		return fmt.Errorf("unsupported DebitPolicy: %s", *attr.DebitPolicy)
+	}
 	accID := utils.AccountKey(attr.Tenant, attr.Account)
 	dirtyActionPlans := make(map[string]*engine.ActionPlan)
 	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
@@ -273,6 +282,9 @@ func (self *ApierV1) SetAccount(attr utils.AttrSetAccount, reply *string) (err e
 		if attr.Disabled != nil {
 			ub.Disabled = *attr.Disabled
 		}
+		if attr.DebitPolicy != nil {
+			ub.DebitPolicy = *attr.DebitPolicy
+		}
 		// All prepared, save account
 		if err := self.DataManager.DataDB().SetAccount(ub); err != nil {
 			return 0, err
@@ -293,7 +305,13 @@ func (self *ApierV1) SetAccount(attr utils.AttrSetAccount, reply *string) (err e
 	return nil
 }
 
-func (self *ApierV1) RemoveAccount(attr utils.AttrRemoveAccount, reply *string) (err error) {
+func (self *ApierV1) RemoveAccount(attr utils.AttrRemoveAccount, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV1RemoveAccount, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.removeAccount(attr, reply) })
+}
+
+func (self *ApierV1) removeAccount(attr utils.AttrRemoveAccount, reply *string) (err error) {
 	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
@@ -420,13 +438,20 @@ type AttrAddBalance struct {
 	Overwrite      bool // When true it will reset if the balance is already there
 	Blocker        *bool
 	Disabled       *bool
+	Currency       *string
+	Rollover       *utils.RolloverPolicy
+	RequestID      string // optional, retries with the same RequestID receive the first result
 }
 
 func (self *ApierV1) AddBalance(attr *AttrAddBalance, reply *string) error {
-	return self.modifyBalance(engine.TOPUP, attr, reply)
+	return engine.ProcessRequestOnce(utils.ApierV1AddBalance, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.modifyBalance(engine.TOPUP, attr, reply) })
 }
 func (self *ApierV1) DebitBalance(attr *AttrAddBalance, reply *string) error {
-	return self.modifyBalance(engine.DEBIT, attr, reply)
+	return engine.ProcessRequestOnce(utils.ApierV1DebitBalance, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.modifyBalance(engine.DEBIT, attr, reply) })
 }
 
 func (self *ApierV1) modifyBalance(aType string, attr *AttrAddBalance, reply *string) error {
@@ -443,6 +468,11 @@ func (self *ApierV1) modifyBalance(aType string, attr *AttrAddBalance, reply *st
 		}
 		expTime = &expTimeVal
 	}
+	if attr.Rollover != nil {
+		if _, err := attr.Rollover.NextExpiry(time.Now()); err != nil {
+			return err
+		}
+	}
 	accID := utils.AccountKey(attr.Tenant, attr.Account)
 	if _, err := self.DataManager.DataDB().GetAccount(accID); err != nil {
 		// create account if does not exist
@@ -456,6 +486,11 @@ func (self *ApierV1) modifyBalance(aType string, attr *AttrAddBalance, reply *st
 	}
 	at := &engine.ActionTiming{}
 	at.SetAccountIDs(utils.StringMap{accID: true})
+	if aType == engine.TOPUP {
+		at.SetAuditCause(utils.ApierV1AddBalance)
+	} else {
+		at.SetAuditCause(utils.ApierV1DebitBalance)
+	}
 
 	if attr.Overwrite {
 		aType += "_reset" // => *topup_reset/*debit_reset
@@ -469,6 +504,8 @@ func (self *ApierV1) modifyBalance(aType string, attr *AttrAddBalance, reply *st
 			Value:          &utils.ValueFormula{Static: attr.Value},
 			ExpirationDate: expTime,
 			RatingSubject:  attr.RatingSubject,
+			Currency:       attr.Currency,
+			Rollover:       attr.Rollover,
 			Weight:         attr.Weight,
 			Blocker:        attr.Blocker,
 			Disabled:       attr.Disabled,
@@ -498,6 +535,12 @@ func (self *ApierV1) modifyBalance(aType string, attr *AttrAddBalance, reply *st
 }
 
 func (self *ApierV1) SetBalance(attr *utils.AttrSetBalance, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV1SetBalance, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.setBalance(attr, reply) })
+}
+
+func (self *ApierV1) setBalance(attr *utils.AttrSetBalance, reply *string) error {
 	if missing := utils.MissingStructFields(attr, []string{"Tenant", "Account", "BalanceType"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
@@ -515,6 +558,11 @@ func (self *ApierV1) SetBalance(attr *utils.AttrSetBalance, reply *string) error
 		}
 		expTime = &expTimeVal
 	}
+	if attr.Rollover != nil {
+		if _, err := attr.Rollover.NextExpiry(time.Now()); err != nil {
+			return err
+		}
+	}
 	accID := utils.AccountKey(attr.Tenant, attr.Account)
 	if _, err := self.DataManager.DataDB().GetAccount(accID); err != nil {
 		// create account if not exists
@@ -528,6 +576,7 @@ func (self *ApierV1) SetBalance(attr *utils.AttrSetBalance, reply *string) error
 	}
 	at := &engine.ActionTiming{}
 	at.SetAccountIDs(utils.StringMap{accID: true})
+	at.SetAuditCause(utils.ApierV1SetBalance)
 
 	a := &engine.Action{
 		ActionType: engine.SET_BALANCE,
@@ -537,6 +586,8 @@ func (self *ApierV1) SetBalance(attr *utils.AttrSetBalance, reply *string) error
 			Type:           utils.StringPointer(attr.BalanceType),
 			ExpirationDate: expTime,
 			RatingSubject:  attr.RatingSubject,
+			Currency:       attr.Currency,
+			Rollover:       attr.Rollover,
 			Weight:         attr.Weight,
 			Blocker:        attr.Blocker,
 			Disabled:       attr.Disabled,
@@ -570,6 +621,12 @@ func (self *ApierV1) SetBalance(attr *utils.AttrSetBalance, reply *string) error
 }
 
 func (self *ApierV1) RemoveBalances(attr *utils.AttrSetBalance, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV1RemoveBalances, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.removeBalances(attr, reply) })
+}
+
+func (self *ApierV1) removeBalances(attr *utils.AttrSetBalance, reply *string) error {
 	if missing := utils.MissingStructFields(attr, []string{"Tenant", "Account", "BalanceType"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
@@ -590,6 +647,7 @@ func (self *ApierV1) RemoveBalances(attr *utils.AttrSetBalance, reply *string) e
 
 	at := &engine.ActionTiming{}
 	at.SetAccountIDs(utils.StringMap{accID: true})
+	at.SetAuditCause(utils.ApierV1RemoveBalances)
 	a := &engine.Action{
 		ActionType: engine.REMOVE_BALANCE,
 		Balance: &engine.BalanceFilter{
@@ -598,6 +656,7 @@ func (self *ApierV1) RemoveBalances(attr *utils.AttrSetBalance, reply *string) e
 			Type:           utils.StringPointer(attr.BalanceType),
 			ExpirationDate: expTime,
 			RatingSubject:  attr.RatingSubject,
+			Currency:       attr.Currency,
 			Weight:         attr.Weight,
 			Blocker:        attr.Blocker,
 			Disabled:       attr.Disabled,
@@ -626,3 +685,205 @@ func (self *ApierV1) RemoveBalances(attr *utils.AttrSetBalance, reply *string) e
 	*reply = OK
 	return nil
 }
+
+type AttrSetAccountSubscription struct {
+	Tenant          string
+	Account         string
+	PlanID          string
+	Price           float64
+	BillingCycle    string // <*daily|*weekly|*monthly|*yearly>
+	StartTime       string // defaults to now
+	AnniversaryDate string // defaults to StartTime
+	ActionPlanID    string // ActionPlan charging the subscriptions, defaults to the daily *charge_subscriptions one
+	RequestID       string // optional, retries with the same RequestID receive the first result
+}
+
+// SetAccountSubscription starts a subscription on the account
+// the account is attached to the ActionPlan charging its subscriptions, the first fee being charged right away
+func (self *ApierV1) SetAccountSubscription(attr AttrSetAccountSubscription, reply *string) (err error) {
+	return engine.ProcessRequestOnce(utils.ApierV1SetAccountSubscription, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.setAccountSubscription(attr, reply) })
+}
+
+func (self *ApierV1) setAccountSubscription(attr AttrSetAccountSubscription, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "PlanID", "BillingCycle"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	sub := &engine.Subscription{
+		PlanID:       attr.PlanID,
+		Price:        attr.Price,
+		BillingCycle: attr.BillingCycle,
+		StartTime:    time.Now(),
+	}
+	if attr.StartTime != "" {
+		if sub.StartTime, err = utils.ParseTimeDetectLayout(attr.StartTime,
+			self.Config.GeneralCfg().DefaultTimezone); err != nil {
+			return
+		}
+	}
+	if attr.AnniversaryDate != "" {
+		if sub.AnniversaryDate, err = utils.ParseTimeDetectLayout(attr.AnniversaryDate,
+			self.Config.GeneralCfg().DefaultTimezone); err != nil {
+			return
+		}
+	}
+	accID := utils.AccountKey(attr.Tenant, attr.Account)
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		acc, err := self.DataManager.DataDB().GetAccount(accID)
+		if err != nil {
+			return 0, err
+		}
+		if err = acc.SetSubscription(sub); err != nil {
+			return 0, err
+		}
+		return 0, self.DataManager.DataDB().SetAccount(acc)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return err
+		}
+		return utils.NewErrServerError(err)
+	}
+	if err = engine.AttachSubscriptionsPlan(accID, attr.ActionPlanID); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	if sched := self.ServManager.GetScheduler(); sched != nil { // run the queued charge
+		sched.Reload()
+	}
+	*reply = utils.OK
+	return
+}
+
+type AttrStopAccountSubscription struct {
+	Tenant    string
+	Account   string
+	PlanID    string
+	StopTime  string // defaults to now
+	RequestID string // optional, retries with the same RequestID receive the first result
+}
+
+// StopAccountSubscription ends a subscription of the account
+// the time charged after StopTime is refunded on the next *charge_subscriptions execution
+func (self *ApierV1) StopAccountSubscription(attr AttrStopAccountSubscription, reply *string) (err error) {
+	return engine.ProcessRequestOnce(utils.ApierV1StopAccountSubscription, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.stopAccountSubscription(attr, reply) })
+}
+
+func (self *ApierV1) stopAccountSubscription(attr AttrStopAccountSubscription, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "PlanID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	stopTime := time.Now()
+	if attr.StopTime != "" {
+		if stopTime, err = utils.ParseTimeDetectLayout(attr.StopTime,
+			self.Config.GeneralCfg().DefaultTimezone); err != nil {
+			return
+		}
+	}
+	accID := utils.AccountKey(attr.Tenant, attr.Account)
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		acc, err := self.DataManager.DataDB().GetAccount(accID)
+		if err != nil {
+			return 0, err
+		}
+		if err = acc.StopSubscription(attr.PlanID, stopTime); err != nil {
+			return 0, err
+		}
+		return 0, self.DataManager.DataDB().SetAccount(acc)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return err
+		}
+		return utils.NewErrServerError(err)
+	}
+	*reply = utils.OK
+	return
+}
+
+// GetAccountAudits returns the balance changes of the accounts out of StorDB, ordered on Timestamp
+func (self *ApierV1) GetAccountAudits(args engine.AccountAuditFilter, reply *[]*engine.AccountAudit) error {
+	if self.CdrDb == nil {
+		return utils.NewErrNotConnected(utils.StorDB)
+	}
+	audits, err := self.CdrDb.GetAccountAudits(&args)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = audits
+	return nil
+}
+
+type AttrSnapshotAccounts struct {
+	Tenant     string
+	AccountIDs []string // all accounts of the tenant if empty
+}
+
+// SnapshotAccounts stores the current balances of the accounts in StorDB
+func (self *ApierV1) SnapshotAccounts(attr AttrSnapshotAccounts, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	var accIDs []string
+	if len(attr.AccountIDs) == 0 {
+		accKeys, err := self.DataManager.DataDB().GetKeysForPrefix(utils.ACCOUNT_PREFIX + attr.Tenant + utils.CONCATENATED_KEY_SEP)
+		if err != nil {
+			return utils.NewErrServerError(err)
+		}
+		for _, accKey := range accKeys {
+			accIDs = append(accIDs, accKey[len(utils.ACCOUNT_PREFIX):])
+		}
+	} else {
+		for _, acntID := range attr.AccountIDs {
+			accIDs = append(accIDs, utils.AccountKey(attr.Tenant, acntID))
+		}
+	}
+	for _, accID := range accIDs {
+		if _, err = guardian.Guardian.Guard(func() (interface{}, error) {
+			acc, err := self.DataManager.DataDB().GetAccount(accID)
+			if err != nil {
+				return 0, err
+			}
+			return 0, engine.SnapshotAccount(acc)
+		}, config.CgrConfig().GeneralCfg().LockingTimeout, accID); err != nil {
+			if err != utils.ErrNotFound {
+				err = utils.NewErrServerError(err)
+			}
+			return
+		}
+	}
+	*reply = utils.OK
+	return
+}
+
+type AttrGetAccountAt struct {
+	Tenant  string
+	Account string
+	Time    string // balances as they were at this time
+}
+
+// GetAccountAt returns the account balances at a time in the past, reconstructed out of the
+// nearest snapshot before it with the changes following it
+func (self *ApierV1) GetAccountAt(attr AttrGetAccountAt, reply *engine.Account) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "Time"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	at, err := utils.ParseTimeDetectLayout(attr.Time, self.Config.GeneralCfg().DefaultTimezone)
+	if err != nil {
+		return
+	}
+	acc, err := engine.GetAccountAt(attr.Tenant, attr.Account, at)
+	if err != nil {
+		if err != utils.ErrNotFound && err != utils.ErrAccountAuditsDisabled {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = *acc
+	return
+}
@@ -19,6 +19,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package v1
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -175,13 +176,21 @@ func (self *ApierV1) GetRatingPlan(rplnId string, reply *engine.RatingPlan) erro
 	return nil
 }
 
+// ExecuteAction executes the actions of ActionsId, on the account if specified
 func (self *ApierV1) ExecuteAction(attr *utils.AttrExecuteAction, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV1ExecuteAction, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.executeAction(attr, reply) })
+}
+
+func (self *ApierV1) executeAction(attr *utils.AttrExecuteAction, reply *string) error {
 	at := &engine.ActionTiming{
 		ActionsID: attr.ActionsId,
 	}
 	if attr.Tenant != "" && attr.Account != "" {
 		at.SetAccountIDs(utils.StringMap{utils.AccountKey(attr.Tenant, attr.Account): true})
 	}
+	at.SetAuditCause(utils.ApierV1ExecuteAction)
 	if err := at.Execute(nil, nil); err != nil {
 		*reply = err.Error()
 		return err
@@ -473,7 +482,9 @@ type V1TPAction struct {
 	ExtraParameters string
 	BalanceBlocker  string
 	BalanceDisabled string
-	Weight          float64 // Action's weight
+	FilterIDs       []string // FilterS filters checked against the account and event before the action
+	FailurePolicy   string   // <*stop_on_failure|*continue> with the remaining actions when this one fails
+	Weight          float64  // Action's weight
 }
 
 func (self *ApierV1) SetActions(attrs V1AttrSetActions, reply *string) (err error) {
@@ -488,6 +499,9 @@ func (self *ApierV1) SetActions(attrs V1AttrSetActions, reply *string) (err erro
 		if missing := utils.MissingStructFields(action, requiredFields); len(missing) != 0 {
 			return fmt.Errorf("%s:Action:%s:%v", utils.ErrMandatoryIeMissing.Error(), action.Identifier, missing)
 		}
+		if !utils.IsSliceMember([]string{"", utils.MetaStopOnFailure, utils.MetaContinue}, action.FailurePolicy) {
+			return fmt.Errorf("unsupported FailurePolicy: %s", action.FailurePolicy)
+		}
 	}
 	if !attrs.Overwrite {
 		if exists, err := self.DataManager.HasData(utils.ACTION_PREFIX, attrs.ActionsId, ""); err != nil {
@@ -505,6 +519,8 @@ func (self *ApierV1) SetActions(attrs V1AttrSetActions, reply *string) (err erro
 			ExpirationString: apiAct.ExpiryTime,
 			ExtraParameters:  apiAct.ExtraParameters,
 			Filter:           apiAct.Filter,
+			FilterIDs:        apiAct.FilterIDs,
+			FailurePolicy:    apiAct.FailurePolicy,
 			Balance: &engine.BalanceFilter{ // TODO: update this part
 				Uuid:           utils.StringPointer(apiAct.BalanceUuid),
 				ID:             utils.StringPointer(apiAct.BalanceId),
@@ -541,6 +557,8 @@ func (self *ApierV1) GetActions(actsId string, reply *[]*utils.TPAction) error {
 			ExpiryTime:      engAct.ExpirationString,
 			ExtraParameters: engAct.ExtraParameters,
 			Filter:          engAct.Filter,
+			FilterIDs:       engAct.FilterIDs,
+			FailurePolicy:   engAct.FailurePolicy,
 			Weight:          engAct.Weight,
 		}
 		bf := engAct.Balance
@@ -821,6 +839,14 @@ func (self *ApierV1) ReloadCache(attrs utils.AttrReloadCache, reply *string) (er
 	if err = self.reloadCache(utils.DispatcherProfilePrefix, attrs.DispatcherProfileIDs); err != nil {
 		return
 	}
+	// TaxProfiles
+	if err = self.reloadCache(utils.TaxProfilePrefix, attrs.TaxProfileIDs); err != nil {
+		return
+	}
+	// Calendars
+	if err = self.reloadCache(utils.CalendarPrefix, attrs.CalendarIDs); err != nil {
+		return
+	}
 
 	*reply = utils.OK
 	return nil
@@ -830,7 +856,7 @@ func (self *ApierV1) LoadCache(args utils.AttrReloadCache, reply *string) (err e
 	if args.FlushAll {
 		engine.Cache.Clear(nil)
 	}
-	var dstIDs, rvDstIDs, rplIDs, rpfIDs, actIDs, aplIDs, aapIDs, atrgIDs, sgIDs, lcrIDs, dcIDs, alsIDs, rvAlsIDs, rspIDs, resIDs, stqIDs, stqpIDs, thIDs, thpIDs, fltrIDs, splpIDs, alsPrfIDs, cppIDs, dppIDs []string
+	var dstIDs, rvDstIDs, rplIDs, rpfIDs, actIDs, aplIDs, aapIDs, atrgIDs, sgIDs, lcrIDs, dcIDs, alsIDs, rvAlsIDs, rspIDs, resIDs, stqIDs, stqpIDs, thIDs, thpIDs, fltrIDs, splpIDs, alsPrfIDs, cppIDs, dppIDs, txpIDs, calIDs []string
 	if args.DestinationIDs == nil {
 		dstIDs = nil
 	} else {
@@ -946,10 +972,20 @@ func (self *ApierV1) LoadCache(args utils.AttrReloadCache, reply *string) (err e
 	} else {
 		dppIDs = *args.DispatcherProfileIDs
 	}
+	if args.TaxProfileIDs == nil {
+		txpIDs = nil
+	} else {
+		txpIDs = *args.TaxProfileIDs
+	}
+	if args.CalendarIDs == nil {
+		calIDs = nil
+	} else {
+		calIDs = *args.CalendarIDs
+	}
 	if err := self.DataManager.LoadDataDBCache(dstIDs, rvDstIDs, rplIDs,
 		rpfIDs, actIDs, aplIDs, aapIDs, atrgIDs, sgIDs, lcrIDs, dcIDs, alsIDs,
 		rvAlsIDs, rspIDs, resIDs, stqIDs, stqpIDs, thIDs, thpIDs,
-		fltrIDs, splpIDs, alsPrfIDs, cppIDs, dppIDs); err != nil {
+		fltrIDs, splpIDs, alsPrfIDs, cppIDs, dppIDs, txpIDs, calIDs); err != nil {
 		return utils.NewErrServerError(err)
 	}
 	*reply = utils.OK
@@ -995,6 +1031,8 @@ func (self *ApierV1) FlushCache(args utils.AttrReloadCache, reply *string) (err
 	flushCache(utils.CacheAttributeProfiles, args.AttributeProfileIDs)
 	flushCache(utils.CacheChargerProfiles, args.ChargerProfileIDs)
 	flushCache(utils.CacheDispatcherProfiles, args.DispatcherProfileIDs)
+	flushCache(utils.CacheTaxProfiles, args.TaxProfileIDs)
+	flushCache(utils.CacheCalendars, args.CalendarIDs)
 
 	*reply = utils.OK
 	return
@@ -1024,6 +1062,8 @@ func (self *ApierV1) GetCacheStats(attrs utils.AttrCacheStats, reply *utils.Cach
 	cs.AttributeProfiles = len(engine.Cache.GetItemIDs(utils.CacheAttributeProfiles, ""))
 	cs.ChargerProfiles = len(engine.Cache.GetItemIDs(utils.CacheChargerProfiles, ""))
 	cs.DispatcherProfiles = len(engine.Cache.GetItemIDs(utils.CacheDispatcherProfiles, ""))
+	cs.TaxProfiles = len(engine.Cache.GetItemIDs(utils.CacheTaxProfiles, ""))
+	cs.Calendars = len(engine.Cache.GetItemIDs(utils.CacheCalendars, ""))
 
 	if self.Users != nil {
 		var ups engine.UserProfiles
@@ -1194,6 +1234,18 @@ func (v1 *ApierV1) GetCacheKeys(args utils.ArgsCacheKeys, reply *utils.ArgsCache
 			reply.ChargerProfileIDs = &ids
 		}
 	}
+	if args.TaxProfileIDs != nil {
+		ids := getCacheKeys(utils.CacheTaxProfiles, args.TaxProfileIDs, args.Paginator)
+		if len(ids) != 0 {
+			reply.TaxProfileIDs = &ids
+		}
+	}
+	if args.CalendarIDs != nil {
+		ids := getCacheKeys(utils.CacheCalendars, args.CalendarIDs, args.Paginator)
+		if len(ids) != 0 {
+			reply.CalendarIDs = &ids
+		}
+	}
 
 	return
 }
@@ -1234,6 +1286,9 @@ func (self *ApierV1) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 			path.Join(attrs.FolderPath, utils.AttributesCsv),
 			path.Join(attrs.FolderPath, utils.ChargersCsv),
 			path.Join(attrs.FolderPath, utils.DispatchersCsv),
+			path.Join(attrs.FolderPath, utils.ExchangeRatesCsv),
+			path.Join(attrs.FolderPath, utils.TaxProfilesCsv),
+			path.Join(attrs.FolderPath, utils.CalendarsCsv),
 		), "", self.Config.GeneralCfg().DefaultTimezone)
 	if err := loader.LoadAll(); err != nil {
 		return utils.NewErrServerError(err)
@@ -1519,6 +1574,14 @@ func (v1 *ApierV1) ReplayFailedPosts(args ArgsReplyFailedPosts, reply *string) (
 				v1.Config.GeneralCfg().ReplyTimeout).Post(ffn.Address,
 				utils.PosterTransportContentTypes[ffn.Transport], fileContent,
 				v1.Config.GeneralCfg().PosterAttempts, failoverPath)
+		case utils.MetaHTTPjsonHeaders: // the failoverPath is written below, with the headers
+			var req engine.HTTPPostRequest
+			if err = json.Unmarshal(fileContent, &req); err == nil {
+				_, err = engine.NewHTTPPoster(v1.Config.GeneralCfg().HttpSkipTlsVerify,
+					v1.Config.GeneralCfg().ReplyTimeout).PostWithHeaders(ffn.Address,
+					utils.CONTENT_JSON, []byte(req.Body), req.Headers,
+					v1.Config.GeneralCfg().PosterAttempts, utils.META_NONE)
+			}
 		case utils.MetaAMQPjsonCDR, utils.MetaAMQPjsonMap:
 			err = engine.PostersCache.PostAMQP(ffn.Address,
 				v1.Config.GeneralCfg().PosterAttempts, fileContent,
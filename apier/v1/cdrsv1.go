@@ -84,3 +84,12 @@ func (self *CdrsV1) CountCDRs(args utils.RPCCDRsFilter, reply *int64) error {
 func (self *CdrsV1) GetCDRs(args utils.RPCCDRsFilter, reply *[]*engine.CDR) error {
 	return self.CdrSrv.V1GetCDRs(args, reply)
 }
+
+func (self *CdrsV1) GetSMCosts(args engine.ArgsGetSMCosts, reply *[]*engine.SMCost) error {
+	return self.CdrSrv.V1GetSMCosts(args, reply)
+}
+
+// GetDedupStats returns the counters of the duplicated CDRs, indexed on dedup rule ID
+func (self *CdrsV1) GetDedupStats(ignore string, reply *map[string]*engine.CDRDedupStats) error {
+	return self.CdrSrv.V1GetDedupStats(ignore, reply)
+}
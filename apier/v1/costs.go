@@ -97,3 +97,52 @@ func (apier *ApierV1) GetDataCost(attrs AttrGetDataCost, reply *engine.DataCost)
 	}
 	return nil
 }
+
+// AttrSimulateRating selects the CDRs to be re-rated on the rating data of a TariffPlan in StorDB
+type AttrSimulateRating struct {
+	TPid         string                // TariffPlan holding the candidate rating data
+	RatingPlanID string                // rate all CDRs on this RatingPlan instead of the RatingProfiles in TPid
+	CDRsFilter   *utils.RPCCDRsFilter  // select the CDRs out of StorDB
+	CDRs         []*engine.ExternalCDR // CDRs passed inline
+}
+
+// SimulateRating re-rates CDRs in memory on the rating data of a TariffPlan, returning the old versus the new costs
+func (apier *ApierV1) SimulateRating(attrs AttrSimulateRating, reply *engine.RatingSimulation) (err error) {
+	if missing := utils.MissingStructFields(&attrs, []string{"TPid"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if attrs.CDRsFilter == nil && len(attrs.CDRs) == 0 {
+		return utils.NewErrMandatoryIeMissing("CDRs")
+	}
+	tz := apier.Config.GeneralCfg().DefaultTimezone
+	cdrs := make([]*engine.CDR, len(attrs.CDRs))
+	for i, extCDR := range attrs.CDRs {
+		if cdrs[i], err = engine.NewCDRFromExternalCDR(extCDR, tz); err != nil {
+			return utils.NewErrServerError(err)
+		}
+	}
+	if attrs.CDRsFilter != nil {
+		var cdrsFltr *utils.CDRsFilter
+		if cdrsFltr, err = attrs.CDRsFilter.AsCDRsFilter(tz); err != nil {
+			return utils.NewErrServerError(err)
+		}
+		storedCDRs, _, err := apier.CdrDb.GetCDRs(cdrsFltr, false)
+		if err != nil && err != utils.ErrNotFound {
+			return utils.NewErrServerError(err)
+		}
+		cdrs = append(cdrs, storedCDRs...)
+	}
+	if len(cdrs) == 0 {
+		return utils.ErrNotFound
+	}
+	rSim, err := engine.SimulateRating(apier.StorDb, attrs.TPid,
+		attrs.RatingPlanID, tz, cdrs)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = *rSim
+	return
+}
@@ -147,6 +147,10 @@ func testPrecacheGetCacheStatsBeforeLoad(t *testing.T) {
 			Items:  0,
 			Groups: 0,
 		},
+		"calendars": {
+			Items:  0,
+			Groups: 0,
+		},
 		"charger_filter_indexes": {
 			Items:  0,
 			Groups: 0,
@@ -235,6 +239,14 @@ func testPrecacheGetCacheStatsBeforeLoad(t *testing.T) {
 			Items:  0,
 			Groups: 0,
 		},
+		"tax_filter_indexes": {
+			Items:  0,
+			Groups: 0,
+		},
+		"tax_profiles": {
+			Items:  0,
+			Groups: 0,
+		},
 		"threshold_filter_indexes": {
 			Items:  0,
 			Groups: 0,
@@ -319,6 +331,10 @@ func testPrecacheGetCacheStatsAfterRestart(t *testing.T) {
 			Items:  1,
 			Groups: 0,
 		},
+		"calendars": {
+			Items:  0,
+			Groups: 0,
+		},
 		"charger_filter_indexes": {
 			Items:  0,
 			Groups: 0,
@@ -407,6 +423,14 @@ func testPrecacheGetCacheStatsAfterRestart(t *testing.T) {
 			Items:  3, // expected to have 3 items
 			Groups: 0,
 		},
+		"tax_filter_indexes": {
+			Items:  0,
+			Groups: 0,
+		},
+		"tax_profiles": {
+			Items:  0,
+			Groups: 0,
+		},
 		"threshold_filter_indexes": {
 			Items:  0,
 			Groups: 0,
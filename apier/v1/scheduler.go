@@ -26,6 +26,7 @@ import (
 
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/scheduler"
+	"github.com/cgrates/cgrates/servmanager"
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -199,3 +200,67 @@ func (self *ApierV1) ExecuteScheduledActions(attr AttrsExecuteScheduledActions,
 	*reply = utils.OK
 	return nil
 }
+
+func NewSchedulerSv1(srvMngr *servmanager.ServiceManager) *SchedulerSv1 {
+	return &SchedulerSv1{srvMngr: srvMngr}
+}
+
+// Exports RPC from SchedulerS
+type SchedulerSv1 struct {
+	srvMngr *servmanager.ServiceManager
+}
+
+// Call implements rpcclient.RpcClientConnection interface for internal RPC
+func (schSv1 *SchedulerSv1) Call(serviceMethod string,
+	args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(schSv1, serviceMethod, args, reply)
+}
+
+// GetExecutionHistory returns the past executions of the ActionTimings, newest first
+func (schSv1 *SchedulerSv1) GetExecutionHistory(args scheduler.ArgsGetExecutionHistory,
+	reply *[]*engine.ExecutionRecord) error {
+	sched := schSv1.srvMngr.GetScheduler()
+	if sched == nil {
+		return errors.New(utils.SchedulerNotRunningCaps)
+	}
+	recs, err := sched.GetExecutionHistory(args)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	}
+	if len(recs) == 0 {
+		return utils.ErrNotFound
+	}
+	*reply = recs
+	return nil
+}
+
+// GetExecutionProgress returns the executions in progress with the number of accounts processed
+func (schSv1 *SchedulerSv1) GetExecutionProgress(ignore string,
+	reply *[]*scheduler.ExecutionProgress) error {
+	sched := schSv1.srvMngr.GetScheduler()
+	if sched == nil {
+		return errors.New(utils.SchedulerNotRunningCaps)
+	}
+	prgs := sched.GetExecutionProgress()
+	if len(prgs) == 0 {
+		return utils.ErrNotFound
+	}
+	*reply = prgs
+	return nil
+}
+
+// QueueActionPlan adds the ActionTimings of one ActionPlan to the scheduler queue, without reloading the others
+func (schSv1 *SchedulerSv1) QueueActionPlan(apID string, reply *string) error {
+	sched := schSv1.srvMngr.GetScheduler()
+	if sched == nil {
+		return errors.New(utils.SchedulerNotRunningCaps)
+	}
+	if err := sched.QueueActionPlan(apID); err != nil {
+		if err == utils.ErrNotFound {
+			return err
+		}
+		return utils.NewErrServerError(err)
+	}
+	*reply = utils.OK
+	return nil
+}
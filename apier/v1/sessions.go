@@ -20,6 +20,7 @@ package v1
 
 import (
 	"github.com/cenkalti/rpc2"
+	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/sessions"
 	"github.com/cgrates/cgrates/utils"
 )
@@ -36,20 +37,23 @@ type SessionSv1 struct {
 // Publishes BiJSONRPC methods exported by SessionSv1
 func (ssv1 *SessionSv1) Handlers() map[string]interface{} {
 	return map[string]interface{}{
-		utils.SessionSv1AuthorizeEvent:             ssv1.BiRpcAuthorizeEvent,
-		utils.SessionSv1AuthorizeEventWithDigest:   ssv1.BiRpcAuthorizeEventWithDigest,
-		utils.SessionSv1InitiateSession:            ssv1.BiRpcInitiateSession,
-		utils.SessionSv1InitiateSessionWithDigest:  ssv1.BiRpcInitiateSessionWithDigest,
-		utils.SessionSv1UpdateSession:              ssv1.BiRpcUpdateSession,
-		utils.SessionSv1SyncSessions:               ssv1.BiRpcSyncSessions,
-		utils.SessionSv1TerminateSession:           ssv1.BiRpcTerminateSession,
-		utils.SessionSv1ProcessCDR:                 ssv1.BiRpcProcessCDR,
-		utils.SessionSv1ProcessEvent:               ssv1.BiRpcProcessEvent,
-		utils.SessionSv1GetActiveSessions:          ssv1.BiRPCV1GetActiveSessions,
-		utils.SessionSv1ForceDisconnect:            ssv1.BiRPCV1ForceDisconnect,
-		utils.SessionSv1GetPassiveSessions:         ssv1.BiRPCV1GetPassiveSessions,
-		utils.SessionSv1RegisterInternalBiJSONConn: ssv1.BiRPCv1RegisterInternalBiJSONConn,
-		utils.SessionSv1Ping:                       ssv1.BiRPCPing,
+		utils.SessionSv1AuthorizeEvent:               ssv1.BiRpcAuthorizeEvent,
+		utils.SessionSv1AuthorizeEventWithDigest:     ssv1.BiRpcAuthorizeEventWithDigest,
+		utils.SessionSv1InitiateSession:              ssv1.BiRpcInitiateSession,
+		utils.SessionSv1InitiateSessionWithDigest:    ssv1.BiRpcInitiateSessionWithDigest,
+		utils.SessionSv1UpdateSession:                ssv1.BiRpcUpdateSession,
+		utils.SessionSv1SyncSessions:                 ssv1.BiRpcSyncSessions,
+		utils.SessionSv1TerminateSession:             ssv1.BiRpcTerminateSession,
+		utils.SessionSv1ProcessCDR:                   ssv1.BiRpcProcessCDR,
+		utils.SessionSv1ProcessEvent:                 ssv1.BiRpcProcessEvent,
+		utils.SessionSv1GetActiveSessions:            ssv1.BiRPCV1GetActiveSessions,
+		utils.SessionSv1ForceDisconnect:              ssv1.BiRPCV1ForceDisconnect,
+		utils.SessionSv1GetActiveSessionsWithFilters: ssv1.BiRPCV1GetActiveSessionsWithFilters,
+		utils.SessionSv1ForceDisconnectWithFilters:   ssv1.BiRPCV1ForceDisconnectWithFilters,
+		utils.SessionSv1GetPassiveSessions:           ssv1.BiRPCV1GetPassiveSessions,
+		utils.SessionSv1GetSessionHistory:            ssv1.BiRPCV1GetSessionHistory,
+		utils.SessionSv1RegisterInternalBiJSONConn:   ssv1.BiRPCv1RegisterInternalBiJSONConn,
+		utils.SessionSv1Ping:                         ssv1.BiRPCPing,
 	}
 }
 
@@ -105,10 +109,25 @@ func (ssv1 *SessionSv1) ForceDisconnect(args map[string]string, rply *string) er
 	return ssv1.SMG.BiRPCV1ForceDisconnect(nil, args, rply)
 }
 
+func (ssv1 *SessionSv1) GetActiveSessionsWithFilters(args *sessions.ArgsFilterSessions,
+	rply *[]*sessions.ActiveSession) error {
+	return ssv1.SMG.BiRPCV1GetActiveSessionsWithFilters(nil, args, rply)
+}
+
+func (ssv1 *SessionSv1) ForceDisconnectWithFilters(args *sessions.ArgsFilterSessions,
+	rply *[]string) error {
+	return ssv1.SMG.BiRPCV1ForceDisconnectWithFilters(nil, args, rply)
+}
+
 func (ssv1 *SessionSv1) GetPassiveSessions(args map[string]string, rply *[]*sessions.ActiveSession) error {
 	return ssv1.SMG.BiRPCV1GetPassiveSessions(nil, args, rply)
 }
 
+func (ssv1 *SessionSv1) GetSessionHistory(cgrID string,
+	rply *map[string][]*engine.SessionHistoryEntry) error {
+	return ssv1.SMG.BiRPCV1GetSessionHistory(nil, cgrID, rply)
+}
+
 func (ssv1 *SessionSv1) BiRpcAuthorizeEvent(clnt *rpc2.Client, args *sessions.V1AuthorizeArgs,
 	rply *sessions.V1AuthorizeReply) error {
 	return ssv1.SMG.BiRPCv1AuthorizeEvent(clnt, args, rply)
@@ -163,11 +182,26 @@ func (ssv1 *SessionSv1) BiRPCV1ForceDisconnect(clnt *rpc2.Client, args map[strin
 	return ssv1.SMG.BiRPCV1ForceDisconnect(clnt, args, rply)
 }
 
+func (ssv1 *SessionSv1) BiRPCV1GetActiveSessionsWithFilters(clnt *rpc2.Client,
+	args *sessions.ArgsFilterSessions, rply *[]*sessions.ActiveSession) error {
+	return ssv1.SMG.BiRPCV1GetActiveSessionsWithFilters(clnt, args, rply)
+}
+
+func (ssv1 *SessionSv1) BiRPCV1ForceDisconnectWithFilters(clnt *rpc2.Client,
+	args *sessions.ArgsFilterSessions, rply *[]string) error {
+	return ssv1.SMG.BiRPCV1ForceDisconnectWithFilters(clnt, args, rply)
+}
+
 func (ssv1 *SessionSv1) BiRPCV1GetPassiveSessions(clnt *rpc2.Client, args map[string]string,
 	rply *[]*sessions.ActiveSession) error {
 	return ssv1.SMG.BiRPCV1GetPassiveSessions(clnt, args, rply)
 }
 
+func (ssv1 *SessionSv1) BiRPCV1GetSessionHistory(clnt *rpc2.Client, cgrID string,
+	rply *map[string][]*engine.SessionHistoryEntry) error {
+	return ssv1.SMG.BiRPCV1GetSessionHistory(clnt, cgrID, rply)
+}
+
 func (ssv1 *SessionSv1) BiRPCv1RegisterInternalBiJSONConn(clnt *rpc2.Client, args string,
 	rply *string) error {
 	return ssv1.SMG.BiRPCv1RegisterInternalBiJSONConn(clnt, args, rply)
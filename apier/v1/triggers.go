@@ -51,9 +51,16 @@ type AttrAddAccountActionTriggers struct {
 	ActionTriggerOverwrite bool
 	ActivationDate         string
 	Executed               bool
+	RequestID              string // optional, retries with the same RequestID receive the first result
 }
 
 func (self *ApierV1) AddAccountActionTriggers(attr AttrAddAccountActionTriggers, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV1AddAccountActionTriggers, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.addAccountActionTriggers(attr, reply) })
+}
+
+func (self *ApierV1) addAccountActionTriggers(attr AttrAddAccountActionTriggers, reply *string) error {
 	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
@@ -108,13 +115,20 @@ func (self *ApierV1) AddAccountActionTriggers(attr AttrAddAccountActionTriggers,
 }
 
 type AttrRemoveAccountActionTriggers struct {
-	Tenant   string
-	Account  string
-	GroupID  string
-	UniqueID string
+	Tenant    string
+	Account   string
+	GroupID   string
+	UniqueID  string
+	RequestID string // optional, retries with the same RequestID receive the first result
 }
 
 func (self *ApierV1) RemoveAccountActionTriggers(attr AttrRemoveAccountActionTriggers, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV1RemoveAccountActionTriggers, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.removeAccountActionTriggers(attr, reply) })
+}
+
+func (self *ApierV1) removeAccountActionTriggers(attr AttrRemoveAccountActionTriggers, reply *string) error {
 	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
@@ -148,14 +162,21 @@ func (self *ApierV1) RemoveAccountActionTriggers(attr AttrRemoveAccountActionTri
 }
 
 type AttrResetAccountActionTriggers struct {
-	Tenant   string
-	Account  string
-	GroupID  string
-	UniqueID string
-	Executed bool
+	Tenant    string
+	Account   string
+	GroupID   string
+	UniqueID  string
+	Executed  bool
+	RequestID string // optional, retries with the same RequestID receive the first result
 }
 
 func (self *ApierV1) ResetAccountActionTriggers(attr AttrResetAccountActionTriggers, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV1ResetAccountActionTriggers, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.resetAccountActionTriggers(attr, reply) })
+}
+
+func (self *ApierV1) resetAccountActionTriggers(attr AttrResetAccountActionTriggers, reply *string) error {
 
 	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
@@ -215,9 +236,16 @@ type AttrSetAccountActionTriggers struct {
 	BalanceDisabled       *bool
 	MinQueuedItems        *int
 	ActionsID             *string
+	RequestID             string // optional, retries with the same RequestID receive the first result
 }
 
 func (self *ApierV1) SetAccountActionTriggers(attr AttrSetAccountActionTriggers, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV1SetAccountActionTriggers, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.setAccountActionTriggers(attr, reply) })
+}
+
+func (self *ApierV1) setAccountActionTriggers(attr AttrSetAccountActionTriggers, reply *string) error {
 
 	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
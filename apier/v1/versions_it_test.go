@@ -119,9 +119,9 @@ func testVrsDataDB(t *testing.T) {
 func testVrsStorDB(t *testing.T) {
 	var result engine.Versions
 	expectedVrs := engine.Versions{"TpDerivedChargers": 1, "TpDestinations": 1, "TpResource": 1, "TpThresholds": 1,
-		"TpActions": 1, "TpDestinationRates": 1, "TpFilters": 1, "TpRates": 1, "CDRs": 2, "TpActionTriggers": 1, "TpRatingPlans": 1,
-		"TpSharedGroups": 1, "TpSuppliers": 1, "SessionSCosts": 3, "TpDerivedCharges": 1, "TpRatingProfiles": 1, "TpStats": 1, "TpTiming": 1,
-		"CostDetails": 2, "TpAccountActions": 1, "TpActionPlans": 1, "TpChargers": 1, "TpRatingProfile": 1, "TpUsers": 1,
+		"TpActions": 2, "TpDestinationRates": 3, "TpFilters": 1, "TpRates": 1, "CDRs": 3, "TpActionTriggers": 1, "TpRatingPlans": 1,
+		"TpSharedGroups": 1, "TpSuppliers": 1, "SessionSCosts": 4, "AccountAudits": 1, "AccountSnapshots": 1, "TpDerivedCharges": 1, "TpRatingProfiles": 1, "TpStats": 1, "TpTiming": 2,
+		"CostDetails": 2, "TpAccountActions": 2, "TpActionPlans": 1, "TpChargers": 1, "TpRatingProfile": 1, "TpUsers": 1,
 		"TpAliases": 1, "TpRatingPlan": 1, "TpResources": 1}
 	if err := vrsRPC.Call("ApierV1.GetStorDBVersions", "", &result); err != nil {
 		t.Error(err)
@@ -96,13 +96,24 @@ type AttrSetAccount struct {
 	ActionTriggerOverwrite bool
 	AllowNegative          *bool
 	Disabled               *bool
+	DebitPolicy            *string
 	ReloadScheduler        bool
+	RequestID              string // optional, retries with the same RequestID receive the first result
 }
 
 func (self *ApierV2) SetAccount(attr AttrSetAccount, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV2SetAccount, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.setAccount(attr, reply) })
+}
+
+func (self *ApierV2) setAccount(attr AttrSetAccount, reply *string) error {
 	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
+	if attr.DebitPolicy != nil && !engine.IsDebitPolicy(*attr.DebitPolicy) {
+		return fmt.Errorf("unsupported DebitPolicy: %s", *attr.DebitPolicy)
+	}
 	accID := utils.AccountKey(attr.Tenant, attr.Account)
 	dirtyActionPlans := make(map[string]*engine.ActionPlan)
 	var ub *engine.Account
@@ -228,6 +239,9 @@ func (self *ApierV2) SetAccount(attr AttrSetAccount, reply *string) error {
 		if attr.Disabled != nil {
 			ub.Disabled = *attr.Disabled
 		}
+		if attr.DebitPolicy != nil {
+			ub.DebitPolicy = *attr.DebitPolicy
+		}
 		// All prepared, save account
 		return 0, self.DataManager.DataDB().SetAccount(ub)
 	}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
@@ -146,6 +146,9 @@ func (self *ApierV2) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 			path.Join(attrs.FolderPath, utils.AttributesCsv),
 			path.Join(attrs.FolderPath, utils.ChargersCsv),
 			path.Join(attrs.FolderPath, utils.DispatchersCsv),
+			path.Join(attrs.FolderPath, utils.ExchangeRatesCsv),
+			path.Join(attrs.FolderPath, utils.TaxProfilesCsv),
+			path.Join(attrs.FolderPath, utils.CalendarsCsv),
 		), "", self.Config.GeneralCfg().DefaultTimezone)
 	if err := loader.LoadAll(); err != nil {
 		return utils.NewErrServerError(err)
@@ -306,6 +309,9 @@ func (self *ApierV2) SetActions(attrs utils.AttrSetActions, reply *string) error
 		if missing := utils.MissingStructFields(action, requiredFields); len(missing) != 0 {
 			return fmt.Errorf("%s:Action:%s:%v", utils.ErrMandatoryIeMissing.Error(), action.Identifier, missing)
 		}
+		if !utils.IsSliceMember([]string{"", utils.MetaStopOnFailure, utils.MetaContinue}, action.FailurePolicy) {
+			return fmt.Errorf("unsupported FailurePolicy: %s", action.FailurePolicy)
+		}
 	}
 	if !attrs.Overwrite {
 		if exists, err := self.DataManager.HasData(utils.ACTION_PREFIX, attrs.ActionsId, ""); err != nil {
@@ -341,6 +347,8 @@ func (self *ApierV2) SetActions(attrs utils.AttrSetActions, reply *string) error
 			ExpirationString: apiAct.ExpiryTime,
 			ExtraParameters:  apiAct.ExtraParameters,
 			Filter:           apiAct.Filter,
+			FilterIDs:        apiAct.FilterIDs,
+			FailurePolicy:    apiAct.FailurePolicy,
 			Balance: &engine.BalanceFilter{ // TODO: update this part
 				Uuid:           utils.StringPointer(apiAct.BalanceUuid),
 				ID:             utils.StringPointer(apiAct.BalanceId),
@@ -53,6 +53,7 @@ type AttrSetAccountActionTriggers struct {
 	BalanceDisabled       *bool
 	MinQueuedItems        *int
 	ActionsID             *string
+	RequestID             string // optional, retries with the same RequestID receive the first result
 }
 
 func (attr *AttrSetAccountActionTriggers) UpdateActionTrigger(at *engine.ActionTrigger, timezone string) (updated bool, err error) {
@@ -154,6 +155,12 @@ func (attr *AttrSetAccountActionTriggers) UpdateActionTrigger(at *engine.ActionT
 
 // SetAccountActionTriggers Updates or Creates ActionTriggers for an Account
 func (self *ApierV2) SetAccountActionTriggers(attr AttrSetAccountActionTriggers, reply *string) error {
+	return engine.ProcessRequestOnce(utils.ApierV2SetAccountActionTriggers, utils.AccountKey(attr.Tenant, attr.Account),
+		attr.RequestID, self.Config.RalsCfg().RequestIDTTL, reply,
+		func() error { return self.setAccountActionTriggers(attr, reply) })
+}
+
+func (self *ApierV2) setAccountActionTriggers(attr AttrSetAccountActionTriggers, reply *string) error {
 	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
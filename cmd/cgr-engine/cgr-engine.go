@@ -150,8 +150,12 @@ func startCdrc(internalCdrSChan, internalRaterChan chan rpcclient.RpcClientConne
 
 func startSessionS(internalSMGChan, internalRaterChan, internalResourceSChan, internalThresholdSChan,
 	internalStatSChan, internalSupplierSChan, internalAttrSChan,
-	internalCDRSChan, internalChargerSChan chan rpcclient.RpcClientConnection, server *utils.Server, exitChan chan bool) {
+	internalCDRSChan, internalChargerSChan chan rpcclient.RpcClientConnection,
+	filterSChan chan *engine.FilterS, dm *engine.DataManager,
+	server *utils.Server, exitChan chan bool) {
 	utils.Logger.Info("Starting CGRateS Session service.")
+	filterS := <-filterSChan
+	filterSChan <- filterS
 	var err error
 	var ralsConns, resSConns, threshSConns, statSConns, suplSConns, attrSConns, cdrsConn, chargerSConn *rpcclient.RpcClientPool
 	if len(cfg.SessionSCfg().ChargerSConns) != 0 {
@@ -283,7 +287,7 @@ func startSessionS(internalSMGChan, internalRaterChan, internalResourceSChan, in
 	}
 	sm := sessions.NewSMGeneric(cfg, ralsConns, resSConns, threshSConns,
 		statSConns, suplSConns, attrSConns, cdrsConn, chargerSConn,
-		smgReplConns, cfg.GeneralCfg().DefaultTimezone)
+		filterS, dm, smgReplConns, cfg.GeneralCfg().DefaultTimezone)
 	if err = sm.Connect(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<%s> error: %s!", utils.SessionS, err))
 	}
@@ -1327,7 +1331,8 @@ func main() {
 	if cfg.RalsCfg().RALsEnabled || cfg.PubSubServerEnabled ||
 		cfg.AliasesServerEnabled || cfg.UserServerEnabled || cfg.SchedulerCfg().Enabled ||
 		cfg.AttributeSCfg().Enabled || cfg.ResourceSCfg().Enabled || cfg.StatSCfg().Enabled ||
-		cfg.ThresholdSCfg().Enabled || cfg.SupplierSCfg().Enabled || cfg.DispatcherSCfg().Enabled { // Some services can run without db, ie: SessionS or CDRC
+		cfg.ThresholdSCfg().Enabled || cfg.SupplierSCfg().Enabled || cfg.DispatcherSCfg().Enabled ||
+		(cfg.SessionSCfg().Enabled && cfg.SessionSCfg().DataDBReplicationInterval != 0) { // Some services can run without db, ie: SessionS or CDRC
 		dm, err = engine.ConfigureDataStorage(cfg.DataDbCfg().DataDbType,
 			cfg.DataDbCfg().DataDbHost, cfg.DataDbCfg().DataDbPort,
 			cfg.DataDbCfg().DataDbName, cfg.DataDbCfg().DataDbUser,
@@ -1367,7 +1372,9 @@ func main() {
 	}
 	// Done initing DBs
 	engine.SetRoundingDecimals(cfg.GeneralCfg().RoundingDecimals)
+	utils.SetDecimalPrecision(cfg.GeneralCfg().DecimalPrecision)
 	engine.SetRpSubjectPrefixMatching(cfg.RalsCfg().RpSubjectPrefixMatching)
+	engine.SetAuditAccounts(cfg.RalsCfg().AuditAccounts)
 	stopHandled := false
 
 	// Rpc/http server
@@ -1442,7 +1449,7 @@ func main() {
 		go startSessionS(internalSMGChan, internalRaterChan,
 			internalRsChan, internalThresholdSChan,
 			internalStatSChan, internalSupplierSChan, internalAttributeSChan,
-			internalCdrSChan, internalChargerSChan, server, exitChan)
+			internalCdrSChan, internalChargerSChan, filterSChan, dm, server, exitChan)
 	}
 	// Start FreeSWITCHAgent
 	if cfg.FsAgentCfg().Enabled {
@@ -1538,6 +1545,8 @@ func main() {
 		internalSupplierSChan,
 		internalSMGChan, internalAnalyzerSChan, internalDispatcherSChan, exitChan)
 	<-exitChan
+	engine.FlushHTTPPosts()     // the queued posts are written to failed posts
+	engine.FlushAccountAudits() // before closing StorDB
 
 	if *cpuProfDir != "" { // wait to end cpuProfiling
 		cpuProfChanStop <- struct{}{}
@@ -189,6 +189,7 @@ func startRater(internalRaterChan chan rpcclient.RpcClientConnection, cacheS *en
 	if stats != nil {
 		engine.SetStatS(stats)
 	}
+	engine.SetFilterS(filterS)
 	if usersConns != nil {
 		apierRpcV1.Users = usersConns
 	}
@@ -198,6 +199,9 @@ func startRater(internalRaterChan chan rpcclient.RpcClientConnection, cacheS *en
 	server.RpcRegister(responder)
 	server.RpcRegister(apierRpcV1)
 	server.RpcRegister(apierRpcV2)
+	schedulerSv1 := v1.NewSchedulerSv1(serviceManager)
+	server.RpcRegisterName(utils.SchedulerSv1, schedulerSv1)
+	engine.SetSchedulerS(schedulerSv1)
 
 	utils.RegisterRpcParams("PubSubV1", &engine.PubSub{})
 	utils.RegisterRpcParams("AliasesV1", &engine.AliasHandler{})
@@ -301,6 +301,9 @@ func main() {
 			path.Join(*dataPath, utils.AttributesCsv),
 			path.Join(*dataPath, utils.ChargersCsv),
 			path.Join(*dataPath, utils.DispatchersCsv),
+			path.Join(*dataPath, utils.ExchangeRatesCsv),
+			path.Join(*dataPath, utils.TaxProfilesCsv),
+			path.Join(*dataPath, utils.CalendarsCsv),
 		)
 	}
 
@@ -355,7 +358,7 @@ func main() {
 			log.Fatal("Could not write to database: ", err)
 		}
 		var dstIds, revDstIDs, rplIds, rpfIds, actIds, aapIDs, shgIds, alsIds, dcsIds, rspIDs, resIDs,
-			aatIDs, ralsIDs, stqIDs, stqpIDs, trsIDs, trspfIDs, flrIDs, spfIDs, apfIDs, chargerIDs, dppIDs []string
+			aatIDs, ralsIDs, stqIDs, stqpIDs, trsIDs, trspfIDs, flrIDs, spfIDs, apfIDs, chargerIDs, dppIDs, txpIDs, calIDs []string
 		if cacheS != nil {
 			dstIds, _ = tpReader.GetLoadedIds(utils.DESTINATION_PREFIX)
 			revDstIDs, _ = tpReader.GetLoadedIds(utils.REVERSE_DESTINATION_PREFIX)
@@ -379,6 +382,8 @@ func main() {
 			apfIDs, _ = tpReader.GetLoadedIds(utils.AttributeProfilePrefix)
 			chargerIDs, _ = tpReader.GetLoadedIds(utils.ChargerProfilePrefix)
 			dppIDs, _ = tpReader.GetLoadedIds(utils.DispatcherProfilePrefix)
+			txpIDs, _ = tpReader.GetLoadedIds(utils.TaxProfilePrefix)
+			calIDs, _ = tpReader.GetLoadedIds(utils.CalendarPrefix)
 		}
 		aps, _ := tpReader.GetLoadedIds(utils.ACTION_PLAN_PREFIX)
 		// for users reloading
@@ -420,7 +425,9 @@ func main() {
 					SupplierProfileIDs:    &spfIDs,
 					AttributeProfileIDs:   &apfIDs,
 					ChargerProfileIDs:     &chargerIDs,
-					DispatcherProfileIDs:  &dppIDs},
+					DispatcherProfileIDs:  &dppIDs,
+					TaxProfileIDs:         &txpIDs,
+					CalendarIDs:           &calIDs},
 					FlushAll: *flush,
 				}, &reply); err != nil {
 				log.Printf("WARNING: Got error on cache reload: %s\n", err.Error())
@@ -450,6 +457,9 @@ func main() {
 			if len(dppIDs) != 0 {
 				cacheIDs = append(cacheIDs, utils.CacheDispatcherFilterIndexes)
 			}
+			if len(txpIDs) != 0 {
+				cacheIDs = append(cacheIDs, utils.CacheTaxFilterIndexes)
+			}
 			if err = cacheS.Call(utils.CacheSv1Clear, cacheIDs, &reply); err != nil {
 				log.Printf("WARNING: Got error on cache clear: %s\n", err.Error())
 			}
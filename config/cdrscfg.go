@@ -19,14 +19,55 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/cgrates/cgrates/utils"
 )
 
+// CDRDedupRule considers duplicated the CDRs with the same Fields received within Window
+type CDRDedupRule struct {
+	ID     string
+	Fields utils.RSRFields // fields defining the identity of the CDR
+	Window time.Duration
+	Action string // <*drop|*replace|*keep_longest>
+}
+
+func (dr *CDRDedupRule) loadFromJsonCfg(jsnCfg *CDRDedupRuleJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Id != nil {
+		dr.ID = *jsnCfg.Id
+	}
+	if jsnCfg.Fields != nil {
+		if dr.Fields, err = utils.ParseRSRFieldsFromSlice(*jsnCfg.Fields); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Window != nil {
+		if dr.Window, err = utils.ParseDurationWithNanosecs(*jsnCfg.Window); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Action != nil {
+		switch *jsnCfg.Action {
+		case utils.MetaDrop, utils.MetaReplace, utils.MetaKeepLongest:
+			dr.Action = *jsnCfg.Action
+		default:
+			return fmt.Errorf("unsupported action <%s> for dedup rule: %s", *jsnCfg.Action, dr.ID)
+		}
+	}
+	return
+}
+
 type CdrsCfg struct {
 	CDRSEnabled          bool              // Enable CDR Server service
 	CDRSExtraFields      []*utils.RSRField // Extra fields to store in CDRs
 	CDRSStoreCdrs        bool              // store cdrs in storDb
 	CDRSSMCostRetries    int
+	CDRSTaxes            bool // apply TaxProfiles on rated CDRs
+	CDRSDedupRules       []*CDRDedupRule
 	CDRSChargerSConns    []*HaPoolConfig
 	CDRSRaterConns       []*HaPoolConfig // address where to reach the Rater for cost calculation: <""|internal|x.y.z.y:1234>
 	CDRSPubSubSConns     []*HaPoolConfig // address where to reach the pubsub service: <""|internal|x.y.z.y:1234>
@@ -57,6 +98,18 @@ func (cdrscfg *CdrsCfg) loadFromJsonCfg(jsnCdrsCfg *CdrsJsonCfg) (err error) {
 	if jsnCdrsCfg.Sessions_cost_retries != nil {
 		cdrscfg.CDRSSMCostRetries = *jsnCdrsCfg.Sessions_cost_retries
 	}
+	if jsnCdrsCfg.Taxes != nil {
+		cdrscfg.CDRSTaxes = *jsnCdrsCfg.Taxes
+	}
+	if jsnCdrsCfg.Dedup_rules != nil {
+		cdrscfg.CDRSDedupRules = make([]*CDRDedupRule, len(*jsnCdrsCfg.Dedup_rules))
+		for idx, jsnRule := range *jsnCdrsCfg.Dedup_rules {
+			cdrscfg.CDRSDedupRules[idx] = &CDRDedupRule{Action: utils.MetaDrop}
+			if err = cdrscfg.CDRSDedupRules[idx].loadFromJsonCfg(jsnRule); err != nil {
+				return
+			}
+		}
+	}
 	if jsnCdrsCfg.Chargers_conns != nil {
 		cdrscfg.CDRSChargerSConns = make([]*HaPoolConfig, len(*jsnCdrsCfg.Chargers_conns))
 		for idx, jsnHaCfg := range *jsnCdrsCfg.Chargers_conns {
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -43,6 +44,10 @@ func TestCdrsCfgloadFromJsonCfg(t *testing.T) {
 	"extra_fields": [],						// extra fields to store in CDRs for non-generic CDRs
 	"store_cdrs": true,						// store cdrs in storDb
 	"sessions_cost_retries": 5,				// number of queries to sessions_costs before recalculating CDR
+	"dedup_rules": [
+		{"id": "failover", "fields": ["Account", "Destination", "SetupTime"], "window": "5m", "action": "*keep_longest"},
+		{"id": "resend", "fields": ["OriginID"], "window": "1h"}
+	],
 	"chargers_conns": [],					// address where to reach the charger service, empty to disable charger functionality: <""|*internal|x.y.z.y:1234>
 	"rals_conns": [
 		{"address": "*internal"}			// address where to reach the Rater for cost calculation, empty to disable functionality: <""|*internal|x.y.z.y:1234>
@@ -57,8 +62,23 @@ func TestCdrsCfgloadFromJsonCfg(t *testing.T) {
 	},
 }`
 	expected = CdrsCfg{
-		CDRSStoreCdrs:       true,
-		CDRSSMCostRetries:   5,
+		CDRSStoreCdrs:     true,
+		CDRSSMCostRetries: 5,
+		CDRSDedupRules: []*CDRDedupRule{
+			{
+				ID: "failover",
+				Fields: utils.ParseRSRFieldsMustCompile(
+					"Account;Destination;SetupTime", utils.INFIELD_SEP),
+				Window: 5 * time.Minute,
+				Action: utils.MetaKeepLongest,
+			},
+			{
+				ID:     "resend",
+				Fields: utils.ParseRSRFieldsMustCompile("OriginID", utils.INFIELD_SEP),
+				Window: time.Hour,
+				Action: utils.MetaDrop,
+			},
+		},
 		CDRSChargerSConns:   []*HaPoolConfig{},
 		CDRSRaterConns:      []*HaPoolConfig{{Address: utils.MetaInternal}},
 		CDRSPubSubSConns:    []*HaPoolConfig{},
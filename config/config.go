@@ -541,6 +541,10 @@ func (self *CGRConfig) checkConfigSanity() error {
 				}
 			}
 		}
+		if self.sessionSCfg.DataDBReplicationInterval != 0 &&
+			self.sessionSCfg.DataDBReplicationTTL <= self.sessionSCfg.DataDBReplicationInterval {
+			return errors.New("<SessionS> datadb_replication_ttl needs to be higher than datadb_replication_interval")
+		}
 	}
 	// FreeSWITCHAgent checks
 	if self.fsAgentCfg.Enabled {
@@ -725,6 +729,25 @@ func (self *CGRConfig) checkConfigSanity() error {
 			}
 		}
 	}
+	if self.schedulerCfg.LeaseTTL < 0 {
+		return errors.New("<SchedulerS> lease_ttl cannot be negative")
+	}
+	if !utils.IsSliceMember([]string{utils.MetaSkip, utils.MetaRunOnce, utils.MetaRunAll},
+		self.schedulerCfg.CatchupPolicy) {
+		return fmt.Errorf("<SchedulerS> unsupported catchup_policy: %s", self.schedulerCfg.CatchupPolicy)
+	}
+	if self.schedulerCfg.ExecutionWorkers < 1 {
+		return errors.New("<SchedulerS> execution_workers should be at least 1")
+	}
+	if self.schedulerCfg.ExecutionSpread < 0 {
+		return errors.New("<SchedulerS> execution_spread cannot be negative")
+	}
+	if self.schedulerCfg.CheckpointInterval < 0 {
+		return errors.New("<SchedulerS> checkpoint_interval cannot be negative")
+	}
+	if self.schedulerCfg.RolloverInterval < 0 {
+		return errors.New("<SchedulerS> rollover_interval cannot be negative")
+	}
 	return nil
 }
 
@@ -33,6 +33,7 @@ const CGRATES_CFG_JSON = `
 	"log_level": 6,											// control the level of messages logged (0-emerg to 7-debug)
 	"http_skip_tls_verify": false,							// if enabled Http Client will accept any TLS certificate
 	"rounding_decimals": 5,									// system level precision for floats
+	"decimal_precision": 10,								// decimals kept on intermediate results of monetary computations
 	"dbdata_encoding": "*msgpack",							// encoding used to store object data in strings: <*msgpack|*json>
 	"tpexport_dir": "/var/spool/cgrates/tpe",				// path towards export folder for offline Tariff Plans
 	"poster_attempts": 3,									// number of attempts before considering post request failed (eg: *call_url, CDR replication)
@@ -113,6 +114,13 @@ const CGRATES_CFG_JSON = `
 "scheduler": {
 	"enabled": false,				// start Scheduler service: <true|false>
 	"cdrs_conns": [],				// address where to reach CDR Server, empty to disable CDR capturing <*internal|x.y.z.y:1234>
+	"lease_ttl": "0s",				// elect the executing node out of the schedulers sharing the DataDB through a lease with this TTL, 0 to disable
+	"catchup_policy": "*skip",		// executions missed while down, done on start: <*skip|*run_once|*run_all>
+	"execution_history_size": 0,	// number of executions journaled in DataDB for SchedulerSv1.GetExecutionHistory, 0 to disable history
+	"execution_workers": 1,			// accounts of an ActionTiming executed in parallel
+	"execution_spread": "0s",		// window the account executions of an ActionTiming are spread over, 0 to start them at once
+	"checkpoint_interval": 0,		// processed accounts between two checkpoints used to resume an interrupted execution, 0 to disable
+	"rollover_interval": "0s",		// interval to roll over the expired balances of the accounts, 0 to disable
 },
 
 
@@ -142,6 +150,8 @@ const CGRATES_CFG_JSON = `
 	"attribute_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// control attribute profile caching
 	"charger_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// control charger profile caching
 	"dispatcher_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},	// control dispatcher profile caching
+	"tax_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},			// control tax profile caching
+	"calendars": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},				// control calendar caching
 	"resource_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 					// control resource filter indexes caching
 	"stat_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 						// control stat filter indexes caching
 	"threshold_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 				// control threshold filter indexes caching
@@ -149,6 +159,7 @@ const CGRATES_CFG_JSON = `
 	"attribute_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 				// control attribute filter indexes caching
 	"charger_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 					// control charger filter indexes caching
 	"dispatcher_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 				// control dispatcher filter indexes caching
+	"tax_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 						// control tax filter indexes caching
 	"dispatcher_routes": {"limit": -1, "ttl": "", "static_ttl": false}, 						// control dispatcher routes caching
 	"diameter_messages": {"limit": -1, "ttl": "3h", "static_ttl": false},						// diameter messages caching
 },
@@ -168,6 +179,8 @@ const CGRATES_CFG_JSON = `
 	"users_conns": [],						// address where to reach the user service, empty to disable user profile functionality: <""|*internal|x.y.z.y:1234>
 	"aliases_conns": [],					// address where to reach the aliases service, empty to disable aliases functionality: <""|*internal|x.y.z.y:1234>
 	"rp_subject_prefix_matching": false,	// enables prefix matching for the rating profile subject
+	"request_id_ttl": "24h",				// remember the results of the account APIs called with a RequestID for this long, 0 to disable
+	"audit_accounts": false,				// store the balance changes with their cause in StorDB
 	"max_computed_usage": {					// do not compute usage higher than this, prevents memory overload
 		"*any": "189h",
 		"*voice": "72h",
@@ -182,6 +195,8 @@ const CGRATES_CFG_JSON = `
 	"extra_fields": [],						// extra fields to store in CDRs for non-generic CDRs
 	"store_cdrs": true,						// store cdrs in storDb
 	"sessions_cost_retries": 5,				// number of queries to sessions_costs before recalculating CDR
+	"taxes": false,							// apply the matching TaxProfiles on rated CDRs
+	"dedup_rules": [],						// rules detecting duplicated CDRs, eg: {"id": "failover", "fields": ["Account", "Destination", "SetupTime"], "window": "5m", "action": "*drop"}, action: <*drop|*replace|*keep_longest>
 	"chargers_conns": [],					// address where to reach the charger service, empty to disable charger functionality: <""|*internal|x.y.z.y:1234>
 	"rals_conns": [
 		{"address": "*internal"}			// address where to reach the Rater for cost calculation, empty to disable functionality: <""|*internal|x.y.z.y:1234>
@@ -306,6 +321,8 @@ const CGRATES_CFG_JSON = `
 	"suppliers_conns": [],					// address where to reach the SupplierS <""|*internal|127.0.0.1:2013>
 	"attributes_conns": [],					// address where to reach the AttributeS <""|*internal|127.0.0.1:2013>
 	"session_replication_conns": [],		// replicate sessions towards these session services
+	"datadb_replication_interval": "0s",	// store sessions in DataDB at this interval so other nodes can take them over, 0 to disable
+	"datadb_replication_ttl": "15s",		// take over the sessions of the nodes not refreshing them within this interval
 	"debit_interval": "0s",					// interval to perform debits on.
 	"min_call_duration": "0s",				// only authorize calls with allowed duration higher than this
 	"max_call_duration": "3h",				// maximum call duration a prepaid call can last
@@ -314,6 +331,7 @@ const CGRATES_CFG_JSON = `
 	//"session_ttl_last_used": "",			// tweak LastUsed for sessions timing-out, not defined by default
 	//"session_ttl_usage": "",				// tweak Usage for sessions timing-out, not defined by default
 	"session_indexes": [],					// index sessions based on these fields for GetActiveSessions API
+	"session_history_size": 0,				// number of events kept in the history of each session, 0 to disable history
 	"client_protocol": 1.0,					// version of protocol to use when acting as JSON-PRC client <"0","1.0">
 	"channel_sync_interval": "0",			// sync channels regularly (0 to disable sync session)
 },
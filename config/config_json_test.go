@@ -43,6 +43,7 @@ func TestDfGeneralJsonCfg(t *testing.T) {
 		Log_level:            utils.IntPointer(utils.LOGLEVEL_INFO),
 		Http_skip_tls_verify: utils.BoolPointer(false),
 		Rounding_decimals:    utils.IntPointer(5),
+		Decimal_precision:    utils.IntPointer(10),
 		Dbdata_encoding:      utils.StringPointer("*msgpack"),
 		Tpexport_dir:         utils.StringPointer("/var/spool/cgrates/tpe"),
 		Poster_attempts:      utils.IntPointer(3),
@@ -145,6 +146,12 @@ func TestCacheJsonCfg(t *testing.T) {
 		utils.CacheDispatcherProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
 			Precache: utils.BoolPointer(false)},
+		utils.CacheTaxProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+			Precache: utils.BoolPointer(false)},
+		utils.CacheCalendars: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+			Precache: utils.BoolPointer(false)},
 		utils.CacheResourceFilterIndexes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheStatFilterIndexes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
@@ -159,6 +166,8 @@ func TestCacheJsonCfg(t *testing.T) {
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheDispatcherFilterIndexes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
+		utils.CacheTaxFilterIndexes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheDispatcherRoutes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheDiameterMessages: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
@@ -234,6 +243,8 @@ func TestDfRalsJsonCfg(t *testing.T) {
 		Users_conns:                &[]*HaPoolJsonCfg{},
 		Aliases_conns:              &[]*HaPoolJsonCfg{},
 		Rp_subject_prefix_matching: utils.BoolPointer(false),
+		Request_id_ttl:             utils.StringPointer("24h"),
+		Audit_accounts:             utils.BoolPointer(false),
 		Max_computed_usage: &map[string]string{
 			utils.ANY:   "189h",
 			utils.VOICE: "72h",
@@ -249,8 +260,15 @@ func TestDfRalsJsonCfg(t *testing.T) {
 
 func TestDfSchedulerJsonCfg(t *testing.T) {
 	eCfg := &SchedulerJsonCfg{
-		Enabled:    utils.BoolPointer(false),
-		Cdrs_conns: &[]*HaPoolJsonCfg{},
+		Enabled:                utils.BoolPointer(false),
+		Cdrs_conns:             &[]*HaPoolJsonCfg{},
+		Lease_ttl:              utils.StringPointer("0s"),
+		Catchup_policy:         utils.StringPointer(utils.MetaSkip),
+		Execution_history_size: utils.IntPointer(0),
+		Execution_workers:      utils.IntPointer(1),
+		Execution_spread:       utils.StringPointer("0s"),
+		Checkpoint_interval:    utils.IntPointer(0),
+		Rollover_interval:      utils.StringPointer("0s"),
 	}
 	if cfg, err := dfCgrJsonCfg.SchedulerJsonCfg(); err != nil {
 		t.Error(err)
@@ -265,6 +283,8 @@ func TestDfCdrsJsonCfg(t *testing.T) {
 		Extra_fields:          &[]string{},
 		Store_cdrs:            utils.BoolPointer(true),
 		Sessions_cost_retries: utils.IntPointer(5),
+		Taxes:                 utils.BoolPointer(false),
+		Dedup_rules:           &[]*CDRDedupRuleJsonCfg{},
 		Chargers_conns:        &[]*HaPoolJsonCfg{},
 		Rals_conns: &[]*HaPoolJsonCfg{
 			{
@@ -481,19 +501,22 @@ func TestSmgJsonCfg(t *testing.T) {
 			{
 				Address: utils.StringPointer(utils.MetaInternal),
 			}},
-		Resources_conns:           &[]*HaPoolJsonCfg{},
-		Thresholds_conns:          &[]*HaPoolJsonCfg{},
-		Stats_conns:               &[]*HaPoolJsonCfg{},
-		Suppliers_conns:           &[]*HaPoolJsonCfg{},
-		Attributes_conns:          &[]*HaPoolJsonCfg{},
-		Session_replication_conns: &[]*HaPoolJsonCfg{},
-		Debit_interval:            utils.StringPointer("0s"),
-		Min_call_duration:         utils.StringPointer("0s"),
-		Max_call_duration:         utils.StringPointer("3h"),
-		Session_ttl:               utils.StringPointer("0s"),
-		Session_indexes:           &[]string{},
-		Client_protocol:           utils.Float64Pointer(1.0),
-		Channel_sync_interval:     utils.StringPointer("0"),
+		Resources_conns:             &[]*HaPoolJsonCfg{},
+		Thresholds_conns:            &[]*HaPoolJsonCfg{},
+		Stats_conns:                 &[]*HaPoolJsonCfg{},
+		Suppliers_conns:             &[]*HaPoolJsonCfg{},
+		Attributes_conns:            &[]*HaPoolJsonCfg{},
+		Session_replication_conns:   &[]*HaPoolJsonCfg{},
+		Datadb_replication_interval: utils.StringPointer("0s"),
+		Datadb_replication_ttl:      utils.StringPointer("15s"),
+		Debit_interval:              utils.StringPointer("0s"),
+		Min_call_duration:           utils.StringPointer("0s"),
+		Max_call_duration:           utils.StringPointer("3h"),
+		Session_ttl:                 utils.StringPointer("0s"),
+		Session_indexes:             &[]string{},
+		Session_history_size:        utils.IntPointer(0),
+		Client_protocol:             utils.Float64Pointer(1.0),
+		Channel_sync_interval:       utils.StringPointer("0"),
 	}
 	if cfg, err := dfCgrJsonCfg.SessionSJsonCfg(); err != nil {
 		t.Error(err)
@@ -325,6 +325,9 @@ func TestCgrCfgJSONDefaultsGeneral(t *testing.T) {
 	if cgrCfg.GeneralCfg().RoundingDecimals != 5 {
 		t.Errorf("Expected: 5, received: %+v", cgrCfg.GeneralCfg().RoundingDecimals)
 	}
+	if cgrCfg.GeneralCfg().DecimalPrecision != 10 {
+		t.Errorf("Expected: 10, received: %+v", cgrCfg.GeneralCfg().DecimalPrecision)
+	}
 	if cgrCfg.GeneralCfg().DBDataEncoding != "msgpack" {
 		t.Errorf("Expected: msgpack, received: %+v", cgrCfg.GeneralCfg().DBDataEncoding)
 	}
@@ -477,6 +480,12 @@ func TestCgrCfgJSONDefaultsRALs(t *testing.T) {
 	if cgrCfg.RalsCfg().RpSubjectPrefixMatching != false {
 		t.Errorf("Expecting: false , received: %+v", cgrCfg.RalsCfg().RpSubjectPrefixMatching)
 	}
+	if cgrCfg.RalsCfg().RequestIDTTL != time.Duration(24*time.Hour) {
+		t.Errorf("Expecting: 24h , received: %+v", cgrCfg.RalsCfg().RequestIDTTL)
+	}
+	if cgrCfg.RalsCfg().AuditAccounts != false {
+		t.Errorf("Expecting: false , received: %+v", cgrCfg.RalsCfg().AuditAccounts)
+	}
 	eMaxCU := map[string]time.Duration{
 		utils.ANY:   time.Duration(189 * time.Hour),
 		utils.VOICE: time.Duration(72 * time.Hour),
@@ -490,8 +499,15 @@ func TestCgrCfgJSONDefaultsRALs(t *testing.T) {
 
 func TestCgrCfgJSONDefaultsScheduler(t *testing.T) {
 	eSchedulerCfg := &SchedulerCfg{
-		Enabled:   false,
-		CDRsConns: []*HaPoolConfig{},
+		Enabled:              false,
+		CDRsConns:            []*HaPoolConfig{},
+		LeaseTTL:             0,
+		CatchupPolicy:        utils.MetaSkip,
+		ExecutionHistorySize: 0,
+		ExecutionWorkers:     1,
+		ExecutionSpread:      0,
+		CheckpointInterval:   0,
+		RolloverInterval:     0,
 	}
 
 	if !reflect.DeepEqual(cgrCfg.schedulerCfg, eSchedulerCfg) {
@@ -514,6 +530,12 @@ func TestCgrCfgJSONDefaultsCDRS(t *testing.T) {
 	if cgrCfg.CdrsCfg().CDRSSMCostRetries != 5 {
 		t.Errorf("Expecting: 5 , received: %+v", cgrCfg.CdrsCfg().CDRSSMCostRetries)
 	}
+	if cgrCfg.CdrsCfg().CDRSTaxes != false {
+		t.Errorf("Expecting: false , received: %+v", cgrCfg.CdrsCfg().CDRSTaxes)
+	}
+	if len(cgrCfg.CdrsCfg().CDRSDedupRules) != 0 {
+		t.Errorf("Expecting no dedup rules, received: %s", utils.ToJSON(cgrCfg.CdrsCfg().CDRSDedupRules))
+	}
 	if expected := []*HaPoolConfig{{Address: utils.MetaInternal}}; !reflect.DeepEqual(cgrCfg.CdrsCfg().CDRSRaterConns, expected) {
 		t.Errorf("Expecting: %+v , received: %+v", expected, cgrCfg.CdrsCfg().CDRSRaterConns)
 	}
@@ -638,19 +660,22 @@ func TestCgrCfgJSONDefaultsSMGenericCfg(t *testing.T) {
 			{Address: "*internal"}},
 		CDRsConns: []*HaPoolConfig{
 			{Address: "*internal"}},
-		ResSConns:               []*HaPoolConfig{},
-		ThreshSConns:            []*HaPoolConfig{},
-		StatSConns:              []*HaPoolConfig{},
-		SupplSConns:             []*HaPoolConfig{},
-		AttrSConns:              []*HaPoolConfig{},
-		SessionReplicationConns: []*HaPoolConfig{},
-		DebitInterval:           0 * time.Second,
-		MinCallDuration:         0 * time.Second,
-		MaxCallDuration:         3 * time.Hour,
-		SessionTTL:              0 * time.Second,
-		SessionIndexes:          utils.StringMap{},
-		ClientProtocol:          1.0,
-		ChannelSyncInterval:     0,
+		ResSConns:                 []*HaPoolConfig{},
+		ThreshSConns:              []*HaPoolConfig{},
+		StatSConns:                []*HaPoolConfig{},
+		SupplSConns:               []*HaPoolConfig{},
+		AttrSConns:                []*HaPoolConfig{},
+		SessionReplicationConns:   []*HaPoolConfig{},
+		DataDBReplicationInterval: 0,
+		DataDBReplicationTTL:      15 * time.Second,
+		DebitInterval:             0 * time.Second,
+		MinCallDuration:           0 * time.Second,
+		MaxCallDuration:           3 * time.Hour,
+		SessionTTL:                0 * time.Second,
+		SessionIndexes:            utils.StringMap{},
+		SessionHistorySize:        0,
+		ClientProtocol:            1.0,
+		ChannelSyncInterval:       0,
 	}
 	if !reflect.DeepEqual(eSessionSCfg, cgrCfg.sessionSCfg) {
 		t.Errorf("expecting: %s, received: %s",
@@ -710,6 +735,10 @@ func TestCgrCfgJSONDefaultsCacheCFG(t *testing.T) {
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheDispatcherProfiles: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CacheTaxProfiles: &CacheParamCfg{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CacheCalendars: &CacheParamCfg{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheResourceFilterIndexes: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheStatFilterIndexes: &CacheParamCfg{Limit: -1,
@@ -724,6 +753,8 @@ func TestCgrCfgJSONDefaultsCacheCFG(t *testing.T) {
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheDispatcherFilterIndexes: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CacheTaxFilterIndexes: &CacheParamCfg{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheDispatcherRoutes: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheDiameterMessages: &CacheParamCfg{Limit: -1,
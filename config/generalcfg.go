@@ -32,6 +32,7 @@ type GeneralCfg struct {
 	LogLevel          int    // system wide log level, nothing higher than this will be logged
 	HttpSkipTlsVerify bool   // If enabled Http Client will accept any TLS certificate
 	RoundingDecimals  int    // Number of decimals to round end prices at
+	DecimalPrecision  int    // Number of decimals kept on intermediate results of monetary computations
 	DBDataEncoding    string // The encoding used to store object data in strings: <msgpack|json>
 	TpExportPath      string // Path towards export folder for offline Tariff Plans
 	PosterAttempts    int
@@ -103,6 +104,9 @@ func (gencfg *GeneralCfg) loadFromJsonCfg(jsnGeneralCfg *GeneralJsonCfg) (err er
 	if jsnGeneralCfg.Rounding_decimals != nil {
 		gencfg.RoundingDecimals = *jsnGeneralCfg.Rounding_decimals
 	}
+	if jsnGeneralCfg.Decimal_precision != nil {
+		gencfg.DecimalPrecision = *jsnGeneralCfg.Decimal_precision
+	}
 	if jsnGeneralCfg.Http_skip_tls_verify != nil {
 		gencfg.HttpSkipTlsVerify = *jsnGeneralCfg.Http_skip_tls_verify
 	}
@@ -25,6 +25,7 @@ type GeneralJsonCfg struct {
 	Log_level            *int
 	Http_skip_tls_verify *bool
 	Rounding_decimals    *int
+	Decimal_precision    *int
 	Dbdata_encoding      *string
 	Tpexport_dir         *string
 	Poster_attempts      *int
@@ -105,13 +106,22 @@ type RalsJsonCfg struct {
 	Aliases_conns              *[]*HaPoolJsonCfg
 	Users_conns                *[]*HaPoolJsonCfg
 	Rp_subject_prefix_matching *bool
+	Request_id_ttl             *string
+	Audit_accounts             *bool
 	Max_computed_usage         *map[string]string
 }
 
 // Scheduler config section
 type SchedulerJsonCfg struct {
-	Enabled    *bool
-	Cdrs_conns *[]*HaPoolJsonCfg
+	Enabled                *bool
+	Cdrs_conns             *[]*HaPoolJsonCfg
+	Lease_ttl              *string
+	Catchup_policy         *string
+	Execution_history_size *int
+	Execution_workers      *int
+	Execution_spread       *string
+	Checkpoint_interval    *int
+	Rollover_interval      *string
 }
 
 // Cdrs config section
@@ -120,6 +130,8 @@ type CdrsJsonCfg struct {
 	Extra_fields          *[]string
 	Store_cdrs            *bool
 	Sessions_cost_retries *int
+	Taxes                 *bool
+	Dedup_rules           *[]*CDRDedupRuleJsonCfg
 	Chargers_conns        *[]*HaPoolJsonCfg
 	Rals_conns            *[]*HaPoolJsonCfg
 	Pubsubs_conns         *[]*HaPoolJsonCfg
@@ -131,6 +143,14 @@ type CdrsJsonCfg struct {
 	Online_cdr_exports    *[]string
 }
 
+// CDRDedupRuleJsonCfg detects the duplicated CDRs in CDRs
+type CDRDedupRuleJsonCfg struct {
+	Id     *string
+	Fields *[]string
+	Window *string
+	Action *string
+}
+
 type CdrReplicationJsonCfg struct {
 	Transport      *string
 	Address        *string
@@ -212,27 +232,30 @@ type CdrcJsonCfg struct {
 
 // SM-Generic config section
 type SessionSJsonCfg struct {
-	Enabled                   *bool
-	Listen_bijson             *string
-	Chargers_conns            *[]*HaPoolJsonCfg
-	Rals_conns                *[]*HaPoolJsonCfg
-	Resources_conns           *[]*HaPoolJsonCfg
-	Thresholds_conns          *[]*HaPoolJsonCfg
-	Stats_conns               *[]*HaPoolJsonCfg
-	Suppliers_conns           *[]*HaPoolJsonCfg
-	Cdrs_conns                *[]*HaPoolJsonCfg
-	Session_replication_conns *[]*HaPoolJsonCfg
-	Attributes_conns          *[]*HaPoolJsonCfg
-	Debit_interval            *string
-	Min_call_duration         *string
-	Max_call_duration         *string
-	Session_ttl               *string
-	Session_ttl_max_delay     *string
-	Session_ttl_last_used     *string
-	Session_ttl_usage         *string
-	Session_indexes           *[]string
-	Client_protocol           *float64
-	Channel_sync_interval     *string
+	Enabled                     *bool
+	Listen_bijson               *string
+	Chargers_conns              *[]*HaPoolJsonCfg
+	Rals_conns                  *[]*HaPoolJsonCfg
+	Resources_conns             *[]*HaPoolJsonCfg
+	Thresholds_conns            *[]*HaPoolJsonCfg
+	Stats_conns                 *[]*HaPoolJsonCfg
+	Suppliers_conns             *[]*HaPoolJsonCfg
+	Cdrs_conns                  *[]*HaPoolJsonCfg
+	Session_replication_conns   *[]*HaPoolJsonCfg
+	Datadb_replication_interval *string
+	Datadb_replication_ttl      *string
+	Attributes_conns            *[]*HaPoolJsonCfg
+	Debit_interval              *string
+	Min_call_duration           *string
+	Max_call_duration           *string
+	Session_ttl                 *string
+	Session_ttl_max_delay       *string
+	Session_ttl_last_used       *string
+	Session_ttl_usage           *string
+	Session_indexes             *[]string
+	Session_history_size        *int
+	Client_protocol             *float64
+	Channel_sync_interval       *string
 }
 
 // FreeSWITCHAgent config section
@@ -32,7 +32,9 @@ type RalsCfg struct {
 	RALsPubSubSConns        []*HaPoolConfig
 	RALsUserSConns          []*HaPoolConfig
 	RALsAliasSConns         []*HaPoolConfig
-	RpSubjectPrefixMatching bool // enables prefix matching for the rating profile subject
+	RpSubjectPrefixMatching bool          // enables prefix matching for the rating profile subject
+	RequestIDTTL            time.Duration // remember the results of the account APIs called with a RequestID
+	AuditAccounts           bool          // store the balance changes in StorDB
 	RALsMaxComputedUsage    map[string]time.Duration
 }
 
@@ -82,6 +84,14 @@ func (ralsCfg *RalsCfg) loadFromJsonCfg(jsnRALsCfg *RalsJsonCfg) (err error) {
 	if jsnRALsCfg.Rp_subject_prefix_matching != nil {
 		ralsCfg.RpSubjectPrefixMatching = *jsnRALsCfg.Rp_subject_prefix_matching
 	}
+	if jsnRALsCfg.Request_id_ttl != nil {
+		if ralsCfg.RequestIDTTL, err = utils.ParseDurationWithNanosecs(*jsnRALsCfg.Request_id_ttl); err != nil {
+			return
+		}
+	}
+	if jsnRALsCfg.Audit_accounts != nil {
+		ralsCfg.AuditAccounts = *jsnRALsCfg.Audit_accounts
+	}
 	if jsnRALsCfg.Max_computed_usage != nil {
 		for k, v := range *jsnRALsCfg.Max_computed_usage {
 			if ralsCfg.RALsMaxComputedUsage[k], err = utils.ParseDurationWithNanosecs(v); err != nil {
@@ -47,6 +47,8 @@ func TestRalsCfgFromJsonCfg(t *testing.T) {
 	"users_conns": [],						// address where to reach the user service, empty to disable user profile functionality: <""|*internal|x.y.z.y:1234>
 	"aliases_conns": [],					// address where to reach the aliases service, empty to disable aliases functionality: <""|*internal|x.y.z.y:1234>
 	"rp_subject_prefix_matching": false,	// enables prefix matching for the rating profile subject
+	"request_id_ttl": "1h",					// remember the results of the account APIs called with a RequestID for this long, 0 to disable
+	"audit_accounts": true,					// store the balance changes with their cause in StorDB
 	"max_computed_usage": {					// do not compute usage higher than this, prevents memory overload
 		"*any": "189h",
 		"*voice": "72h",
@@ -64,6 +66,8 @@ func TestRalsCfgFromJsonCfg(t *testing.T) {
 		RALsUserSConns:          []*HaPoolConfig{},
 		RALsAliasSConns:         []*HaPoolConfig{},
 		RpSubjectPrefixMatching: false,
+		RequestIDTTL:            time.Duration(time.Hour),
+		AuditAccounts:           true,
 		RALsMaxComputedUsage: map[string]time.Duration{
 			utils.ANY:   time.Duration(189 * time.Hour),
 			utils.VOICE: time.Duration(72 * time.Hour),
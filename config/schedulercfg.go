@@ -18,9 +18,22 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 
 package config
 
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
 type SchedulerCfg struct {
-	Enabled   bool
-	CDRsConns []*HaPoolConfig
+	Enabled              bool
+	CDRsConns            []*HaPoolConfig
+	LeaseTTL             time.Duration // only the node holding the lease executes the actions, 0 to disable
+	CatchupPolicy        string        // <*skip|*run_once|*run_all>
+	ExecutionHistorySize int
+	ExecutionWorkers     int
+	ExecutionSpread      time.Duration
+	CheckpointInterval   int           // processed accounts between checkpoints, 0 to disable
+	RolloverInterval     time.Duration // interval to roll over the expired balances, 0 to disable
 }
 
 func (schdcfg *SchedulerCfg) loadFromJsonCfg(jsnCfg *SchedulerJsonCfg) error {
@@ -38,5 +51,35 @@ func (schdcfg *SchedulerCfg) loadFromJsonCfg(jsnCfg *SchedulerJsonCfg) error {
 			schdcfg.CDRsConns[idx].loadFromJsonCfg(jsnHaCfg)
 		}
 	}
+	if jsnCfg.Lease_ttl != nil {
+		var err error
+		if schdcfg.LeaseTTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Lease_ttl); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Catchup_policy != nil {
+		schdcfg.CatchupPolicy = *jsnCfg.Catchup_policy
+	}
+	if jsnCfg.Execution_history_size != nil {
+		schdcfg.ExecutionHistorySize = *jsnCfg.Execution_history_size
+	}
+	if jsnCfg.Execution_workers != nil {
+		schdcfg.ExecutionWorkers = *jsnCfg.Execution_workers
+	}
+	if jsnCfg.Execution_spread != nil {
+		var err error
+		if schdcfg.ExecutionSpread, err = utils.ParseDurationWithNanosecs(*jsnCfg.Execution_spread); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Checkpoint_interval != nil {
+		schdcfg.CheckpointInterval = *jsnCfg.Checkpoint_interval
+	}
+	if jsnCfg.Rollover_interval != nil {
+		var err error
+		if schdcfg.RolloverInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Rollover_interval); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -21,6 +21,9 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
 )
 
 func TestSchedulerCfgloadFromJsonCfg(t *testing.T) {
@@ -39,11 +42,25 @@ func TestSchedulerCfgloadFromJsonCfg(t *testing.T) {
 "scheduler": {
 	"enabled": true,				// start Scheduler service: <true|false>
 	"cdrs_conns": [],				// address where to reach CDR Server, empty to disable CDR capturing <*internal|x.y.z.y:1234>
+	"lease_ttl": "10s",
+	"catchup_policy": "*run_once",
+	"execution_history_size": 100,
+	"execution_workers": 10,
+	"execution_spread": "1h",
+	"checkpoint_interval": 1000,
+	"rollover_interval": "24h",
 	},
 }`
 	expected = SchedulerCfg{
-		Enabled:   true,
-		CDRsConns: []*HaPoolConfig{},
+		Enabled:              true,
+		CDRsConns:            []*HaPoolConfig{},
+		LeaseTTL:             10 * time.Second,
+		CatchupPolicy:        utils.MetaRunOnce,
+		ExecutionHistorySize: 100,
+		ExecutionWorkers:     10,
+		ExecutionSpread:      time.Hour,
+		CheckpointInterval:   1000,
+		RolloverInterval:     24 * time.Hour,
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromReader(strings.NewReader(cfgJSONStr)); err != nil {
 		t.Error(err)
@@ -99,27 +99,30 @@ func (self *FsConnCfg) loadFromJsonCfg(jsnCfg *FsConnJsonCfg) error {
 }
 
 type SessionSCfg struct {
-	Enabled                 bool
-	ListenBijson            string
-	ChargerSConns           []*HaPoolConfig
-	RALsConns               []*HaPoolConfig
-	ResSConns               []*HaPoolConfig
-	ThreshSConns            []*HaPoolConfig
-	StatSConns              []*HaPoolConfig
-	SupplSConns             []*HaPoolConfig
-	AttrSConns              []*HaPoolConfig
-	CDRsConns               []*HaPoolConfig
-	SessionReplicationConns []*HaPoolConfig
-	DebitInterval           time.Duration
-	MinCallDuration         time.Duration
-	MaxCallDuration         time.Duration
-	SessionTTL              time.Duration
-	SessionTTLMaxDelay      *time.Duration
-	SessionTTLLastUsed      *time.Duration
-	SessionTTLUsage         *time.Duration
-	SessionIndexes          utils.StringMap
-	ClientProtocol          float64
-	ChannelSyncInterval     time.Duration
+	Enabled                   bool
+	ListenBijson              string
+	ChargerSConns             []*HaPoolConfig
+	RALsConns                 []*HaPoolConfig
+	ResSConns                 []*HaPoolConfig
+	ThreshSConns              []*HaPoolConfig
+	StatSConns                []*HaPoolConfig
+	SupplSConns               []*HaPoolConfig
+	AttrSConns                []*HaPoolConfig
+	CDRsConns                 []*HaPoolConfig
+	SessionReplicationConns   []*HaPoolConfig
+	DataDBReplicationInterval time.Duration
+	DataDBReplicationTTL      time.Duration
+	DebitInterval             time.Duration
+	MinCallDuration           time.Duration
+	MaxCallDuration           time.Duration
+	SessionTTL                time.Duration
+	SessionTTLMaxDelay        *time.Duration
+	SessionTTLLastUsed        *time.Duration
+	SessionTTLUsage           *time.Duration
+	SessionIndexes            utils.StringMap
+	SessionHistorySize        int
+	ClientProtocol            float64
+	ChannelSyncInterval       time.Duration
 }
 
 func (self *SessionSCfg) loadFromJsonCfg(jsnCfg *SessionSJsonCfg) (err error) {
@@ -195,6 +198,16 @@ func (self *SessionSCfg) loadFromJsonCfg(jsnCfg *SessionSJsonCfg) (err error) {
 			self.SessionReplicationConns[idx].loadFromJsonCfg(jsnHaCfg)
 		}
 	}
+	if jsnCfg.Datadb_replication_interval != nil {
+		if self.DataDBReplicationInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Datadb_replication_interval); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Datadb_replication_ttl != nil {
+		if self.DataDBReplicationTTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Datadb_replication_ttl); err != nil {
+			return err
+		}
+	}
 	if jsnCfg.Debit_interval != nil {
 		if self.DebitInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Debit_interval); err != nil {
 			return err
@@ -232,6 +245,9 @@ func (self *SessionSCfg) loadFromJsonCfg(jsnCfg *SessionSJsonCfg) (err error) {
 	if jsnCfg.Session_indexes != nil {
 		self.SessionIndexes = utils.StringMapFromSlice(*jsnCfg.Session_indexes)
 	}
+	if jsnCfg.Session_history_size != nil {
+		self.SessionHistorySize = *jsnCfg.Session_history_size
+	}
 	if jsnCfg.Client_protocol != nil {
 		self.ClientProtocol = *jsnCfg.Client_protocol
 	}
@@ -0,0 +1,62 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package console
+
+import "github.com/cgrates/cgrates/apier/v1"
+
+func init() {
+	c := &CmdSetAccountSubscription{
+		name:      "account_subscription_set",
+		rpcMethod: "ApierV1.SetAccountSubscription",
+	}
+	commands[c.Name()] = c
+	c.CommandExecuter = &CommandExecuter{c}
+}
+
+// Commander implementation
+type CmdSetAccountSubscription struct {
+	name      string
+	rpcMethod string
+	rpcParams *v1.AttrSetAccountSubscription
+	*CommandExecuter
+}
+
+func (self *CmdSetAccountSubscription) Name() string {
+	return self.name
+}
+
+func (self *CmdSetAccountSubscription) RpcMethod() string {
+	return self.rpcMethod
+}
+
+func (self *CmdSetAccountSubscription) RpcParams(reset bool) interface{} {
+	if reset || self.rpcParams == nil {
+		self.rpcParams = &v1.AttrSetAccountSubscription{}
+	}
+	return self.rpcParams
+}
+
+func (self *CmdSetAccountSubscription) PostprocessRpcParams() error {
+	return nil
+}
+
+func (self *CmdSetAccountSubscription) RpcResult() interface{} {
+	var s string
+	return &s
+}
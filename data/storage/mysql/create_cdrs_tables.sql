@@ -24,6 +24,7 @@ CREATE TABLE cdrs (
   cost_source varchar(64) NOT NULL,
   cost DECIMAL(20,4) NOT NULL,
   cost_details MEDIUMTEXT,
+  tax_lines text,
   extra_info text,
   created_at TIMESTAMP NULL,
   updated_at TIMESTAMP NULL,
@@ -42,6 +43,7 @@ CREATE TABLE sessions_costs (
   cost_source varchar(64) NOT NULL,
   `usage` BIGINT NOT NULL,
   cost_details MEDIUMTEXT,
+  history MEDIUMTEXT,
   created_at TIMESTAMP NULL,
   deleted_at TIMESTAMP NULL,
   PRIMARY KEY (`id`),
@@ -50,3 +52,33 @@ CREATE TABLE sessions_costs (
   KEY run_origin_idx (run_id, origin_id),
   KEY deleted_at_idx (deleted_at)
 );
+
+DROP TABLE IF EXISTS account_audits;
+CREATE TABLE account_audits (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  tenant varchar(64) NOT NULL,
+  account varchar(128) NOT NULL,
+  balance_type varchar(24) NOT NULL,
+  balance_id varchar(64) NOT NULL,
+  balance_uuid varchar(64) NOT NULL,
+  value_before DECIMAL(20,4) NOT NULL,
+  value_after DECIMAL(20,4) NOT NULL,
+  cause varchar(64) NOT NULL,
+  actions_id varchar(64) NOT NULL,
+  cgrid varchar(40) NOT NULL,
+  `timestamp` TIMESTAMP(6) NULL,
+  PRIMARY KEY (`id`),
+  KEY account_timestamp_idx (tenant, account, `timestamp`),
+  KEY cgrid_idx (cgrid)
+);
+
+DROP TABLE IF EXISTS account_snapshots;
+CREATE TABLE account_snapshots (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  tenant varchar(64) NOT NULL,
+  account varchar(128) NOT NULL,
+  `timestamp` TIMESTAMP(6) NULL,
+  balances MEDIUMTEXT NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY account_timestamp_idx (tenant, account, `timestamp`)
+);
@@ -11,6 +11,7 @@ CREATE TABLE `tp_timings` (
   `month_days` varchar(255) NOT NULL,
   `week_days` varchar(255) NOT NULL,
   `time` varchar(32) NOT NULL,
+  `holidays` varchar(64) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `tpid` (`tpid`),
@@ -71,6 +72,10 @@ CREATE TABLE `tp_destination_rates` (
   `rounding_decimals` tinyint(4) NOT NULL,
   `max_cost` decimal(7,4) NOT NULL,
   `max_cost_strategy` varchar(16) NOT NULL,
+  `tier_period` varchar(16) NOT NULL,
+  `currency` varchar(3) NOT NULL,
+  `min_cost` decimal(7,4) NOT NULL,
+  `free_usage` varchar(32) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `tpid` (`tpid`),
@@ -162,6 +167,8 @@ CREATE TABLE `tp_actions` (
   `extra_parameters` varchar(256) NOT NULL,
   `filter` varchar(256) NOT NULL,
   `weight` DECIMAL(8,2) NOT NULL,
+  `filter_ids` varchar(64) NOT NULL DEFAULT '',
+  `failure_policy` varchar(24) NOT NULL DEFAULT '',
   `created_at` TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `tpid` (`tpid`),
@@ -236,6 +243,7 @@ CREATE TABLE `tp_account_actions` (
   `action_triggers_tag` varchar(64),
   `allow_negative` BOOLEAN NOT NULL,
   `disabled` BOOLEAN NOT NULL,
+  `debit_policy` varchar(24) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `tpid` (`tpid`),
@@ -523,6 +531,62 @@ CREATE TABLE tp_dispatchers (
     `id`,`filter_ids`,`strategy`,`hosts`)
 );
 
+--
+-- Table structure for table `tp_exchange_rates`
+--
+
+DROP TABLE IF EXISTS tp_exchange_rates;
+CREATE TABLE tp_exchange_rates (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `tpid` varchar(64) NOT NULL,
+  `from_currency` varchar(3) NOT NULL,
+  `to_currency` varchar(3) NOT NULL,
+  `rate` decimal(16,8) NOT NULL,
+  `created_at` TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `tpid` (`tpid`),
+  UNIQUE KEY `unique_tp_exchange_rates` (`tpid`,`from_currency`,`to_currency`)
+);
+
+--
+-- Table structure for table `tp_tax_profiles`
+--
+
+DROP TABLE IF EXISTS tp_tax_profiles;
+CREATE TABLE tp_tax_profiles (
+  `pk` int(11) NOT NULL AUTO_INCREMENT,
+  `tpid` varchar(64) NOT NULL,
+  `tenant` varchar(64) NOT NULL,
+  `id` varchar(64) NOT NULL,
+  `filter_ids` varchar(64) NOT NULL,
+  `activation_interval` varchar(64) NOT NULL,
+  `rate` decimal(8,4) NOT NULL,
+  `inclusive` BOOLEAN NOT NULL,
+  `compound` BOOLEAN NOT NULL,
+  `weight` decimal(8,2) NOT NULL,
+  `created_at` TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  KEY `tpid` (`tpid`),
+  UNIQUE KEY `unique_tp_tax_profiles` (`tpid`,`tenant`,`id`,`filter_ids`)
+);
+
+--
+-- Table structure for table `tp_calendars`
+--
+
+DROP TABLE IF EXISTS tp_calendars;
+CREATE TABLE tp_calendars (
+  `pk` int(11) NOT NULL AUTO_INCREMENT,
+  `tpid` varchar(64) NOT NULL,
+  `tenant` varchar(64) NOT NULL,
+  `id` varchar(64) NOT NULL,
+  `holidays` text NOT NULL,
+  `created_at` TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  KEY `tpid` (`tpid`),
+  UNIQUE KEY `unique_tp_calendars` (`tpid`,`tenant`,`id`)
+);
+
 --
 -- Table structure for table `versions`
 --
@@ -24,6 +24,7 @@ CREATE TABLE cdrs (
  cost_source VARCHAR(64) NOT NULL,
  cost NUMERIC(20,4) DEFAULT NULL,
  cost_details jsonb,
+ tax_lines text,
  extra_info text,
  created_at TIMESTAMP WITH TIME ZONE,
  updated_at TIMESTAMP WITH TIME ZONE NULL,
@@ -45,6 +46,7 @@ CREATE TABLE sessions_costs (
   cost_source VARCHAR(64) NOT NULL,
   usage BIGINT NOT NULL,
   cost_details jsonb,
+  history TEXT,
   created_at TIMESTAMP WITH TIME ZONE,
   deleted_at TIMESTAMP WITH TIME ZONE NULL,
   UNIQUE (cgrid, run_id)
@@ -57,3 +59,34 @@ DROP INDEX IF EXISTS run_origin_sessionscost_idx;
 CREATE INDEX run_origin_sessionscost_idx ON sessions_costs (run_id, origin_id);
 DROP INDEX IF EXISTS deleted_at_sessionscost_idx;
 CREATE INDEX deleted_at_sessionscost_idx ON sessions_costs (deleted_at);
+
+DROP TABLE IF EXISTS account_audits;
+CREATE TABLE account_audits (
+  id SERIAL PRIMARY KEY,
+  tenant VARCHAR(64) NOT NULL,
+  account VARCHAR(128) NOT NULL,
+  balance_type VARCHAR(24) NOT NULL,
+  balance_id VARCHAR(64) NOT NULL,
+  balance_uuid VARCHAR(64) NOT NULL,
+  value_before NUMERIC(20,4) NOT NULL,
+  value_after NUMERIC(20,4) NOT NULL,
+  cause VARCHAR(64) NOT NULL,
+  actions_id VARCHAR(64) NOT NULL,
+  cgrid VARCHAR(40) NOT NULL,
+  timestamp TIMESTAMP WITH TIME ZONE
+);
+DROP INDEX IF EXISTS account_timestamp_audits_idx;
+CREATE INDEX account_timestamp_audits_idx ON account_audits (tenant, account, timestamp);
+DROP INDEX IF EXISTS cgrid_audits_idx;
+CREATE INDEX cgrid_audits_idx ON account_audits (cgrid);
+
+DROP TABLE IF EXISTS account_snapshots;
+CREATE TABLE account_snapshots (
+  id SERIAL PRIMARY KEY,
+  tenant VARCHAR(64) NOT NULL,
+  account VARCHAR(128) NOT NULL,
+  timestamp TIMESTAMP WITH TIME ZONE,
+  balances TEXT NOT NULL
+);
+DROP INDEX IF EXISTS account_timestamp_snapshots_idx;
+CREATE INDEX account_timestamp_snapshots_idx ON account_snapshots (tenant, account, timestamp);
@@ -11,6 +11,7 @@ CREATE TABLE tp_timings (
   month_days VARCHAR(255) NOT NULL,
   week_days VARCHAR(255) NOT NULL,
   time VARCHAR(32) NOT NULL,
+  holidays VARCHAR(64) NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE,
   UNIQUE  (tpid, tag)
 );
@@ -68,6 +69,10 @@ CREATE TABLE tp_destination_rates (
   rounding_decimals SMALLINT NOT NULL,
   max_cost NUMERIC(7,4) NOT NULL,
   max_cost_strategy VARCHAR(16) NOT NULL,
+  tier_period VARCHAR(16) NOT NULL,
+  currency VARCHAR(3) NOT NULL,
+  min_cost NUMERIC(7,4) NOT NULL,
+  free_usage VARCHAR(32) NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE,
   UNIQUE (tpid, tag , destinations_tag)
 );
@@ -157,6 +162,8 @@ CREATE TABLE tp_actions (
   extra_parameters VARCHAR(256) NOT NULL,
   filter VARCHAR(256) NOT NULL,
   weight NUMERIC(8,2) NOT NULL,
+  filter_ids VARCHAR(64) NOT NULL DEFAULT '',
+  failure_policy VARCHAR(24) NOT NULL DEFAULT '',
   created_at TIMESTAMP WITH TIME ZONE,
   UNIQUE (tpid, tag, action, balance_tag, balance_type, expiry_time, timing_tags, destination_tags, shared_groups, balance_weight, weight)
 );
@@ -231,6 +238,7 @@ CREATE TABLE tp_account_actions (
   action_triggers_tag VARCHAR(64),
   allow_negative BOOLEAN NOT NULL,
   disabled BOOLEAN NOT NULL,
+  debit_policy VARCHAR(24) NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE,
   UNIQUE (tpid, loadid, tenant, account)
 );
@@ -512,6 +520,59 @@ CREATE INDEX tp_suppliers_unique ON tp_suppliers  ("tpid",  "tenant", "id",
   CREATE INDEX tp_dispatchers_unique ON tp_dispatchers  ("tpid",  "tenant", "id",
     "filter_ids","strategy","hosts");
 
+--
+-- Table structure for table `tp_exchange_rates`
+--
+
+DROP TABLE IF EXISTS tp_exchange_rates;
+CREATE TABLE tp_exchange_rates (
+  id SERIAL PRIMARY KEY,
+  tpid VARCHAR(64) NOT NULL,
+  from_currency VARCHAR(3) NOT NULL,
+  to_currency VARCHAR(3) NOT NULL,
+  rate NUMERIC(16,8) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE,
+  UNIQUE (tpid, from_currency, to_currency)
+);
+CREATE INDEX tpexchangerates_tpid_idx ON tp_exchange_rates (tpid);
+
+--
+-- Table structure for table `tp_tax_profiles`
+--
+
+DROP TABLE IF EXISTS tp_tax_profiles;
+CREATE TABLE tp_tax_profiles (
+  pk SERIAL PRIMARY KEY,
+  tpid VARCHAR(64) NOT NULL,
+  tenant VARCHAR(64) NOT NULL,
+  id VARCHAR(64) NOT NULL,
+  filter_ids VARCHAR(64) NOT NULL,
+  activation_interval VARCHAR(64) NOT NULL,
+  rate NUMERIC(8,4) NOT NULL,
+  inclusive BOOLEAN NOT NULL,
+  compound BOOLEAN NOT NULL,
+  weight NUMERIC(8,2) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE,
+  UNIQUE (tpid, tenant, id, filter_ids)
+);
+CREATE INDEX tptaxprofiles_tpid_idx ON tp_tax_profiles (tpid);
+
+--
+-- Table structure for table `tp_calendars`
+--
+
+DROP TABLE IF EXISTS tp_calendars;
+CREATE TABLE tp_calendars (
+  pk SERIAL PRIMARY KEY,
+  tpid VARCHAR(64) NOT NULL,
+  tenant VARCHAR(64) NOT NULL,
+  id VARCHAR(64) NOT NULL,
+  holidays TEXT NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE,
+  UNIQUE (tpid, tenant, id)
+);
+CREATE INDEX tpcalendars_tpid_idx ON tp_calendars (tpid);
+
 --
 -- Table structure for table `versions`
 --
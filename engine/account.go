@@ -40,9 +40,24 @@ type Account struct {
 	BalanceMap        map[string]Balances
 	UnitCounters      UnitCounters
 	ActionTriggers    ActionTriggers
+	TierCounters      map[string]*TierCounter // usage within billing periods for tiered rates, indexed on TOR:period
 	AllowNegative     bool
 	Disabled          bool
+	DebitPolicy       string                   // order of debiting the balances: <""|*expires_first|*smallest_first|*fifo_topup>, empty orders on Weight
+	Subscriptions     map[string]*Subscription // recurring fees, indexed on PlanID
 	executingTriggers bool
+	auditor           *accountAuditor    // balance changes not yet audited
+	audits            []*AccountAudit    // audited changes waiting for the account to be saved
+	snapshots         []*AccountSnapshot // snapshots waiting for the account to be saved
+}
+
+// IsDebitPolicy checks if the policy is one of the supported orders of debiting the balances
+func IsDebitPolicy(policy string) bool {
+	switch policy {
+	case "", utils.MetaExpiresFirst, utils.MetaSmallestFirst, utils.MetaFIFOTopup:
+		return true
+	}
+	return false
 }
 
 // User's available minutes for the specified destination
@@ -79,7 +94,7 @@ func (ub *Account) getCreditForPrefix(cd *CallDescriptor) (duration time.Duratio
 			extendedMinuteBalances = append(extendedMinuteBalances, mb)
 		}
 	}
-	credit = extendedCreditBalances.GetTotalValue()
+	credit = extendedCreditBalances.GetTotalValueInCurrency(cd.Clone().ratingCurrency())
 	balances = extendedMinuteBalances
 	for _, b := range balances {
 		d, c := b.GetMinutesForCredit(cd, credit)
@@ -136,7 +151,7 @@ func (acc *Account) setBalanceAction(a *Action) error {
 			if a.Balance.Type == nil { // cannot create the entry in the balance map without this info
 				return errors.New("missing balance type")
 			}
-			balance = &Balance{}
+			balance = &Balance{CreationTime: time.Now()}
 			balance.Uuid = utils.GenUUID() // alway overwrite the uuid for consistency
 			acc.BalanceMap[*a.Balance.Type] = append(acc.BalanceMap[*a.Balance.Type], balance)
 		}
@@ -231,6 +246,7 @@ func (ub *Account) debitBalanceAction(a *Action, reset, resetIfNegative bool) er
 		bClone.dirty = true // Mark the balance as dirty since we have modified and it should be checked by action triggers
 		a.balanceValue = bClone.GetValue()
 		bClone.Uuid = utils.GenUUID() // alway overwrite the uuid for consistency
+		bClone.CreationTime = time.Now()
 		// load ValueFactor if defined in extra parametrs
 		if a.ExtraParameters != "" {
 			vf := ValueFactor{}
@@ -336,7 +352,7 @@ func (ub *Account) getBalancesForPrefix(prefix, category, tor string, sharedGrou
 		}
 	}
 	// resort by precision
-	usefulBalances.Sort()
+	usefulBalances.SortForDebit(ub.DebitPolicy)
 	// clear precision
 	for _, b := range usefulBalances {
 		b.precision = 0
@@ -504,15 +520,16 @@ func (ub *Account) debitCreditBalance(cd *CallDescriptor, count bool, dryRun boo
 			}
 
 			if tsIndex == 0 && ts.RateInterval.Rating.ConnectFee > 0 && cc.deductConnectFee && ok {
-
+				cfExchangeRate, _ := debitedConnectFeeBalance.exchangeRate(ts.RateInterval.Rating.Currency)
 				inc := &Increment{
 					Duration: 0,
 					Cost:     ts.RateInterval.Rating.ConnectFee,
 					BalanceInfo: &DebitInfo{
 						Monetary: &MonetaryInfo{
-							UUID:  debitedConnectFeeBalance.Uuid,
-							ID:    debitedConnectFeeBalance.ID,
-							Value: debitedConnectFeeBalance.Value,
+							UUID:         debitedConnectFeeBalance.Uuid,
+							ID:           debitedConnectFeeBalance.ID,
+							Value:        debitedConnectFeeBalance.Value,
+							ExchangeRate: cfExchangeRate,
 						},
 						AccountID: ub.ID,
 					},
@@ -522,6 +539,8 @@ func (ub *Account) debitCreditBalance(cd *CallDescriptor, count bool, dryRun boo
 				ts.Increments = append(incs, ts.Increments...)
 			}
 
+			defaultBalance := ub.GetDefaultMoneyBalance()
+			exchangeRate, _ := defaultBalance.exchangeRate(ts.RateInterval.Rating.Currency)
 			for incIndex, increment := range ts.Increments {
 
 				if tsIndex == 0 && incIndex == 0 && ts.RateInterval.Rating.ConnectFee > 0 && cc.deductConnectFee && ok {
@@ -529,13 +548,13 @@ func (ub *Account) debitCreditBalance(cd *CallDescriptor, count bool, dryRun boo
 					continue
 				}
 
-				cost := increment.Cost
-				defaultBalance := ub.GetDefaultMoneyBalance()
+				cost := convertAmount(increment.Cost, exchangeRate)
 				defaultBalance.SubstractValue(cost)
 				increment.BalanceInfo.Monetary = &MonetaryInfo{
-					UUID:  defaultBalance.Uuid,
-					ID:    defaultBalance.ID,
-					Value: defaultBalance.Value,
+					UUID:         defaultBalance.Uuid,
+					ID:           defaultBalance.ID,
+					Value:        defaultBalance.Value,
+					ExchangeRate: exchangeRate,
 				}
 				increment.BalanceInfo.AccountID = ub.ID
 				increment.paid = true
@@ -554,6 +573,12 @@ func (ub *Account) debitCreditBalance(cd *CallDescriptor, count bool, dryRun boo
 	}
 
 COMMIT:
+	if cd.LoopIndex == 0 && !cd.DeferCallLimits {
+		if err = ub.applyCallLimits(cc, usefulUnitBalances, usefulMoneyBalances,
+			goNegative, count); err != nil {
+			return
+		}
+	}
 	if !dryRun {
 		// save darty shared balances
 		usefulMoneyBalances.SaveDirtyBalances(ub)
@@ -563,6 +588,101 @@ COMMIT:
 	return
 }
 
+// applyCallLimits gives back what was debited for calls shorter than the FreeUsage of the rates
+// and debits the cost missing up to their MinCost
+func (ub *Account) applyCallLimits(cc *CallCost, unitBalances, moneyBalances Balances,
+	goNegative, count bool) (err error) {
+	cost := utils.NewDecimalFromInt64(0)
+	for _, ts := range cc.Timespans {
+		cost = cost.Add(utils.NewDecimalFromFloat64(ts.CalculateCost()))
+	}
+	free, minCostDiff := cc.callLimits(cost.Float64())
+	if free {
+		for _, ts := range cc.Timespans {
+			for _, incr := range ts.Increments {
+				if incr.BalanceInfo == nil {
+					continue
+				}
+				cf := float64(incr.GetCompressFactor())
+				if incr.BalanceInfo.Unit != nil {
+					if b := unitBalances.GetBalance(incr.BalanceInfo.Unit.UUID); b != nil {
+						b.AddValue(incr.BalanceInfo.Unit.Consumed * cf)
+						if count {
+							ub.countUnits(-incr.BalanceInfo.Unit.Consumed*cf, cc.TOR, cc, b)
+						}
+					}
+				}
+				if incr.BalanceInfo.Monetary != nil && incr.Cost != 0 {
+					if b := moneyBalances.GetBalance(incr.BalanceInfo.Monetary.UUID); b != nil {
+						refund := convertAmount(incr.Cost*cf, incr.BalanceInfo.Monetary.ExchangeRate)
+						b.AddValue(refund)
+						if count {
+							ub.countUnits(-refund, utils.MONETARY, cc, b)
+						}
+					}
+				}
+			}
+		}
+		// increments are shared between decompressed timespans, reset them once all were refunded
+		for _, ts := range cc.Timespans {
+			for _, incr := range ts.Increments {
+				incr.Cost = 0
+			}
+		}
+		return
+	}
+	if minCostDiff == 0 {
+		return
+	}
+	return ub.debitMinCost(cc, moneyBalances, minCostDiff, goNegative, count)
+}
+
+// debitMinCost debits minCostDiff out of the first money balance covering it, falling back on the default one
+// when goNegative, otherwise returning ErrInsufficientCredit
+// the debit is recorded as an Increment appended to the last TimeSpan of the CallCost
+func (ub *Account) debitMinCost(cc *CallCost, moneyBalances Balances, minCostDiff float64,
+	goNegative, count bool) (err error) {
+	var moneyBal *Balance
+	var exchangeRate float64
+	for _, mb := range moneyBalances {
+		rate, err := mb.exchangeRate(cc.GetCurrency())
+		if err != nil {
+			continue
+		}
+		if mb.GetValue() >= convertAmount(minCostDiff, rate) {
+			moneyBal, exchangeRate = mb, rate
+			break
+		}
+	}
+	if moneyBal == nil {
+		if !goNegative {
+			return utils.ErrInsufficientCredit
+		}
+		moneyBal = ub.GetDefaultMoneyBalance()
+		exchangeRate, _ = moneyBal.exchangeRate(cc.GetCurrency())
+	}
+	amount := convertAmount(minCostDiff, exchangeRate)
+	moneyBal.SubstractValue(amount)
+	lastTS := cc.Timespans[len(cc.Timespans)-1]
+	lastTS.Increments = append(lastTS.Increments, &Increment{
+		Cost: minCostDiff,
+		BalanceInfo: &DebitInfo{
+			Monetary: &MonetaryInfo{
+				UUID:         moneyBal.Uuid,
+				ID:           moneyBal.ID,
+				Value:        moneyBal.Value,
+				ExchangeRate: exchangeRate,
+			},
+			AccountID: ub.ID,
+		},
+		paid: true,
+	})
+	if count {
+		ub.countUnits(amount, utils.MONETARY, cc, moneyBal)
+	}
+	return
+}
+
 func (ub *Account) GetDefaultMoneyBalance() *Balance {
 	for _, balance := range ub.BalanceMap[utils.MONETARY] {
 		if balance.IsDefault() {
@@ -739,12 +859,15 @@ func (acc *Account) InitCounters() {
 	}
 }
 
+// CleanExpiredStuff removes the expired balances and action triggers
+// expired balances rolling over are kept for RolloverAccountBalances
 func (acc *Account) CleanExpiredStuff() {
 	for key, bm := range acc.BalanceMap {
 		for i := 0; i < len(bm); i++ {
-			if bm[i].IsExpired() {
+			if bm[i].IsExpired() && !bm[i].canRollover() {
 				// delete it
 				bm = append(bm[:i], bm[i+1:]...)
+				i--
 			}
 		}
 		acc.BalanceMap[key] = bm
@@ -757,6 +880,66 @@ func (acc *Account) CleanExpiredStuff() {
 	}
 }
 
+// rolloverBalances replaces the expired balances with the ones receiving their unused value
+// returns the actions used to log the transfers as CDRs
+func (acc *Account) rolloverBalances() (logActs Actions) {
+	for key, bm := range acc.BalanceMap {
+		for i, b := range bm {
+			for b.IsExpired() && b.canRollover() { // the new balance can be expired already
+				rb, err := b.rolledOver()
+				if err != nil {
+					utils.Logger.Warning(fmt.Sprintf("<%s> could not roll over balance <%s> of account <%s>, error: %s",
+						utils.MetaRollover, b.ID, acc.ID, err.Error()))
+					break
+				}
+				logActs = append(logActs, &Action{
+					Id:         b.ID,
+					ActionType: utils.MetaRollover,
+					Balance: &BalanceFilter{
+						Uuid:  utils.StringPointer(rb.Uuid),
+						ID:    utils.StringPointer(rb.ID),
+						Type:  utils.StringPointer(key),
+						Value: &utils.ValueFormula{Static: rb.Value},
+					},
+					balanceValue: rb.Value,
+				})
+				b = rb
+			}
+			bm[i] = b
+		}
+	}
+	return
+}
+
+// RolloverAccountBalances moves the unused value of the expired balances of the account into new balances
+// the transfers are logged as CDRs once the account is saved
+func RolloverAccountBalances(acntID string) (err error) {
+	var acc *Account
+	var logActs Actions
+	if _, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+		if acc, err = dm.DataDB().GetAccount(acntID); err != nil {
+			return
+		}
+		prevCause := acc.auditAs(&auditCause{cause: utils.MetaRollover})
+		if logActs = acc.rolloverBalances(); len(logActs) == 0 {
+			acc.endAudit(prevCause)
+			return
+		}
+		acc.CleanExpiredStuff()
+		acc.endAudit(prevCause)
+		err = setAuditedAccount(acc)
+		return
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.ACCOUNT_PREFIX+acntID); err != nil ||
+		len(logActs) == 0 || schedCdrsConns == nil {
+		return
+	}
+	if err := cdrLogAction(acc, &Action{ActionType: CDRLOG}, logActs, nil); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> could not log the rollovers of account <%s>, error: %s",
+			utils.MetaRollover, acc.ID, err.Error()))
+	}
+	return
+}
+
 func (acc *Account) allBalancesExpired() bool {
 	for _, bm := range acc.BalanceMap {
 		for i := 0; i < len(bm); i++ {
@@ -817,10 +1000,23 @@ func (acc *Account) Clone() *Account {
 		ActionTriggers: nil, // not used when cloned (dryRun)
 		AllowNegative:  acc.AllowNegative,
 		Disabled:       acc.Disabled,
+		DebitPolicy:    acc.DebitPolicy,
 	}
 	for key, balanceChain := range acc.BalanceMap {
 		newAcc.BalanceMap[key] = balanceChain.Clone()
 	}
+	if acc.TierCounters != nil {
+		newAcc.TierCounters = make(map[string]*TierCounter, len(acc.TierCounters))
+		for key, tc := range acc.TierCounters {
+			newAcc.TierCounters[key] = &TierCounter{PeriodStart: tc.PeriodStart, Usage: tc.Usage}
+		}
+	}
+	if acc.Subscriptions != nil {
+		newAcc.Subscriptions = make(map[string]*Subscription, len(acc.Subscriptions))
+		for planID, sub := range acc.Subscriptions {
+			newAcc.Subscriptions[planID] = sub.Clone()
+		}
+	}
 	return newAcc
 }
 
@@ -832,11 +1028,13 @@ func (acc *Account) DebitConnectionFee(cc *CallCost, usefulMoneyBalances Balance
 		//log.Print("CONNECT FEE: %f", connectFee)
 		connectFeePaid := false
 		for _, b := range usefulMoneyBalances {
-			if b.GetValue() >= connectFee {
-				b.SubstractValue(connectFee)
+			exchangeRate, err := b.exchangeRate(cc.GetCurrency())
+			balanceFee := convertAmount(connectFee, exchangeRate)
+			if err == nil && b.GetValue() >= balanceFee { // err means the balance cannot pay in this currency
+				b.SubstractValue(balanceFee)
 				// the conect fee is not refundable!
 				if count {
-					acc.countUnits(connectFee, utils.MONETARY, cc, b)
+					acc.countUnits(balanceFee, utils.MONETARY, cc, b)
 				}
 				connectFeePaid = true
 				debitedBalance = *b
@@ -851,17 +1049,51 @@ func (acc *Account) DebitConnectionFee(cc *CallCost, usefulMoneyBalances Balance
 			cc.negativeConnectFee = true
 			// there are no money for the connect fee; go negative
 			b := acc.GetDefaultMoneyBalance()
-			b.SubstractValue(connectFee)
+			exchangeRate, _ := b.exchangeRate(cc.GetCurrency())
+			balanceFee := convertAmount(connectFee, exchangeRate)
+			b.SubstractValue(balanceFee)
 			debitedBalance = *b
 			// the conect fee is not refundable!
 			if count {
-				acc.countUnits(connectFee, utils.MONETARY, cc, b)
+				acc.countUnits(balanceFee, utils.MONETARY, cc, b)
 			}
 		}
 	}
 	return true, debitedBalance
 }
 
+// asFilterData returns the account fields the action FilterIDs can check,
+// the balance values being indexed on type and ID with their total per type
+func (acc *Account) asFilterData() map[string]interface{} {
+	ta, _ := utils.NewTAFromAccountKey(acc.ID)
+	if ta == nil {
+		ta = new(utils.TenantAccount)
+	}
+	balances := make(map[string]interface{})
+	totals := make(map[string]interface{})
+	for balType, bChain := range acc.BalanceMap {
+		balValues := make(map[string]interface{})
+		for _, b := range bChain {
+			balID := b.ID
+			if balID == "" {
+				balID = b.Uuid
+			}
+			balValues[balID] = b.GetValue()
+		}
+		balances[balType] = balValues
+		totals[balType] = bChain.GetTotalValue()
+	}
+	return map[string]interface{}{
+		utils.ID:        acc.ID,
+		utils.Tenant:    ta.Tenant,
+		utils.Account:   ta.Account,
+		"Disabled":      acc.Disabled,
+		"AllowNegative": acc.AllowNegative,
+		"BalanceMap":    balances,
+		"BalanceTotals": totals,
+	}
+}
+
 func (acc *Account) matchActionFilter(condition string) (bool, error) {
 	sm, err := structmatcher.NewStructMatcher(condition)
 	if err != nil {
@@ -1384,6 +1384,41 @@ func TestCleanExpired(t *testing.T) {
 	}
 }
 
+func TestCleanExpiredRollover(t *testing.T) {
+	expTime := time.Now().Add(-time.Hour)
+	acc := &Account{
+		ID: "cgrates.org:rollover",
+		BalanceMap: map[string]Balances{utils.VOICE: Balances{
+			&Balance{Uuid: "uuid1", ID: "BUNDLE1", Value: 100, ExpirationDate: expTime,
+				Rollover: &utils.RolloverPolicy{Percent: 50, MaxValue: 40,
+					MaxRollovers: 2, Expiry: utils.MetaMonthly}},
+			&Balance{Uuid: "uuid2", ID: "BUNDLE2", Value: 100, ExpirationDate: expTime, Rollovers: 2,
+				Rollover: &utils.RolloverPolicy{MaxRollovers: 2, Expiry: utils.MetaMonthly}},
+			&Balance{Uuid: "uuid3", ID: "BUNDLE3", Value: 10, ExpirationDate: expTime.Add(-47 * time.Hour),
+				Rollover: &utils.RolloverPolicy{Expiry: "24h"}},
+		}},
+	}
+	acc.CleanExpiredStuff() // the balances rolling over are left for rolloverBalances
+	if len(acc.BalanceMap[utils.VOICE]) != 2 {
+		t.Fatalf("received: %s", utils.ToJSON(acc.BalanceMap[utils.VOICE]))
+	}
+	if b := acc.BalanceMap[utils.VOICE][0]; b.Uuid != "uuid1" || b.Value != 100 {
+		t.Errorf("received: %s", utils.ToJSON(b))
+	}
+	if logActs := acc.rolloverBalances(); len(logActs) != 4 {
+		t.Errorf("received: %s", utils.ToJSON(logActs))
+	}
+	if b := acc.BalanceMap[utils.VOICE][0]; b.ID != "BUNDLE1" || b.Uuid == "uuid1" ||
+		b.Value != 40 || b.Rollovers != 1 || !b.ExpirationDate.Equal(expTime.AddDate(0, 1, 0)) {
+		t.Errorf("received: %s", utils.ToJSON(b))
+	}
+	// rolled over until it does not expire anymore
+	if b := acc.BalanceMap[utils.VOICE][1]; b.ID != "BUNDLE3" || b.Value != 10 || b.Rollovers != 3 ||
+		!b.ExpirationDate.Equal(expTime.Add(25*time.Hour)) {
+		t.Errorf("received: %s", utils.ToJSON(b))
+	}
+}
+
 func TestAccountUnitCounting(t *testing.T) {
 	ub := &Account{UnitCounters: UnitCounters{
 		utils.MONETARY: []*UnitCounter{&UnitCounter{
@@ -2188,6 +2223,35 @@ func TestAccountAsAccountDigest(t *testing.T) {
 
 /*********************************** Benchmarks *******************************/
 
+func TestAccountDebitMinCostDenyNegative(t *testing.T) {
+	acc := &Account{
+		ID: "cgrates.org:mincost",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: Balances{&Balance{Uuid: "uuid1", ID: "b1", Value: 0.5}},
+		},
+	}
+	newCC := func() *CallCost {
+		return &CallCost{Timespans: TimeSpans{&TimeSpan{
+			RateInterval: &RateInterval{Rating: &RIRate{}}}}}
+	}
+	cc := newCC()
+	if err := acc.debitMinCost(cc, acc.BalanceMap[utils.MONETARY], 1,
+		false, false); err != utils.ErrInsufficientCredit {
+		t.Errorf("expecting: %v, received: %v", utils.ErrInsufficientCredit, err)
+	}
+	if len(acc.BalanceMap[utils.MONETARY]) != 1 || acc.BalanceMap[utils.MONETARY][0].GetValue() != 0.5 ||
+		len(cc.Timespans[0].Increments) != 0 {
+		t.Errorf("unexpected debit: %s", utils.ToJSON(acc.BalanceMap))
+	}
+	if err := acc.debitMinCost(newCC(), acc.BalanceMap[utils.MONETARY], 1,
+		true, false); err != nil {
+		t.Error(err)
+	}
+	if dflt := acc.GetDefaultMoneyBalance(); dflt.GetValue() != -1 {
+		t.Errorf("unexpected default balance: %s", utils.ToJSON(dflt))
+	}
+}
+
 func BenchmarkGetSecondForPrefix(b *testing.B) {
 	b.StopTimer()
 	b1 := &Balance{Value: 10, Weight: 10, DestinationIDs: utils.StringMap{"NAT": true}}
@@ -0,0 +1,253 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// AccountAudit is one change of a balance value, stored in StorDB
+type AccountAudit struct {
+	Tenant      string
+	Account     string
+	BalanceType string
+	BalanceID   string
+	BalanceUUID string
+	ValueBefore float64
+	ValueAfter  float64
+	Cause       string // <*debit|*refund|*actions|*trigger> or the API method changing the account
+	ActionsID   string
+	CGRID       string
+	Timestamp   time.Time
+}
+
+// AccountAuditFilter selects the AccountAudits out of StorDB, empty fields are not filtering
+type AccountAuditFilter struct {
+	Tenant    string
+	Account   string
+	BalanceID string
+	Cause     string
+	ActionsID string
+	CGRID     string
+	TimeStart *time.Time // Timestamp greater or equal
+	TimeEnd   *time.Time // Timestamp smaller
+	utils.Paginator
+}
+
+// auditCause is the reason of the account changes
+type auditCause struct {
+	cause     string
+	actionsID string
+	cgrID     string
+}
+
+// auditBalance is the value of a balance when last audited
+type auditBalance struct {
+	typ   string
+	id    string
+	value float64
+}
+
+// accountAuditor records the changes of the account balances since the last audit
+type accountAuditor struct {
+	cause    *auditCause
+	balances map[string]*auditBalance // indexed on balance UUID
+}
+
+func (acc *Account) auditBalances() (abs map[string]*auditBalance) {
+	abs = make(map[string]*auditBalance)
+	for balType, bChain := range acc.BalanceMap {
+		for _, b := range bChain {
+			abs[b.Uuid] = &auditBalance{typ: balType, id: b.ID, value: b.GetValue()}
+		}
+	}
+	return
+}
+
+// auditAs attributes the changes following to cause, returning the previous cause to be restored with endAudit
+// changes pending under the previous cause are audited first
+func (acc *Account) auditAs(cause *auditCause) (prev *auditCause) {
+	if !auditAccounts || cdrStorage == nil {
+		return
+	}
+	if acc.auditor == nil {
+		acc.auditor = &accountAuditor{cause: cause, balances: acc.auditBalances()}
+		return
+	}
+	acc.flushAudit()
+	prev = acc.auditor.cause
+	acc.auditor.cause = cause
+	return
+}
+
+// endAudit records the changes done under the current cause and restores the previous one
+func (acc *Account) endAudit(prev *auditCause) {
+	if acc.auditor == nil {
+		return
+	}
+	acc.flushAudit()
+	if prev == nil {
+		acc.auditor = nil
+		return
+	}
+	acc.auditor.cause = prev
+}
+
+// flushAudit records the balance changes since the last audit, to be stored once the account is saved
+func (acc *Account) flushAudit() {
+	crnt := acc.auditBalances()
+	var audits []*AccountAudit
+	now := time.Now()
+	newAudit := func(uuid string, ab *auditBalance) *AccountAudit {
+		aud := &AccountAudit{BalanceType: ab.typ, BalanceID: ab.id, BalanceUUID: uuid,
+			Cause: acc.auditor.cause.cause, ActionsID: acc.auditor.cause.actionsID,
+			CGRID: acc.auditor.cause.cgrID, Timestamp: now}
+		if ta, err := utils.NewTAFromAccountKey(acc.ID); err == nil {
+			aud.Tenant, aud.Account = ta.Tenant, ta.Account
+		}
+		return aud
+	}
+	for uuid, ab := range crnt {
+		prev, has := acc.auditor.balances[uuid]
+		if has && prev.value == ab.value {
+			continue
+		}
+		aud := newAudit(uuid, ab)
+		if has {
+			aud.ValueBefore = prev.value
+		}
+		aud.ValueAfter = ab.value
+		audits = append(audits, aud)
+	}
+	for uuid, prev := range acc.auditor.balances {
+		if _, has := crnt[uuid]; has {
+			continue
+		}
+		aud := newAudit(uuid, prev) // removed balance
+		aud.ValueBefore = prev.value
+		audits = append(audits, aud)
+	}
+	acc.auditor.balances = crnt
+	if len(audits) == 0 {
+		return
+	}
+	sort.Slice(audits, func(i, j int) bool {
+		if audits[i].BalanceType != audits[j].BalanceType {
+			return audits[i].BalanceType < audits[j].BalanceType
+		}
+		return audits[i].BalanceUUID < audits[j].BalanceUUID
+	})
+	acc.audits = append(acc.audits, audits...)
+}
+
+// setAuditedAccount saves the account in DataDB, storing its audits only if the save succeeds
+func setAuditedAccount(acc *Account) (err error) {
+	if acc.auditor != nil {
+		acc.flushAudit()
+	}
+	if err = dm.DataDB().SetAccount(acc); err != nil {
+		acc.audits, acc.snapshots = nil, nil // the changes were not applied
+		return
+	}
+	acc.storeAudits()
+	return
+}
+
+// storeAudits queues the recorded audits and snapshots to be written to StorDB out of the account lock
+func (acc *Account) storeAudits() {
+	if len(acc.audits) == 0 && len(acc.snapshots) == 0 {
+		return
+	}
+	accountAudits.enqueue(&accountAuditsBatch{acntID: acc.ID,
+		audits: acc.audits, snapshots: acc.snapshots})
+	acc.audits, acc.snapshots = nil, nil
+}
+
+const accountAuditQueueSize = 1024
+
+// accountAuditsBatch are the audits and snapshots recorded at one save of the account
+type accountAuditsBatch struct {
+	acntID    string
+	audits    []*AccountAudit
+	snapshots []*AccountSnapshot
+}
+
+// accountAuditQueue writes the batches to StorDB one by one, in the order the accounts were saved
+type accountAuditQueue struct {
+	startOnce    sync.Once
+	batches      chan *accountAuditsBatch
+	sync.RWMutex                // protect closed against enqueuing while flushing
+	closed       bool           // flushed on shutdown, the new batches are written directly
+	pending      sync.WaitGroup // batches queued or being written
+}
+
+var accountAudits = &accountAuditQueue{batches: make(chan *accountAuditsBatch, accountAuditQueueSize)}
+
+// FlushAccountAudits waits for the queued audits to be written to StorDB, called on shutdown
+func FlushAccountAudits() {
+	accountAudits.flush()
+}
+
+// enqueue blocks while the queue is full, not to lose or reorder the audits
+func (q *accountAuditQueue) enqueue(batch *accountAuditsBatch) {
+	q.startOnce.Do(func() { go q.work() })
+	q.RLock()
+	defer q.RUnlock()
+	if q.closed {
+		writeAccountAudits(batch.acntID, batch.audits, batch.snapshots)
+		return
+	}
+	q.pending.Add(1)
+	q.batches <- batch
+}
+
+func (q *accountAuditQueue) work() {
+	for batch := range q.batches {
+		writeAccountAudits(batch.acntID, batch.audits, batch.snapshots)
+		q.pending.Done()
+	}
+}
+
+// flush stops queueing and waits for the queued batches to be written
+func (q *accountAuditQueue) flush() {
+	q.Lock()
+	q.closed = true
+	q.Unlock()
+	q.pending.Wait()
+}
+
+func writeAccountAudits(acntID string, audits []*AccountAudit, snps []*AccountSnapshot) {
+	if len(audits) != 0 {
+		if err := cdrStorage.SetAccountAudits(audits); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> cannot store the audits of account: %s, error: %s",
+				utils.RALService, acntID, err.Error()))
+		}
+	}
+	for _, snp := range snps {
+		if err := cdrStorage.SetAccountSnapshot(snp); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> cannot store the snapshot of account: %s, error: %s",
+				utils.RALService, acntID, err.Error()))
+		}
+	}
+}
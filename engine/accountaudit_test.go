@@ -0,0 +1,108 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAccountAuditNested(t *testing.T) {
+	storDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevCdrStorage, prevAudit := cdrStorage, auditAccounts
+	cdrStorage, auditAccounts = storDB, true
+	defer func() { cdrStorage, auditAccounts = prevCdrStorage, prevAudit }()
+	acc := &Account{
+		ID: "cgrates.org:audit",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: Balances{&Balance{Uuid: "uuid1", ID: "b1", Value: 10}},
+		},
+	}
+	prev := acc.auditAs(&auditCause{cause: utils.MetaDebit, cgrID: "cgrid1"})
+	acc.BalanceMap[utils.MONETARY][0].SetValue(7)
+	trgPrev := acc.auditAs(&auditCause{cause: utils.MetaTrigger})
+	acc.BalanceMap[utils.MONETARY][0].SetValue(107)
+	acc.endAudit(trgPrev)
+	acc.BalanceMap[utils.MONETARY][0].SetValue(105)
+	acc.endAudit(prev)
+	if acc.auditor != nil {
+		t.Errorf("auditor not released: %+v", acc.auditor)
+	}
+	// nothing stored before the account is saved
+	if _, err := storDB.GetAccountAudits(&AccountAuditFilter{Account: "audit"}); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+	if len(acc.audits) != 3 {
+		t.Fatalf("expecting 3 pending audits, received: %s", utils.ToJSON(acc.audits))
+	}
+	writeAccountAudits(acc.ID, acc.audits, nil)
+	audits, err := storDB.GetAccountAudits(&AccountAuditFilter{Account: "audit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []struct {
+		cause, cgrID  string
+		before, after float64
+	}{
+		{utils.MetaDebit, "cgrid1", 10, 7},
+		{utils.MetaTrigger, "", 7, 107},
+		{utils.MetaDebit, "cgrid1", 107, 105},
+	}
+	if len(audits) != len(exp) {
+		t.Fatalf("expecting %d audits, received: %s", len(exp), utils.ToJSON(audits))
+	}
+	for i, e := range exp {
+		if audits[i].Cause != e.cause || audits[i].CGRID != e.cgrID ||
+			audits[i].ValueBefore != e.before || audits[i].ValueAfter != e.after ||
+			audits[i].Tenant != "cgrates.org" || audits[i].BalanceID != "b1" {
+			t.Errorf("unexpected audit %d: %s", i, utils.ToJSON(audits[i]))
+		}
+	}
+	if _, err := storDB.GetAccountAudits(&AccountAuditFilter{Cause: utils.MetaRefund}); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+}
+
+func TestAccountAuditQueueFlush(t *testing.T) {
+	storDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevCdrStorage := cdrStorage
+	cdrStorage = storDB
+	defer func() { cdrStorage = prevCdrStorage }()
+	q := &accountAuditQueue{batches: make(chan *accountAuditsBatch, 2)}
+	for i, val := range []float64{7, 5, 2} { // the last one written directly, after flushing
+		if i == 2 {
+			q.flush()
+		}
+		q.enqueue(&accountAuditsBatch{acntID: "cgrates.org:queued",
+			audits: []*AccountAudit{{Tenant: "cgrates.org", Account: "queued",
+				BalanceID: "b1", ValueAfter: val, Cause: utils.MetaDebit}}})
+	}
+	if audits, err := storDB.GetAccountAudits(&AccountAuditFilter{Account: "queued"}); err != nil {
+		t.Error(err)
+	} else if len(audits) != 3 || audits[0].ValueAfter != 7 || audits[2].ValueAfter != 2 {
+		t.Errorf("received: %s", utils.ToJSON(audits))
+	}
+}
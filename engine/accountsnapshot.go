@@ -0,0 +1,151 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// AccountSnapshot is the state of the account balances at Timestamp, stored in StorDB
+type AccountSnapshot struct {
+	Tenant     string
+	Account    string
+	Timestamp  time.Time
+	BalanceMap map[string]Balances
+}
+
+// SnapshotAccount stores the current balances of the saved account in StorDB
+func SnapshotAccount(acc *Account) (err error) {
+	snp, err := newAccountSnapshot(acc)
+	if err != nil {
+		return
+	}
+	return cdrStorage.SetAccountSnapshot(snp)
+}
+
+// newAccountSnapshot copies the current balances of the account
+func newAccountSnapshot(acc *Account) (snp *AccountSnapshot, err error) {
+	if cdrStorage == nil {
+		return nil, utils.NewErrNotConnected(utils.StorDB)
+	}
+	ta, err := utils.NewTAFromAccountKey(acc.ID)
+	if err != nil {
+		return
+	}
+	if acc.auditor != nil { // changes before the snapshot should not be replayed on top of it
+		acc.flushAudit()
+	}
+	return &AccountSnapshot{
+		Tenant:     ta.Tenant,
+		Account:    ta.Account,
+		Timestamp:  time.Now(),
+		BalanceMap: acc.Clone().BalanceMap,
+	}, nil
+}
+
+// GetAccountAt reconstructs the balances of the account as they were at the given time
+// starting from the nearest snapshot before it, debits are replayed out of the CDRs
+// (each increment considered at its start, so the calls spanning the snapshot or the given time
+// count only partly) and the other changes out of the account audits
+// when StorDB is not storing CDRs, the debits are also replayed out of the audits
+func GetAccountAt(tenant, account string, at time.Time) (acc *Account, err error) {
+	if !auditAccounts { // without audits the changes following the snapshot are lost
+		return nil, utils.ErrAccountAuditsDisabled
+	}
+	if cdrStorage == nil {
+		return nil, utils.NewErrNotConnected(utils.StorDB)
+	}
+	snp, err := cdrStorage.GetAccountSnapshot(tenant, account, at)
+	if err != nil {
+		return
+	}
+	acc = &Account{
+		ID:         utils.ConcatenatedKey(tenant, account),
+		BalanceMap: snp.BalanceMap,
+	}
+	if acc.BalanceMap == nil {
+		acc.BalanceMap = make(map[string]Balances)
+	}
+	blncs := make(map[string]*Balance) // indexed on UUID
+	for _, bChain := range acc.BalanceMap {
+		for _, b := range bChain {
+			blncs[b.Uuid] = b
+		}
+	}
+	end := at.Add(time.Nanosecond) // at is included in the interval
+	// the calls answered before the snapshot could still be running at that time
+	cdrsStart := snp.Timestamp.Add(-config.CgrConfig().MaxCallDuration)
+	cdrs, _, err := cdrStorage.GetCDRs(&utils.CDRsFilter{Tenants: []string{tenant},
+		Accounts: []string{account}, AnswerTimeStart: &cdrsStart, AnswerTimeEnd: &end}, false)
+	cdrDebits := err != utils.ErrNotImplemented // without CDRs in StorDB the debits are replayed out of audits
+	if err != nil && err != utils.ErrNotFound && cdrDebits {
+		return nil, err
+	}
+	audits, err := cdrStorage.GetAccountAudits(&AccountAuditFilter{Tenant: tenant,
+		Account: account, TimeStart: &snp.Timestamp, TimeEnd: &end})
+	if err != nil && err != utils.ErrNotFound {
+		return nil, err
+	}
+	for _, aud := range audits {
+		if !aud.Timestamp.After(snp.Timestamp) ||
+			(cdrDebits && (aud.Cause == utils.MetaDebit || aud.Cause == utils.MetaRefund)) {
+			continue
+		}
+		b, has := blncs[aud.BalanceUUID]
+		if !has {
+			b = &Balance{Uuid: aud.BalanceUUID, ID: aud.BalanceID}
+			acc.BalanceMap[aud.BalanceType] = append(acc.BalanceMap[aud.BalanceType], b)
+			blncs[aud.BalanceUUID] = b
+		}
+		b.SetValue(b.GetValue() + aud.ValueAfter - aud.ValueBefore)
+	}
+	for _, cdr := range cdrs {
+		if cdr.CostDetails == nil {
+			continue
+		}
+		var beforeSnp map[string]float64 // consumed within the snapshot already
+		if !cdr.CostDetails.StartTime.After(snp.Timestamp) {
+			beforeSnp = cdr.CostDetails.BalancesConsumedUntil(snp.Timestamp)
+		}
+		for uuid, units := range cdr.CostDetails.BalancesConsumedUntil(at) {
+			if b, has := blncs[uuid]; has { // shared balances of other accounts are not ours
+				b.SetValue(b.GetValue() - (units - beforeSnp[uuid]))
+			}
+		}
+	}
+	return acc, nil
+}
+
+// snapshotAccountAction stores the account balances in StorDB so it can be reconstructed later
+// the snapshot is stored together with the audits, once the account is saved
+func snapshotAccountAction(ub *Account, a *Action, acs Actions, extraData interface{}) (err error) {
+	if ub == nil {
+		return errors.New("nil account")
+	}
+	snp, err := newAccountSnapshot(ub)
+	if err != nil {
+		return
+	}
+	ub.snapshots = append(ub.snapshots, snp)
+	return
+}
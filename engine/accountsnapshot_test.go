@@ -0,0 +1,113 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestGetAccountAt(t *testing.T) {
+	storDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevCdrStorage, prevAudit := cdrStorage, auditAccounts
+	cdrStorage, auditAccounts = storDB, false
+	defer func() { cdrStorage, auditAccounts = prevCdrStorage, prevAudit }()
+	if _, err := GetAccountAt("cgrates.org", "snapshot", time.Now()); err != utils.ErrAccountAuditsDisabled {
+		t.Errorf("expecting %v, received: %v", utils.ErrAccountAuditsDisabled, err)
+	}
+	auditAccounts = true
+	acc := &Account{
+		ID: "cgrates.org:snapshot",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: Balances{&Balance{Uuid: "uuid1", ID: "b1", Value: 10}},
+		},
+	}
+	if err := SnapshotAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := storDB.SetAccountAudits([]*AccountAudit{
+		{Tenant: "cgrates.org", Account: "snapshot", BalanceType: utils.MONETARY, BalanceID: "b1",
+			BalanceUUID: "uuid1", ValueBefore: 10, ValueAfter: 7, Cause: utils.MetaDebit,
+			CGRID: "cgrid1", Timestamp: now.Add(time.Hour)},
+		{Tenant: "cgrates.org", Account: "snapshot", BalanceType: utils.VOICE, BalanceID: "b2",
+			BalanceUUID: "uuid2", ValueBefore: 0, ValueAfter: 60, Cause: utils.MetaActions,
+			ActionsID: "TOPUP_VOICE", Timestamp: now.Add(time.Hour)},
+		{Tenant: "cgrates.org", Account: "snapshot", BalanceType: utils.MONETARY, BalanceID: "b1",
+			BalanceUUID: "uuid1", ValueBefore: 7, ValueAfter: 17, Cause: utils.MetaActions,
+			ActionsID: "TOPUP_10", Timestamp: now.Add(2 * time.Hour)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetAccountAt("cgrates.org", "snapshot", now.Add(-time.Hour)); err != utils.ErrNotFound {
+		t.Errorf("expecting not found before the snapshot, received: %v", err)
+	}
+	if rcv, err := GetAccountAt("cgrates.org", "snapshot", now.Add(90*time.Minute)); err != nil {
+		t.Error(err)
+	} else if len(rcv.BalanceMap[utils.MONETARY]) != 1 ||
+		rcv.BalanceMap[utils.MONETARY][0].GetValue() != 7 {
+		t.Errorf("unexpected monetary balances: %s", utils.ToJSON(rcv.BalanceMap[utils.MONETARY]))
+	} else if len(rcv.BalanceMap[utils.VOICE]) != 1 ||
+		rcv.BalanceMap[utils.VOICE][0].ID != "b2" || rcv.BalanceMap[utils.VOICE][0].GetValue() != 60 {
+		t.Errorf("unexpected voice balances: %s", utils.ToJSON(rcv.BalanceMap[utils.VOICE]))
+	}
+	if rcv, err := GetAccountAt("cgrates.org", "snapshot", now.Add(2*time.Hour)); err != nil {
+		t.Error(err)
+	} else if rcv.BalanceMap[utils.MONETARY][0].GetValue() != 17 {
+		t.Errorf("unexpected monetary balances: %s", utils.ToJSON(rcv.BalanceMap[utils.MONETARY]))
+	}
+}
+
+func TestSnapshotAccountActionPending(t *testing.T) {
+	storDB, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevCdrStorage := cdrStorage
+	cdrStorage = storDB
+	defer func() { cdrStorage = prevCdrStorage }()
+	acc := &Account{
+		ID: "cgrates.org:pending",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: Balances{&Balance{Uuid: "uuid1", ID: "b1", Value: 10}},
+		},
+	}
+	if err := snapshotAccountAction(acc, &Action{ActionType: MetaSnapshotAccount}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	acc.BalanceMap[utils.MONETARY][0].SetValue(5)
+	// not stored before the account is saved
+	if _, err := storDB.GetAccountSnapshot("cgrates.org", "pending", time.Now()); err != utils.ErrNotFound {
+		t.Errorf("expecting not found, received: %v", err)
+	}
+	if len(acc.snapshots) != 1 {
+		t.Fatalf("expecting 1 pending snapshot, received: %s", utils.ToJSON(acc.snapshots))
+	}
+	writeAccountAudits(acc.ID, acc.audits, acc.snapshots)
+	if snp, err := storDB.GetAccountSnapshot("cgrates.org", "pending", time.Now()); err != nil {
+		t.Error(err)
+	} else if snp.BalanceMap[utils.MONETARY][0].GetValue() != 10 {
+		t.Errorf("unexpected snapshot: %s", utils.ToJSON(snp))
+	}
+}
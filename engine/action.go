@@ -48,10 +48,13 @@ type Action struct {
 	ActionType       string
 	ExtraParameters  string
 	Filter           string
-	ExpirationString string // must stay as string because it can have relative values like 1month
+	FilterIDs        []string // FilterS filters checked against the account and event before executing
+	FailurePolicy    string   // <*stop_on_failure|*continue> with the remaining actions when this one fails
+	ExpirationString string   // must stay as string because it can have relative values like 1month
 	Weight           float64
 	Balance          *BalanceFilter
 	balanceValue     float64 // balance value after action execution, used with cdrlog
+	charges          Actions // subscription charges done by the action, used with cdrlog
 }
 
 const (
@@ -84,6 +87,11 @@ const (
 	SetExpiry                 = "*set_expiry"
 	MetaPublishAccount        = "*publish_account"
 	MetaPublishBalance        = "*publish_balance"
+	MetaChargeSubscriptions   = "*charge_subscriptions"
+	MetaHTTPPost              = "*http_post"
+	MetaBranch                = "*branch"
+	MetaScheduleActions       = "*schedule_actions"
+	MetaSnapshotAccount       = "*snapshot_account"
 )
 
 func (a *Action) Clone() *Action {
@@ -94,6 +102,88 @@ func (a *Action) Clone() *Action {
 
 type actionTypeFunc func(*Account, *Action, Actions, interface{}) error
 
+// maxActionBranches limits the *branch actions followed within one execution so loops end
+const maxActionBranches = 10
+
+func newActionsFlow(acts Actions) *actionsFlow {
+	return &actionsFlow{pending: acts}
+}
+
+// actionsFlow walks over the actions to execute, skipping the ones not passing
+// their filters and continuing with the Actions of a *branch
+type actionsFlow struct {
+	pending  Actions // Actions walked over, the ones of the last *branch
+	idx      int
+	executed Actions // actions returned so far, out of all the branches, passed to the action functions (eg: *cdrlog)
+	branches int
+}
+
+// next returns the next action to execute, nil when done
+func (af *actionsFlow) next(acc *Account, extraData interface{}) (a *Action, err error) {
+	for af.idx < len(af.pending) {
+		a = af.pending[af.idx]
+		af.idx++
+		if len(a.Filter) > 0 && acc != nil {
+			if matched, err := acc.matchActionFilter(a.Filter); err != nil {
+				return nil, err
+			} else if !matched {
+				continue
+			}
+		}
+		if pass, err := a.passFilterIDs(acc, extraData); err != nil {
+			return nil, err
+		} else if !pass {
+			continue
+		}
+		if a.ActionType != MetaBranch {
+			af.executed = append(af.executed, a)
+			return a, nil
+		}
+		if af.branches++; af.branches > maxActionBranches {
+			return nil, fmt.Errorf("more than %d branches, last to: %s", maxActionBranches, a.ExtraParameters)
+		}
+		acts, err := dm.GetActions(a.ExtraParameters, false, utils.NonTransactional)
+		if err != nil {
+			return nil, err
+		}
+		cln, err := acts.Clone() // cached ones are shared between executions
+		if err != nil {
+			return nil, err
+		}
+		af.pending = cln.(Actions)
+		af.pending.Sort()
+		af.idx = 0
+	}
+	return nil, nil
+}
+
+// passFilterIDs checks the FilterIDs of the action against the account, under *account,
+// and the event out of extraData, under *req
+func (a *Action) passFilterIDs(acc *Account, extraData interface{}) (pass bool, err error) {
+	if len(a.FilterIDs) == 0 {
+		return true, nil
+	}
+	if filterS == nil {
+		return false, utils.NewErrNotConnected(utils.FilterS)
+	}
+	tnt := config.CgrConfig().GeneralCfg().DefaultTenant
+	dP := make(map[string]interface{})
+	switch ev := extraData.(type) {
+	case *utils.CGREvent:
+		tnt = ev.Tenant
+		dP[utils.MetaReq] = ev.Event
+	case map[string]interface{}:
+		dP[utils.MetaReq] = ev
+	}
+	if acc != nil {
+		if ta, err := utils.NewTAFromAccountKey(acc.ID); err == nil {
+			tnt = ta.Tenant
+		}
+		dP[utils.MetaAccount] = acc.asFilterData()
+	}
+	return filterS.Pass(tnt, a.FilterIDs, config.NewNavigableMap(dP))
+}
+
 func getActionFunc(typ string) (actionTypeFunc, bool) {
 	actionFuncMap := map[string]actionTypeFunc{
 		LOG:                       logAction,
@@ -124,6 +214,10 @@ func getActionFunc(typ string) (actionTypeFunc, bool) {
 		SetExpiry:                 setExpiryAction,
 		MetaPublishAccount:        publishAccount,
 		MetaPublishBalance:        publishBalance,
+		MetaChargeSubscriptions:   chargeSubscriptionsAction,
+		MetaHTTPPost:              httpPostAction,
+		MetaScheduleActions:       scheduleActionsAction,
+		MetaSnapshotAccount:       snapshotAccountAction,
 		utils.MetaAMQPjsonMap:     sendAMQP,
 		utils.MetaAWSjsonMap:      sendAWS,
 		utils.MetaSQSjsonMap:      sendSQS,
@@ -170,11 +264,7 @@ func cdrLogAction(acc *Account, a *Action, acs Actions, extraData interface{}) (
 	}
 	// set stored cdr values
 	var cdrs []*CDR
-	for _, action := range acs {
-		if !utils.IsSliceMember([]string{DEBIT, DEBIT_RESET, TOPUP, TOPUP_RESET}, action.ActionType) ||
-			action.Balance == nil {
-			continue // Only log specific actions
-		}
+	for _, action := range cdrLogActions(acs) {
 		cdr := &CDR{
 			RunID:     action.ActionType,
 			Source:    CDRLOG,
@@ -219,6 +309,22 @@ func cdrLogAction(acc *Account, a *Action, acs Actions, extraData interface{}) (
 	return
 }
 
+// cdrLogActions returns the actions which are logged as CDRs
+func cdrLogActions(acs Actions) (logActs Actions) {
+	for _, action := range acs {
+		if action.ActionType == MetaChargeSubscriptions {
+			logActs = append(logActs, action.charges...)
+			continue
+		}
+		if !utils.IsSliceMember([]string{DEBIT, DEBIT_RESET, TOPUP, TOPUP_RESET, utils.MetaRollover}, action.ActionType) ||
+			action.Balance == nil {
+			continue // Only log specific actions
+		}
+		logActs = append(logActs, action)
+	}
+	return
+}
+
 func resetTriggersAction(ub *Account, a *Action, acs Actions, extraData interface{}) (err error) {
 	if ub == nil {
 		return errors.New("nil account")
@@ -309,6 +415,15 @@ func debitAction(ub *Account, a *Action, acs Actions, extraData interface{}) (er
 	return
 }
 
+// chargeSubscriptionsAction charges the subscriptions of the account up to now
+func chargeSubscriptionsAction(ub *Account, a *Action, acs Actions, extraData interface{}) (err error) {
+	if ub == nil {
+		return errors.New("nil account")
+	}
+	a.charges, err = ub.chargeSubscriptions(time.Now())
+	return
+}
+
 func resetCountersAction(ub *Account, a *Action, acs Actions, extraData interface{}) (err error) {
 	if ub == nil {
 		return errors.New("nil account")
@@ -473,6 +588,108 @@ func callUrlAsync(ub *Account, a *Action, acs Actions, extraData interface{}) er
 	return nil
 }
 
+// httpPostParams are the ExtraParameters of the *http_post action
+type httpPostParams struct {
+	Url       string
+	Headers   map[string]string
+	AuthToken string // sent as Bearer token within the Authorization header
+	Attempts  int    // defaults to poster_attempts out of general config
+	Fields    []*config.FcTemplateJsonCfg
+}
+
+// httpPostAction queues the JSON built out of the template fields for posting, out of the
+// account lock, retrying with backoff before writing it with the headers to failed posts
+func httpPostAction(ub *Account, a *Action, acs Actions, extraData interface{}) (err error) {
+	var params httpPostParams
+	if err = json.Unmarshal([]byte(a.ExtraParameters), &params); err != nil {
+		return
+	}
+	if params.Url == "" {
+		return utils.NewErrMandatoryIeMissing("Url")
+	}
+	cfg := config.CgrConfig()
+	tpl, err := config.FCTemplatesFromFCTemplatesJsonCfg(params.Fields, cfg.GeneralCfg().RsrSepatarot)
+	if err != nil {
+		return
+	}
+	body, err := httpPostPayload(tpl, newHTTPPostProvider(ub, a, extraData))
+	if err != nil {
+		return
+	}
+	headers := make(map[string]string)
+	for hdr, val := range params.Headers {
+		headers[hdr] = val
+	}
+	if params.AuthToken != "" {
+		headers["Authorization"] = "Bearer " + params.AuthToken
+	}
+	attempts := params.Attempts
+	if attempts <= 0 {
+		attempts = cfg.GeneralCfg().PosterAttempts
+	}
+	ffn := &utils.FallbackFileName{
+		Module:     fmt.Sprintf("%s>%s", utils.ActionsPoster, a.ActionType),
+		Transport:  utils.MetaHTTPjsonHeaders,
+		Address:    params.Url,
+		RequestID:  utils.GenUUID(),
+		FileSuffix: utils.JSNSuffix,
+	}
+	httpPosts.enqueue(&httpQueuedPost{
+		poster: NewHTTPPoster(cfg.GeneralCfg().HttpSkipTlsVerify,
+			cfg.GeneralCfg().ReplyTimeout),
+		addr:       params.Url,
+		req:        &HTTPPostRequest{Headers: headers, Body: body},
+		attempts:   attempts,
+		failedDir:  cfg.GeneralCfg().FailedPostsDir,
+		failedFile: ffn.AsString(),
+	})
+	return
+}
+
+// httpPostPayload builds the JSON body, FieldId being the path of the value within it
+func httpPostPayload(tpl []*config.FCTemplate, dP config.DataProvider) (body []byte, err error) {
+	payload := make(map[string]interface{})
+	for _, tplFld := range tpl {
+		var out string
+		switch tplFld.Type {
+		case utils.META_CONSTANT:
+			out, err = tplFld.Value.ParseValue(utils.EmptyString)
+		case utils.MetaVariable, utils.META_COMPOSED:
+			out, err = tplFld.Value.ParseDataProvider(dP, utils.NestingSep)
+		default:
+			return nil, fmt.Errorf("unsupported type: <%s>", tplFld.Type)
+		}
+		if err != nil {
+			if err != utils.ErrNotFound {
+				return nil, err
+			}
+			if tplFld.Mandatory {
+				return nil, utils.ErrPrefixNotFound(tplFld.Tag)
+			}
+			err = nil
+			continue
+		}
+		fldPath := strings.Split(tplFld.FieldId, utils.NestingSep)
+		mp := payload
+		for _, spath := range fldPath[:len(fldPath)-1] {
+			if _, has := mp[spath]; !has {
+				mp[spath] = make(map[string]interface{})
+			}
+			subMp, canCast := mp[spath].(map[string]interface{})
+			if !canCast {
+				return nil, fmt.Errorf("field <%s> already populated", spath)
+			}
+			mp = subMp
+		}
+		lastPath := fldPath[len(fldPath)-1]
+		if prevOut, canCast := mp[lastPath].(string); canCast && tplFld.Type == utils.META_COMPOSED {
+			out = prevOut + out
+		}
+		mp[lastPath] = out
+	}
+	return json.Marshal(payload)
+}
+
 // Mails the balance hitting the threshold towards predefined list of addresses
 func mailAsync(ub *Account, a *Action, acs Actions, extraData interface{}) error {
 	cgrCfg := config.CgrConfig()
@@ -825,6 +1042,89 @@ func publishBalance(ub *Account, a *Action, acs Actions, extraData interface{})
 	return nil
 }
 
+// scheduleActionsParams is the ExtraParameters of the *schedule_actions action
+type scheduleActionsParams struct {
+	ActionsID string
+	Time      string // absolute or relative to now, eg: +30d
+}
+
+// scheduleActionsAction schedules the ActionsID for the account at the given time
+// through a one-off ActionPlan which is removed once executed
+func scheduleActionsAction(ub *Account, a *Action, acs Actions, extraData interface{}) (err error) {
+	if ub == nil {
+		return errors.New("nil account")
+	}
+	var params scheduleActionsParams
+	if err = json.Unmarshal([]byte(a.ExtraParameters), &params); err != nil {
+		return
+	}
+	if missing := utils.MissingStructFields(&params, []string{"ActionsID", "Time"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if _, err = dm.GetActions(params.ActionsID, false, utils.NonTransactional); err != nil {
+		return fmt.Errorf("%s for actions: %s", err.Error(), params.ActionsID)
+	}
+	schedTime, err := utils.ParseTimeDetectLayout(params.Time,
+		config.CgrConfig().GeneralCfg().DefaultTimezone)
+	if err != nil {
+		return
+	}
+	if !schedTime.After(time.Now()) {
+		return fmt.Errorf("schedule time in the past: %v", schedTime)
+	}
+	schedTime = schedTime.Local() // the scheduler computes the start times in local time
+	ap := &ActionPlan{
+		Id:         utils.ConcatenatedKey(MetaScheduleActions, utils.GenUUID()),
+		AccountIDs: utils.StringMap{ub.ID: true},
+		ActionTimings: []*ActionTiming{{
+			Uuid: utils.GenUUID(),
+			Timing: &RateInterval{
+				Timing: &RITiming{
+					Years:     utils.Years{schedTime.Year()},
+					Months:    utils.Months{schedTime.Month()},
+					MonthDays: utils.MonthDays{schedTime.Day()},
+					StartTime: schedTime.Format("15:04:05"),
+				},
+			},
+			ActionsID:      params.ActionsID,
+			Weight:         a.Weight,
+			RemoveAfterRun: true,
+		}},
+	}
+	if _, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		if err := dm.DataDB().SetActionPlan(ap.Id, ap, true, utils.NonTransactional); err != nil {
+			return 0, err
+		}
+		if err := dm.CacheDataFromDB(utils.ACTION_PLAN_PREFIX, []string{ap.Id}, true); err != nil {
+			return 0, err
+		}
+		if err := dm.DataDB().SetAccountActionPlans(ub.ID, []string{ap.Id}, false); err != nil {
+			return 0, err
+		}
+		return 0, dm.CacheDataFromDB(utils.AccountActionPlansPrefix, []string{ub.ID}, true)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.ACTION_PLAN_PREFIX); err != nil {
+		return
+	}
+	if schedulerS == nil { // the leading scheduler queues it out of the tasks
+		return dm.DataDB().PushTask(&Task{Uuid: utils.GenUUID(), ActionPlanID: ap.Id})
+	}
+	// not waiting for the scheduler, which could be executing this action
+	go func() {
+		var reply string
+		err := schedulerS.Call(utils.SchedulerSv1QueueActionPlan, ap.Id, &reply)
+		if err == nil {
+			return
+		}
+		utils.Logger.Warning(fmt.Sprintf("<%s> cannot queue action plan %s: %v, leaving it as task",
+			MetaScheduleActions, ap.Id, err))
+		if err := dm.DataDB().PushTask(&Task{Uuid: utils.GenUUID(), ActionPlanID: ap.Id}); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> cannot push task for action plan %s: %v, queued on next reload",
+				MetaScheduleActions, ap.Id, err))
+		}
+	}()
+	return
+}
+
 // Structure to store actions according to weight
 type Actions []*Action
 
@@ -959,3 +1259,71 @@ func (cdrP *cdrLogProvider) AsNavigableMap([]*config.FCTemplate) (
 func (cdrP *cdrLogProvider) RemoteHost() net.Addr {
 	return utils.LocalAddr()
 }
+
+func newHTTPPostProvider(acnt *Account, action *Action, extraData interface{}) (dP config.DataProvider) {
+	if action.Balance == nil { // balance fields are read out of the filter
+		actCpy := *action
+		actCpy.Balance = &BalanceFilter{}
+		action = &actCpy
+	}
+	hpP := &httpPostProvider{acnt: acnt, actionDP: newCdrLogProvider(acnt, action)}
+	switch ev := extraData.(type) {
+	case *utils.CGREvent:
+		hpP.eventDP = MapEvent(ev.Event)
+	case map[string]interface{}:
+		hpP.eventDP = MapEvent(ev)
+	}
+	return hpP
+}
+
+// httpPostProvider implements engine.DataProvider, exposing the triggering event
+// under *req next to the account, action and balance fields of *cdrlog
+type httpPostProvider struct {
+	acnt     *Account
+	actionDP config.DataProvider
+	eventDP  config.DataProvider
+}
+
+// String is part of engine.DataProvider interface
+func (hpP *httpPostProvider) String() string {
+	return utils.ToJSON(hpP)
+}
+
+// FieldAsInterface is part of engine.DataProvider interface
+func (hpP *httpPostProvider) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if len(fldPath) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	if fldPath[0] == utils.MetaReq {
+		if hpP.eventDP == nil {
+			return nil, utils.ErrNotFound
+		}
+		return hpP.eventDP.FieldAsInterface(fldPath[1:])
+	}
+	if hpP.acnt == nil && fldPath[0] == "AccountID" {
+		return nil, utils.ErrNotFound
+	}
+	return hpP.actionDP.FieldAsInterface(fldPath)
+}
+
+// FieldAsString is part of engine.DataProvider interface
+func (hpP *httpPostProvider) FieldAsString(fldPath []string) (data string, err error) {
+	var valIface interface{}
+	valIface, err = hpP.FieldAsInterface(fldPath)
+	if err != nil {
+		return
+	}
+	data, err = utils.IfaceAsString(valIface)
+	return
+}
+
+// AsNavigableMap is part of engine.DataProvider interface
+func (hpP *httpPostProvider) AsNavigableMap([]*config.FCTemplate) (
+	nm *config.NavigableMap, err error) {
+	return nil, utils.ErrNotImplemented
+}
+
+// RemoteHost is part of engine.DataProvider interface
+func (hpP *httpPostProvider) RemoteHost() net.Addr {
+	return utils.LocalAddr()
+}
@@ -21,6 +21,7 @@ package engine
 import (
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/cgrates/cgrates/config"
@@ -34,21 +35,24 @@ const (
 )
 
 type ActionTiming struct {
-	Uuid         string
-	Timing       *RateInterval
-	ActionsID    string
-	ExtraData    interface{}
-	Weight       float64
-	actions      Actions
-	accountIDs   utils.StringMap // copy of action plans accounts
-	actionPlanID string          // the id of the belonging action plan (info only)
-	stCache      time.Time       // cached time of the next start
+	Uuid           string
+	Timing         *RateInterval
+	ActionsID      string
+	ExtraData      interface{}
+	Weight         float64
+	RemoveAfterRun bool // one-off timing, its ActionPlan is removed after execution
+	actions        Actions
+	accountIDs     utils.StringMap // copy of action plans accounts
+	actionPlanID   string          // the id of the belonging action plan (info only)
+	stCache        time.Time       // cached time of the next start
+	auditCause     string          // API method executing the actions, audited instead of *actions
 }
 
 type Task struct {
-	Uuid      string
-	AccountID string
-	ActionsID string
+	Uuid         string
+	AccountID    string
+	ActionsID    string
+	ActionPlanID string // queued by the leading scheduler instead of executing the ActionsID
 }
 
 type ActionPlan struct {
@@ -253,6 +257,28 @@ YEARS:
 	return
 }
 
+// MissedStartTimes returns maximum maxItems start times after lastExecution, not later than now
+func (at *ActionTiming) MissedStartTimes(lastExecution, now time.Time, maxItems int) (sts []time.Time) {
+	defer at.ResetStartTimeCache()
+	for t := lastExecution; len(sts) < maxItems; {
+		at.ResetStartTimeCache()
+		if t = at.GetNextStartTime(t); t.IsZero() || t.After(now) {
+			break
+		}
+		sts = append(sts, t)
+	}
+	return
+}
+
+// ExecutionKey identifies the ActionTiming across reloads of its ActionPlan, used to store the last execution
+func (at *ActionTiming) ExecutionKey() string {
+	var cronStr string
+	if at.Timing != nil && at.Timing.Timing != nil {
+		cronStr = at.Timing.Timing.CronString()
+	}
+	return utils.ConcatenatedKey(at.actionPlanID, at.ActionsID, cronStr)
+}
+
 func (at *ActionTiming) ResetStartTimeCache() {
 	at.stCache = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
 }
@@ -288,75 +314,153 @@ func (at *ActionTiming) getActions() (as []*Action, err error) {
 // Execute will execute all actions in an action plan
 // Reports on success/fail via channel if != nil
 func (at *ActionTiming) Execute(successActions, failedActions chan *Action) (err error) {
+	_, err = at.ExecuteOnAccounts(successActions, failedActions)
+	return
+}
+
+// ExecutionCheckpoint marks how far an execution over the accounts of an ActionTiming went
+type ExecutionCheckpoint struct {
+	ScheduledTime time.Time
+	LastAccountID string // last account processed, in the order of their IDs
+	Processed     int    // accounts processed
+}
+
+// ExecutionRecord is the outcome of executing an ActionTiming on one account
+type ExecutionRecord struct {
+	ActionPlanID     string
+	ActionTimingUUID string
+	ActionsID        string
+	AccountID        string    // empty for the accountless executions
+	ScheduledTime    time.Time // start time the execution was due at
+	ExecutionTime    time.Time
+	CatchUp          bool   // executed for a start time missed while down
+	Error            string // empty on success
+}
+
+// ExecuteOpts controls how the accounts of an ActionTiming are processed
+type ExecuteOpts struct {
+	Workers    int                                       // accounts executed in parallel, 1 if not set
+	Spread     time.Duration                             // window the start of the account executions is spread over
+	After      string                                    // accounts with IDs up to this one, in order, were already processed
+	OnProgress func(processed int, lastAccountID string) // called, not concurrently, as the processed accounts in order advance
+	Proceed    func() error                              // checked before each action, the execution is aborted on error
+}
+
+// ExecuteOnAccounts executes the actions returning the error for each account, nil if successful
+// the accountless executions are reported on empty account ID
+func (at *ActionTiming) ExecuteOnAccounts(successActions, failedActions chan *Action) (accErrs map[string]error, err error) {
+	return at.ExecuteWithOpts(successActions, failedActions, nil)
+}
+
+// ExecuteWithOpts executes the actions over a pool of workers, returning the error for each processed account
+func (at *ActionTiming) ExecuteWithOpts(successActions, failedActions chan *Action,
+	opts *ExecuteOpts) (accErrs map[string]error, err error) {
 	at.ResetStartTimeCache()
 	aac, err := at.getActions()
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("Failed to get actions for %s: %s", at.ActionsID, err))
 		return
 	}
-	for accID, _ := range at.accountIDs {
-		_, err = guardian.Guardian.Guard(func() (interface{}, error) {
-			acc, err := dm.DataDB().GetAccount(accID)
-			if err != nil {
-				utils.Logger.Warning(fmt.Sprintf("Could not get account id: %s. Skipping!", accID))
-				return 0, err
+	if opts == nil {
+		opts = new(ExecuteOpts)
+	}
+	accIDs := at.accountIDs.Slice()
+	sort.Strings(accIDs)
+	var skipped int // accounts processed before resuming, stable when accounts were added or removed meanwhile
+	if opts.After != "" {
+		skipped = sort.Search(len(accIDs), func(i int) bool { return accIDs[i] > opts.After })
+		accIDs = accIDs[skipped:]
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	var delay time.Duration
+	if opts.Spread > 0 && len(accIDs) > 1 {
+		delay = opts.Spread / time.Duration(len(accIDs))
+	}
+	accErrs = make(map[string]error)
+	guardErrs := make([]error, len(accIDs))
+	done := make([]bool, len(accIDs))
+	var processed int // accounts done without gaps
+	var mux sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	var abortErr error // first one returned by opts.Proceed
+	var abortMux sync.Mutex
+	proceed := func() error {
+		if opts.Proceed == nil {
+			return nil
+		}
+		err := opts.Proceed()
+		if err != nil {
+			abortMux.Lock()
+			if abortErr == nil {
+				abortErr = err
 			}
-			transactionFailed := false
-			removeAccountActionFound := false
-			for _, a := range aac {
-				// check action filter
-				if len(a.Filter) > 0 {
-					matched, err := acc.matchActionFilter(a.Filter)
-					//log.Print("Checkng: ", a.Filter, matched)
-					if err != nil {
-						return 0, err
-					}
-					if !matched {
-						continue
-					}
-				}
-				if a.Balance == nil {
-					a.Balance = &BalanceFilter{}
-				}
-				if a.ExpirationString != "" { // if it's *unlimited then it has to be zero time
-					if expDate, parseErr := utils.ParseTimeDetectLayout(a.ExpirationString,
-						config.CgrConfig().GeneralCfg().DefaultTimezone); parseErr == nil {
-						a.Balance.ExpirationDate = &time.Time{}
-						*a.Balance.ExpirationDate = expDate
+			abortMux.Unlock()
+		}
+		return err
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				acts := aac
+				if workers > 1 { // actions keep state during execution
+					if cln, err := Actions(aac).Clone(); err == nil {
+						acts = cln.(Actions)
 					}
 				}
-
-				actionFunction, exists := getActionFunc(a.ActionType)
-				if !exists {
-					// do not allow the action plan to be rescheduled
-					at.Timing = nil
-					utils.Logger.Err(fmt.Sprintf("Function type %v not available, aborting execution!", a.ActionType))
-					transactionFailed = true
-					break
-				}
-				if err := actionFunction(acc, a, aac, at.ExtraData); err != nil {
-					utils.Logger.Err(fmt.Sprintf("Error executing action %s: %v!", a.ActionType, err))
-					transactionFailed = true
-					if failedActions != nil {
-						go func() { failedActions <- a }()
-					}
-					break
+				accErr, guardErr := at.executeOnAccount(accIDs[idx], acts, successActions, failedActions, proceed)
+				if guardErr != nil {
+					accErr = guardErr
 				}
-				if successActions != nil {
-					go func() { successActions <- a }()
+				mux.Lock()
+				accErrs[accIDs[idx]] = accErr
+				guardErrs[idx] = guardErr
+				done[idx] = true
+				prevProcessed := processed
+				for processed < len(done) && done[processed] {
+					processed++
 				}
-				if a.ActionType == REMOVE_ACCOUNT {
-					removeAccountActionFound = true
+				if processed != prevProcessed && opts.OnProgress != nil {
+					opts.OnProgress(skipped+processed, accIDs[processed-1])
 				}
+				mux.Unlock()
 			}
-			if !transactionFailed && !removeAccountActionFound {
-				dm.DataDB().SetAccount(acc)
-			}
-			return 0, nil
-		}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
+		}()
+	}
+	for idx := range accIDs {
+		if idx != 0 && delay != 0 {
+			time.Sleep(delay)
+		}
+		if proceed() != nil { // the remaining accounts are left for the next execution
+			break
+		}
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+	if len(guardErrs) != 0 {
+		err = guardErrs[len(guardErrs)-1]
 	}
 	if len(at.accountIDs) == 0 { // action timing executing without accounts
-		for _, a := range aac {
+		var accErr error
+		flow := newActionsFlow(aac)
+		for {
+			a, err := flow.next(nil, at.ExtraData)
+			if err != nil {
+				accErr = err
+				break
+			}
+			if a == nil {
+				break
+			}
+			if accErr = proceed(); accErr != nil {
+				break
+			}
 			if expDate, parseErr := utils.ParseTimeDetectLayout(a.ExpirationString,
 				config.CgrConfig().GeneralCfg().DefaultTimezone); (a.Balance == nil || a.Balance.EmptyExpirationDate()) &&
 				parseErr == nil && !expDate.IsZero() {
@@ -369,26 +473,41 @@ func (at *ActionTiming) Execute(successActions, failedActions chan *Action) (err
 				// do not allow the action plan to be rescheduled
 				at.Timing = nil
 				utils.Logger.Err(fmt.Sprintf("Function type %v not available, aborting execution!", a.ActionType))
+				accErr = fmt.Errorf("unsupported action type: %s", a.ActionType)
 				if failedActions != nil {
 					go func() { failedActions <- a }()
 				}
 				break
 			}
-			if err := actionFunction(nil, a, aac, at.ExtraData); err != nil {
+			if err := actionFunction(nil, a, flow.executed, at.ExtraData); err != nil {
 				utils.Logger.Err(fmt.Sprintf("Error executing accountless action %s: %v!", a.ActionType, err))
+				accErr = err
 				if failedActions != nil {
 					go func() { failedActions <- a }()
 				}
+				if a.FailurePolicy == utils.MetaContinue {
+					continue
+				}
 				break
 			}
 			if successActions != nil {
 				go func() { successActions <- a }()
 			}
 		}
+		accErrs[""] = accErr
+	}
+	if abortErr != nil {
+		utils.Logger.Warning(fmt.Sprintf("Aborted execution of action plan %s: %v", at.actionPlanID, abortErr))
+		return accErrs, abortErr
+	}
+	if at.RemoveAfterRun && at.actionPlanID != "" {
+		if rmErr := removeActionPlan(at.actionPlanID); rmErr != nil {
+			utils.Logger.Warning(fmt.Sprintf("Cannot remove one-off action plan %s: %v", at.actionPlanID, rmErr))
+		}
 	}
 	if err != nil {
 		utils.Logger.Warning(fmt.Sprintf("Error executing action plan: %v", err))
-		return err
+		return
 	}
 	Publish(CgrEvent{
 		"EventName": utils.EVT_ACTION_TIMING_FIRED,
@@ -399,6 +518,114 @@ func (at *ActionTiming) Execute(successActions, failedActions chan *Action) (err
 	return
 }
 
+// removeActionPlan removes the ActionPlan together with its references from the accounts
+func removeActionPlan(apID string) (err error) {
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		ap, err := dm.DataDB().GetActionPlan(apID, true, utils.NonTransactional)
+		if err != nil {
+			return 0, err
+		}
+		accIDs := ap.AccountIDs.Slice()
+		for _, accID := range accIDs {
+			if err := dm.DataDB().RemAccountActionPlans(accID, []string{apID}); err != nil &&
+				err != utils.ErrNotFound {
+				return 0, err
+			}
+		}
+		if err := dm.DataDB().RemoveActionPlan(apID, utils.NonTransactional); err != nil {
+			return 0, err
+		}
+		if err := dm.CacheDataFromDB(utils.AccountActionPlansPrefix, accIDs, true); err != nil &&
+			err.Error() != utils.ErrNotFound.Error() {
+			return 0, err
+		}
+		return 0, nil
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.ACTION_PLAN_PREFIX)
+	return
+}
+
+// executeOnAccount executes the actions on one account, accErr is the one of the actions
+func (at *ActionTiming) executeOnAccount(accID string, aac Actions,
+	successActions, failedActions chan *Action, proceed func() error) (accErr, err error) {
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		acc, err := dm.DataDB().GetAccount(accID)
+		if err != nil {
+			utils.Logger.Warning(fmt.Sprintf("Could not get account id: %s. Skipping!", accID))
+			return 0, err
+		}
+		cause := &auditCause{cause: at.auditCause, actionsID: at.ActionsID}
+		if cause.cause == "" {
+			cause.cause = utils.MetaActions
+		}
+		prevCause := acc.auditAs(cause)
+		transactionFailed := false
+		removeAccountActionFound := false
+		flow := newActionsFlow(aac)
+		for {
+			a, err := flow.next(acc, at.ExtraData)
+			if err != nil {
+				return 0, err
+			}
+			if a == nil {
+				break
+			}
+			if accErr = proceed(); accErr != nil { // leave the account unchanged
+				transactionFailed = true
+				break
+			}
+			if a.Balance == nil {
+				a.Balance = &BalanceFilter{}
+			}
+			if a.ExpirationString != "" { // if it's *unlimited then it has to be zero time
+				if expDate, parseErr := utils.ParseTimeDetectLayout(a.ExpirationString,
+					config.CgrConfig().GeneralCfg().DefaultTimezone); parseErr == nil {
+					a.Balance.ExpirationDate = &time.Time{}
+					*a.Balance.ExpirationDate = expDate
+				}
+			}
+
+			actionFunction, exists := getActionFunc(a.ActionType)
+			if !exists {
+				// do not allow the action plan to be rescheduled
+				at.Timing = nil
+				utils.Logger.Err(fmt.Sprintf("Function type %v not available, aborting execution!", a.ActionType))
+				accErr = fmt.Errorf("unsupported action type: %s", a.ActionType)
+				transactionFailed = true
+				break
+			}
+			if err := actionFunction(acc, a, flow.executed, at.ExtraData); err != nil {
+				utils.Logger.Err(fmt.Sprintf("Error executing action %s: %v!", a.ActionType, err))
+				accErr = err
+				if failedActions != nil {
+					go func() { failedActions <- a }()
+				}
+				if a.FailurePolicy == utils.MetaContinue {
+					continue
+				}
+				transactionFailed = true
+				break
+			}
+			if successActions != nil {
+				go func() { successActions <- a }()
+			}
+			if a.ActionType == REMOVE_ACCOUNT {
+				removeAccountActionFound = true
+			}
+		}
+		if !transactionFailed && !removeAccountActionFound {
+			acc.endAudit(prevCause)
+			setAuditedAccount(acc)
+		}
+		return 0, nil
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, accID)
+	return
+}
+
+// SetAuditCause sets the cause of the account changes in the audit, eg: the API method
+func (at *ActionTiming) SetAuditCause(cause string) {
+	at.auditCause = cause
+}
+
 func (at *ActionTiming) IsASAP() bool {
 	if at.Timing == nil {
 		return false
@@ -20,6 +20,7 @@ package engine
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -55,3 +56,120 @@ func TestCacheGetCloned(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", at1, at1Cloned)
 	}
 }
+
+func TestActionTimingMissedStartTimes(t *testing.T) {
+	at := &ActionTiming{Timing: &RateInterval{Timing: &RITiming{StartTime: "10:00:00"}}}
+	lastExec := time.Date(2018, 3, 1, 10, 0, 0, 0, time.Local)
+	now := time.Date(2018, 3, 4, 9, 0, 0, 0, time.Local)
+	eSts := []time.Time{
+		time.Date(2018, 3, 2, 10, 0, 0, 0, time.Local),
+		time.Date(2018, 3, 3, 10, 0, 0, 0, time.Local),
+	}
+	if sts := at.MissedStartTimes(lastExec, now, 10); !reflect.DeepEqual(eSts, sts) {
+		t.Errorf("Expecting: %+v, received: %+v", eSts, sts)
+	}
+	if sts := at.MissedStartTimes(lastExec, now, 1); !reflect.DeepEqual(eSts[:1], sts) {
+		t.Errorf("Expecting: %+v, received: %+v", eSts[:1], sts)
+	}
+	if sts := at.MissedStartTimes(eSts[1], now, 10); len(sts) != 0 {
+		t.Errorf("received: %+v", sts)
+	}
+	if st := at.GetNextStartTime(now); !st.Equal(time.Date(2018, 3, 4, 10, 0, 0, 0, time.Local)) {
+		t.Errorf("start time cache not reset, received: %v", st)
+	}
+}
+
+func TestActionTimingExecuteWithOpts(t *testing.T) {
+	accIDs := []string{"cgrates.org:exec1", "cgrates.org:exec2", "cgrates.org:exec3", "cgrates.org:exec4"}
+	at := &ActionTiming{
+		accountIDs: utils.NewStringMap(accIDs...),
+		actions: Actions{&Action{
+			ActionType: TOPUP,
+			Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+				Value: &utils.ValueFormula{Static: 10}},
+		}},
+	}
+	for _, accID := range accIDs {
+		if err := dm.DataDB().SetAccount(&Account{ID: accID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var progress []int
+	var lastAccID string
+	accErrs, err := at.ExecuteWithOpts(nil, nil, &ExecuteOpts{
+		Workers: 2,
+		Spread:  4 * time.Millisecond,
+		After:   "cgrates.org:exec1", // processed before interruption
+		OnProgress: func(processed int, accID string) {
+			progress = append(progress, processed)
+			lastAccID = accID
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(accErrs) != 3 {
+		t.Errorf("received: %+v", accErrs)
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != 4 {
+		t.Errorf("received: %+v", progress)
+	}
+	if lastAccID != "cgrates.org:exec4" {
+		t.Errorf("received: %s", lastAccID)
+	}
+	for i, accID := range accIDs {
+		eVal := 10.0
+		if i == 0 {
+			eVal = 0
+		}
+		if acc, err := dm.DataDB().GetAccount(accID); err != nil {
+			t.Error(err)
+		} else if val := acc.BalanceMap[utils.MONETARY].GetTotalValue(); val != eVal {
+			t.Errorf("account: %s, expecting: %v, received: %v", accID, eVal, val)
+		}
+	}
+}
+
+func TestActionTimingExecuteWithOptsProceed(t *testing.T) {
+	accIDs := []string{"cgrates.org:abort1"}
+	at := &ActionTiming{
+		accountIDs: utils.NewStringMap(accIDs...),
+		actions: Actions{
+			&Action{
+				ActionType: TOPUP,
+				Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+					Value: &utils.ValueFormula{Static: 10}},
+			},
+			&Action{
+				ActionType: TOPUP,
+				Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+					Value: &utils.ValueFormula{Static: 5}},
+			},
+		},
+	}
+	for _, accID := range accIDs {
+		if err := dm.DataDB().SetAccount(&Account{ID: accID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var checks int
+	accErrs, err := at.ExecuteWithOpts(nil, nil, &ExecuteOpts{
+		Proceed: func() error {
+			if checks++; checks > 2 { // lease lost before the second action
+				return utils.ErrLeaseLost
+			}
+			return nil
+		},
+	})
+	if err != utils.ErrLeaseLost {
+		t.Errorf("expecting: %v, received: %v", utils.ErrLeaseLost, err)
+	}
+	if len(accErrs) != 1 || accErrs[accIDs[0]] != utils.ErrLeaseLost {
+		t.Errorf("received: %+v", accErrs)
+	}
+	if acc, err := dm.DataDB().GetAccount(accIDs[0]); err != nil {
+		t.Error(err)
+	} else if val := acc.BalanceMap[utils.MONETARY].GetTotalValue(); val != 0 { // first top-up not saved
+		t.Errorf("received: %v", val)
+	}
+}
@@ -65,18 +65,20 @@ func (at *ActionTrigger) Execute(ub *Account) (err error) {
 	}
 	aac.Sort()
 	at.Executed = true
+	if ub != nil {
+		prevCause := ub.auditAs(&auditCause{cause: utils.MetaTrigger, actionsID: at.ActionsID})
+		defer ub.endAudit(prevCause)
+	}
 	transactionFailed := false
 	removeAccountActionFound := false
-	for _, a := range aac {
-		// check action filter
-		if len(a.Filter) > 0 {
-			matched, err := ub.matchActionFilter(a.Filter)
-			if err != nil {
-				return err
-			}
-			if !matched {
-				continue
-			}
+	flow := newActionsFlow(aac)
+	for {
+		a, err := flow.next(ub, nil)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			break
 		}
 		if a.Balance == nil {
 			a.Balance = &BalanceFilter{}
@@ -96,8 +98,11 @@ func (at *ActionTrigger) Execute(ub *Account) (err error) {
 			break
 		}
 		//go utils.Logger.Info(fmt.Sprintf("Executing %v, %v: %v", ub, sq, a))
-		if err := actionFunction(ub, a, aac, nil); err != nil {
+		if err := actionFunction(ub, a, flow.executed, nil); err != nil {
 			utils.Logger.Err(fmt.Sprintf("Error executing action %s: %v!", a.ActionType, err))
+			if a.FailurePolicy == utils.MetaContinue {
+				continue
+			}
 			transactionFailed = false
 			break
 		}
@@ -115,7 +120,7 @@ func (at *ActionTrigger) Execute(ub *Account) (err error) {
 			"Id":        at.ID,
 			"ActionIds": at.ActionsID,
 		})
-		dm.DataDB().SetAccount(ub)
+		setAuditedAccount(ub)
 	}
 	return
 }
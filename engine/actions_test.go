@@ -24,6 +24,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -2599,3 +2600,189 @@ func BenchmarkUUID(b *testing.B) {
 		b.StartTimer()
 	}
 }
+
+func TestHttpPostPayload(t *testing.T) {
+	acc := &Account{ID: "cgrates.org:1001"}
+	a := &Action{Id: "CRM_NOTIFY", ActionType: MetaHTTPPost,
+		Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+			ID: utils.StringPointer("MAIN")}}
+	ev := &utils.CGREvent{Tenant: "cgrates.org",
+		Event: map[string]interface{}{"EventType": "LowBalance"}}
+	tpl, err := config.FCTemplatesFromFCTemplatesJsonCfg([]*config.FcTemplateJsonCfg{
+		{Tag: utils.StringPointer("Source"), Field_id: utils.StringPointer("source"),
+			Type: utils.StringPointer(utils.META_CONSTANT), Value: utils.StringPointer("cgrates")},
+		{Tag: utils.StringPointer("Customer"), Field_id: utils.StringPointer("customer.id"),
+			Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("~Tenant")},
+		{Tag: utils.StringPointer("Customer"), Field_id: utils.StringPointer("customer.id"),
+			Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("/;~Account")},
+		{Tag: utils.StringPointer("Balance"), Field_id: utils.StringPointer("customer.balance"),
+			Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("~BalanceID")},
+		{Tag: utils.StringPointer("Event"), Field_id: utils.StringPointer("event"),
+			Type: utils.StringPointer(utils.MetaVariable), Value: utils.StringPointer("~*req.EventType")},
+		{Tag: utils.StringPointer("Reason"), Field_id: utils.StringPointer("reason"),
+			Type: utils.StringPointer(utils.MetaVariable), Value: utils.StringPointer("~*req.Reason")},
+	}, utils.INFIELD_SEP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eBody := `{"customer":{"balance":"MAIN","id":"cgrates.org/1001"},"event":"LowBalance","source":"cgrates"}`
+	if body, err := httpPostPayload(tpl, newHTTPPostProvider(acc, a, ev)); err != nil {
+		t.Error(err)
+	} else if string(body) != eBody {
+		t.Errorf("expecting: %s, received: %s", eBody, string(body))
+	}
+	tpl[len(tpl)-1].Mandatory = true
+	if _, err := httpPostPayload(tpl, newHTTPPostProvider(acc, a, ev)); err == nil ||
+		err.Error() != utils.ErrPrefixNotFound("Reason").Error() {
+		t.Error(err)
+	}
+}
+
+func TestActionsFlowFilterIDsBranch(t *testing.T) {
+	filterS = NewFilterS(config.CgrConfig(), nil, dm)
+	defer func() { filterS = nil }()
+	if err := dm.SetActions("ACT_BRANCHED", Actions{
+		&Action{Id: "ACT_BRANCHED", ActionType: TOPUP, Weight: 10,
+			Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+				ID: utils.StringPointer("MAIN"), Value: &utils.ValueFormula{Static: 5}}},
+	}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.DataDB().SetAccount(&Account{ID: "cgrates.org:flow",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: {&Balance{ID: "MAIN", Value: 1}}}}); err != nil {
+		t.Fatal(err)
+	}
+	at := &ActionTiming{
+		accountIDs: utils.StringMap{"cgrates.org:flow": true},
+		actions: Actions{
+			&Action{Id: "ACT_FLOW", ActionType: TOPUP, Weight: 40, // account not disabled, skipped
+				FilterIDs: []string{"*string:*account.Disabled:true"},
+				Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+					ID: utils.StringPointer("MAIN"), Value: &utils.ValueFormula{Static: 1000}}},
+			&Action{Id: "ACT_FLOW", ActionType: CDRLOG, Weight: 30, // fails without CDRs connection
+				FailurePolicy: utils.MetaContinue},
+			&Action{Id: "ACT_FLOW", ActionType: MetaBranch, Weight: 20,
+				ExtraParameters: "ACT_BRANCHED",
+				FilterIDs:       []string{"*gte:*account.BalanceTotals.*monetary:1"}},
+			&Action{Id: "ACT_FLOW", ActionType: TOPUP, Weight: 10, // not reached after branching
+				Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+					ID: utils.StringPointer("MAIN"), Value: &utils.ValueFormula{Static: 100}}},
+		},
+	}
+	accErrs, err := at.ExecuteOnAccounts(nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if accErrs["cgrates.org:flow"] == nil { // *continue on *cdrlog failure still reports it
+		t.Errorf("received: %+v", accErrs)
+	}
+	if acc, err := dm.DataDB().GetAccount("cgrates.org:flow"); err != nil {
+		t.Error(err)
+	} else if val := acc.BalanceMap[utils.MONETARY].GetTotalValue(); val != 6 {
+		t.Errorf("expecting: 6, received: %v", val)
+	}
+}
+
+func TestActionsFlowExecuted(t *testing.T) {
+	if err := dm.SetActions("ACT_BRANCHED_LOG", Actions{
+		&Action{Id: "ACT_BRANCHED_LOG", ActionType: TOPUP, Weight: 20},
+		&Action{Id: "ACT_BRANCHED_LOG", ActionType: CDRLOG, Weight: 10},
+	}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	flow := newActionsFlow(Actions{
+		&Action{Id: "ACT_FLOW_LOG", ActionType: DEBIT, Weight: 20},
+		&Action{Id: "ACT_FLOW_LOG", ActionType: MetaBranch, Weight: 10,
+			ExtraParameters: "ACT_BRANCHED_LOG"},
+	})
+	var types []string
+	for {
+		a, err := flow.next(nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a == nil {
+			break
+		}
+		types = append(types, a.ActionType)
+	}
+	eTypes := []string{DEBIT, TOPUP, CDRLOG}
+	if !reflect.DeepEqual(eTypes, types) {
+		t.Errorf("expecting: %+v, received: %+v", eTypes, types)
+	}
+	if len(flow.executed) != 3 || flow.executed[0].Id != "ACT_FLOW_LOG" ||
+		flow.executed[2].Id != "ACT_BRANCHED_LOG" {
+		t.Errorf("received: %s", utils.ToJSON(flow.executed))
+	}
+}
+
+func TestActionScheduleActions(t *testing.T) {
+	if err := dm.SetActions("ACT_SCHEDULED", Actions{
+		&Action{Id: "ACT_SCHEDULED", ActionType: TOPUP, Weight: 10,
+			Balance: &BalanceFilter{Type: utils.StringPointer(utils.MONETARY),
+				ID: utils.StringPointer("MAIN"), Value: &utils.ValueFormula{Static: 5}}},
+	}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	acc := &Account{ID: "cgrates.org:sched",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: {&Balance{ID: "MAIN", Value: 1}}}}
+	if err := dm.DataDB().SetAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	a := &Action{ActionType: MetaScheduleActions,
+		ExtraParameters: `{"ActionsID":"ACT_SCHEDULED","Time":"+30d"}`}
+	if err := scheduleActionsAction(acc, a, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	apIDs, err := dm.DataDB().GetAccountActionPlans(acc.ID, true, utils.NonTransactional)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(apIDs) != 1 || !strings.HasPrefix(apIDs[0], MetaScheduleActions) {
+		t.Fatalf("received: %+v", apIDs)
+	}
+	ap, err := dm.DataDB().GetActionPlan(apIDs[0], true, utils.NonTransactional)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(ap.ActionTimings) != 1 || !ap.AccountIDs[acc.ID] {
+		t.Fatalf("received: %s", utils.ToJSON(ap))
+	}
+	var queued bool // left as task for the scheduler, without connection
+	for {
+		task, err := dm.DataDB().PopTask()
+		if err != nil || task == nil {
+			break
+		}
+		queued = queued || task.ActionPlanID == ap.Id
+	}
+	if !queued {
+		t.Errorf("no task queueing the action plan %s", ap.Id)
+	}
+	at := ap.ActionTimings[0]
+	expected := time.Now().AddDate(0, 0, 30)
+	if st := at.GetNextStartTime(time.Now()); st.Sub(expected) > time.Second ||
+		expected.Sub(st) > time.Second {
+		t.Errorf("expecting: %v, received: %v", expected, st)
+	}
+	at.SetAccountIDs(ap.AccountIDs)
+	at.SetActionPlanID(ap.Id)
+	if err := at.Execute(nil, nil); err != nil {
+		t.Error(err)
+	}
+	if acc, err := dm.DataDB().GetAccount(acc.ID); err != nil {
+		t.Error(err)
+	} else if val := acc.BalanceMap[utils.MONETARY].GetTotalValue(); val != 6 {
+		t.Errorf("expecting: 6, received: %v", val)
+	}
+	if _, err := dm.DataDB().GetActionPlan(ap.Id, true, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dm.DataDB().GetAccountActionPlans(acc.ID, true, utils.NonTransactional); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	a.ExtraParameters = `{"ActionsID":"ACT_SCHEDULED","Time":"2010-01-01T00:00:00Z"}`
+	if err := scheduleActionsAction(acc, a, nil, nil); err == nil {
+		t.Error("expecting error for time in the past")
+	}
+}
@@ -41,6 +41,8 @@ type BalanceFilter struct {
 	Disabled       *bool
 	Factor         *ValueFactor
 	Blocker        *bool
+	Currency       *string
+	Rollover       *utils.RolloverPolicy
 }
 
 func (bp *BalanceFilter) CreateBalance() *Balance {
@@ -59,6 +61,8 @@ func (bp *BalanceFilter) CreateBalance() *Balance {
 		Disabled:       bp.GetDisabled(),
 		Factor:         bp.GetFactor(),
 		Blocker:        bp.GetBlocker(),
+		Currency:       bp.GetCurrency(),
+		Rollover:       bp.Rollover,
 	}
 	return b.Clone()
 }
@@ -81,6 +85,14 @@ func (bf *BalanceFilter) Clone() *BalanceFilter {
 		result.RatingSubject = new(string)
 		*result.RatingSubject = *bf.RatingSubject
 	}
+	if bf.Currency != nil {
+		result.Currency = new(string)
+		*result.Currency = *bf.Currency
+	}
+	if bf.Rollover != nil {
+		result.Rollover = new(utils.RolloverPolicy)
+		*result.Rollover = *bf.Rollover
+	}
 	if bf.Type != nil {
 		result.Type = new(string)
 		*result.Type = *bf.Type
@@ -143,6 +155,12 @@ func (bf *BalanceFilter) LoadFromBalance(b *Balance) *BalanceFilter {
 	if b.RatingSubject != "" {
 		bf.RatingSubject = &b.RatingSubject
 	}
+	if b.Currency != "" {
+		bf.Currency = &b.Currency
+	}
+	if b.Rollover != nil {
+		bf.Rollover = b.Rollover
+	}
 	if !b.Categories.IsEmpty() {
 		bf.Categories = &b.Categories
 	}
@@ -257,6 +275,13 @@ func (bp *BalanceFilter) GetRatingSubject() string {
 	return *bp.RatingSubject
 }
 
+func (bp *BalanceFilter) GetCurrency() string {
+	if bp == nil || bp.Currency == nil {
+		return ""
+	}
+	return *bp.Currency
+}
+
 func (bp *BalanceFilter) GetDisabled() bool {
 	if bp == nil || bp.Disabled == nil {
 		return false
@@ -308,6 +333,12 @@ func (bf *BalanceFilter) ModifyBalance(b *Balance) {
 	if bf.RatingSubject != nil {
 		b.RatingSubject = *bf.RatingSubject
 	}
+	if bf.Currency != nil {
+		b.Currency = *bf.Currency
+	}
+	if bf.Rollover != nil {
+		b.Rollover = bf.Rollover
+	}
 	if bf.Categories != nil {
 		b.Categories = *bf.Categories
 	}
@@ -45,6 +45,10 @@ type Balance struct {
 	Disabled       bool
 	Factor         ValueFactor
 	Blocker        bool
+	Currency       string                // ISO 4217 code of a monetary balance, empty for the default currency
+	CreationTime   time.Time             // time of the topup creating the balance
+	Rollover       *utils.RolloverPolicy // moves the unused value into a new balance at expiry
+	Rollovers      int                   // number of times the value was rolled over into this balance
 	precision      int
 	account        *Account // used to store ub reference for shared balances
 	dirty          bool
@@ -66,7 +70,8 @@ func (b *Balance) Equal(o *Balance) bool {
 		b.Categories.Equal(o.Categories) &&
 		b.SharedGroups.Equal(o.SharedGroups) &&
 		b.Disabled == o.Disabled &&
-		b.Blocker == o.Blocker
+		b.Blocker == o.Blocker &&
+		b.Currency == o.Currency
 }
 
 func (b *Balance) MatchFilter(o *BalanceFilter, skipIds, skipExpiry bool) bool {
@@ -91,7 +96,8 @@ func (b *Balance) MatchFilter(o *BalanceFilter, skipIds, skipExpiry bool) bool {
 		(o.Categories == nil || b.Categories.Includes(*o.Categories)) &&
 		(o.TimingIDs == nil || b.TimingIDs.Includes(*o.TimingIDs)) &&
 		(o.SharedGroups == nil || b.SharedGroups.Includes(*o.SharedGroups)) &&
-		(o.RatingSubject == nil || b.RatingSubject == *o.RatingSubject)
+		(o.RatingSubject == nil || b.RatingSubject == *o.RatingSubject) &&
+		(o.Currency == nil || b.Currency == *o.Currency)
 }
 
 func (b *Balance) HardMatchFilter(o *BalanceFilter, skipIds bool) bool {
@@ -112,7 +118,8 @@ func (b *Balance) HardMatchFilter(o *BalanceFilter, skipIds bool) bool {
 		(o.Categories == nil || b.Categories.Equal(*o.Categories)) &&
 		(o.TimingIDs == nil || b.TimingIDs.Equal(*o.TimingIDs)) &&
 		(o.SharedGroups == nil || b.SharedGroups.Equal(*o.SharedGroups)) &&
-		(o.RatingSubject == nil || b.RatingSubject == *o.RatingSubject)
+		(o.RatingSubject == nil || b.RatingSubject == *o.RatingSubject) &&
+		(o.Currency == nil || b.Currency == *o.Currency)
 }
 
 // the default balance has standard Id
@@ -125,6 +132,39 @@ func (b *Balance) IsExpired() bool {
 	return !b.ExpirationDate.IsZero() && b.ExpirationDate.Before(time.Now().Add(1*time.Second))
 }
 
+// canRollover returns true if the unused value of b is moved into a new balance at its expiry
+func (b *Balance) canRollover() bool {
+	return b.Rollover != nil && b.Value > 0 &&
+		(b.Rollover.MaxRollovers == 0 || b.Rollovers < b.Rollover.MaxRollovers)
+}
+
+// rolledOver returns the balance receiving the unused value of b at its expiry, nil if nothing rolls over
+func (b *Balance) rolledOver() (rb *Balance, err error) {
+	if !b.canRollover() {
+		return
+	}
+	value := utils.NewDecimalFromFloat64(b.Value)
+	if b.Rollover.Percent != 0 {
+		value = value.Mul(utils.NewDecimalFromFloat64(b.Rollover.Percent)).
+			Div(utils.NewDecimalFromInt64(100))
+	}
+	rolled := value.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
+	if b.Rollover.MaxValue != 0 && rolled > b.Rollover.MaxValue {
+		rolled = b.Rollover.MaxValue
+	}
+	rb = b.Clone()
+	if rb.ExpirationDate, err = b.Rollover.NextExpiry(b.ExpirationDate); err != nil {
+		return nil, err
+	}
+	rb.Uuid = utils.GenUUID()
+	rb.Value = rolled
+	rb.Factor = b.Factor
+	rb.Rollovers++
+	rb.CreationTime = time.Now()
+	rb.dirty = true
+	return
+}
+
 func (b *Balance) IsActive() bool {
 	return b.IsActiveAt(time.Now())
 }
@@ -177,11 +217,18 @@ func (b *Balance) Clone() *Balance {
 		Timings:        b.Timings, // should not be a problem with aliasing
 		Blocker:        b.Blocker,
 		Disabled:       b.Disabled,
+		Currency:       b.Currency,
+		CreationTime:   b.CreationTime,
+		Rollovers:      b.Rollovers,
 		dirty:          b.dirty,
 	}
 	if b.DestinationIDs != nil {
 		n.DestinationIDs = b.DestinationIDs.Clone()
 	}
+	if b.Rollover != nil {
+		n.Rollover = new(utils.RolloverPolicy)
+		*n.Rollover = *b.Rollover
+	}
 	return n
 }
 
@@ -279,16 +326,20 @@ func (b *Balance) GetValue() float64 {
 }
 
 func (b *Balance) AddValue(amount float64) {
-	b.SetValue(b.GetValue() + amount)
+	b.setDecimalValue(utils.NewDecimalFromFloat64(b.GetValue()).Add(utils.NewDecimalFromFloat64(amount)))
 }
 
 func (b *Balance) SubstractValue(amount float64) {
-	b.SetValue(b.GetValue() - amount)
+	b.setDecimalValue(utils.NewDecimalFromFloat64(b.GetValue()).Sub(utils.NewDecimalFromFloat64(amount)))
 }
 
 func (b *Balance) SetValue(amount float64) {
-	b.Value = amount
-	b.Value = utils.Round(b.GetValue(), globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	b.setDecimalValue(utils.NewDecimalFromFloat64(amount))
+}
+
+// setDecimalValue rounds the value as Decimal, converting it to float only when stored
+func (b *Balance) setDecimalValue(value *utils.Decimal) {
+	b.Value = value.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
 	b.dirty = true
 }
 
@@ -469,6 +520,10 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 					// go to nextincrement
 					continue
 				}
+				if strategy == utils.MAX_COST_FREE {
+					cost = cd.capCost(cost, maxCost)
+					inc.Cost = cost
+				}
 				var moneyBal *Balance
 				for _, mb := range moneyBalances {
 					if mb.GetValue() >= cost {
@@ -569,14 +624,16 @@ func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Bala
 
 		if tsIndex == 0 && ts.RateInterval.Rating.ConnectFee > 0 && debitConnectFee && cc.deductConnectFee && ok {
 
+			cfExchangeRate, _ := debitedConnectFeeBalance.exchangeRate(ts.RateInterval.Rating.Currency)
 			inc := &Increment{
 				Duration: 0,
 				Cost:     ts.RateInterval.Rating.ConnectFee,
 				BalanceInfo: &DebitInfo{
 					Monetary: &MonetaryInfo{
-						UUID:  debitedConnectFeeBalance.Uuid,
-						ID:    debitedConnectFeeBalance.ID,
-						Value: debitedConnectFeeBalance.Value,
+						UUID:         debitedConnectFeeBalance.Uuid,
+						ID:           debitedConnectFeeBalance.ID,
+						Value:        debitedConnectFeeBalance.Value,
+						ExchangeRate: cfExchangeRate,
 					},
 					AccountID: ub.ID,
 				},
@@ -586,6 +643,17 @@ func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Bala
 			ts.Increments = append(incs, ts.Increments...)
 		}
 
+		exchangeRate, err := b.exchangeRate(ts.RateInterval.Rating.Currency)
+		if err != nil { // balance cannot pay in this currency, stop here like for insufficient credit
+			utils.Logger.Warning(
+				fmt.Sprintf("<Rater> cannot convert <%s> into currency of balance <%s>: %s",
+					ts.RateInterval.Rating.Currency, b.Uuid, err.Error()))
+			cc.Timespans = cc.Timespans[:tsIndex]
+			if len(cc.Timespans) == 0 {
+				cc = nil
+			}
+			return cc, nil
+		}
 		maxCost, strategy := ts.RateInterval.GetMaxCost()
 		//log.Printf("Timing: %+v", ts.RateInterval.Timing)
 		//log.Printf("Rate: %+v", ts.RateInterval.Rating)
@@ -598,7 +666,7 @@ func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Bala
 				continue
 			}
 
-			amount := inc.Cost
+			amount := convertAmount(inc.Cost, exchangeRate)
 			inc.paid = false
 			if strategy == utils.MAX_COST_DISCONNECT && cd.MaxCostSoFar >= maxCost {
 				// cut the entire current timespan
@@ -617,9 +685,10 @@ func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Bala
 			if strategy == utils.MAX_COST_FREE && cd.MaxCostSoFar >= maxCost {
 				amount, inc.Cost = 0.0, 0.0
 				inc.BalanceInfo.Monetary = &MonetaryInfo{
-					UUID:  b.Uuid,
-					ID:    b.ID,
-					Value: b.Value,
+					UUID:         b.Uuid,
+					ID:           b.ID,
+					Value:        b.Value,
+					ExchangeRate: exchangeRate,
 				}
 				inc.BalanceInfo.AccountID = ub.ID
 				if b.RatingSubject != "" {
@@ -634,14 +703,19 @@ func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Bala
 				// go to nextincrement
 				continue
 			}
+			if strategy == utils.MAX_COST_FREE {
+				inc.Cost = cd.capCost(inc.Cost, maxCost)
+				amount = convertAmount(inc.Cost, exchangeRate)
+			}
 
 			if b.GetValue() >= amount {
 				b.SubstractValue(amount)
-				cd.MaxCostSoFar += amount
+				cd.MaxCostSoFar += inc.Cost // MaxCost is defined in the rating currency
 				inc.BalanceInfo.Monetary = &MonetaryInfo{
-					UUID:  b.Uuid,
-					ID:    b.ID,
-					Value: b.Value,
+					UUID:         b.Uuid,
+					ID:           b.ID,
+					Value:        b.Value,
+					ExchangeRate: exchangeRate,
 				}
 				inc.BalanceInfo.AccountID = ub.ID
 				if b.RatingSubject != "" {
@@ -748,14 +822,65 @@ func (bc Balances) Sort() {
 	sort.Sort(bc)
 }
 
+// SortForDebit orders the balances in the order they should be debited: better destination
+// precision first, then the ones preferred by the debit policy, with Weight deciding on equality
+func (bc Balances) SortForDebit(debitPolicy string) {
+	if debitPolicy == "" {
+		bc.Sort()
+		return
+	}
+	sort.SliceStable(bc, func(i, j int) bool {
+		if bc[i].precision != bc[j].precision {
+			return bc[i].precision > bc[j].precision
+		}
+		switch debitPolicy {
+		case utils.MetaExpiresFirst: // balances without expiry go last
+			if !bc[i].ExpirationDate.Equal(bc[j].ExpirationDate) {
+				return bc[j].ExpirationDate.IsZero() ||
+					(!bc[i].ExpirationDate.IsZero() && bc[i].ExpirationDate.Before(bc[j].ExpirationDate))
+			}
+		case utils.MetaSmallestFirst:
+			if bc[i].GetValue() != bc[j].GetValue() {
+				return bc[i].GetValue() < bc[j].GetValue()
+			}
+		case utils.MetaFIFOTopup:
+			if !bc[i].CreationTime.Equal(bc[j].CreationTime) {
+				return bc[i].CreationTime.Before(bc[j].CreationTime)
+			}
+		}
+		return bc[i].Weight > bc[j].Weight
+	})
+}
+
 func (bc Balances) GetTotalValue() (total float64) {
+	dTotal := utils.NewDecimalFromInt64(0)
 	for _, b := range bc {
 		if !b.IsExpired() && b.IsActive() {
-			total += b.GetValue()
+			dTotal = dTotal.Add(utils.NewDecimalFromFloat64(b.GetValue()))
 		}
 	}
-	total = utils.Round(total, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
-	return
+	return dTotal.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
+}
+
+// GetTotalValueInCurrency sums the values of the balances converted into currency
+// balances without an exchange rate towards it are left out
+func (bc Balances) GetTotalValueInCurrency(currency string) (total float64) {
+	dTotal := utils.NewDecimalFromInt64(0)
+	for _, b := range bc {
+		if b.IsExpired() || !b.IsActive() {
+			continue
+		}
+		rate, err := b.exchangeRate(currency)
+		if err != nil {
+			continue
+		}
+		value := utils.NewDecimalFromFloat64(b.GetValue())
+		if rate != 0 { // the rate converts into the balance currency
+			value = value.Div(utils.NewDecimalFromFloat64(rate))
+		}
+		dTotal = dTotal.Add(value)
+	}
+	return dTotal.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
 }
 
 func (bc Balances) Equal(o Balances) bool {
@@ -855,7 +980,10 @@ func (bc Balances) SaveDirtyBalances(acc *Account) {
 			})
 		}
 		if b.account != nil && b.account != acc && b.dirty && savedAccounts[b.account.ID] == nil {
-			dm.DataDB().SetAccount(b.account)
+			if err := setAuditedAccount(b.account); err != nil {
+				utils.Logger.Warning(fmt.Sprintf("<AccountS> cannot save account: %s, error: %s",
+					b.account.ID, err.Error()))
+			}
 			savedAccounts[b.account.ID] = b.account
 		}
 	}
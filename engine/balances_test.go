@@ -19,6 +19,7 @@ package engine
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -78,6 +79,46 @@ func TestBalanceSortWeightLess(t *testing.T) {
 	}
 }
 
+func TestBalanceSortForDebitExpiresFirst(t *testing.T) {
+	now := time.Now()
+	mb1 := &Balance{Weight: 10, ExpirationDate: now.Add(time.Hour)}
+	mb2 := &Balance{Weight: 20}
+	mb3 := &Balance{Weight: 5, ExpirationDate: now.Add(time.Minute)}
+	mb4 := &Balance{Weight: 1, precision: 3}
+	bs := Balances{mb1, mb2, mb3, mb4}
+	bs.SortForDebit(utils.MetaExpiresFirst)
+	if bs[0] != mb4 || bs[1] != mb3 || bs[2] != mb1 || bs[3] != mb2 {
+		t.Errorf("Balances not sorted by expiry: %s", utils.ToJSON(bs))
+	}
+}
+
+func TestBalanceSortForDebitSmallestFirst(t *testing.T) {
+	mb1 := &Balance{Weight: 10, Value: 5}
+	mb2 := &Balance{Weight: 20, Value: 10}
+	mb3 := &Balance{Weight: 30, Value: 5}
+	bs := Balances{mb1, mb2, mb3}
+	bs.SortForDebit(utils.MetaSmallestFirst)
+	if bs[0] != mb3 || bs[1] != mb1 || bs[2] != mb2 {
+		t.Errorf("Balances not sorted by value: %s", utils.ToJSON(bs))
+	}
+}
+
+func TestBalanceSortForDebitFIFOTopup(t *testing.T) {
+	now := time.Now()
+	mb1 := &Balance{Weight: 10, CreationTime: now}
+	mb2 := &Balance{Weight: 20, CreationTime: now.Add(time.Hour)}
+	mb3 := &Balance{Weight: 5, CreationTime: now.Add(-time.Hour)}
+	bs := Balances{mb1, mb2, mb3}
+	bs.SortForDebit(utils.MetaFIFOTopup)
+	if bs[0] != mb3 || bs[1] != mb1 || bs[2] != mb2 {
+		t.Errorf("Balances not sorted by topup: %s", utils.ToJSON(bs))
+	}
+	bs.SortForDebit("") // back to Weight
+	if bs[0] != mb2 || bs[1] != mb1 || bs[2] != mb3 {
+		t.Errorf("Balances not sorted by weight: %s", utils.ToJSON(bs))
+	}
+}
+
 func TestBalanceEqual(t *testing.T) {
 	mb1 := &Balance{Weight: 1, precision: 1, RatingSubject: "1", DestinationIDs: utils.StringMap{}}
 	mb2 := &Balance{Weight: 1, precision: 1, RatingSubject: "1", DestinationIDs: utils.StringMap{}}
@@ -59,6 +59,8 @@ var precachedPartitions = []string{
 	utils.CacheAttributeProfiles,
 	utils.CacheChargerProfiles,
 	utils.CacheDispatcherProfiles,
+	utils.CacheTaxProfiles,
+	utils.CacheCalendars,
 	utils.CacheDiameterMessages,
 }
 
@@ -0,0 +1,94 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	holidayDateLayout   = "2006-01-02"
+	holidayYearlyLayout = "01-02"
+)
+
+// Calendar is a named list of holidays of a tenant, referenced by the timings
+type Calendar struct {
+	Tenant   string
+	ID       string
+	Holidays []string // dates in 2006-01-02 format or 01-02 for yearly recurring ones
+}
+
+// TenantID returns the concatenated key beteen tenant and ID
+func (cal *Calendar) TenantID() string {
+	return utils.ConcatenatedKey(cal.Tenant, cal.ID)
+}
+
+// IsHoliday returns true if the day of t is listed in the Calendar
+func (cal *Calendar) IsHoliday(t time.Time) bool {
+	if cal == nil {
+		return false
+	}
+	date, yearly := t.Format(holidayDateLayout), t.Format(holidayYearlyLayout)
+	for _, day := range cal.Holidays {
+		if day == date || day == yearly {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidHoliday checks the format of a holiday definition
+func isValidHoliday(day string) bool {
+	if _, err := time.Parse(holidayDateLayout, day); err == nil {
+		return true
+	}
+	_, err := time.Parse(holidayYearlyLayout, day)
+	return err == nil
+}
+
+// resolveCalendars attaches the Calendars of the tenant to the timings referencing them.
+// The timings are copied since the originals are shared by the cached RatingPlan.
+// With skipCache the Calendars are read out of rDM only, without touching the cache.
+func (ril RateIntervalList) resolveCalendars(rDM *DataManager, skipCache bool,
+	transID, tenant string) {
+	cals := make(map[string]*Calendar)
+	for _, ri := range ril {
+		if ri.Timing == nil || ri.Timing.CalendarID == "" {
+			continue
+		}
+		cal, has := cals[ri.Timing.CalendarID]
+		if !has {
+			var err error
+			if cal, err = rDM.GetCalendar(tenant, ri.Timing.CalendarID,
+				!skipCache, !skipCache, transID); err != nil {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> error: %s querying calendar: %s for tenant: %s",
+						utils.RALService, err.Error(), ri.Timing.CalendarID, tenant))
+				cal = nil // timing considered without holidays
+			}
+			cals[ri.Timing.CalendarID] = cal
+		}
+		rit := *ri.Timing
+		rit.calendar = cal
+		ri.Timing = &rit
+	}
+}
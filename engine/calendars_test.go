@@ -0,0 +1,124 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestCalendarIsHoliday(t *testing.T) {
+	cal := &Calendar{Tenant: "cgrates.org", ID: "CAL_TEST",
+		Holidays: []string{"2018-12-25", "01-01"}}
+	if !cal.IsHoliday(time.Date(2018, 12, 25, 10, 0, 0, 0, time.UTC)) {
+		t.Error("Expecting holiday")
+	}
+	if cal.IsHoliday(time.Date(2019, 12, 25, 10, 0, 0, 0, time.UTC)) {
+		t.Error("Not expecting holiday")
+	}
+	if !cal.IsHoliday(time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Error("Expecting yearly holiday")
+	}
+	var nilCal *Calendar
+	if nilCal.IsHoliday(time.Date(2018, 12, 25, 10, 0, 0, 0, time.UTC)) {
+		t.Error("Not expecting holiday on missing calendar")
+	}
+}
+
+func TestRITimingHolidays(t *testing.T) {
+	if err := dm.SetCalendar(&Calendar{Tenant: "cgrates.org", ID: "CAL_TEST",
+		Holidays: []string{"2018-12-25"}}); err != nil {
+		t.Fatal(err)
+	}
+	hldTm := &RITiming{StartTime: "00:00:00",
+		CalendarID: "CAL_TEST", HolidayFilter: utils.MetaHoliday}
+	wrkTm := &RITiming{StartTime: "00:00:00",
+		CalendarID: "CAL_TEST", HolidayFilter: utils.MetaNotHoliday}
+	ril := RateIntervalList{&RateInterval{Timing: hldTm}, &RateInterval{Timing: wrkTm}}
+	ril.resolveCalendars(dm, false, utils.NonTransactional, "cgrates.org")
+	if ril[0].Timing == hldTm || hldTm.calendar != nil {
+		t.Error("Shared timing modified")
+	}
+	xmas := time.Date(2018, 12, 25, 10, 0, 0, 0, time.UTC)
+	workDay := time.Date(2018, 12, 27, 10, 0, 0, 0, time.UTC)
+	if !ril[0].Timing.IsActiveAt(xmas) || ril[0].Timing.IsActiveAt(workDay) {
+		t.Error("Wrong *holiday timing activation")
+	}
+	if ril[1].Timing.IsActiveAt(xmas) || !ril[1].Timing.IsActiveAt(workDay) {
+		t.Error("Wrong *not_holiday timing activation")
+	}
+	if hldTm.Stringify() == wrkTm.Stringify() {
+		t.Error("Expecting different timing hashes")
+	}
+}
+
+func TestCalendarCached(t *testing.T) {
+	if err := dm.SetCalendar(&Calendar{Tenant: "cgrates.org", ID: "CAL_CACHED",
+		Holidays: []string{"2018-12-25"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dm.GetCalendar("cgrates.org", "CAL_CACHED",
+		true, true, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if _, has := Cache.Get(utils.CacheCalendars, "cgrates.org:CAL_CACHED"); !has {
+		t.Error("Calendar not cached")
+	}
+	// updating the calendar refreshes the cached copy
+	if err := dm.SetCalendar(&Calendar{Tenant: "cgrates.org", ID: "CAL_CACHED",
+		Holidays: []string{"2018-12-26"}}); err != nil {
+		t.Fatal(err)
+	}
+	if cal, err := dm.GetCalendar("cgrates.org", "CAL_CACHED",
+		true, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !cal.IsHoliday(time.Date(2018, 12, 26, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Stale calendar received: %s", utils.ToJSON(cal))
+	}
+	if err := dm.RemoveCalendar("cgrates.org", "CAL_CACHED", utils.NonTransactional); err != nil {
+		t.Error(err)
+	}
+	if _, has := Cache.Get(utils.CacheCalendars, "cgrates.org:CAL_CACHED"); has {
+		t.Error("Calendar still cached")
+	}
+}
+
+func TestResolveCalendarsSkipCache(t *testing.T) {
+	simDB, _ := NewMapStorage()
+	if err := simDB.SetCalendarDrv(&Calendar{Tenant: "cgrates.org", ID: "CAL_SIM",
+		Holidays: []string{"2018-12-25"}}); err != nil {
+		t.Fatal(err)
+	}
+	ril := RateIntervalList{
+		&RateInterval{Timing: &RITiming{CalendarID: "CAL_SIM"}},
+		&RateInterval{Timing: &RITiming{CalendarID: "CAL_SIM_MISSING"}},
+	}
+	ril.resolveCalendars(NewDataManager(simDB), true, utils.NonTransactional, "cgrates.org")
+	if ril[0].Timing.calendar == nil {
+		t.Error("Calendar not resolved")
+	}
+	// misses out of the simulated data should not reach the cache used by the live rating
+	for _, tntID := range []string{"cgrates.org:CAL_SIM", "cgrates.org:CAL_SIM_MISSING"} {
+		if _, has := Cache.Get(utils.CacheCalendars, tntID); has {
+			t.Errorf("Calendar: %s cached", tntID)
+		}
+	}
+}
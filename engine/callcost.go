@@ -82,6 +82,33 @@ func (cc *CallCost) GetConnectFee() float64 {
 	return cc.Timespans[0].RateInterval.Rating.ConnectFee
 }
 
+// GetCurrency returns the currency the CallCost was rated in
+func (cc *CallCost) GetCurrency() string {
+	if len(cc.Timespans) == 0 ||
+		cc.Timespans[0].RateInterval == nil ||
+		cc.Timespans[0].RateInterval.Rating == nil {
+		return ""
+	}
+	return cc.Timespans[0].RateInterval.Rating.Currency
+}
+
+// callLimits checks the cost of the call against the FreeUsage and the MinCost
+// of the rates the call started with, returning the cost missing up to the MinCost
+func (cc *CallCost) callLimits(cost float64) (free bool, minCostDiff float64) {
+	if len(cc.Timespans) == 0 {
+		return
+	}
+	minCost, freeUsage := cc.Timespans[0].RateInterval.GetCallLimits()
+	if freeUsage > 0 && cc.GetDuration() < freeUsage {
+		return true, 0
+	}
+	if minCost > cost {
+		minCostDiff = utils.NewDecimalFromFloat64(minCost).
+			Sub(utils.NewDecimalFromFloat64(cost)).Float64()
+	}
+	return
+}
+
 // Creates a CallDescriptor structure copying related data from CallCost
 func (cc *CallCost) CreateCallDescriptor() *CallDescriptor {
 	return &CallDescriptor{
@@ -168,16 +195,15 @@ func (cc *CallCost) UpdateCost() {
 }
 
 func (cc *CallCost) updateCost() {
-	cost := 0.0
+	cost := utils.NewDecimalFromInt64(0)
 	//if cc.deductConnectFee { // add back the connectFee
 	//	cost += cc.GetConnectFee()
 	//}
 	for _, ts := range cc.Timespans {
 		ts.Cost = ts.CalculateCost()
-		cost += ts.Cost
-		cost = utils.Round(cost, globalRoundingDecimals, utils.ROUNDING_MIDDLE) // just get rid of the extra decimals
+		cost = cost.Add(utils.NewDecimalFromFloat64(ts.Cost))
 	}
-	cc.Cost = cost
+	cc.Cost = cost.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64() // just get rid of the extra decimals
 }
 
 // Round creates the RoundIncrements in timespans
@@ -185,7 +211,7 @@ func (cc *CallCost) Round() {
 	if len(cc.Timespans) == 0 || cc.Timespans[0] == nil {
 		return
 	}
-	var totalCorrectionCost float64
+	totalCorrectionCost := utils.NewDecimalFromInt64(0)
 	for _, ts := range cc.Timespans {
 		if len(ts.Increments) == 0 {
 			continue // safe check
@@ -195,22 +221,21 @@ func (cc *CallCost) Round() {
 			// this is a unit paid timespan, nothing to round
 			continue
 		}
-		cost := ts.CalculateCost()
-		roundedCost := utils.Round(cost,
-			ts.RateInterval.Rating.RoundingDecimals,
+		cost := utils.NewDecimalFromFloat64(ts.CalculateCost())
+		roundedCost := cost.Round(ts.RateInterval.Rating.RoundingDecimals,
 			ts.RateInterval.Rating.RoundingMethod)
-		correctionCost := roundedCost - cost
+		correctionCost := roundedCost.Sub(cost)
 		//log.Print(cost, roundedCost, correctionCost)
-		if correctionCost != 0 {
+		if correctionCost.Float64() != 0 {
 			ts.RoundIncrement = &Increment{
-				Cost:        correctionCost,
+				Cost:        correctionCost.Float64(),
 				BalanceInfo: inc.BalanceInfo,
 			}
-			totalCorrectionCost += correctionCost
-			ts.Cost += correctionCost
+			totalCorrectionCost = totalCorrectionCost.Add(correctionCost)
+			ts.Cost = utils.NewDecimalFromFloat64(ts.Cost).Add(correctionCost).Float64()
 		}
 	}
-	cc.Cost += totalCorrectionCost
+	cc.Cost = utils.NewDecimalFromFloat64(cc.Cost).Add(totalCorrectionCost).Float64()
 }
 
 func (cc *CallCost) GetRoundIncrements() (roundIncrements Increments) {
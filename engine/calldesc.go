@@ -63,6 +63,9 @@ var (
 	userService             rpcclient.RpcClientConnection
 	aliasService            rpcclient.RpcClientConnection
 	schedCdrsConns          rpcclient.RpcClientConnection
+	filterS                 *FilterS                      // used by actions to check their FilterIDs
+	schedulerS              rpcclient.RpcClientConnection // used by actions to queue the ActionPlans they create
+	auditAccounts           bool                          // store the balance changes in StorDB
 	rpSubjectPrefixMatching bool
 )
 
@@ -79,6 +82,14 @@ func SetStatS(stsS rpcclient.RpcClientConnection) {
 	statS = stsS
 }
 
+func SetFilterS(fS *FilterS) {
+	filterS = fS
+}
+
+func SetSchedulerS(schdS rpcclient.RpcClientConnection) {
+	schedulerS = schdS
+}
+
 // Sets the global rounding method and decimal precision for GetCost method
 func SetRoundingDecimals(rd int) {
 	globalRoundingDecimals = rd
@@ -88,6 +99,11 @@ func SetRpSubjectPrefixMatching(flag bool) {
 	rpSubjectPrefixMatching = flag
 }
 
+// SetAuditAccounts enables storing the balance changes in StorDB
+func SetAuditAccounts(flag bool) {
+	auditAccounts = flag
+}
+
 /*
 Sets the database for CDR storing, used by *cdrlog in first place
 */
@@ -191,8 +207,12 @@ type CallDescriptor struct {
 	PerformRounding     bool // flag for rating info rounding
 	DryRun              bool
 	DenyNegativeAccount bool // prevent account going on negative during debit
+	DeferCallLimits     bool // MinCost and FreeUsage are applied by the caller on the totals of the call (eg: sessions debiting in chunks)
 	account             *Account
-	testCallcost        *CallCost // testing purpose only!
+	tierUsages          map[string]time.Duration // usage of the account within billing periods, consulted by tiered rates
+	ratingDM            *DataManager             // rating data source other than the global one, used in simulations
+	ratingTransID       string                   // cache transaction isolating the lookups on ratingDM
+	testCallcost        *CallCost                // testing purpose only!
 }
 
 // AsCGREvent converts the CallDescriptor into CGREvent
@@ -299,6 +319,14 @@ func (cd *CallDescriptor) getAccount() (ub *Account, err error) {
 	return cd.account, err
 }
 
+// ratingDataManager returns the DataManager to query rating data from, together with the caching options
+func (cd *CallDescriptor) ratingDataManager() (rDM *DataManager, skipCache bool, transID string) {
+	if cd.ratingDM == nil {
+		return dm, false, utils.NonTransactional
+	}
+	return cd.ratingDM, true, cd.ratingTransID
+}
+
 /*
 Restores the activation periods for the specified prefix from storage.
 */
@@ -329,7 +357,7 @@ func (cd *CallDescriptor) getRatingPlansForPrefix(key string, recursionDepth int
 	if recursionDepth > RECURSION_MAX_DEPTH {
 		return utils.ErrMaxRecursionDepth, recursionDepth
 	}
-	rpf, err := RatingProfileSubjectPrefixMatching(key)
+	rpf, err := ratingProfileSubjectPrefixMatching(cd, key)
 	if err != nil || rpf == nil {
 		return utils.ErrNotFound, recursionDepth
 	}
@@ -344,9 +372,11 @@ func (cd *CallDescriptor) getRatingPlansForPrefix(key string, recursionDepth int
 			}
 			if len(ri.FallbackKeys) > 0 {
 				tempCD := &CallDescriptor{
-					Category:    cd.Category,
-					Tenant:      cd.Tenant,
-					Destination: cd.Destination,
+					Category:      cd.Category,
+					Tenant:        cd.Tenant,
+					Destination:   cd.Destination,
+					ratingDM:      cd.ratingDM,
+					ratingTransID: cd.ratingTransID,
 				}
 				if index == 0 {
 					tempCD.TimeStart = cd.TimeStart
@@ -447,7 +477,7 @@ func (cd *CallDescriptor) GetAccountKey() string {
 // Splits the received timespan into sub time spans according to the activation periods intervals.
 func (cd *CallDescriptor) splitInTimeSpans() (timespans []*TimeSpan) {
 	firstSpan := &TimeSpan{TimeStart: cd.TimeStart, TimeEnd: cd.TimeEnd,
-		DurationIndex: cd.DurationIndex}
+		DurationIndex: cd.DurationIndex, tierUsages: cd.tierUsages}
 
 	timespans = append(timespans, firstSpan)
 	if len(cd.RatingInfos) == 0 {
@@ -565,49 +595,90 @@ func (cd *CallDescriptor) GetDuration() time.Duration {
 	return cd.TimeEnd.Sub(cd.TimeStart)
 }
 
+// ratingCurrency returns the currency of the rates matching the start of the call
+func (cd *CallDescriptor) ratingCurrency() string {
+	if err := cd.LoadRatingPlans(); err != nil || len(cd.RatingInfos) == 0 {
+		return ""
+	}
+	for _, ri := range cd.RatingInfos[0].RateIntervals {
+		if ri.Rating != nil && ri.Contains(cd.TimeStart, false) {
+			return ri.Rating.Currency
+		}
+	}
+	return ""
+}
+
 /*
 Creates a CallCost structure with the cost information calculated for the received CallDescriptor.
 */
 func (cd *CallDescriptor) GetCost() (*CallCost, error) {
 	cd.account = nil // make sure it's not cached
+	cd.tierUsages = nil
 	cc, err := cd.getCost()
 	if err != nil || cd.GetDuration() == 0 {
 		return cc, err
 	}
 
-	cost := 0.0
+	cc.Timespans.Decompress()
+	cost := utils.NewDecimalFromInt64(0)
 	for i, ts := range cc.Timespans {
 		// only add connect fee if this is the first/only call cost request
-		//log.Printf("Interval: %+v", ts.RateInterval.Timing)
 		if cd.LoopIndex == 0 && i == 0 && ts.RateInterval != nil {
-			cost += ts.RateInterval.Rating.ConnectFee
+			cost = cost.Add(utils.NewDecimalFromFloat64(ts.RateInterval.Rating.ConnectFee))
 		}
-		//log.Printf("TS: %+v", ts)
 		// handle max cost
 		maxCost, strategy := ts.RateInterval.GetMaxCost()
-
+		incrs := make(Increments, len(ts.Increments))
+		for j, incr := range ts.Increments {
+			incrs[j] = incr.Clone() // decompressed timespans share the increments
+			if strategy == utils.MAX_COST_FREE {
+				incrs[j].Cost = cd.capCost(incr.Cost, maxCost)
+			}
+			cd.MaxCostSoFar = utils.NewDecimalFromFloat64(cd.MaxCostSoFar).
+				Add(utils.NewDecimalFromFloat64(incrs[j].Cost)).Float64()
+		}
+		ts.Increments = incrs
 		ts.Cost = ts.CalculateCost()
-		cost += ts.Cost
-		cd.MaxCostSoFar += cost
-		//log.Print("Before: ", cost)
-		if strategy != "" && maxCost > 0 {
-			//log.Print("HERE: ", strategy, maxCost)
-			if strategy == utils.MAX_COST_FREE && cd.MaxCostSoFar >= maxCost {
-				cost = maxCost
-				cd.MaxCostSoFar = maxCost
+		cost = cost.Add(utils.NewDecimalFromFloat64(ts.Cost))
+	}
+	cc.Cost = cost.Float64()
+	if cd.LoopIndex == 0 && !cd.DeferCallLimits {
+		if free, minCostDiff := cc.callLimits(cc.Cost); free {
+			for _, ts := range cc.Timespans {
+				for _, incr := range ts.Increments {
+					incr.Cost = 0
+				}
+				ts.Cost = 0
 			}
-
+			cc.Cost = 0
+		} else if minCostDiff != 0 {
+			lastTS := cc.Timespans[len(cc.Timespans)-1]
+			lastTS.Increments = append(lastTS.Increments,
+				&Increment{Cost: minCostDiff, BalanceInfo: &DebitInfo{}})
+			lastTS.Cost = lastTS.CalculateCost()
+			cc.Cost = utils.NewDecimalFromFloat64(cc.Cost).
+				Add(utils.NewDecimalFromFloat64(minCostDiff)).Float64()
 		}
-		//log.Print("Cost: ", cost)
 	}
-	cc.Cost = cost
 	// global rounding
 	roundingDecimals, roundingMethod := cc.GetLongestRounding()
 	cc.Cost = utils.Round(cc.Cost, roundingDecimals, roundingMethod)
-
+	cc.Timespans.Compress()
 	return cc, nil
 }
 
+// capCost returns the part of cost which can still be charged before reaching maxCost
+func (cd *CallDescriptor) capCost(cost, maxCost float64) float64 {
+	left := utils.NewDecimalFromFloat64(maxCost).Sub(utils.NewDecimalFromFloat64(cd.MaxCostSoFar)).Float64()
+	if left <= 0 {
+		return 0
+	}
+	if left < cost {
+		return left
+	}
+	return cost
+}
+
 func (cd *CallDescriptor) getCost() (*CallCost, error) {
 	// check for 0 duration
 	if cd.GetDuration() == 0 {
@@ -634,6 +705,13 @@ func (cd *CallDescriptor) getCost() (*CallCost, error) {
 	if err != nil {
 		return &CallCost{Cost: -1}, err
 	}
+	if cd.tierUsages == nil {
+		acnt := cd.account
+		if acnt == nil {
+			acnt, _ = dm.DataDB().GetAccount(cd.GetAccountKey())
+		}
+		cd.setTierUsages(acnt)
+	}
 	timespans := cd.splitInTimeSpans()
 	cost := 0.0
 
@@ -731,6 +809,7 @@ func (origCD *CallDescriptor) getMaxSessionDuration(origAcc *Account) (time.Dura
 
 func (cd *CallDescriptor) GetMaxSessionDuration() (duration time.Duration, err error) {
 	cd.account = nil // make sure it's not cached
+	cd.tierUsages = nil
 	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
 		account, err := cd.getAccount()
 		if err != nil {
@@ -775,6 +854,13 @@ func (cd *CallDescriptor) debit(account *Account, dryRun bool, goNegative bool)
 	if cd.TOR == "" {
 		cd.TOR = utils.VOICE
 	}
+	if cd.tierUsages == nil {
+		cd.setTierUsages(account)
+	}
+	if !dryRun {
+		prevCause := account.auditAs(&auditCause{cause: utils.MetaDebit, cgrID: cd.CgrID})
+		defer account.endAudit(prevCause)
+	}
 	//log.Printf("Debit CD: %+v", cd)
 	cc, err = account.debitCreditBalance(cd, !dryRun, dryRun, goNegative)
 	//log.Printf("HERE: %+v %v", cc, err)
@@ -784,9 +870,12 @@ func (cd *CallDescriptor) debit(account *Account, dryRun bool, goNegative bool)
 	}
 	cc.updateCost()
 	cc.UpdateRatedUsage()
+	if !dryRun {
+		account.countTierUsage(cc)
+	}
 	cc.Timespans.Compress()
 	if !dryRun {
-		dm.DataDB().SetAccount(account)
+		setAuditedAccount(account)
 	}
 	if cd.PerformRounding {
 		cc.Round()
@@ -803,6 +892,7 @@ func (cd *CallDescriptor) debit(account *Account, dryRun bool, goNegative bool)
 
 func (cd *CallDescriptor) Debit() (cc *CallCost, err error) {
 	cd.account = nil // make sure it's not cached
+	cd.tierUsages = nil
 	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
 		// lock all group members
 		account, err := cd.getAccount()
@@ -837,6 +927,7 @@ func (cd *CallDescriptor) Debit() (cc *CallCost, err error) {
 // by the GetMaxSessionDuration method. The amount filed has to be filled in call descriptor.
 func (cd *CallDescriptor) MaxDebit() (cc *CallCost, err error) {
 	cd.account = nil // make sure it's not cached
+	cd.tierUsages = nil
 	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
 		account, err := cd.getAccount()
 		if err != nil {
@@ -900,6 +991,78 @@ func (cd *CallDescriptor) MaxDebit() (cc *CallCost, err error) {
 	return cc, err
 }
 
+// debitMinCost has no locks
+// debits the Cost of the increments as the part missing up to the MinCost of the call
+func (cd *CallDescriptor) debitMinCost(account *Account) (cc *CallCost, err error) {
+	minCostDiff := utils.NewDecimalFromInt64(0)
+	for _, incr := range cd.Increments {
+		minCostDiff = minCostDiff.Add(utils.NewDecimalFromFloat64(incr.Cost))
+	}
+	if err = cd.LoadRatingPlans(); err != nil {
+		return
+	}
+	ts := &TimeSpan{
+		TimeStart:      cd.TimeStart,
+		TimeEnd:        cd.TimeStart,
+		CompressFactor: 1,
+	}
+	if len(cd.RatingInfos) != 0 {
+		ts.setRatingInfo(cd.RatingInfos[0])
+		for _, ri := range cd.RatingInfos[0].RateIntervals {
+			if ri.Contains(cd.TimeStart, false) {
+				ts.SetRateInterval(ri)
+			}
+		}
+	}
+	if ts.RateInterval == nil { // no currency to debit the MinCost in
+		return nil, utils.ErrRatingPlanNotFound
+	}
+	cc = cd.CreateCallCost()
+	cc.Timespans = TimeSpans{ts}
+	prevCause := account.auditAs(&auditCause{cause: utils.MetaDebit, cgrID: cd.CgrID})
+	defer account.endAudit(prevCause)
+	moneyBalances := account.getAlldBalancesForPrefix(cd.Destination, cd.Category, utils.MONETARY)
+	if err = account.debitMinCost(cc, moneyBalances, minCostDiff.Float64(),
+		!cd.DenyNegativeAccount, true); err != nil {
+		return nil, err
+	}
+	moneyBalances.SaveDirtyBalances(account)
+	cc.updateCost()
+	setAuditedAccount(account)
+	return
+}
+
+// DebitMinCost debits the cost missing up to the MinCost of a call rated in chunks
+// the amount to debit is passed as the Cost of the Increments
+func (cd *CallDescriptor) DebitMinCost() (cc *CallCost, err error) {
+	cd.account = nil // make sure it's not cached
+	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+		// lock all group members
+		account, err := cd.getAccount()
+		if err != nil {
+			return nil, err
+		}
+		acntIDs, sgerr := account.GetUniqueSharedGroupMembers(cd)
+		if sgerr != nil {
+			return nil, sgerr
+		}
+		var lkIDs []string
+		for acntID := range acntIDs {
+			if acntID != cd.GetAccountKey() {
+				lkIDs = append(lkIDs, utils.ACCOUNT_PREFIX+acntID)
+			}
+		}
+		_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+			if cc, err = cd.debitMinCost(account); err == nil {
+				cc.AccountSummary = cd.AccountSummary()
+			}
+			return
+		}, config.CgrConfig().GeneralCfg().LockingTimeout, lkIDs...)
+		return
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.ACCOUNT_PREFIX+cd.GetAccountKey())
+	return
+}
+
 // refundIncrements has no locks
 // returns the updated account referenced by the CallDescriptor
 func (cd *CallDescriptor) refundIncrements() (acnt *Account, err error) {
@@ -910,8 +1073,12 @@ func (cd *CallDescriptor) refundIncrements() (acnt *Account, err error) {
 			if acc, err := dm.DataDB().GetAccount(increment.BalanceInfo.AccountID); err == nil && acc != nil {
 				account = acc
 				accountsCache[increment.BalanceInfo.AccountID] = account
+				account.auditAs(&auditCause{cause: utils.MetaRefund, cgrID: cd.CgrID})
 				// will save the account only once at the end of the function
-				defer dm.DataDB().SetAccount(account)
+				defer func(acc *Account) {
+					acc.endAudit(nil)
+					setAuditedAccount(acc)
+				}(account)
 			}
 		}
 		if account == nil {
@@ -929,13 +1096,15 @@ func (cd *CallDescriptor) refundIncrements() (acnt *Account, err error) {
 			balance.AddValue(float64(increment.Duration.Nanoseconds()))
 			account.countUnits(-float64(increment.Duration.Nanoseconds()), unitType, cc, balance)
 		}
+		account.refundTierUsage(unitType, increment)
 		// check money too
 		if increment.BalanceInfo.Monetary != nil && increment.BalanceInfo.Monetary.UUID != "" {
 			if balance = account.BalanceMap[utils.MONETARY].GetBalance(increment.BalanceInfo.Monetary.UUID); balance == nil {
 				return
 			}
-			balance.AddValue(increment.Cost)
-			account.countUnits(-increment.Cost, utils.MONETARY, cc, balance)
+			refundValue := convertAmount(increment.Cost, increment.BalanceInfo.Monetary.ExchangeRate)
+			balance.AddValue(refundValue)
+			account.countUnits(-refundValue, utils.MONETARY, cc, balance)
 		}
 	}
 	acnt = accountsCache[utils.ConcatenatedKey(cd.Tenant, cd.Account)]
@@ -973,8 +1142,12 @@ func (cd *CallDescriptor) refundRounding() (err error) {
 			if acc, err := dm.DataDB().GetAccount(increment.BalanceInfo.AccountID); err == nil && acc != nil {
 				account = acc
 				accountsCache[increment.BalanceInfo.AccountID] = account
+				account.auditAs(&auditCause{cause: utils.MetaRefund, cgrID: cd.CgrID})
 				// will save the account only once at the end of the function
-				defer dm.DataDB().SetAccount(account)
+				defer func(acc *Account) {
+					acc.endAudit(nil)
+					setAuditedAccount(acc)
+				}(account)
 			}
 		}
 		if account == nil {
@@ -987,8 +1160,9 @@ func (cd *CallDescriptor) refundRounding() (err error) {
 			if balance = account.BalanceMap[utils.MONETARY].GetBalance(increment.BalanceInfo.Monetary.UUID); balance == nil {
 				return
 			}
-			balance.AddValue(-increment.Cost)
-			account.countUnits(increment.Cost, utils.MONETARY, cc, balance)
+			roundingValue := convertAmount(increment.Cost, increment.BalanceInfo.Monetary.ExchangeRate)
+			balance.AddValue(-roundingValue)
+			account.countUnits(roundingValue, utils.MONETARY, cc, balance)
 		}
 	}
 	return
@@ -1038,8 +1212,12 @@ func (cd *CallDescriptor) Clone() *CallDescriptor {
 		ForceDuration:   cd.ForceDuration,
 		PerformRounding: cd.PerformRounding,
 		DryRun:          cd.DryRun,
+		DeferCallLimits: cd.DeferCallLimits,
 		CgrID:           cd.CgrID,
 		RunID:           cd.RunID,
+		tierUsages:      cd.tierUsages,
+		ratingDM:        cd.ratingDM,
+		ratingTransID:   cd.ratingTransID,
 	}
 }
 
@@ -35,7 +35,7 @@ func NewCDRFromExternalCDR(extCdr *ExternalCDR, timezone string) (*CDR, error) {
 		OriginID: extCdr.OriginID, OriginHost: extCdr.OriginHost,
 		Source: extCdr.Source, RequestType: extCdr.RequestType, Tenant: extCdr.Tenant, Category: extCdr.Category,
 		Account: extCdr.Account, Subject: extCdr.Subject, Destination: extCdr.Destination,
-		CostSource: extCdr.CostSource, Cost: extCdr.Cost, PreRated: extCdr.PreRated}
+		CostSource: extCdr.CostSource, Cost: extCdr.Cost, TaxLines: extCdr.TaxLines, PreRated: extCdr.PreRated}
 	if extCdr.SetupTime != "" {
 		if cdr.SetupTime, err = utils.ParseTimeDetectLayout(extCdr.SetupTime, timezone); err != nil {
 			return nil, err
@@ -104,6 +104,7 @@ type CDR struct {
 	CostSource  string            // The source of this cost
 	Cost        float64           //
 	CostDetails *EventCost        // Attach the cost details to CDR when possible
+	TaxLines    TaxLines          // Taxes charged on the Cost
 }
 
 // AddDefaults will add missing information based on other fields
@@ -463,6 +464,7 @@ func (cdr *CDR) AsExternalCDR() *ExternalCDR {
 		CostSource:  cdr.CostSource,
 		Cost:        cdr.Cost,
 		CostDetails: cdr.CostDetailsJson(),
+		TaxLines:    cdr.TaxLines,
 		ExtraInfo:   cdr.ExtraInfo,
 		PreRated:    cdr.PreRated,
 	}
@@ -665,6 +667,9 @@ func (cdr *CDR) AsCDRsql() (cdrSql *CDRsql) {
 	cdrSql.CostSource = cdr.CostSource
 	cdrSql.Cost = cdr.Cost
 	cdrSql.CostDetails = utils.ToJSON(cdr.CostDetails)
+	if len(cdr.TaxLines) != 0 {
+		cdrSql.TaxLines = utils.ToJSON(cdr.TaxLines)
+	}
 	cdrSql.ExtraInfo = cdr.ExtraInfo
 	cdrSql.CreatedAt = time.Now()
 	return
@@ -778,6 +783,11 @@ func NewCDRFromSQL(cdrSql *CDRsql) (cdr *CDR, err error) {
 			return nil, err
 		}
 	}
+	if cdrSql.TaxLines != "" {
+		if err = json.Unmarshal([]byte(cdrSql.TaxLines), &cdr.TaxLines); err != nil {
+			return nil, err
+		}
+	}
 	return
 }
 
@@ -802,6 +812,7 @@ type ExternalCDR struct {
 	CostSource  string
 	Cost        float64
 	CostDetails string
+	TaxLines    TaxLines
 	ExtraInfo   string
 	PreRated    bool // Mark the CDR as rated so we do not process it during mediation
 }
@@ -95,13 +95,17 @@ func NewCdrServer(cgrCfg *config.CGRConfig, cdrDb CdrStorage, dm *DataManager, r
 	if chargerS != nil && reflect.ValueOf(chargerS).IsNil() {
 		chargerS = nil
 	}
+	var dedup *cdrDeduplicator
+	if len(cgrCfg.CdrsCfg().CDRSDedupRules) != 0 {
+		dedup = newCDRDeduplicator(cgrCfg.CdrsCfg().CDRSDedupRules, dm)
+	}
 	return &CdrServer{cgrCfg: cgrCfg, cdrDb: cdrDb, dm: dm,
 		rals: rater, pubsub: pubsub, attrS: attrs,
 		users: users, aliases: aliases,
 		stats: stats, thdS: thdS,
 		chargerS: chargerS, guard: guardian.Guardian,
 		httpPoster: NewHTTPPoster(cgrCfg.GeneralCfg().HttpSkipTlsVerify,
-			cgrCfg.GeneralCfg().ReplyTimeout), filterS: filterS}, nil
+			cgrCfg.GeneralCfg().ReplyTimeout), filterS: filterS, dedup: dedup}, nil
 }
 
 type CdrServer struct {
@@ -120,6 +124,7 @@ type CdrServer struct {
 	responseCache *utils.ResponseCache
 	httpPoster    *HTTPPoster // used for replication
 	filterS       *FilterS
+	dedup         *cdrDeduplicator // nil when no dedup rules are configured
 }
 
 func (self *CdrServer) Timezone() string {
@@ -191,9 +196,19 @@ func (self *CdrServer) processCdr(cdr *CDR) (err error) {
 	if cdr.RunID == utils.MetaRaw {
 		cdr.Cost = -1.0
 	}
+	var ratingLkID string // released once the CDR is rated
+	if self.dedup != nil {
+		var drop bool
+		if drop, ratingLkID, err = self.dedupCDR(cdr, self.rals != nil && !cdr.PreRated); err != nil {
+			return err
+		} else if drop {
+			return nil
+		}
+	}
 	if self.cgrCfg.CdrsCfg().CDRSStoreCdrs { // Store RawCDRs, this we do sync so we can reply with the status
 		if err := self.cdrDb.SetCDR(cdr, false); err != nil {
 			utils.Logger.Err(fmt.Sprintf("<CDRS> Storing primary CDR %+v, got error: %s", cdr, err.Error()))
+			unguardRating(ratingLkID)
 			return err // Error is propagated back and we don't continue processing the CDR if we cannot store it
 		}
 	}
@@ -210,12 +225,120 @@ func (self *CdrServer) processCdr(cdr *CDR) (err error) {
 		self.replicateCDRs([]*CDR{cdr})
 	}
 	if self.rals != nil && !cdr.PreRated { // CDRs not rated will be processed by Rating
-		go self.deriveRateStoreStatsReplicate(cdr, self.cgrCfg.CdrsCfg().CDRSStoreCdrs,
-			true, len(self.cgrCfg.CdrsCfg().CDRSOnlineCDRExports) != 0)
+		go func() {
+			defer unguardRating(ratingLkID)
+			self.deriveRateStoreStatsReplicate(cdr, self.cgrCfg.CdrsCfg().CDRSStoreCdrs,
+				true, len(self.cgrCfg.CdrsCfg().CDRSOnlineCDRExports) != 0)
+		}()
 	}
 	return nil
 }
 
+// ratingLockID is locked while the CDR with cgrID is rated and stored, so the CDR replacing it finds the cost to refund
+func ratingLockID(cgrID string) string {
+	return utils.ConcatenatedKey(utils.CDRs, utils.MetaRating, cgrID)
+}
+
+// unguardRating releases the rating lock, empty lockID when the CDR was not locked
+func unguardRating(lockID string) {
+	if lockID != "" {
+		guardian.Guardian.UnguardIDs(lockID)
+	}
+}
+
+// dedupCDR checks the CDR against the dedup rules, refunding and removing out of StorDB the CDR it replaces
+// the CDR to be rated by the caller is locked once recorded, ratingLkID being released after rating it
+func (self *CdrServer) dedupCDR(cdr *CDR, rated bool) (drop bool, ratingLkID string, err error) {
+	var guard func()
+	if rated {
+		guard = func() {
+			ratingLkID = ratingLockID(cdr.CGRID)
+			guardian.Guardian.GuardIDs(self.cgrCfg.GeneralCfg().LockingTimeout, ratingLkID)
+		}
+	}
+	if drop, err = self.dedup.process(cdr, self.replaceCDR, guard); err != nil {
+		unguardRating(ratingLkID)
+		ratingLkID = ""
+	}
+	return
+}
+
+// replaceCDR refunds the costs debited for the runs of the CDR with cgrID, removing them out of StorDB
+// so the CDR replacing it is not charged twice
+// the rating of the replaced CDR is waited for, each run being refunded once within ttl so the retries
+// after a partial failure skip the runs refunded already
+func (self *CdrServer) replaceCDR(cgrID string, ttl time.Duration) (err error) {
+	if !self.cgrCfg.CdrsCfg().CDRSStoreCdrs {
+		utils.Logger.Warning(fmt.Sprintf("<%s> cannot refund the replaced CDR: %s, CDRs are not stored",
+			utils.CDRs, cgrID))
+		return
+	}
+	lkID := ratingLockID(cgrID)
+	guardian.Guardian.GuardIDs(self.cgrCfg.GeneralCfg().LockingTimeout, lkID)
+	defer guardian.Guardian.UnguardIDs(lkID)
+	cdrs, _, err := self.cdrDb.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{cgrID}}, false)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	for _, cdr := range cdrs {
+		var reply string
+		if err = ProcessRequestOnce("Responder.RefundIncrements", utils.AccountKey(cdr.Tenant, cdr.Account),
+			utils.ConcatenatedKey(cdr.CGRID, cdr.RunID), ttl, &reply, func() error {
+				reply = utils.OK
+				return self.refundCDR(cdr)
+			}); err != nil {
+			utils.Logger.Err(fmt.Sprintf("<%s> refunding CDR: %s, run: %s, got error: %s",
+				utils.CDRs, cdr.CGRID, cdr.RunID, err.Error()))
+			return
+		}
+	}
+	if _, _, err = self.cdrDb.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{cgrID}}, true); err == utils.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+// refundCDR gives back to the accounts the cost debited for the rated CDR
+func (self *CdrServer) refundCDR(cdr *CDR) (err error) {
+	if self.rals == nil || cdr.CostDetails == nil ||
+		!utils.IsSliceMember([]string{utils.META_PSEUDOPREPAID, utils.META_POSTPAID, utils.META_PREPAID,
+			utils.PSEUDOPREPAID, utils.POSTPAID, utils.PREPAID}, cdr.RequestType) { // only these were debited
+		return
+	}
+	cc := cdr.CostDetails.AsCallCost()
+	var incrmts Increments
+	for _, tmspn := range cc.Timespans {
+		tmspn.SetTierPeriod() // so the tier counters can be refunded
+		for _, incr := range tmspn.Increments {
+			if incr.BalanceInfo == nil ||
+				(incr.BalanceInfo.Unit == nil &&
+					incr.BalanceInfo.Monetary == nil) {
+				continue // not enough information for refunds, most probably free units uncounted
+			}
+			incrmts = append(incrmts, incr)
+		}
+	}
+	if len(incrmts) == 0 {
+		return
+	}
+	cd := &CallDescriptor{
+		CgrID:       cdr.CGRID,
+		RunID:       cdr.RunID,
+		Category:    cdr.Category,
+		Tenant:      cdr.Tenant,
+		Subject:     cdr.Subject,
+		Account:     cdr.Account,
+		Destination: cdr.Destination,
+		TOR:         cdr.ToR,
+		Increments:  incrmts,
+	}
+	var acnt Account
+	return self.rals.Call("Responder.RefundIncrements", cd, &acnt)
+}
+
 // Returns error if not able to properly store the CDR, mediation is async since we can always recover offline
 func (self *CdrServer) deriveRateStoreStatsReplicate(cdr *CDR, store, cdrstats, replicate bool) (err error) {
 	cdrRuns, err := self.deriveCdrs(cdr)
@@ -274,6 +397,9 @@ func (self *CdrServer) deriveRateStoreStatsReplicate(cdr *CDR, store, cdrstats,
 			}
 		}
 	}
+	if self.cgrCfg.CdrsCfg().CDRSTaxes {
+		self.taxCDRs(ratedCDRs)
+	}
 	// Store rated CDRs
 	if store {
 		for _, ratedCDR := range ratedCDRs {
@@ -394,27 +520,21 @@ func (self *CdrServer) rateCDR(cdr *CDR) ([]*CDR, error) {
 		cdr.Usage = time.Duration(0)
 	}
 	cdr.ExtraInfo = "" // Clean previous ExtraInfo, useful when re-rating
+	if sbStr := cdr.ExtraFields[utils.CGRSplitBilling]; sbStr != "" &&
+		cdr.RequestType != utils.META_RATED {
+		return self.rateSplitCDR(cdr, sbStr)
+	}
 	var cdrsRated []*CDR
 	_, hasLastUsed := cdr.ExtraFields[utils.LastUsed]
 	if utils.IsSliceMember([]string{utils.META_PREPAID, utils.PREPAID}, cdr.RequestType) &&
 		(cdr.Usage != 0 || hasLastUsed) { // ToDo: Get rid of PREPAID as soon as we don't want to support it backwards
 		// Should be previously calculated and stored in DB
-		fib := utils.Fib()
-		var smCosts []*SMCost
 		cgrID := cdr.CGRID
 		if _, hasIT := cdr.ExtraFields[utils.OriginIDPrefix]; hasIT {
 			cgrID = "" // for queries involving originIDPrefix we ignore CGRID
 		}
-		for i := 0; i < self.cgrCfg.CdrsCfg().CDRSSMCostRetries; i++ {
-			smCosts, err = self.cdrDb.GetSMCosts(cgrID, cdr.RunID, cdr.OriginHost,
-				cdr.ExtraFields[utils.OriginIDPrefix])
-			if err == nil && len(smCosts) != 0 {
-				break
-			}
-			if i != 3 {
-				time.Sleep(time.Duration(fib()) * time.Second)
-			}
-		}
+		smCosts, _ := self.getSMCosts(cgrID, cdr.RunID, cdr.OriginHost,
+			cdr.ExtraFields[utils.OriginIDPrefix], nil)
 		if len(smCosts) != 0 { // Cost retrieved from SMCost table
 			for _, smCost := range smCosts {
 				cdrClone := cdr.Clone()
@@ -447,15 +567,88 @@ func (self *CdrServer) rateCDR(cdr *CDR) ([]*CDR, error) {
 	return []*CDR{cdr}, nil
 }
 
-// Retrive the cost from engine
-func (self *CdrServer) getCostFromRater(cdr *CDR) (*CallCost, error) {
-	cc := new(CallCost)
-	var err error
+// getSMCosts queries the costs stored by SessionS, retrying since they might not be stored yet
+// filter, when present, selects the costs to be returned out of the queried ones
+func (self *CdrServer) getSMCosts(cgrID, runID, originHost, originIDPrfx string,
+	filter func(*SMCost) bool) (smCosts []*SMCost, err error) {
+	fib := utils.Fib()
+	retries := self.cgrCfg.CdrsCfg().CDRSSMCostRetries
+	for i := 0; i < retries; i++ {
+		var qrySMCs []*SMCost
+		if qrySMCs, err = self.cdrDb.GetSMCosts(cgrID, runID, originHost, originIDPrfx); err == nil {
+			smCosts = smCosts[:0]
+			for _, smCost := range qrySMCs {
+				if filter == nil || filter(smCost) {
+					smCosts = append(smCosts, smCost)
+				}
+			}
+			if len(smCosts) != 0 {
+				break
+			}
+		}
+		if i != retries-1 {
+			time.Sleep(time.Duration(fib()) * time.Second)
+		}
+	}
+	return
+}
+
+// rateSplitCDR rates the CDR of a split billed run, returning one CDR for each payer
+// costs are taken out of the SMCosts stored by SessionS or debited now via RALs
+func (self *CdrServer) rateSplitCDR(cdr *CDR, sbStr string) (cdrsRated []*CDR, err error) {
+	if _, err = NewSplitBillingFromString(sbStr); err != nil {
+		return
+	}
+	runPrfx := cdr.RunID + utils.CONCATENATED_KEY_SEP
+	var smCosts []*SMCost
+	if utils.IsSliceMember([]string{utils.META_PREPAID, utils.PREPAID}, cdr.RequestType) {
+		smCosts, _ = self.getSMCosts(cdr.CGRID, "", cdr.OriginHost, "",
+			func(smCost *SMCost) bool { return strings.HasPrefix(smCost.RunID, runPrfx) })
+	}
+	if len(smCosts) != 0 {
+		for _, smCost := range smCosts {
+			cdrsRated = append(cdrsRated, splitPayerCDR(cdr, smCost.RunID,
+				smCost.CostDetails, smCost.CostSource))
+		}
+		return
+	}
+	var ccs map[string]*CallCost
+	cd := cdrCallDescriptor(cdr)
+	cd.ExtraFields = cdr.ExtraFields
+	if err = self.rals.Call("Responder.SplitDebit", cd, &ccs); err != nil {
+		return nil, err
+	}
+	for acntKey, cc := range ccs {
+		runID := SplitBillingRunID(cdr.RunID, acntKey)
+		cdrsRated = append(cdrsRated, splitPayerCDR(cdr, runID,
+			NewEventCostFromCallCost(cc, cdr.CGRID, runID), utils.MetaCDRs))
+	}
+	return
+}
+
+// splitPayerCDR returns the CDR charged to one payer of a split billed run
+func splitPayerCDR(cdr *CDR, runID string, ec *EventCost, costSource string) (pyrCDR *CDR) {
+	pyrCDR = cdr.Clone()
+	pyrCDR.RunID = runID
+	if acntSplt := strings.SplitN(strings.TrimPrefix(runID,
+		cdr.RunID+utils.CONCATENATED_KEY_SEP), utils.CONCATENATED_KEY_SEP, 2); len(acntSplt) == 2 {
+		pyrCDR.Tenant, pyrCDR.Account = acntSplt[0], acntSplt[1]
+	}
+	ec.Compute()
+	pyrCDR.Usage = ec.GetUsage()
+	pyrCDR.Cost = ec.GetCost()
+	pyrCDR.CostDetails = ec
+	pyrCDR.CostSource = costSource
+	return
+}
+
+// cdrCallDescriptor builds the CallDescriptor used to rate the CDR
+func cdrCallDescriptor(cdr *CDR) *CallDescriptor {
 	timeStart := cdr.AnswerTime
 	if timeStart.IsZero() { // Fix for FreeSWITCH unanswered calls
 		timeStart = cdr.SetupTime
 	}
-	cd := &CallDescriptor{
+	return &CallDescriptor{
 		TOR:             cdr.ToR,
 		Tenant:          cdr.Tenant,
 		Category:        cdr.Category,
@@ -467,6 +660,13 @@ func (self *CdrServer) getCostFromRater(cdr *CDR) (*CallCost, error) {
 		DurationIndex:   cdr.Usage,
 		PerformRounding: true,
 	}
+}
+
+// Retrive the cost from engine
+func (self *CdrServer) getCostFromRater(cdr *CDR) (*CallCost, error) {
+	cc := new(CallCost)
+	var err error
+	cd := cdrCallDescriptor(cdr)
 	if utils.IsSliceMember([]string{utils.META_PSEUDOPREPAID, utils.META_POSTPAID, utils.META_PREPAID,
 		utils.PSEUDOPREPAID, utils.POSTPAID, utils.PREPAID}, cdr.RequestType) { // Prepaid - Cost can be recalculated in case of missing records from SM
 		err = self.rals.Call("Responder.Debit", cd, cc)
@@ -596,6 +796,7 @@ func (cdrs *CdrServer) V2StoreSMCost(args ArgsV2CDRSStoreSMCost, reply *string)
 		CostSource:  args.Cost.CostSource,
 		Usage:       args.Cost.Usage,
 		CostDetails: args.Cost.CostDetails,
+		History:     args.Cost.History,
 	}, args.CheckDuplicate); err != nil {
 		cdrs.getCache().Cache(cacheKey, &utils.ResponseCacheItem{Err: err})
 		return utils.NewErrServerError(err)
@@ -696,15 +897,23 @@ func (cdrS *CdrServer) raStoReThStaCDR(cdr *CDR) {
 		cdr.ExtraInfo = err.Error()
 		ratedCDRs = []*CDR{cdr}
 	}
+	if cdrS.cgrCfg.CdrsCfg().CDRSTaxes {
+		cdrS.taxCDRs(ratedCDRs)
+	}
 	for _, rtCDR := range ratedCDRs {
 		if cdrS.cgrCfg.CdrsCfg().CDRSStoreCdrs { // Store CDR
-			go func(rtCDR *CDR) {
+			storeCDR := func(rtCDR *CDR) {
 				if err := cdrS.cdrDb.SetCDR(rtCDR, true); err != nil {
 					utils.Logger.Warning(
 						fmt.Sprintf("<%s> error: %s storing CDR  %+v.",
 							utils.CDRs, err.Error(), rtCDR))
 				}
-			}(rtCDR)
+			}
+			if cdrS.dedup != nil { // stored under the rating lock so the CDR replacing it finds the cost
+				storeCDR(rtCDR)
+			} else {
+				go storeCDR(rtCDR)
+			}
 		}
 		if len(cdrS.cgrCfg.CdrsCfg().CDRSOnlineCDRExports) != 0 {
 			go cdrS.replicateCDRs([]*CDR{rtCDR})
@@ -719,6 +928,18 @@ func (cdrS *CdrServer) raStoReThStaCDR(cdr *CDR) {
 	}
 }
 
+// taxCDRs populates the TaxLines of the rated CDRs out of the matching TaxProfiles
+func (cdrS *CdrServer) taxCDRs(cdrs []*CDR) {
+	for _, cdr := range cdrs {
+		if err := TaxCDR(cdrS.dm, cdrS.filterS, cdr,
+			cdrS.cgrCfg.FilterSCfg().IndexedSelects); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s computing taxes for CDR %+v.",
+					utils.CDRs, err.Error(), cdr))
+		}
+	}
+}
+
 // chrgrSProcessEvent will process the CGREvent with ChargerS subsystem
 func (cdrS *CdrServer) chrgrSProcessEvent(cgrEv *utils.CGREvent) {
 	var chrgrs []*ChrgSProcessEventReply
@@ -774,8 +995,19 @@ func (cdrS *CdrServer) V2ProcessCDR(cgrEv *utils.CGREvent, reply *string) (err e
 	if cdrS.chargerS == nil { // backwards compatibility for DerivedChargers
 		return cdrS.V1ProcessCDR(rawCDR, reply)
 	}
+	var ratingLkID string // released once the CDR is rated
+	if cdrS.dedup != nil {
+		var drop bool
+		if drop, ratingLkID, err = cdrS.dedupCDR(rawCDR, cdrS.chargerS != nil); err != nil {
+			return utils.NewErrServerError(err)
+		} else if drop {
+			*reply = utils.OK
+			return nil
+		}
+	}
 	if cdrS.cgrCfg.CdrsCfg().CDRSStoreCdrs { // Store *raw CDR
 		if err = cdrS.cdrDb.SetCDR(rawCDR, false); err != nil {
+			unguardRating(ratingLkID)
 			return utils.NewErrServerError(err) // Cannot store CDR
 		}
 	}
@@ -789,7 +1021,10 @@ func (cdrS *CdrServer) V2ProcessCDR(cgrEv *utils.CGREvent, reply *string) (err e
 		go cdrS.statSProcessEvent(cgrEv)
 	}
 	if cdrS.chargerS != nil {
-		go cdrS.chrgrSProcessEvent(cgrEv)
+		go func() {
+			defer unguardRating(ratingLkID)
+			cdrS.chrgrSProcessEvent(cgrEv)
+		}()
 	}
 	*reply = utils.OK
 	return nil
@@ -849,3 +1084,37 @@ func (self *CdrServer) V1CountCDRs(args utils.RPCCDRsFilter, cnt *int64) error {
 	}
 	return nil
 }
+
+// V1GetDedupStats returns the counters of the duplicated CDRs, indexed on dedup rule ID
+func (self *CdrServer) V1GetDedupStats(ignore string, reply *map[string]*CDRDedupStats) error {
+	if self.dedup == nil {
+		return utils.ErrNotFound
+	}
+	*reply = self.dedup.getStats()
+	return nil
+}
+
+// ArgsGetSMCosts filters the SMCosts returned by V1GetSMCosts
+type ArgsGetSMCosts struct {
+	CGRID          string
+	RunID          string
+	OriginHost     string
+	OriginIDPrefix string
+}
+
+// V1GetSMCosts returns SMCosts from DB
+func (self *CdrServer) V1GetSMCosts(args ArgsGetSMCosts, smCosts *[]*SMCost) error {
+	if args.CGRID == "" && args.OriginHost == "" && args.OriginIDPrefix == "" {
+		return utils.NewErrMandatoryIeMissing(utils.CGRID)
+	}
+	qrySMCs, err := self.cdrDb.GetSMCosts(args.CGRID, args.RunID,
+		args.OriginHost, args.OriginIDPrefix)
+	if err != nil {
+		if err.Error() != utils.NotFoundCaps {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*smCosts = qrySMCs
+	return nil
+}
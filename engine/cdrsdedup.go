@@ -0,0 +1,192 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// CDRDedupStats counts the duplicated CDRs detected by one dedup rule
+type CDRDedupStats struct {
+	Duplicates int64 // duplicated CDRs received
+	Dropped    int64 // duplicates not processed
+	Replaced   int64 // CDRs replaced by their duplicates
+}
+
+// CDRDedupRecord is the CDR kept for one identity within the window of a dedup rule, stored in DataDB
+type CDRDedupRecord struct {
+	ID     string // dedup rule ID, RunID and identity of the CDR
+	CGRID  string
+	Usage  time.Duration
+	Expiry time.Time // end of the window opened by the first CDR with the identity
+}
+
+// Expired checks if the window of the record passed
+func (rec *CDRDedupRecord) Expired(now time.Time) bool {
+	return now.After(rec.Expiry)
+}
+
+// dedupMatch is the identity of the CDR out of one rule
+type dedupMatch struct {
+	rule *config.CDRDedupRule
+	key  string
+}
+
+func newCDRDeduplicator(rules []*config.CDRDedupRule, dm *DataManager) (dd *cdrDeduplicator) {
+	dd = &cdrDeduplicator{
+		rules: rules,
+		dm:    dm,
+		stats: make(map[string]*CDRDedupStats),
+	}
+	for _, rule := range rules {
+		dd.stats[rule.ID] = new(CDRDedupStats)
+	}
+	return
+}
+
+// cdrDeduplicator detects the duplicated CDRs based on the dedup rules
+// the identities are recorded in DataDB so they survive the restarts
+type cdrDeduplicator struct {
+	sync.Mutex // protects the stats
+	rules      []*config.CDRDedupRule
+	dm         *DataManager
+	stats      map[string]*CDRDedupStats // indexed on rule ID
+}
+
+// identity returns the values of the rule fields within the CDR
+func (dd *cdrDeduplicator) identity(rule *config.CDRDedupRule, cdr *CDR) (string, error) {
+	vals := make([]string, len(rule.Fields))
+	for i, fld := range rule.Fields {
+		val, err := cdr.FieldAsStringWithRSRField(fld)
+		if err != nil {
+			return "", err
+		}
+		vals[i] = val
+	}
+	return utils.ConcatenatedKey(rule.ID, cdr.RunID, strings.Join(vals, utils.INFIELD_SEP)), nil
+}
+
+// process checks the CDR against the identities recorded, returning whether it should be dropped
+// the first rule with the CDR identity recorded within the window decides, the records of the other rules
+// pointing to the replaced CDR are moved to the CDR replacing it
+// replace receives the CGRID of the CDR to be replaced and the time left out of its window,
+// its error leaving the records untouched
+// guard, when not nil, is called before recording the CDR, still under the identity locks,
+// so the CDRs replacing it can wait for its rating
+func (dd *cdrDeduplicator) process(cdr *CDR, replace func(rplcCGRID string, ttl time.Duration) error,
+	guard func()) (drop bool, err error) {
+	var matches []*dedupMatch
+	for _, rule := range dd.rules {
+		key, err := dd.identity(rule, cdr)
+		if err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> error: %s computing identity of CDR: %s for dedup rule: %s",
+				utils.CDRs, err.Error(), cdr.CGRID, rule.ID))
+			continue
+		}
+		matches = append(matches, &dedupMatch{rule: rule, key: key})
+	}
+	if len(matches) == 0 {
+		return
+	}
+	lockIDs := make([]string, len(matches))
+	for i, m := range matches {
+		lockIDs[i] = utils.CDRDedupPrefix + m.key
+	}
+	guardian.Guardian.GuardIDs(config.CgrConfig().GeneralCfg().LockingTimeout, lockIDs...)
+	defer guardian.Guardian.UnguardIDs(lockIDs...)
+	now := time.Now()
+	recs := make([]*CDRDedupRecord, len(matches)) // nil for the identities not recorded
+	for i, m := range matches {
+		if recs[i], err = dd.dm.GetCDRDedupRecord(m.key); err == utils.ErrNotFound {
+			recs[i], err = nil, nil
+		} else if err != nil {
+			return
+		}
+	}
+	var rplcCGRID string
+	for i, m := range matches {
+		rec := recs[i]
+		if rec == nil {
+			continue
+		}
+		if m.rule.Action == utils.MetaDrop ||
+			(m.rule.Action == utils.MetaKeepLongest && cdr.Usage <= rec.Usage) {
+			dd.countDuplicate(m.rule.ID, true)
+			utils.Logger.Info(fmt.Sprintf("<%s> dropping CDR: %s, duplicate of: %s on rule: %s",
+				utils.CDRs, cdr.CGRID, rec.CGRID, m.rule.ID))
+			return true, nil
+		}
+		if err = replace(rec.CGRID, rec.Expiry.Sub(now)); err != nil {
+			return
+		}
+		dd.countDuplicate(m.rule.ID, false)
+		utils.Logger.Info(fmt.Sprintf("<%s> CDR: %s replacing its duplicate: %s on rule: %s",
+			utils.CDRs, cdr.CGRID, rec.CGRID, m.rule.ID))
+		rplcCGRID = rec.CGRID
+		break
+	}
+	if guard != nil {
+		guard()
+	}
+	for i, m := range matches {
+		rec := recs[i]
+		if rec == nil {
+			rec = &CDRDedupRecord{ID: m.key, Expiry: now.Add(m.rule.Window)}
+		} else if rec.CGRID != rplcCGRID { // pointing to a CDR not replaced
+			continue
+		}
+		rec.CGRID, rec.Usage = cdr.CGRID, cdr.Usage // window stays the one of the first CDR
+		if err = dd.dm.SetCDRDedupRecord(rec, rec.Expiry.Sub(now)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// countDuplicate counts one duplicate of the rule, either dropped or replacing the CDR recorded
+func (dd *cdrDeduplicator) countDuplicate(ruleID string, dropped bool) {
+	dd.Lock()
+	defer dd.Unlock()
+	st := dd.stats[ruleID]
+	st.Duplicates++
+	if dropped {
+		st.Dropped++
+	} else {
+		st.Replaced++
+	}
+}
+
+// getStats returns a copy of the counters, indexed on rule ID
+func (dd *cdrDeduplicator) getStats() (stats map[string]*CDRDedupStats) {
+	dd.Lock()
+	defer dd.Unlock()
+	stats = make(map[string]*CDRDedupStats, len(dd.stats))
+	for ruleID, st := range dd.stats {
+		cln := *st
+		stats[ruleID] = &cln
+	}
+	return
+}
@@ -0,0 +1,153 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestCDRDeduplicatorProcess(t *testing.T) {
+	data, _ := NewMapStorage()
+	dd := newCDRDeduplicator([]*config.CDRDedupRule{
+		{
+			ID:     "failover",
+			Fields: utils.ParseRSRFieldsMustCompile("Account;Destination;SetupTime", utils.INFIELD_SEP),
+			Window: time.Minute,
+			Action: utils.MetaKeepLongest,
+		},
+		{
+			ID:     "resend",
+			Fields: utils.ParseRSRFieldsMustCompile("OriginID", utils.INFIELD_SEP),
+			Window: time.Minute,
+			Action: utils.MetaDrop,
+		},
+	}, NewDataManager(data))
+	setupTime := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	newCDR := func(cgrID, originID string, usage time.Duration) *CDR {
+		return &CDR{CGRID: cgrID, RunID: utils.MetaRaw, OriginID: originID,
+			Account: "1001", Destination: "1002", SetupTime: setupTime, Usage: usage}
+	}
+	var replaced []string
+	var rplcErr error
+	replace := func(rplcCGRID string, ttl time.Duration) error {
+		if rplcErr != nil {
+			return rplcErr
+		}
+		replaced = append(replaced, rplcCGRID)
+		return nil
+	}
+	if drop, err := dd.process(newCDR("cgrid1", "orig1", 10*time.Second), replace, nil); err != nil || drop {
+		t.Errorf("first CDR, drop: %v, error: %v", drop, err)
+	}
+	// same call out of the failover switch, shorter usage
+	if drop, err := dd.process(newCDR("cgrid2", "orig2", 8*time.Second), replace, nil); err != nil || !drop {
+		t.Errorf("shorter duplicate, drop: %v, error: %v", drop, err)
+	}
+	// the replacement failing leaves the recorded CDR in place
+	rplcErr = utils.ErrServerError
+	if _, err := dd.process(newCDR("cgrid3", "orig3", 12*time.Second), replace, nil); err != utils.ErrServerError {
+		t.Errorf("expecting: %v, received: %v", utils.ErrServerError, err)
+	}
+	rplcErr = nil
+	// same call out of the failover switch, longer usage
+	if drop, err := dd.process(newCDR("cgrid3", "orig3", 12*time.Second), replace, nil); err != nil || drop {
+		t.Errorf("longer duplicate, drop: %v, error: %v", drop, err)
+	}
+	if !reflect.DeepEqual([]string{"cgrid1"}, replaced) {
+		t.Errorf("unexpected replaced CDRs: %v", replaced)
+	}
+	// resent by the first switch with other setup time
+	cdr := newCDR("cgrid4", "orig1", 10*time.Second)
+	cdr.SetupTime = setupTime.Add(time.Second)
+	if drop, err := dd.process(cdr, replace, nil); err != nil || !drop {
+		t.Errorf("resent CDR, drop: %v, error: %v", drop, err)
+	}
+	// other run of the same call is not a duplicate
+	cdr = newCDR("cgrid1", "orig1", 10*time.Second)
+	cdr.RunID = utils.META_DEFAULT
+	if drop, err := dd.process(cdr, replace, nil); err != nil || drop {
+		t.Errorf("other run, drop: %v, error: %v", drop, err)
+	}
+	eStats := map[string]*CDRDedupStats{
+		"failover": {Duplicates: 2, Dropped: 1, Replaced: 1},
+		"resend":   {Duplicates: 1, Dropped: 1},
+	}
+	if stats := dd.getStats(); !reflect.DeepEqual(eStats, stats) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eStats), utils.ToJSON(stats))
+	}
+	// the identities are kept in DataDB, out of window once expired
+	key, err := dd.identity(dd.rules[0], newCDR("cgrid3", "orig3", 12*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec, err := dd.dm.GetCDRDedupRecord(key); err != nil {
+		t.Fatal(err)
+	} else if rec.CGRID != "cgrid3" {
+		t.Errorf("unexpected record: %s", utils.ToJSON(rec))
+	} else {
+		rec.Expiry = time.Now().Add(-time.Second)
+		if err := dd.dm.SetCDRDedupRecord(rec, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if drop, err := dd.process(newCDR("cgrid5", "orig5", 5*time.Second), replace, nil); err != nil || drop {
+		t.Errorf("CDR out of window, drop: %v, error: %v", drop, err)
+	}
+	if len(replaced) != 1 {
+		t.Errorf("unexpected replaced CDRs: %v", replaced)
+	}
+	// the records of all the rules matched follow the CDR replacing, the CDRs recorded are guarded
+	var guarded int
+	guard := func() { guarded++ }
+	cdr = newCDR("cgrid6", "orig6", 10*time.Second)
+	cdr.SetupTime = setupTime.Add(time.Hour)
+	if drop, err := dd.process(cdr, replace, guard); err != nil || drop {
+		t.Errorf("first CDR, drop: %v, error: %v", drop, err)
+	}
+	cdr = newCDR("cgrid7", "orig6", 20*time.Second)
+	cdr.SetupTime = setupTime.Add(time.Hour)
+	if drop, err := dd.process(cdr, replace, guard); err != nil || drop {
+		t.Errorf("longer duplicate, drop: %v, error: %v", drop, err)
+	}
+	if drop, err := dd.process(newCDR("cgrid8", "orig6", 5*time.Second), replace, guard); err != nil || !drop {
+		t.Errorf("resent CDR, drop: %v, error: %v", drop, err)
+	}
+	if guarded != 2 {
+		t.Errorf("expecting 2 CDRs guarded, received: %d", guarded)
+	}
+	if !reflect.DeepEqual([]string{"cgrid1", "cgrid6"}, replaced) {
+		t.Errorf("unexpected replaced CDRs: %v", replaced)
+	}
+	for _, rule := range dd.rules {
+		key, err := dd.identity(rule, cdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec, err := dd.dm.GetCDRDedupRecord(key); err != nil {
+			t.Fatal(err)
+		} else if rec.CGRID != "cgrid7" || rec.Usage != 20*time.Second {
+			t.Errorf("unexpected record on rule: %s: %s", rule.ID, utils.ToJSON(rec))
+		}
+	}
+}
@@ -117,6 +117,9 @@ func (cS *ChargerService) processEvent(cgrEv *utils.CGREvent) (rply []*ChrgSProc
 	for i, cP := range cPs {
 		clonedEv := cgrEv.Clone()
 		clonedEv.Event[utils.RunID] = cP.RunID
+		if cP.SplitBilling != "" {
+			clonedEv.Event[utils.CGRSplitBilling] = cP.SplitBilling
+		}
 		rply[i] = &ChrgSProcessEventReply{
 			ChargerSProfile: cP.ID,
 			CGREvent:        clonedEv,
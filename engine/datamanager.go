@@ -18,6 +18,7 @@ package engine
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
@@ -46,7 +47,7 @@ func (dm *DataManager) DataDB() DataDB {
 
 func (dm *DataManager) LoadDataDBCache(dstIDs, rvDstIDs, rplIDs, rpfIDs, actIDs, aplIDs,
 	aaPlIDs, atrgIDs, sgIDs, lcrIDs, dcIDs, alsIDs, rvAlsIDs, rpIDs, resIDs,
-	stqIDs, stqpIDs, thIDs, thpIDs, fltrIDs, splPrflIDs, alsPrfIDs, cppIDs, dppIDs []string) (err error) {
+	stqIDs, stqpIDs, thIDs, thpIDs, fltrIDs, splPrflIDs, alsPrfIDs, cppIDs, dppIDs, txpIDs, calIDs []string) (err error) {
 	if dm.DataDB().GetStorageType() == utils.MAPSTOR {
 		if dm.cacheCfg == nil {
 			return
@@ -59,7 +60,8 @@ func (dm *DataManager) LoadDataDBCache(dstIDs, rvDstIDs, rplIDs, rpfIDs, actIDs,
 				utils.SHARED_GROUP_PREFIX, utils.ALIASES_PREFIX, utils.REVERSE_ALIASES_PREFIX, utils.StatQueuePrefix,
 				utils.StatQueueProfilePrefix, utils.ThresholdPrefix, utils.ThresholdProfilePrefix,
 				utils.FilterPrefix, utils.SupplierProfilePrefix,
-				utils.AttributeProfilePrefix, utils.ChargerProfilePrefix, utils.DispatcherProfilePrefix}, k) && cacheCfg.Precache {
+				utils.AttributeProfilePrefix, utils.ChargerProfilePrefix, utils.DispatcherProfilePrefix,
+				utils.TaxProfilePrefix, utils.CalendarPrefix}, k) && cacheCfg.Precache {
 				if err := dm.PreloadCacheForPrefix(k); err != nil && err != utils.ErrInvalidKey {
 					return err
 				}
@@ -91,6 +93,8 @@ func (dm *DataManager) LoadDataDBCache(dstIDs, rvDstIDs, rplIDs, rpfIDs, actIDs,
 			utils.AttributeProfilePrefix:     alsPrfIDs,
 			utils.ChargerProfilePrefix:       cppIDs,
 			utils.DispatcherProfilePrefix:    dppIDs,
+			utils.TaxProfilePrefix:           txpIDs,
+			utils.CalendarPrefix:             calIDs,
 		} {
 			if err = dm.CacheDataFromDB(key, ids, false); err != nil {
 				return
@@ -151,7 +155,9 @@ func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached b
 		utils.SupplierProfilePrefix,
 		utils.AttributeProfilePrefix,
 		utils.ChargerProfilePrefix,
-		utils.DispatcherProfilePrefix}, prfx) {
+		utils.DispatcherProfilePrefix,
+		utils.TaxProfilePrefix,
+		utils.CalendarPrefix}, prfx) {
 		return utils.NewCGRError(utils.DataManager,
 			utils.MandatoryIEMissingCaps,
 			utils.UnsupportedCachePrefix,
@@ -247,6 +253,12 @@ func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached b
 		case utils.DispatcherProfilePrefix:
 			tntID := utils.NewTenantID(dataID)
 			_, err = dm.GetDispatcherProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+		case utils.TaxProfilePrefix:
+			tntID := utils.NewTenantID(dataID)
+			_, err = dm.GetTaxProfile(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
+		case utils.CalendarPrefix:
+			tntID := utils.NewTenantID(dataID)
+			_, err = dm.GetCalendar(tntID.Tenant, tntID.ID, false, true, utils.NonTransactional)
 		}
 		if err != nil {
 			return utils.NewCGRError(utils.DataManager,
@@ -1371,3 +1383,243 @@ func (dm *DataManager) RemoveDispatcherProfile(tenant, id string,
 	}
 	return
 }
+
+// GetNodeSessions returns the sessions replicated in DataDB by nodeID, not cached
+func (dm *DataManager) GetNodeSessions(nodeID string) (ns *NodeSessions, err error) {
+	return dm.DataDB().GetNodeSessionsDrv(nodeID)
+}
+
+func (dm *DataManager) SetNodeSessions(ns *NodeSessions) (err error) {
+	return dm.DataDB().SetNodeSessionsDrv(ns)
+}
+
+func (dm *DataManager) RemoveNodeSessions(nodeID string) (err error) {
+	return dm.DataDB().RemoveNodeSessionsDrv(nodeID)
+}
+
+// ClaimNodeSessions removes the NodeSessions of nodeID if their heartbeat is still the one given,
+// returning ErrNotFound if they were refreshed or claimed by another node in the meantime
+func (dm *DataManager) ClaimNodeSessions(nodeID string, heartbeat time.Time) (err error) {
+	return dm.DataDB().ClaimNodeSessionsDrv(nodeID, heartbeat)
+}
+
+// GetExchangeRate returns the rate converting fromCurrency into toCurrency, not cached
+func (dm *DataManager) GetExchangeRate(fromCurrency, toCurrency string) (exr *ExchangeRate, err error) {
+	return dm.DataDB().GetExchangeRateDrv(fromCurrency, toCurrency)
+}
+
+func (dm *DataManager) SetExchangeRate(exr *ExchangeRate) (err error) {
+	return dm.DataDB().SetExchangeRateDrv(exr)
+}
+
+func (dm *DataManager) RemoveExchangeRate(fromCurrency, toCurrency string) (err error) {
+	return dm.DataDB().RemoveExchangeRateDrv(fromCurrency, toCurrency)
+}
+
+func (dm *DataManager) GetTaxProfile(tenant, id string, cacheRead, cacheWrite bool,
+	transactionID string) (txp *TaxProfile, err error) {
+	tntID := utils.ConcatenatedKey(tenant, id)
+	if cacheRead {
+		if x, ok := Cache.Get(utils.CacheTaxProfiles, tntID); ok {
+			if x == nil {
+				return nil, utils.ErrNotFound
+			}
+			return x.(*TaxProfile), nil
+		}
+	}
+	txp, err = dm.dataDB.GetTaxProfileDrv(tenant, id)
+	if err != nil {
+		if err == utils.ErrNotFound && cacheWrite {
+			Cache.Set(utils.CacheTaxProfiles, tntID, nil, nil,
+				cacheCommit(transactionID), transactionID)
+		}
+		return nil, err
+	}
+	if cacheWrite {
+		Cache.Set(utils.CacheTaxProfiles, tntID, txp, nil,
+			cacheCommit(transactionID), transactionID)
+	}
+	return
+}
+
+func (dm *DataManager) SetTaxProfile(txp *TaxProfile, withIndex bool) (err error) {
+	oldTxp, err := dm.GetTaxProfile(txp.Tenant, txp.ID, true, false, utils.NonTransactional)
+	if err != nil && err != utils.ErrNotFound {
+		return err
+	}
+	if err = dm.DataDB().SetTaxProfileDrv(txp); err != nil {
+		return err
+	}
+	if err = dm.CacheDataFromDB(utils.TaxProfilePrefix, []string{txp.TenantID()}, true); err != nil {
+		return
+	}
+	if withIndex {
+		if oldTxp != nil {
+			var needsRemove bool
+			for _, fltrID := range oldTxp.FilterIDs {
+				if !utils.IsSliceMember(txp.FilterIDs, fltrID) {
+					needsRemove = true
+				}
+			}
+			if needsRemove {
+				if err = NewFilterIndexer(dm, utils.TaxProfilePrefix,
+					txp.Tenant).RemoveItemFromIndex(txp.Tenant, txp.ID, oldTxp.FilterIDs); err != nil {
+					return
+				}
+			}
+		}
+		return createAndIndex(utils.TaxProfilePrefix, txp.Tenant, utils.EmptyString, txp.ID, txp.FilterIDs, dm)
+	}
+	return
+}
+
+func (dm *DataManager) RemoveTaxProfile(tenant, id string,
+	transactionID string, withIndex bool) (err error) {
+	oldTxp, err := dm.GetTaxProfile(tenant, id, true, false, utils.NonTransactional)
+	if err != nil && err != utils.ErrNotFound {
+		return err
+	}
+	if err = dm.DataDB().RemoveTaxProfileDrv(tenant, id); err != nil {
+		return
+	}
+	Cache.Remove(utils.CacheTaxProfiles, utils.ConcatenatedKey(tenant, id),
+		cacheCommit(transactionID), transactionID)
+	if oldTxp == nil {
+		return utils.ErrNotFound
+	}
+	if withIndex {
+		return NewFilterIndexer(dm, utils.TaxProfilePrefix, tenant).RemoveItemFromIndex(tenant, id, oldTxp.FilterIDs)
+	}
+	return
+}
+
+func (dm *DataManager) GetCalendar(tenant, id string, cacheRead, cacheWrite bool,
+	transactionID string) (cal *Calendar, err error) {
+	tntID := utils.ConcatenatedKey(tenant, id)
+	if cacheRead {
+		if x, ok := Cache.Get(utils.CacheCalendars, tntID); ok {
+			if x == nil {
+				return nil, utils.ErrNotFound
+			}
+			return x.(*Calendar), nil
+		}
+	}
+	cal, err = dm.dataDB.GetCalendarDrv(tenant, id)
+	if err != nil {
+		if err == utils.ErrNotFound && cacheWrite {
+			Cache.Set(utils.CacheCalendars, tntID, nil, nil,
+				cacheCommit(transactionID), transactionID)
+		}
+		return nil, err
+	}
+	if cacheWrite {
+		Cache.Set(utils.CacheCalendars, tntID, cal, nil,
+			cacheCommit(transactionID), transactionID)
+	}
+	return
+}
+
+func (dm *DataManager) SetCalendar(cal *Calendar) (err error) {
+	if err = dm.DataDB().SetCalendarDrv(cal); err != nil {
+		return
+	}
+	return dm.CacheDataFromDB(utils.CalendarPrefix, []string{cal.TenantID()}, true)
+}
+
+func (dm *DataManager) RemoveCalendar(tenant, id, transactionID string) (err error) {
+	if err = dm.DataDB().RemoveCalendarDrv(tenant, id); err != nil {
+		return
+	}
+	Cache.Remove(utils.CacheCalendars, utils.ConcatenatedKey(tenant, id),
+		cacheCommit(transactionID), transactionID)
+	return
+}
+
+// GetLease returns the lease out of DataDB, not cached since it changes with every renewal
+func (dm *DataManager) GetLease(leaseID string) (ls *Lease, err error) {
+	return dm.DataDB().GetLeaseDrv(leaseID)
+}
+
+// AcquireLease obtains or renews the lease for holderID, false if another holder has it
+func (dm *DataManager) AcquireLease(leaseID, holderID string, ttl time.Duration) (bool, error) {
+	return dm.DataDB().AcquireLeaseDrv(leaseID, holderID, ttl)
+}
+
+// GetLastExecution returns the last time the ActionTiming with the execution key was executed
+func (dm *DataManager) GetLastExecution(execKey string) (time.Time, error) {
+	return dm.DataDB().GetLastExecutionDrv(execKey)
+}
+
+func (dm *DataManager) SetLastExecution(execKey string, execTime time.Time) (err error) {
+	return dm.DataDB().SetLastExecutionDrv(execKey, execTime)
+}
+
+func (dm *DataManager) GetExecutionCheckpoint(execKey string) (*ExecutionCheckpoint, error) {
+	return dm.DataDB().GetExecutionCheckpointDrv(execKey)
+}
+
+func (dm *DataManager) SetExecutionCheckpoint(execKey string, chk *ExecutionCheckpoint) (err error) {
+	return dm.DataDB().SetExecutionCheckpointDrv(execKey, chk)
+}
+
+func (dm *DataManager) RemoveExecutionCheckpoint(execKey string) (err error) {
+	return dm.DataDB().RemoveExecutionCheckpointDrv(execKey)
+}
+
+// GetExecutionHistory returns limit executions journaled by the schedulers, all if 0, newest first after skipping offset
+func (dm *DataManager) GetExecutionHistory(offset, limit int) ([]*ExecutionRecord, error) {
+	return dm.DataDB().GetExecutionHistoryDrv(offset, limit)
+}
+
+// AddExecutionRecords journals the executions, keeping only the newest histSize ones
+func (dm *DataManager) AddExecutionRecords(recs []*ExecutionRecord, histSize int) (err error) {
+	return dm.DataDB().AddExecutionRecordsDrv(recs, histSize)
+}
+
+// ReleaseLease frees the lease if held by holderID so other nodes can acquire it without waiting for expiry
+func (dm *DataManager) ReleaseLease(leaseID, holderID string) (err error) {
+	return dm.DataDB().ReleaseLeaseDrv(leaseID, holderID)
+}
+
+// GetRequestResult returns the result of the request with reqID while not expired
+func (dm *DataManager) GetRequestResult(reqID string) (*RequestResult, error) {
+	return dm.DataDB().GetRequestResultDrv(reqID)
+}
+
+// SetRequestResult stores the result of a request, remembered for ttl
+func (dm *DataManager) SetRequestResult(rr *RequestResult, ttl time.Duration) (err error) {
+	return dm.DataDB().SetRequestResultDrv(rr, ttl)
+}
+
+// ReserveRequestResult stores the result only if there is none for the request, atomically
+// between the nodes sharing the DataDB, returning false if already present
+func (dm *DataManager) ReserveRequestResult(rr *RequestResult, ttl time.Duration) (reserved bool, err error) {
+	return dm.DataDB().ReserveRequestResultDrv(rr, ttl)
+}
+
+// RemoveRequestResult frees the reservation of rr, unless taken over by another execution after expiring
+func (dm *DataManager) RemoveRequestResult(rr *RequestResult) (err error) {
+	return dm.DataDB().RemoveRequestResultDrv(rr)
+}
+
+// GetCDRDedupRecord returns the CDR recorded for the dedup identity while within the window
+func (dm *DataManager) GetCDRDedupRecord(id string) (*CDRDedupRecord, error) {
+	return dm.DataDB().GetCDRDedupRecordDrv(id)
+}
+
+// SetCDRDedupRecord stores the CDR recorded for a dedup identity, remembered for ttl
+func (dm *DataManager) SetCDRDedupRecord(rec *CDRDedupRecord, ttl time.Duration) (err error) {
+	return dm.DataDB().SetCDRDedupRecordDrv(rec, ttl)
+}
+
+// GetNodeSessionsIDs returns the IDs of the nodes having sessions replicated in DataDB
+func (dm *DataManager) GetNodeSessionsIDs() (nodeIDs []string, err error) {
+	keys, err := dm.DataDB().GetKeysForPrefix(utils.NodeSessionsPrefix)
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		nodeIDs = append(nodeIDs, key[len(utils.NodeSessionsPrefix):])
+	}
+	return
+}
@@ -68,10 +68,11 @@ func NewEventCostFromCallCost(cc *CallCost, cgrID, runID string) (ec *EventCost)
 				if incr.BalanceInfo.Monetary != nil {
 					if uuid := ec.Accounting.GetIDWithSet(
 						&BalanceCharge{
-							AccountID:   incr.BalanceInfo.AccountID,
-							BalanceUUID: incr.BalanceInfo.Monetary.UUID,
-							Units:       incr.Cost,
-							RatingID:    ec.ratingIDForRateInterval(incr.BalanceInfo.Monetary.RateInterval, rf),
+							AccountID:    incr.BalanceInfo.AccountID,
+							BalanceUUID:  incr.BalanceInfo.Monetary.UUID,
+							Units:        incr.Cost,
+							RatingID:     ec.ratingIDForRateInterval(incr.BalanceInfo.Monetary.RateInterval, rf),
+							ExchangeRate: incr.BalanceInfo.Monetary.ExchangeRate,
 						}); uuid != "" {
 						ecUUID = uuid
 					}
@@ -86,10 +87,11 @@ func NewEventCostFromCallCost(cc *CallCost, cgrID, runID string) (ec *EventCost)
 			} else if incr.BalanceInfo.Monetary != nil { // Only monetary
 				cIt.AccountingID = ec.Accounting.GetIDWithSet(
 					&BalanceCharge{
-						AccountID:   incr.BalanceInfo.AccountID,
-						BalanceUUID: incr.BalanceInfo.Monetary.UUID,
-						Units:       incr.Cost,
-						RatingID:    ec.ratingIDForRateInterval(incr.BalanceInfo.Monetary.RateInterval, rf)})
+						AccountID:    incr.BalanceInfo.AccountID,
+						BalanceUUID:  incr.BalanceInfo.Monetary.UUID,
+						Units:        incr.Cost,
+						RatingID:     ec.ratingIDForRateInterval(incr.BalanceInfo.Monetary.RateInterval, rf),
+						ExchangeRate: incr.BalanceInfo.Monetary.ExchangeRate})
 			}
 			cIl.Increments[j] = cIt
 		}
@@ -145,7 +147,11 @@ func (ec *EventCost) ratingIDForRateInterval(ri *RateInterval, rf RatingMatchedF
 			MaxCostStrategy:  ri.Rating.MaxCostStrategy,
 			TimingID:         tmID,
 			RatesID:          rtUUID,
-			RatingFiltersID:  rfUUID})
+			RatingFiltersID:  rfUUID,
+			Currency:         ri.Rating.Currency,
+			MinCost:          ri.Rating.MinCost,
+			FreeUsage:        ri.Rating.FreeUsage,
+			TierPeriod:       ri.Rating.TierPeriod})
 }
 
 func (ec *EventCost) rateIntervalForRatingID(ratingID string) (ri *RateInterval) {
@@ -157,7 +163,10 @@ func (ec *EventCost) rateIntervalForRatingID(ratingID string) (ri *RateInterval)
 	ri.Rating = &RIRate{ConnectFee: cIlRU.ConnectFee,
 		RoundingMethod:   cIlRU.RoundingMethod,
 		RoundingDecimals: cIlRU.RoundingDecimals,
-		MaxCost:          cIlRU.MaxCost, MaxCostStrategy: cIlRU.MaxCostStrategy}
+		MaxCost:          cIlRU.MaxCost, MaxCostStrategy: cIlRU.MaxCostStrategy,
+		Currency: cIlRU.Currency,
+		MinCost:  cIlRU.MinCost, FreeUsage: cIlRU.FreeUsage,
+		TierPeriod: cIlRU.TierPeriod}
 	if cIlRU.RatesID != "" {
 		ri.Rating.Rates = ec.Rates[cIlRU.RatesID]
 	}
@@ -218,14 +227,37 @@ func (ec *EventCost) ResetCounters() {
 	}
 }
 
+// CallLimits returns the MinCost and the FreeUsage of the rating the event started with
+func (ec *EventCost) CallLimits() (minCost float64, freeUsage time.Duration) {
+	if len(ec.Charges) == 0 {
+		return
+	}
+	if ru, has := ec.Rating[ec.Charges[0].RatingID]; has {
+		return ru.MinCost, ru.FreeUsage
+	}
+	return
+}
+
+// ResetCost zeroes the charges and drops their accounting, used once everything charged was refunded
+func (ec *EventCost) ResetCost() {
+	for _, cIl := range ec.Charges {
+		for _, cIt := range cIl.Increments {
+			cIt.Cost = 0
+			cIt.AccountingID = ""
+		}
+	}
+	ec.RemoveStaleReferences()
+	ec.ResetCounters()
+}
+
 // ComputeCost iterates through Charges, computing EventCost.Cost
 func (ec *EventCost) GetCost() float64 {
 	if ec.Cost == nil {
-		var cost float64
+		dCost := utils.NewDecimalFromInt64(0)
 		for _, ci := range ec.Charges {
-			cost += ci.TotalCost()
+			dCost = dCost.Add(utils.NewDecimalFromFloat64(ci.TotalCost()))
 		}
-		cost = utils.Round(cost, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+		cost := dCost.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
 		ec.Cost = &cost
 	}
 	return *ec.Cost
@@ -243,6 +275,74 @@ func (ec *EventCost) GetUsage() time.Duration {
 	return *ec.Usage
 }
 
+// BalancesConsumed returns the units charged out of each balance, indexed on balance UUID
+func (ec *EventCost) BalancesConsumed() (blncs map[string]float64) {
+	blncs = make(map[string]float64)
+	for _, cIl := range ec.Charges {
+		for _, incr := range cIl.Increments {
+			if incr.AccountingID == "" {
+				continue
+			}
+			ec.addBalanceCharge(blncs, incr.AccountingID,
+				float64(incr.CompressFactor*cIl.CompressFactor))
+		}
+	}
+	return
+}
+
+// BalancesConsumedUntil returns the units charged out of each balance by the increments
+// started up to and including the given time, counted from StartTime
+func (ec *EventCost) BalancesConsumedUntil(until time.Time) (blncs map[string]float64) {
+	blncs = make(map[string]float64)
+	limit := until.Sub(ec.StartTime)
+	var offset time.Duration // start of the charging interval within the event
+	for _, cIl := range ec.Charges {
+		ilUsage := *cIl.Usage()
+		for i := 0; i < cIl.CompressFactor && offset <= limit; i++ {
+			incrOffset := offset
+			for _, incr := range cIl.Increments {
+				if incrOffset > limit {
+					break
+				}
+				started := incr.CompressFactor
+				if incr.Usage > 0 {
+					if n := int((limit-incrOffset)/incr.Usage) + 1; n < started {
+						started = n
+					}
+				}
+				if incr.AccountingID != "" {
+					ec.addBalanceCharge(blncs, incr.AccountingID, float64(started))
+				}
+				incrOffset += incr.TotalUsage()
+			}
+			offset += ilUsage
+		}
+		if offset > limit {
+			break
+		}
+	}
+	return
+}
+
+// addBalanceCharge adds the units of the BalanceCharge with accountingID, following the extra charges
+// the units are converted with the recorded exchange rate into the currency of the balance
+func (ec *EventCost) addBalanceCharge(blncs map[string]float64, accountingID string, factor float64) {
+	bc, has := ec.Accounting[accountingID]
+	if !has {
+		return
+	}
+	if bc.BalanceUUID != "" {
+		units := bc.Units
+		if bc.ExchangeRate != 0 {
+			units *= bc.ExchangeRate
+		}
+		blncs[bc.BalanceUUID] += units * factor
+	}
+	if bc.ExtraChargeID != "" && bc.ExtraChargeID != utils.META_NONE {
+		ec.addBalanceCharge(blncs, bc.ExtraChargeID, factor)
+	}
+}
+
 // ComputeEventCostUsageIndexes will iterate through Chargers and populate their ecUsageIdx
 func (ec *EventCost) ComputeEventCostUsageIndexes() {
 	var totalUsage time.Duration
@@ -310,7 +410,8 @@ func (ec *EventCost) AsCallCost() *CallCost {
 					}
 				}
 				if cBC.ExtraChargeID != utils.META_NONE {
-					incr.BalanceInfo.Monetary = &MonetaryInfo{UUID: cBC.BalanceUUID}
+					incr.BalanceInfo.Monetary = &MonetaryInfo{UUID: cBC.BalanceUUID,
+						ExchangeRate: cBC.ExchangeRate}
 					incr.BalanceInfo.Monetary.RateInterval = ec.rateIntervalForRatingID(cBC.RatingID)
 				}
 			}
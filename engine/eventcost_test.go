@@ -290,6 +290,57 @@ func TestECComputeAndReset(t *testing.T) {
 	}
 }
 
+func TestECBalancesConsumed(t *testing.T) {
+	eBlncs := map[string]float64{
+		"8c54a9e9-d610-4c82-bcb5-a315b9a65010": 3.52,
+		"7a54a9e9-d610-4c82-bcb5-a315b9a65010": 180,
+		"9d54a9e9-d610-4c82-bcb5-a315b9a65089": 60,
+	}
+	blncs := testEC.BalancesConsumed()
+	for blncUUID, units := range blncs {
+		blncs[blncUUID] = utils.Round(units, 5, utils.ROUNDING_MIDDLE)
+	}
+	if !reflect.DeepEqual(eBlncs, blncs) {
+		t.Errorf("Expecting: %+v, received: %+v", eBlncs, blncs)
+	}
+}
+
+func TestECBalancesConsumedUntil(t *testing.T) {
+	eBlncs := map[string]float64{
+		"8c54a9e9-d610-4c82-bcb5-a315b9a65010": 0.1, // connect fee
+		"9d54a9e9-d610-4c82-bcb5-a315b9a65089": 6,   // increments started at 0s to 5s
+	}
+	if blncs := testEC.BalancesConsumedUntil(testEC.StartTime.Add(5 * time.Second)); !reflect.DeepEqual(eBlncs, blncs) {
+		t.Errorf("Expecting: %+v, received: %+v", eBlncs, blncs)
+	}
+	if blncs := testEC.BalancesConsumedUntil(testEC.StartTime.Add(-time.Second)); len(blncs) != 0 {
+		t.Errorf("received: %+v", blncs)
+	}
+	eBlncs = testEC.BalancesConsumed()
+	blncs := testEC.BalancesConsumedUntil(testEC.StartTime.Add(time.Hour))
+	for blncUUID := range blncs {
+		blncs[blncUUID] = utils.Round(blncs[blncUUID], 5, utils.ROUNDING_MIDDLE)
+		eBlncs[blncUUID] = utils.Round(eBlncs[blncUUID], 5, utils.ROUNDING_MIDDLE)
+	}
+	if !reflect.DeepEqual(eBlncs, blncs) {
+		t.Errorf("Expecting: %+v, received: %+v", eBlncs, blncs)
+	}
+}
+
+func TestECBalancesConsumedExchangeRate(t *testing.T) {
+	ec := &EventCost{
+		Charges: []*ChargingInterval{{
+			Increments: []*ChargingIncrement{{
+				Usage: time.Minute, Cost: 0.5, AccountingID: "eur", CompressFactor: 2}},
+			CompressFactor: 1}},
+		Accounting: Accounting{
+			"eur": &BalanceCharge{BalanceUUID: "usd_balance", Units: 0.5, ExchangeRate: 1.2}},
+	}
+	if blncs := ec.BalancesConsumed(); utils.Round(blncs["usd_balance"], 5, utils.ROUNDING_MIDDLE) != 1.2 {
+		t.Errorf("received: %+v", blncs)
+	}
+}
+
 func TestNewEventCostFromCallCost(t *testing.T) {
 	acntSummary := &AccountSummary{
 		Tenant: "cgrates.org",
@@ -0,0 +1,80 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// ExchangeRate converts monetary amounts from one currency into another
+type ExchangeRate struct {
+	FromCurrency string
+	ToCurrency   string
+	Rate         float64 // units of ToCurrency for one unit of FromCurrency
+}
+
+// ID returns the key used to store the ExchangeRate
+func (exr *ExchangeRate) ID() string {
+	return utils.ConcatenatedKey(exr.FromCurrency, exr.ToCurrency)
+}
+
+// getExchangeRate returns the rate converting amounts out of fromCurrency into toCurrency
+// 0 is returned if no conversion is needed, the inverse rate is used if only that one is defined
+func getExchangeRate(fromCurrency, toCurrency string) (rate float64, err error) {
+	if fromCurrency == "" || toCurrency == "" ||
+		fromCurrency == toCurrency {
+		return
+	}
+	var exr *ExchangeRate
+	if exr, err = dm.GetExchangeRate(fromCurrency, toCurrency); err == nil {
+		return exr.Rate, nil
+	} else if err != utils.ErrNotFound {
+		return
+	}
+	if exr, err = dm.GetExchangeRate(toCurrency, fromCurrency); err != nil {
+		return
+	}
+	if exr.Rate == 0 {
+		return 0, fmt.Errorf("invalid exchange rate <%s>", exr.ID())
+	}
+	return 1 / exr.Rate, nil
+}
+
+// convertAmount applies the exchangeRate on amount, 0 meaning no conversion
+func convertAmount(amount, exchangeRate float64) float64 {
+	if exchangeRate == 0 {
+		return amount
+	}
+	return utils.NewDecimalFromFloat64(amount).Mul(utils.NewDecimalFromFloat64(exchangeRate)).
+		Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
+}
+
+// exchangeRate returns the rate converting costs in the rating currency into
+// the currency of the balance, falling back to no conversion for the default balance
+func (b *Balance) exchangeRate(ratingCurrency string) (rate float64, err error) {
+	if rate, err = getExchangeRate(ratingCurrency, b.Currency); err != nil && b.IsDefault() {
+		utils.Logger.Warning(
+			fmt.Sprintf("<Rater> no exchange rate from <%s> to <%s> for default balance <%s>, debiting unconverted",
+				ratingCurrency, b.Currency, b.Uuid))
+		return 0, nil
+	}
+	return
+}
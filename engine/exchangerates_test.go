@@ -0,0 +1,129 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestGetExchangeRate(t *testing.T) {
+	if err := dm.SetExchangeRate(&ExchangeRate{FromCurrency: "EUR",
+		ToCurrency: "USD", Rate: 1.25}); err != nil {
+		t.Fatal(err)
+	}
+	if rate, err := getExchangeRate("EUR", "USD"); err != nil {
+		t.Error(err)
+	} else if rate != 1.25 {
+		t.Errorf("expecting: 1.25, received: %v", rate)
+	}
+	if rate, err := getExchangeRate("USD", "EUR"); err != nil {
+		t.Error(err)
+	} else if rate != 0.8 {
+		t.Errorf("expecting: 0.8, received: %v", rate)
+	}
+	if rate, err := getExchangeRate("EUR", ""); err != nil {
+		t.Error(err)
+	} else if rate != 0 {
+		t.Errorf("expecting no conversion, received: %v", rate)
+	}
+	if _, err := getExchangeRate("GBP", "EUR"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestDebitCreditExchangeRate(t *testing.T) {
+	if err := dm.SetExchangeRate(&ExchangeRate{FromCurrency: "EUR",
+		ToCurrency: "USD", Rate: 1.25}); err != nil {
+		t.Fatal(err)
+	}
+	cc := &CallCost{
+		Destination: "0723045326",
+		Timespans: []*TimeSpan{
+			&TimeSpan{
+				TimeStart:     time.Date(2018, 10, 10, 10, 0, 0, 0, time.UTC),
+				TimeEnd:       time.Date(2018, 10, 10, 10, 0, 10, 0, time.UTC),
+				DurationIndex: 10 * time.Second,
+				RateInterval: &RateInterval{Rating: &RIRate{
+					RoundingMethod: utils.ROUNDING_MIDDLE, RoundingDecimals: 4,
+					Currency: "EUR",
+					Rates: RateGroups{&Rate{GroupIntervalStart: 0,
+						Value: 1, RateIncrement: time.Second,
+						RateUnit: time.Second}}}},
+			},
+		},
+		TOR: utils.VOICE,
+	}
+	cd := &CallDescriptor{
+		TimeStart:    time.Date(2018, 10, 10, 10, 0, 0, 0, time.UTC),
+		TimeEnd:      time.Date(2018, 10, 10, 10, 0, 10, 0, time.UTC),
+		Destination:  "0723045326",
+		Category:     "0",
+		TOR:          utils.VOICE,
+		testCallcost: cc,
+	}
+	acc := &Account{ID: "cgrates.org:exr",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: Balances{&Balance{Uuid: "usd", Value: 100, Currency: "USD"}}}}
+	var err error
+	if cc, err = acc.debitCreditBalance(cd, false, false, true); err != nil {
+		t.Fatal(err)
+	}
+	if val := acc.BalanceMap[utils.MONETARY][0].GetValue(); val != 87.5 {
+		t.Errorf("expecting: 87.5, received: %v", val)
+	}
+	if mi := cc.Timespans[0].Increments[0].BalanceInfo.Monetary; mi.ExchangeRate != 1.25 {
+		t.Errorf("unexpected monetary info: %s", utils.ToJSON(mi))
+	}
+	ec := NewEventCostFromCallCost(cc, "cgrid", utils.META_DEFAULT)
+	for _, bc := range ec.Accounting {
+		if bc.ExchangeRate != 1.25 {
+			t.Errorf("unexpected balance charge: %s", utils.ToJSON(bc))
+		}
+	}
+	if err := dm.DataDB().SetAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	refundCD := &CallDescriptor{TOR: utils.VOICE, Tenant: "cgrates.org", Account: "exr",
+		Increments: cc.Timespans[0].Increments}
+	refundCD.Increments.Decompress()
+	if acc, err = refundCD.refundIncrements(); err != nil {
+		t.Fatal(err)
+	} else if val := acc.BalanceMap[utils.MONETARY][0].GetValue(); val != 100 {
+		t.Errorf("expecting: 100, received: %v", val)
+	}
+}
+
+func TestBalancesGetTotalValueInCurrency(t *testing.T) {
+	if err := dm.SetExchangeRate(&ExchangeRate{FromCurrency: "EUR",
+		ToCurrency: "USD", Rate: 1.25}); err != nil {
+		t.Fatal(err)
+	}
+	bc := Balances{
+		&Balance{Value: 10, Currency: "EUR"},
+		&Balance{Value: 12.5, Currency: "USD"},
+		&Balance{Value: 5, Currency: "GBP"}, // no exchange rate, left out
+		&Balance{Value: 3},
+	}
+	if total := bc.GetTotalValueInCurrency("EUR"); total != 23 {
+		t.Errorf("expecting: 23, received: %v", total)
+	}
+}
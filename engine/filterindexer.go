@@ -100,6 +100,9 @@ func (rfi *FilterIndexer) cacheRemItemType() { // ToDo: tune here by removing pe
 
 	case utils.DispatcherProfilePrefix:
 		Cache.Clear([]string{utils.CacheDispatcherFilterIndexes})
+
+	case utils.TaxProfilePrefix:
+		Cache.Clear([]string{utils.CacheTaxFilterIndexes})
 	}
 }
 
@@ -215,6 +218,17 @@ func (rfi *FilterIndexer) RemoveItemFromIndex(tenant, itemID string, oldFilters
 				filterIDs[i] = fltrID
 			}
 		}
+	case utils.TaxProfilePrefix:
+		txp, err := rfi.dm.GetTaxProfile(tenant, itemID, true, false, utils.NonTransactional)
+		if err != nil && err != utils.ErrNotFound {
+			return err
+		}
+		if txp != nil {
+			filterIDs = make([]string, len(txp.FilterIDs))
+			for i, fltrID := range txp.FilterIDs {
+				filterIDs[i] = fltrID
+			}
+		}
 	default:
 	}
 	if len(filterIDs) == 0 {
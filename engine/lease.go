@@ -0,0 +1,35 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"time"
+)
+
+// Lease is a lock with expiry kept in DataDB, held by one node at a time, eg: the leading scheduler
+type Lease struct {
+	ID       string
+	HolderID string    // node holding the lease
+	Expiry   time.Time // the lease can be acquired by other nodes after this time
+}
+
+// Expired checks if the holder did not renew the lease in time
+func (ls *Lease) Expired(now time.Time) bool {
+	return now.After(ls.Expiry)
+}
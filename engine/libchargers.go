@@ -32,6 +32,7 @@ type ChargerProfile struct {
 	ActivationInterval *utils.ActivationInterval // Activation interval
 	RunID              string
 	AttributeIDs       []string // perform data aliasing based on these Attributes
+	SplitBilling       string   // charge the run on more accounts, ie: *ratio;cgrates.org:company:0.7;cgrates.org:1001:0.3
 	Weight             float64
 }
 
@@ -93,19 +93,19 @@ func (cIl *ChargingInterval) EndTime(cIlST time.Time) (et time.Time) {
 // Cost computes the total cost on this ChargingInterval
 func (cIl *ChargingInterval) Cost() float64 {
 	if cIl.cost == nil {
-		var cost float64
+		dCost := utils.NewDecimalFromInt64(0)
 		for _, incr := range cIl.Increments {
-			cost += incr.Cost * float64(incr.CompressFactor)
+			dCost = dCost.Add(utils.NewDecimalFromFloat64(incr.TotalCost()))
 		}
-		cost = utils.Round(cost, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+		cost := dCost.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
 		cIl.cost = &cost
 	}
 	return *cIl.cost
 }
 
 func (cIl *ChargingInterval) TotalCost() float64 {
-	return utils.Round((cIl.Cost() * float64(cIl.CompressFactor)),
-		globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	return utils.NewDecimalFromFloat64(cIl.Cost()).Mul(utils.NewDecimalFromInt64(int64(cIl.CompressFactor))).
+		Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
 }
 
 // Clone returns a new instance of ChargingInterval with independent data
@@ -154,7 +154,7 @@ func (cIt *ChargingIncrement) TotalUsage() time.Duration {
 }
 
 func (cIt *ChargingIncrement) TotalCost() float64 {
-	return cIt.Cost * float64(cIt.CompressFactor)
+	return utils.NewDecimalFromFloat64(cIt.Cost).Mul(utils.NewDecimalFromInt64(int64(cIt.CompressFactor))).Float64()
 }
 
 // BalanceCharge represents one unit charged to a balance
@@ -164,6 +164,7 @@ type BalanceCharge struct {
 	RatingID      string  // special price applied on this balance
 	Units         float64 // number of units charged
 	ExtraChargeID string  // used in cases when paying *voice with *monetary
+	ExchangeRate  float64 // rate used to convert Units into the balance currency, 0 for none
 }
 
 func (bc *BalanceCharge) Equals(oBC *BalanceCharge) bool {
@@ -179,6 +180,7 @@ func (bc *BalanceCharge) Equals(oBC *BalanceCharge) bool {
 		bc.BalanceUUID == oBC.BalanceUUID &&
 		bc.RatingID == oBC.RatingID &&
 		bc.Units == oBC.Units &&
+		bc.ExchangeRate == oBC.ExchangeRate &&
 		bcExtraChargeID == oBCExtraChargerID
 }
 
@@ -238,6 +240,10 @@ type RatingUnit struct {
 	TimingID         string // This RatingUnit is bounded to specific timing profile
 	RatesID          string
 	RatingFiltersID  string
+	Currency         string // currency of the rates
+	MinCost          float64
+	FreeUsage        time.Duration
+	TierPeriod       string // tier counter period, set for tiered rates
 }
 
 func (ru *RatingUnit) Equals(oRU *RatingUnit) bool {
@@ -248,7 +254,11 @@ func (ru *RatingUnit) Equals(oRU *RatingUnit) bool {
 		ru.MaxCostStrategy == oRU.MaxCostStrategy &&
 		ru.TimingID == oRU.TimingID &&
 		ru.RatesID == oRU.RatesID &&
-		ru.RatingFiltersID == oRU.RatingFiltersID
+		ru.RatingFiltersID == oRU.RatingFiltersID &&
+		ru.Currency == oRU.Currency &&
+		ru.MinCost == oRU.MinCost &&
+		ru.FreeUsage == oRU.FreeUsage &&
+		ru.TierPeriod == oRU.TierPeriod
 }
 
 func (ru *RatingUnit) Clone() (cln *RatingUnit) {
@@ -147,6 +147,9 @@ func LoadTariffPlanFromFolder(tpPath, timezone string, dm *DataManager, disable_
 		path.Join(tpPath, utils.AttributesCsv),
 		path.Join(tpPath, utils.ChargersCsv),
 		path.Join(tpPath, utils.DispatchersCsv),
+		path.Join(tpPath, utils.ExchangeRatesCsv),
+		path.Join(tpPath, utils.TaxProfilesCsv),
+		path.Join(tpPath, utils.CalendarsCsv),
 	), "", timezone)
 	if err := loader.LoadAll(); err != nil {
 		return utils.NewErrServerError(err)
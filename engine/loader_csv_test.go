@@ -56,6 +56,7 @@ WORKDAYS_00,*any,*any,*any,1;2;3;4;5,00:00:00
 WORKDAYS_18,*any,*any,*any,1;2;3;4;5,18:00:00
 WEEKENDS,*any,*any,*any,6;7,00:00:00
 ONE_TIME_RUN,2012,,,,*asap
+HOLIDAYS,*any,*any,*any,*any,00:00:00,*holiday:CAL_DE
 `
 	rates = `
 R1,0,0.2,60s,1s,0s
@@ -294,6 +295,22 @@ cgrates.org,Charger1,*string:Account:1001,2014-07-29T15:00:00Z,*rated,ATTR_1001_
 #Tenant,ID,FilterIDs,ActivationInterval,Strategy,Hosts,Weight
 cgrates.org,D1,*any,*string:Account:1001,2014-07-29T15:00:00Z,*first,,C1,*gt:Usage:10,10,false,192.168.56.203,20
 cgrates.org,D1,,,,*first,,C2,*lt:Usage:10,10,false,192.168.56.204,
+`
+	exchangeRates = `
+#FromCurrency,ToCurrency,Rate
+EUR,USD,1.15
+USD,RON,4.05
+`
+	taxProfiles = `
+#Tenant,ID,FilterIDs,ActivationInterval,Rate,Inclusive,Compound,Weight
+cgrates.org,GST_CA,*string:Account:1001,2014-07-29T15:00:00Z,5,false,false,20
+cgrates.org,QST_CA,*string:Account:1001,,9.975,false,true,10
+cgrates.org,QST_CA,*string:Category:call,,0,false,false,0
+`
+	calendars = `
+#Tenant,ID,Holidays
+cgrates.org,CAL_DE,2018-12-25;2018-12-26
+cgrates.org,CAL_DE,01-01;2018-12-25
 `
 )
 
@@ -303,7 +320,7 @@ func init() {
 	csvr = NewTpReader(dm.dataDB, NewStringCSVStorage(',', destinations, timings, rates, destinationRates,
 		ratingPlans, ratingProfiles, sharedGroups, actions, actionPlans, actionTriggers,
 		accountActions, derivedCharges, users, aliases, resProfiles, stats, thresholds,
-		filters, sppProfiles, attributeProfiles, chargerProfiles, dispatcherProfiles), testTPID, "")
+		filters, sppProfiles, attributeProfiles, chargerProfiles, dispatcherProfiles, exchangeRates, taxProfiles, calendars), testTPID, "")
 
 	if err := csvr.LoadDestinations(); err != nil {
 		log.Print("error in LoadDestinations:", err)
@@ -371,6 +388,15 @@ func init() {
 	if err := csvr.LoadDispatcherProfiles(); err != nil {
 		log.Print("error in LoadChargerProfiles:", err)
 	}
+	if err := csvr.LoadExchangeRates(); err != nil {
+		log.Print("error in LoadExchangeRates:", err)
+	}
+	if err := csvr.LoadTaxProfiles(); err != nil {
+		log.Print("error in LoadTaxProfiles:", err)
+	}
+	if err := csvr.LoadCalendars(); err != nil {
+		log.Print("error in LoadCalendars:", err)
+	}
 	csvr.WriteToDatabase(false, false, false)
 	Cache.Clear(nil)
 	//dm.LoadDataDBCache(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
@@ -447,7 +473,7 @@ func TestLoadReverseDestinations(t *testing.T) {
 }
 
 func TestLoadTimimgs(t *testing.T) {
-	if len(csvr.timings) != 6 {
+	if len(csvr.timings) != 7 {
 		t.Error("Failed to load timings: ", csvr.timings)
 	}
 	timing := csvr.timings["WORKDAYS_00"]
@@ -494,6 +520,19 @@ func TestLoadTimimgs(t *testing.T) {
 	}) {
 		t.Error("Error loading timing: ", timing)
 	}
+	timing = csvr.timings["HOLIDAYS"]
+	if !reflect.DeepEqual(timing, &utils.TPTiming{
+		ID:            "HOLIDAYS",
+		Years:         utils.Years{},
+		Months:        utils.Months{},
+		MonthDays:     utils.MonthDays{},
+		WeekDays:      utils.WeekDays{},
+		StartTime:     "00:00:00",
+		CalendarID:    "CAL_DE",
+		HolidayFilter: utils.MetaHoliday,
+	}) {
+		t.Error("Error loading timing: ", timing)
+	}
 }
 
 func TestLoadRates(t *testing.T) {
@@ -1776,6 +1815,20 @@ func TestLoadDispatcherProfiles(t *testing.T) {
 	}
 }
 
+func TestLoadExchangeRates(t *testing.T) {
+	eExr := &ExchangeRate{FromCurrency: "EUR", ToCurrency: "USD", Rate: 1.15}
+	if len(csvr.exchangeRates) != 2 {
+		t.Errorf("Failed to load exchangeRates: %s", utils.ToIJSON(csvr.exchangeRates))
+	} else if !reflect.DeepEqual(eExr, csvr.exchangeRates[eExr.ID()]) {
+		t.Errorf("Expecting: %+v, received: %+v", utils.ToJSON(eExr), utils.ToJSON(csvr.exchangeRates[eExr.ID()]))
+	}
+	if exr, err := dm.GetExchangeRate("USD", "RON"); err != nil {
+		t.Error(err)
+	} else if exr.Rate != 4.05 {
+		t.Errorf("Unexpected exchange rate: %+v", exr)
+	}
+}
+
 func TestLoadResource(t *testing.T) {
 	eResources := []*utils.TenantID{
 		&utils.TenantID{
@@ -1819,3 +1872,46 @@ func TestLoadThresholds(t *testing.T) {
 		t.Errorf("Failed to load thresholds: %s", utils.ToIJSON(csvr.thresholds))
 	}
 }
+
+func TestLoadTaxProfiles(t *testing.T) {
+	eTxp := &TaxProfile{
+		Tenant:    "cgrates.org",
+		ID:        "QST_CA",
+		FilterIDs: []string{"*string:Account:1001", "*string:Category:call"},
+		Rate:      9.975,
+		Compound:  true,
+		Weight:    10,
+	}
+	txpKey := utils.TenantID{Tenant: "cgrates.org", ID: "QST_CA"}
+	if len(csvr.taxProfiles) != 2 {
+		t.Errorf("Failed to load taxProfiles: %s", utils.ToIJSON(csvr.taxProfiles))
+	} else if !reflect.DeepEqual(eTxp, csvr.taxProfiles[txpKey]) {
+		t.Errorf("Expecting: %+v, received: %+v", utils.ToJSON(eTxp), utils.ToJSON(csvr.taxProfiles[txpKey]))
+	}
+	if txp, err := dm.GetTaxProfile("cgrates.org", "GST_CA",
+		false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if txp.Rate != 5 || txp.ActivationInterval == nil {
+		t.Errorf("Unexpected tax profile: %+v", txp)
+	}
+}
+
+func TestLoadCalendars(t *testing.T) {
+	eCal := &Calendar{
+		Tenant:   "cgrates.org",
+		ID:       "CAL_DE",
+		Holidays: []string{"2018-12-25", "2018-12-26", "01-01"},
+	}
+	calKey := utils.TenantID{Tenant: "cgrates.org", ID: "CAL_DE"}
+	if len(csvr.calendars) != 1 {
+		t.Errorf("Failed to load calendars: %s", utils.ToIJSON(csvr.calendars))
+	} else if !reflect.DeepEqual(eCal, csvr.calendars[calKey]) {
+		t.Errorf("Expecting: %+v, received: %+v", utils.ToJSON(eCal), utils.ToJSON(csvr.calendars[calKey]))
+	}
+	if cal, err := dm.GetCalendar("cgrates.org", "CAL_DE",
+		false, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCal, cal) {
+		t.Errorf("Expecting: %+v, received: %+v", utils.ToJSON(eCal), utils.ToJSON(cal))
+	}
+}
@@ -118,6 +118,9 @@ func TestLoaderITRemoveLoad(t *testing.T) {
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.AttributesCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.ChargersCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.DispatchersCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.ExchangeRatesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.TaxProfilesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.CalendarsCsv),
 	), "", "")
 
 	if err = loader.LoadDestinations(); err != nil {
@@ -222,6 +225,9 @@ func TestLoaderITLoadFromCSV(t *testing.T) {
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.AttributesCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.ChargersCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.DispatchersCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.ExchangeRatesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.TaxProfilesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.CalendarsCsv),
 	), "", "")
 
 	if err = loader.LoadDestinations(); err != nil {
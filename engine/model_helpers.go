@@ -33,6 +33,10 @@ import (
 func csvLoad(s interface{}, values []string) (interface{}, error) {
 	fieldValueMap := make(map[string]string)
 	st := reflect.TypeOf(s)
+	if nrCols := getColumnCount(s); len(values) > nrCols {
+		return nil, fmt.Errorf("invalid %v record, expecting maximum %d fields, received: %d",
+			st.Name(), nrCols, len(values))
+	}
 	numFields := st.NumField()
 	for i := 0; i < numFields; i++ {
 		field := st.Field(i)
@@ -40,6 +44,10 @@ func csvLoad(s interface{}, values []string) (interface{}, error) {
 		index := field.Tag.Get("index")
 		if index != "" {
 			idx, err := strconv.Atoi(index)
+			if err == nil && len(values) <= idx &&
+				field.Tag.Get("optional") == "true" {
+				continue // column missing in older tariff plans, keep the default
+			}
 			if err != nil || len(values) <= idx {
 				return nil, fmt.Errorf("invalid %v.%v index %v", st.Name(), field.Name, index)
 			}
@@ -186,6 +194,18 @@ func getColumnCount(s interface{}) int {
 	return count
 }
 
+// getFieldsPerRecord returns the number of fields a CSV reader should expect for s,
+// -1 (variable) if s has optional trailing columns
+func getFieldsPerRecord(s interface{}) int {
+	st := reflect.TypeOf(s)
+	for i := 0; i < st.NumField(); i++ {
+		if st.Field(i).Tag.Get("optional") == "true" {
+			return -1
+		}
+	}
+	return getColumnCount(s)
+}
+
 type TpDestinations []TpDestination
 
 func (tps TpDestinations) AsMapDestinations() (map[string]*Destination, error) {
@@ -253,6 +273,7 @@ func (tps TpTimings) AsMapTPTimings() (map[string]*utils.ApierTPTiming, error) {
 			MonthDays: tp.MonthDays,
 			WeekDays:  tp.WeekDays,
 			Time:      tp.Time,
+			Holidays:  tp.Holidays,
 		}
 		result[tp.Tag] = t
 	}
@@ -273,6 +294,14 @@ func MapTPTimings(tps []*utils.ApierTPTiming) (map[string]*utils.TPTiming, error
 		if len(times) > 1 {
 			t.EndTime = times[1]
 		}
+		if tp.Holidays != "" {
+			hldSplt := strings.SplitN(tp.Holidays, utils.InInFieldSep, 2)
+			if len(hldSplt) != 2 ||
+				(hldSplt[0] != utils.MetaHoliday && hldSplt[0] != utils.MetaNotHoliday) {
+				return nil, fmt.Errorf("invalid holidays for timing %s: %s", tp.ID, tp.Holidays)
+			}
+			t.HolidayFilter, t.CalendarID = hldSplt[0], hldSplt[1]
+		}
 		if _, found := result[tp.ID]; found {
 			return nil, fmt.Errorf("duplicate timing tag: %s", tp.ID)
 		}
@@ -298,6 +327,7 @@ func APItoModelTiming(t *utils.ApierTPTiming) (result TpTiming) {
 		MonthDays: t.MonthDays,
 		WeekDays:  t.WeekDays,
 		Time:      t.Time,
+		Holidays:  t.Holidays,
 	}
 }
 
@@ -411,6 +441,10 @@ func (tps TpDestinationRates) AsMapDestinationRates() (map[string]*utils.TPDesti
 					RoundingDecimals: tp.RoundingDecimals,
 					MaxCost:          tp.MaxCost,
 					MaxCostStrategy:  tp.MaxCostStrategy,
+					TierPeriod:       tp.TierPeriod,
+					Currency:         tp.Currency,
+					MinCost:          tp.MinCost,
+					FreeUsage:        tp.FreeUsage,
 				},
 			},
 		}
@@ -439,6 +473,11 @@ func (tps TpDestinationRates) AsTPDestinationRates() (result []*utils.TPDestinat
 func MapTPDestinationRates(s []*utils.TPDestinationRate) (map[string]*utils.TPDestinationRate, error) {
 	result := make(map[string]*utils.TPDestinationRate)
 	for _, e := range s {
+		for _, dr := range e.DestinationRates {
+			if _, err := dr.FreeUsageDuration(); err != nil {
+				return nil, fmt.Errorf("invalid FreeUsage %s for DestinationRate %s: %s", dr.FreeUsage, e.ID, err.Error())
+			}
+		}
 		if _, found := result[e.ID]; !found {
 			result[e.ID] = e
 		} else {
@@ -460,6 +499,10 @@ func APItoModelDestinationRate(d *utils.TPDestinationRate) (result TpDestination
 				RoundingDecimals: dr.RoundingDecimals,
 				MaxCost:          dr.MaxCost,
 				MaxCostStrategy:  dr.MaxCostStrategy,
+				TierPeriod:       dr.TierPeriod,
+				Currency:         dr.Currency,
+				MinCost:          dr.MinCost,
+				FreeUsage:        dr.FreeUsage,
 			})
 		}
 		if len(d.DestinationRates) == 0 {
@@ -519,14 +562,17 @@ func (tps TpRatingPlans) AsTPRatingPlans() (result []*utils.TPRatingPlan, err er
 }
 
 func GetRateInterval(rpl *utils.TPRatingPlanBinding, dr *utils.DestinationRate) (i *RateInterval) {
+	freeUsage, _ := dr.FreeUsageDuration() // validated when loading the DestinationRates
 	i = &RateInterval{
 		Timing: &RITiming{
-			Years:     rpl.Timing().Years,
-			Months:    rpl.Timing().Months,
-			MonthDays: rpl.Timing().MonthDays,
-			WeekDays:  rpl.Timing().WeekDays,
-			StartTime: rpl.Timing().StartTime,
-			tag:       rpl.Timing().ID,
+			Years:         rpl.Timing().Years,
+			Months:        rpl.Timing().Months,
+			MonthDays:     rpl.Timing().MonthDays,
+			WeekDays:      rpl.Timing().WeekDays,
+			StartTime:     rpl.Timing().StartTime,
+			CalendarID:    rpl.Timing().CalendarID,
+			HolidayFilter: rpl.Timing().HolidayFilter,
+			tag:           rpl.Timing().ID,
 		},
 		Weight: rpl.Weight,
 		Rating: &RIRate{
@@ -535,6 +581,10 @@ func GetRateInterval(rpl *utils.TPRatingPlanBinding, dr *utils.DestinationRate)
 			RoundingDecimals: dr.RoundingDecimals,
 			MaxCost:          dr.MaxCost,
 			MaxCostStrategy:  dr.MaxCostStrategy,
+			TierPeriod:       dr.TierPeriod,
+			Currency:         dr.Currency,
+			MinCost:          dr.MinCost,
+			FreeUsage:        freeUsage,
 			tag:              dr.Rate.ID,
 		},
 	}
@@ -783,8 +833,12 @@ func (tps TpActions) AsMapTPActions() (map[string]*utils.TPActions, error) {
 			BalanceBlocker:  tp.BalanceBlocker,
 			BalanceDisabled: tp.BalanceDisabled,
 			ExtraParameters: tp.ExtraParameters,
+			FailurePolicy:   tp.FailurePolicy,
 			Weight:          tp.Weight,
 		}
+		if tp.FilterIDs != "" {
+			a.FilterIDs = strings.Split(tp.FilterIDs, utils.INFIELD_SEP)
+		}
 		if existing, exists := result[as.ID]; !exists {
 			as.Actions = []*utils.TPAction{a}
 			result[as.ID] = as
@@ -841,6 +895,8 @@ func APItoModelAction(as *utils.TPActions) (result TpActions) {
 				BalanceBlocker:  a.BalanceBlocker,
 				BalanceDisabled: a.BalanceDisabled,
 				ExtraParameters: a.ExtraParameters,
+				FilterIDs:       strings.Join(a.FilterIDs, utils.INFIELD_SEP),
+				FailurePolicy:   a.FailurePolicy,
 				Weight:          a.Weight,
 			})
 		}
@@ -1070,6 +1126,7 @@ func (tps TpAccountActions) AsMapTPAccountActions() (map[string]*utils.TPAccount
 			ActionTriggersId: tp.ActionTriggersTag,
 			AllowNegative:    tp.AllowNegative,
 			Disabled:         tp.Disabled,
+			DebitPolicy:      tp.DebitPolicy,
 		}
 		result[aas.KeyId()] = aas
 	}
@@ -1109,6 +1166,7 @@ func APItoModelAccountAction(aa *utils.TPAccountActions) *TpAccountAction {
 		ActionTriggersTag: aa.ActionTriggersId,
 		AllowNegative:     aa.AllowNegative,
 		Disabled:          aa.Disabled,
+		DebitPolicy:       aa.DebitPolicy,
 	}
 }
 
@@ -2845,3 +2903,200 @@ func APItoDispatcherProfile(tpDPP *utils.TPDispatcherProfile, timezone string) (
 	}
 	return dpp, nil
 }
+
+type TpExchangeRates []TpExchangeRate
+
+func (tps TpExchangeRates) AsTPExchangeRates() (result []*utils.TPExchangeRate) {
+	result = make([]*utils.TPExchangeRate, len(tps))
+	for i, tp := range tps {
+		result[i] = &utils.TPExchangeRate{
+			TPid:         tp.Tpid,
+			FromCurrency: tp.FromCurrency,
+			ToCurrency:   tp.ToCurrency,
+			Rate:         tp.Rate,
+		}
+	}
+	return
+}
+
+func APItoModelExchangeRate(tpExr *utils.TPExchangeRate) TpExchangeRate {
+	return TpExchangeRate{
+		Tpid:         tpExr.TPid,
+		FromCurrency: tpExr.FromCurrency,
+		ToCurrency:   tpExr.ToCurrency,
+		Rate:         tpExr.Rate,
+	}
+}
+
+func APItoExchangeRate(tpExr *utils.TPExchangeRate) *ExchangeRate {
+	return &ExchangeRate{
+		FromCurrency: tpExr.FromCurrency,
+		ToCurrency:   tpExr.ToCurrency,
+		Rate:         tpExr.Rate,
+	}
+}
+
+type TpTaxProfiles []*TpTaxProfile
+
+func (tps TpTaxProfiles) AsTPTaxProfiles() (result []*utils.TPTaxProfile) {
+	mst := make(map[string]*utils.TPTaxProfile)
+	filterMap := make(map[string]utils.StringMap)
+	var tntIDs []string // keep the order of definition
+	for _, tp := range tps {
+		tntID := utils.ConcatenatedKey(tp.Tenant, tp.ID)
+		tpTxp, found := mst[tntID]
+		if !found {
+			tpTxp = &utils.TPTaxProfile{
+				TPid:   tp.Tpid,
+				Tenant: tp.Tenant,
+				ID:     tp.ID,
+			}
+			mst[tntID] = tpTxp
+			filterMap[tntID] = make(utils.StringMap)
+			tntIDs = append(tntIDs, tntID)
+		}
+		if tp.Rate != 0 {
+			tpTxp.Rate = tp.Rate
+		}
+		if tp.Inclusive {
+			tpTxp.Inclusive = tp.Inclusive
+		}
+		if tp.Compound {
+			tpTxp.Compound = tp.Compound
+		}
+		if tp.Weight != 0 {
+			tpTxp.Weight = tp.Weight
+		}
+		if len(tp.ActivationInterval) != 0 {
+			tpTxp.ActivationInterval = new(utils.TPActivationInterval)
+			aiSplt := strings.Split(tp.ActivationInterval, utils.INFIELD_SEP)
+			if len(aiSplt) == 2 {
+				tpTxp.ActivationInterval.ActivationTime = aiSplt[0]
+				tpTxp.ActivationInterval.ExpiryTime = aiSplt[1]
+			} else if len(aiSplt) == 1 {
+				tpTxp.ActivationInterval.ActivationTime = aiSplt[0]
+			}
+		}
+		if tp.FilterIDs != "" {
+			for _, fltrID := range strings.Split(tp.FilterIDs, utils.INFIELD_SEP) {
+				if !filterMap[tntID].HasKey(fltrID) {
+					filterMap[tntID][fltrID] = true
+					tpTxp.FilterIDs = append(tpTxp.FilterIDs, fltrID)
+				}
+			}
+		}
+	}
+	result = make([]*utils.TPTaxProfile, len(tntIDs))
+	for i, tntID := range tntIDs {
+		result[i] = mst[tntID]
+	}
+	return
+}
+
+func APItoModelTPTaxProfile(tpTxp *utils.TPTaxProfile) (mdls TpTaxProfiles) {
+	if tpTxp == nil {
+		return
+	}
+	mdl := &TpTaxProfile{
+		Tpid:      tpTxp.TPid,
+		Tenant:    tpTxp.Tenant,
+		ID:        tpTxp.ID,
+		FilterIDs: strings.Join(tpTxp.FilterIDs, utils.INFIELD_SEP),
+		Rate:      tpTxp.Rate,
+		Inclusive: tpTxp.Inclusive,
+		Compound:  tpTxp.Compound,
+		Weight:    tpTxp.Weight,
+	}
+	if tpTxp.ActivationInterval != nil {
+		if tpTxp.ActivationInterval.ActivationTime != "" {
+			mdl.ActivationInterval = tpTxp.ActivationInterval.ActivationTime
+		}
+		if tpTxp.ActivationInterval.ExpiryTime != "" {
+			mdl.ActivationInterval += utils.INFIELD_SEP + tpTxp.ActivationInterval.ExpiryTime
+		}
+	}
+	return TpTaxProfiles{mdl}
+}
+
+func APItoTaxProfile(tpTxp *utils.TPTaxProfile, timezone string) (txp *TaxProfile, err error) {
+	txp = &TaxProfile{
+		Tenant:    tpTxp.Tenant,
+		ID:        tpTxp.ID,
+		FilterIDs: make([]string, len(tpTxp.FilterIDs)),
+		Rate:      tpTxp.Rate,
+		Inclusive: tpTxp.Inclusive,
+		Compound:  tpTxp.Compound,
+		Weight:    tpTxp.Weight,
+	}
+	for i, fltrID := range tpTxp.FilterIDs {
+		txp.FilterIDs[i] = fltrID
+	}
+	if tpTxp.ActivationInterval != nil {
+		if txp.ActivationInterval, err = tpTxp.ActivationInterval.AsActivationInterval(timezone); err != nil {
+			return nil, err
+		}
+	}
+	return txp, nil
+}
+
+type TpCalendars []*TpCalendar
+
+func (tps TpCalendars) AsTPCalendars() (result []*utils.TPCalendar) {
+	mst := make(map[string]*utils.TPCalendar)
+	hldMap := make(map[string]utils.StringMap)
+	var tntIDs []string // keep the order of definition
+	for _, tp := range tps {
+		tntID := utils.ConcatenatedKey(tp.Tenant, tp.ID)
+		tpCal, found := mst[tntID]
+		if !found {
+			tpCal = &utils.TPCalendar{
+				TPid:   tp.Tpid,
+				Tenant: tp.Tenant,
+				ID:     tp.ID,
+			}
+			mst[tntID] = tpCal
+			hldMap[tntID] = make(utils.StringMap)
+			tntIDs = append(tntIDs, tntID)
+		}
+		if tp.Holidays != "" {
+			for _, day := range strings.Split(tp.Holidays, utils.INFIELD_SEP) {
+				if !hldMap[tntID].HasKey(day) {
+					hldMap[tntID][day] = true
+					tpCal.Holidays = append(tpCal.Holidays, day)
+				}
+			}
+		}
+	}
+	result = make([]*utils.TPCalendar, len(tntIDs))
+	for i, tntID := range tntIDs {
+		result[i] = mst[tntID]
+	}
+	return
+}
+
+func APItoModelTPCalendar(tpCal *utils.TPCalendar) (mdls TpCalendars) {
+	if tpCal == nil {
+		return
+	}
+	return TpCalendars{&TpCalendar{
+		Tpid:     tpCal.TPid,
+		Tenant:   tpCal.Tenant,
+		ID:       tpCal.ID,
+		Holidays: strings.Join(tpCal.Holidays, utils.INFIELD_SEP),
+	}}
+}
+
+func APItoCalendar(tpCal *utils.TPCalendar) (cal *Calendar, err error) {
+	cal = &Calendar{
+		Tenant:   tpCal.Tenant,
+		ID:       tpCal.ID,
+		Holidays: make([]string, len(tpCal.Holidays)),
+	}
+	for i, day := range tpCal.Holidays {
+		if !isValidHoliday(day) {
+			return nil, fmt.Errorf("invalid holiday <%s> in calendar %s", day, tpCal.ID)
+		}
+		cal.Holidays[i] = day
+	}
+	return
+}
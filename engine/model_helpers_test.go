@@ -35,6 +35,89 @@ func TestModelHelperCsvLoad(t *testing.T) {
 	}
 }
 
+func TestModelHelperCsvLoadOptional(t *testing.T) {
+	// DestinationRates.csv without the TierPeriod, Currency, MinCost and FreeUsage columns
+	l, err := csvLoad(TpDestinationRate{},
+		[]string{"DR_RETAIL", "GERMANY", "RT_1CENT", "*up", "4", "0", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpdr := l.(TpDestinationRate); tpdr.Tag != "DR_RETAIL" ||
+		tpdr.RoundingDecimals != 4 || tpdr.TierPeriod != "" ||
+		tpdr.Currency != "" || tpdr.MinCost != 0 || tpdr.FreeUsage != "" {
+		t.Errorf("model load failed: %+v", tpdr)
+	}
+	l, err = csvLoad(TpDestinationRate{},
+		[]string{"DR_RETAIL", "GERMANY", "RT_1CENT", "*up", "4", "0", "", "*monthly", "EUR", "0.1", "60s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpdr := l.(TpDestinationRate); tpdr.TierPeriod != utils.MetaMonthly ||
+		tpdr.Currency != "EUR" || tpdr.MinCost != 0.1 || tpdr.FreeUsage != "60s" {
+		t.Errorf("model load failed: %+v", tpdr)
+	}
+	if _, err = csvLoad(TpDestinationRate{},
+		[]string{"DR_RETAIL", "GERMANY", "RT_1CENT", "*up", "4", "0"}); err == nil {
+		t.Error("expecting error on missing mandatory column")
+	}
+	if _, err = csvLoad(TpDestinationRate{},
+		[]string{"DR_RETAIL", "GERMANY", "RT_1CENT", "*up", "4", "0", "", "*monthly", "EUR", "0.1", "60s", "extra"}); err == nil {
+		t.Error("expecting error on too many columns")
+	}
+}
+
+func TestModelHelperCsvLoadTimingNoHolidays(t *testing.T) {
+	l, err := csvLoad(TpTiming{}, []string{"ALWAYS", "*any", "*any", "*any", "*any", "00:00:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpt := l.(TpTiming); tpt.Tag != "ALWAYS" || tpt.Time != "00:00:00" || tpt.Holidays != "" {
+		t.Errorf("model load failed: %+v", tpt)
+	}
+}
+
+func TestModelHelperCsvLoadAccountActionNoDebitPolicy(t *testing.T) {
+	l, err := csvLoad(TpAccountAction{}, []string{"cgrates.org", "1001", "PACKAGE_1001", "", "", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpaa := l.(TpAccountAction); tpaa.Account != "1001" ||
+		tpaa.ActionPlanTag != "PACKAGE_1001" || tpaa.DebitPolicy != "" {
+		t.Errorf("model load failed: %+v", tpaa)
+	}
+}
+
+func TestModelHelperCsvLoadActionOptionalColumns(t *testing.T) {
+	l, err := csvLoad(TpAction{}, []string{"TOPUP", "*topup", "", "", "", "*monetary",
+		"", "", "", "", "", "", "10", "", "", "", "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpa := l.(TpAction); tpa.Action != "*topup" || tpa.Weight != 10 ||
+		tpa.FilterIDs != "" || tpa.FailurePolicy != "" {
+		t.Errorf("model load failed: %+v", tpa)
+	}
+	l, err = csvLoad(TpAction{}, []string{"TOPUP", "*topup", "", "", "", "*monetary",
+		"", "", "", "", "", "", "10", "", "", "", "10", "FLTR_1;FLTR_2", utils.MetaContinue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpAs, err := TpActions{l.(TpAction)}.AsTPActions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eFltrs := []string{"FLTR_1", "FLTR_2"}
+	if len(tpAs) != 1 || len(tpAs[0].Actions) != 1 ||
+		!reflect.DeepEqual(eFltrs, tpAs[0].Actions[0].FilterIDs) ||
+		tpAs[0].Actions[0].FailurePolicy != utils.MetaContinue {
+		t.Errorf("received: %s", utils.ToJSON(tpAs))
+	}
+	if mdls := APItoModelAction(tpAs[0]); len(mdls) != 1 ||
+		mdls[0].FilterIDs != "FLTR_1;FLTR_2" || mdls[0].FailurePolicy != utils.MetaContinue {
+		t.Errorf("received: %+v", mdls)
+	}
+}
+
 func TestModelHelperCsvDump(t *testing.T) {
 	tpd := TpDestination{
 		Tag:    "TEST_DEST",
@@ -38,6 +38,7 @@ type TpTiming struct {
 	MonthDays string `index:"3" re:"\*any\s*,\s*|(?:\d{1,4};?)+\s*,\s*|\s*,\s*"`
 	WeekDays  string `index:"4" re:"\*any\s*,\s*|(?:\d{1,4};?)+\s*,\s*|\s*,\s*"`
 	Time      string `index:"5" re:"\d{2}:\d{2}:\d{2}|\*asap"`
+	Holidays  string `index:"6" re:"^((\*holiday|\*not_holiday):\w+)?$" optional:"true"`
 	CreatedAt time.Time
 }
 
@@ -71,6 +72,10 @@ type TpDestinationRate struct {
 	RoundingDecimals int     `index:"4" re:"\d+"`
 	MaxCost          float64 `index:"5" re:"\d+\.*\d*s*"`
 	MaxCostStrategy  string  `index:"6" re:"\*free|\*disconnect"`
+	TierPeriod       string  `index:"7" re:"^(\*daily|\*weekly|\*monthly|\*yearly)?$" optional:"true"`
+	Currency         string  `index:"8" re:"^([A-Z]{3})?$" optional:"true"`
+	MinCost          float64 `index:"9" re:"\d+\.*\d*s*" optional:"true"`
+	FreeUsage        string  `index:"10" re:"\d+\.*\d*(ns|us|µs|ms|s|m|h)*\s*" optional:"true"`
 	CreatedAt        time.Time
 }
 
@@ -134,6 +139,8 @@ type TpAction struct {
 	BalanceBlocker  string  `index:"14" re:""`
 	BalanceDisabled string  `index:"15" re:""`
 	Weight          float64 `index:"16" re:"\d+\.?\d*\s*"`
+	FilterIDs       string  `index:"17" re:"" optional:"true"`
+	FailurePolicy   string  `index:"18" re:"" optional:"true"`
 	CreatedAt       time.Time
 }
 
@@ -184,6 +191,7 @@ type TpAccountAction struct {
 	ActionTriggersTag string `index:"3" re:"\w+\s*"`
 	AllowNegative     bool   `index:"4" re:""`
 	Disabled          bool   `index:"5" re:""`
+	DebitPolicy       string `index:"6" re:"" optional:"true"`
 	CreatedAt         time.Time
 }
 
@@ -410,6 +418,7 @@ type CDRsql struct {
 	CostSource  string
 	Cost        float64
 	CostDetails string
+	TaxLines    string
 	ExtraInfo   string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
@@ -429,6 +438,7 @@ type SessionsCostsSQL struct {
 	CostSource  string
 	Usage       int64
 	CostDetails string
+	History     string
 	CreatedAt   time.Time
 	DeletedAt   *time.Time
 }
@@ -437,6 +447,37 @@ func (t SessionsCostsSQL) TableName() string {
 	return utils.SessionsCostsTBL
 }
 
+type AccountAuditSQL struct {
+	ID          int64
+	Tenant      string
+	Account     string
+	BalanceType string
+	BalanceID   string
+	BalanceUUID string
+	ValueBefore float64
+	ValueAfter  float64
+	Cause       string
+	ActionsID   string
+	Cgrid       string
+	Timestamp   time.Time
+}
+
+func (t AccountAuditSQL) TableName() string {
+	return utils.AccountAuditsTBL
+}
+
+type AccountSnapshotSQL struct {
+	ID        int64
+	Tenant    string
+	Account   string
+	Timestamp time.Time
+	Balances  string
+}
+
+func (t AccountSnapshotSQL) TableName() string {
+	return utils.AccountSnapshotsTBL
+}
+
 type TBLVersion struct {
 	ID      uint
 	Item    string
@@ -517,3 +558,35 @@ type TPDispatcher struct {
 	Weight             float64 `index:"12" re:"\d+\.?\d*"`
 	CreatedAt          time.Time
 }
+
+type TpExchangeRate struct {
+	Id           int64
+	Tpid         string
+	FromCurrency string  `index:"0" re:"[A-Z]{3}"`
+	ToCurrency   string  `index:"1" re:"[A-Z]{3}"`
+	Rate         float64 `index:"2" re:"\d+\.?\d*"`
+	CreatedAt    time.Time
+}
+
+type TpTaxProfile struct {
+	PK                 uint `gorm:"primary_key"`
+	Tpid               string
+	Tenant             string  `index:"0" re:""`
+	ID                 string  `index:"1" re:""`
+	FilterIDs          string  `index:"2" re:""`
+	ActivationInterval string  `index:"3" re:""`
+	Rate               float64 `index:"4" re:"\d+\.?\d*"`
+	Inclusive          bool    `index:"5" re:""`
+	Compound           bool    `index:"6" re:""`
+	Weight             float64 `index:"7" re:"\d+\.?\d*"`
+	CreatedAt          time.Time
+}
+
+type TpCalendar struct {
+	PK        uint `gorm:"primary_key"`
+	Tpid      string
+	Tenant    string `index:"0" re:""`
+	ID        string `index:"1" re:""`
+	Holidays  string `index:"2" re:""`
+	CreatedAt time.Time
+}
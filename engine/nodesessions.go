@@ -0,0 +1,36 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"time"
+)
+
+// NodeSessions is the snapshot of the sessions handled by one SessionS node,
+// stored in DataDB so other nodes can take them over once the heartbeat expires
+type NodeSessions struct {
+	NodeID    string
+	Heartbeat time.Time         // last time the node refreshed the snapshot
+	Sessions  map[string][]byte // marshaled sessions, indexed on CGRID
+}
+
+// Expired checks if the node did not refresh its heartbeat within ttl
+func (ns *NodeSessions) Expired(ttl time.Duration) bool {
+	return time.Now().After(ns.Heartbeat.Add(ttl))
+}
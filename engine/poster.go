@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -104,16 +105,20 @@ type HTTPPoster struct {
 // Post with built-in failover
 // Returns also reference towards client so we can close it's connections when done
 func (poster *HTTPPoster) Post(addr string, contentType string, content interface{}, attempts int, fallbackFilePath string) (respBody []byte, err error) {
+	return poster.PostWithHeaders(addr, contentType, content, nil, attempts, fallbackFilePath)
+}
+
+// PostWithHeaders posts like Post, adding the headers to each request
+func (poster *HTTPPoster) PostWithHeaders(addr string, contentType string, content interface{},
+	headers map[string]string, attempts int, fallbackFilePath string) (respBody []byte, err error) {
 	if !utils.IsSliceMember([]string{utils.CONTENT_JSON, utils.CONTENT_FORM, utils.CONTENT_TEXT}, contentType) {
 		return nil, fmt.Errorf("unsupported ContentType: %s", contentType)
 	}
-	var body []byte        // Used to write in file and send over http
-	var urlVals url.Values // Used when posting form
+	var body []byte // Used to write in file and send over http
 	if utils.IsSliceMember([]string{utils.CONTENT_JSON, utils.CONTENT_TEXT}, contentType) {
 		body = content.([]byte)
 	} else if contentType == utils.CONTENT_FORM {
-		urlVals = content.(url.Values)
-		body = []byte(urlVals.Encode())
+		body = []byte(content.(url.Values).Encode())
 	}
 	fib := utils.Fib()
 	bodyType := "application/x-www-form-urlencoded"
@@ -121,13 +126,16 @@ func (poster *HTTPPoster) Post(addr string, contentType string, content interfac
 		bodyType = "application/json"
 	}
 	for i := 0; i < attempts; i++ {
-		var resp *http.Response
-		if utils.IsSliceMember([]string{utils.CONTENT_JSON, utils.CONTENT_TEXT}, contentType) {
-			resp, err = poster.httpClient.Post(addr, bodyType, bytes.NewBuffer(body))
-		} else if contentType == utils.CONTENT_FORM {
-			resp, err = poster.httpClient.PostForm(addr, urlVals)
+		var req *http.Request
+		if req, err = http.NewRequest(http.MethodPost, addr, bytes.NewBuffer(body)); err != nil {
+			return
 		}
-		if err != nil {
+		req.Header.Set("Content-Type", bodyType)
+		for hdr, val := range headers {
+			req.Header.Set(hdr, val)
+		}
+		var resp *http.Response
+		if resp, err = poster.httpClient.Do(req); err != nil {
 			utils.Logger.Warning(fmt.Sprintf("<HTTPPoster> Posting to : <%s>, error: <%s>", addr, err.Error()))
 			time.Sleep(time.Duration(fib()) * time.Second)
 			continue
@@ -161,6 +169,119 @@ func (poster *HTTPPoster) Post(addr string, contentType string, content interfac
 	return
 }
 
+// HTTPPostRequest is a JSON post together with its headers, written as such to failed posts
+type HTTPPostRequest struct {
+	Headers map[string]string
+	Body    json.RawMessage
+}
+
+const (
+	httpPostQueueSize    = 1024
+	httpPostQueueWorkers = 4
+)
+
+// httpQueuedPost is a request waiting within the httpPostQueue
+type httpQueuedPost struct {
+	poster     *HTTPPoster
+	addr       string
+	req        *HTTPPostRequest
+	attempts   int
+	failedDir  string
+	failedFile string
+}
+
+// httpPostQueue sends the posts out of the callers, retrying with backoff before
+// writing them to failed posts
+type httpPostQueue struct {
+	startOnce    sync.Once
+	posts        chan *httpQueuedPost
+	sync.RWMutex                // protect closed against enqueuing while flushing
+	closed       bool           // flushed on shutdown, the new posts are written to failed posts
+	pending      sync.WaitGroup // posts queued or being sent
+}
+
+var httpPosts = &httpPostQueue{posts: make(chan *httpQueuedPost, httpPostQueueSize)}
+
+// redactedHTTPHeaders carry credentials, not written to failed posts
+var redactedHTTPHeaders = utils.NewStringMap("Authorization", "Proxy-Authorization",
+	"Cookie", "X-Api-Key", "X-Auth-Token")
+
+// FlushHTTPPosts writes the queued posts to failed posts and waits for the ones being sent, called on shutdown
+func FlushHTTPPosts() {
+	httpPosts.flush()
+}
+
+// enqueue never blocks, writing the post to failed posts when the queue is full
+func (q *httpPostQueue) enqueue(qp *httpQueuedPost) {
+	q.startOnce.Do(func() {
+		for i := 0; i < httpPostQueueWorkers; i++ {
+			go q.work()
+		}
+	})
+	q.RLock()
+	defer q.RUnlock()
+	if q.closed {
+		utils.Logger.Warning(fmt.Sprintf("<HTTPPoster> Shutting down, not posting to : <%s>", qp.addr))
+		q.fail(qp)
+		return
+	}
+	q.pending.Add(1)
+	select {
+	case q.posts <- qp:
+	default:
+		q.pending.Done()
+		utils.Logger.Warning(fmt.Sprintf("<HTTPPoster> Queue full, not posting to : <%s>", qp.addr))
+		q.fail(qp)
+	}
+}
+
+func (q *httpPostQueue) work() {
+	for qp := range q.posts {
+		if _, err := qp.poster.PostWithHeaders(qp.addr, utils.CONTENT_JSON, []byte(qp.req.Body),
+			qp.req.Headers, qp.attempts, utils.META_NONE); err != nil {
+			q.fail(qp)
+		}
+		q.pending.Done()
+	}
+}
+
+// flush stops queueing and writes the posts not yet taken by the workers to failed posts
+func (q *httpPostQueue) flush() {
+	q.Lock()
+	q.closed = true
+	q.Unlock()
+	for drained := false; !drained; {
+		select {
+		case qp := <-q.posts:
+			q.fail(qp)
+			q.pending.Done()
+		default:
+			drained = true
+		}
+	}
+	q.pending.Wait()
+}
+
+// fail writes the post with its headers so it can be replayed, except the credentials
+func (q *httpPostQueue) fail(qp *httpQueuedPost) {
+	if qp.failedDir == utils.META_NONE {
+		return
+	}
+	req := &HTTPPostRequest{Headers: make(map[string]string), Body: qp.req.Body}
+	for hdr, val := range qp.req.Headers {
+		if !redactedHTTPHeaders.HasKey(http.CanonicalHeaderKey(hdr)) {
+			req.Headers[hdr] = val
+		}
+	}
+	content, err := json.Marshal(req)
+	if err == nil {
+		err = writeToFile(qp.failedDir, qp.failedFile, content)
+	}
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<HTTPPoster> Failed writing post to : <%s>, error: <%s>", qp.addr, err.Error()))
+	}
+}
+
 type Poster interface {
 	Post([]byte, string) error
 	Close()
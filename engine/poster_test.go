@@ -19,6 +19,10 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
 	"reflect"
 	"testing"
 
@@ -41,3 +45,53 @@ func TestAMQPPosterParseURL(t *testing.T) {
 		t.Errorf("Expected: %s ,recived: %s", utils.ToJSON(expected), utils.ToJSON(amqp))
 	}
 }
+
+func TestHTTPPostQueueFail(t *testing.T) {
+	failedDir, err := ioutil.TempDir("", "failed_posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(failedDir)
+	req := &HTTPPostRequest{
+		Headers: map[string]string{"authorization": "Bearer 123456", "X-Source": "cgrates"},
+		Body:    json.RawMessage(`{"event":"LowBalance"}`),
+	}
+	httpPosts.fail(&httpQueuedPost{addr: "http://127.0.0.1:12080/crm", req: req,
+		failedDir: failedDir, failedFile: "failed.json"})
+	var rcv *HTTPPostRequest
+	if content, err := ioutil.ReadFile(path.Join(failedDir, "failed.json")); err != nil {
+		t.Fatal(err)
+	} else if err = json.Unmarshal(content, &rcv); err != nil {
+		t.Fatal(err)
+	}
+	eReq := &HTTPPostRequest{ // without the credentials
+		Headers: map[string]string{"X-Source": "cgrates"},
+		Body:    req.Body,
+	}
+	if !reflect.DeepEqual(eReq, rcv) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eReq), utils.ToJSON(rcv))
+	}
+}
+
+func TestHTTPPostQueueFlush(t *testing.T) {
+	failedDir, err := ioutil.TempDir("", "failed_posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(failedDir)
+	q := &httpPostQueue{posts: make(chan *httpQueuedPost, 2)}
+	q.startOnce.Do(func() {}) // no workers, the posts stay queued
+	for _, fileName := range []string{"queued.json", "closed.json"} {
+		if fileName == "closed.json" {
+			q.flush()
+		}
+		q.enqueue(&httpQueuedPost{addr: "http://127.0.0.1:12080/crm",
+			req:       &HTTPPostRequest{Body: json.RawMessage(`{"event":"LowBalance"}`)},
+			failedDir: failedDir, failedFile: fileName})
+	}
+	for _, fileName := range []string{"queued.json", "closed.json"} {
+		if _, err := os.Stat(path.Join(failedDir, fileName)); err != nil {
+			t.Error(err)
+		}
+	}
+}
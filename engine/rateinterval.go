@@ -45,8 +45,11 @@ type RITiming struct {
 	MonthDays          utils.MonthDays
 	WeekDays           utils.WeekDays
 	StartTime, EndTime string // ##:##:## format
+	CalendarID         string // tenant Calendar listing the holidays
+	HolidayFilter      string // <*holiday|*not_holiday>
 	cronString         string
-	tag                string // loading validation only
+	tag                string    // loading validation only
+	calendar           *Calendar // resolved for the tenant at rating time
 }
 
 func (rit *RITiming) CronString() string {
@@ -174,6 +177,11 @@ func (rit *RITiming) IsActiveAt(t time.Time) bool {
 	if len(rit.WeekDays) > 0 && !rit.WeekDays.Contains(t.Weekday()) {
 		return false
 	}
+	// check for holidays
+	if rit.CalendarID != "" &&
+		rit.calendar.IsHoliday(t) != (rit.HolidayFilter == utils.MetaHoliday) {
+		return false
+	}
 	//log.Print("Time: ", t)
 
 	//log.Print("Left Margin: ", rit.getLeftMargin(t))
@@ -204,7 +212,12 @@ func (rit *RITiming) IsBlank() bool {
 }
 
 func (rit *RITiming) Stringify() string {
-	return utils.Sha1(fmt.Sprintf("%v", rit))[:8]
+	str := fmt.Sprintf("&{%v %v %v %v %v %v %v %v}", rit.Years, rit.Months,
+		rit.MonthDays, rit.WeekDays, rit.StartTime, rit.EndTime, rit.cronString, rit.tag)
+	if rit.CalendarID != "" { // keep the hash of timings without holidays unchanged
+		str += " " + rit.HolidayFilter + utils.InInFieldSep + rit.CalendarID
+	}
+	return utils.Sha1(str)[:8]
 }
 
 // Separate structure used for rating plan size optimization
@@ -214,12 +227,25 @@ type RIRate struct {
 	RoundingDecimals int
 	MaxCost          float64
 	MaxCostStrategy  string
-	Rates            RateGroups // GroupRateInterval (start time): Rate
-	tag              string     // loading validation only
+	TierPeriod       string        // GroupIntervalStart is applied on the usage of the account within this period instead of the call
+	Currency         string        // currency of the rates, converted at debit time if the balance has a different one
+	MinCost          float64       // minimum cost charged for a call
+	FreeUsage        time.Duration // calls shorter than this are not charged
+	Rates            RateGroups    // GroupRateInterval (start time): Rate
+	tag              string        // loading validation only
 }
 
 func (rir *RIRate) Stringify() string {
 	str := fmt.Sprintf("%v %v %v %v %v", rir.ConnectFee, rir.RoundingMethod, rir.RoundingDecimals, rir.MaxCost, rir.MaxCostStrategy)
+	if rir.TierPeriod != "" { // keep the hash of untiered rates unchanged
+		str += " " + rir.TierPeriod
+	}
+	if rir.Currency != "" {
+		str += " " + rir.Currency
+	}
+	if rir.MinCost != 0 || rir.FreeUsage != 0 {
+		str += fmt.Sprintf(" %v %v", rir.MinCost, rir.FreeUsage)
+	}
 	for _, r := range rir.Rates {
 		str += r.Stringify()
 	}
@@ -349,9 +375,9 @@ func (i *RateInterval) Equal(o *RateInterval) bool {
 
 func (i *RateInterval) GetCost(duration, startSecond time.Duration) float64 {
 	price, _, rateUnit := i.GetRateParameters(startSecond)
-	price /= float64(rateUnit.Nanoseconds())
-	d := float64(duration.Nanoseconds())
-	return utils.Round(d*price, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	cost := utils.NewDecimalFromFloat64(price).Mul(utils.NewDecimalFromInt64(duration.Nanoseconds())).
+		Div(utils.NewDecimalFromInt64(rateUnit.Nanoseconds()))
+	return cost.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
 }
 
 // Gets the price for a the provided start second
@@ -382,6 +408,14 @@ func (ri *RateInterval) GetMaxCost() (float64, string) {
 	return ri.Rating.MaxCost, ri.Rating.MaxCostStrategy
 }
 
+// GetCallLimits returns the minimum cost and the free usage applied on the whole call
+func (ri *RateInterval) GetCallLimits() (float64, time.Duration) {
+	if ri == nil || ri.Rating == nil {
+		return 0.0, 0
+	}
+	return ri.Rating.MinCost, ri.Rating.FreeUsage
+}
+
 // Structure to store intervals according to weight
 type RateIntervalList []*RateInterval
 
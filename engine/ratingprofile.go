@@ -153,8 +153,9 @@ func (ris RatingInfos) String() string {
 
 func (rpf *RatingProfile) GetRatingPlansForPrefix(cd *CallDescriptor) (err error) {
 	var ris RatingInfos
+	rDM, skipCache, transID := cd.ratingDataManager()
 	for index, rpa := range rpf.RatingPlanActivations.GetActiveForCall(cd) {
-		rpl, err := dm.GetRatingPlan(rpa.RatingPlanId, false, utils.NonTransactional)
+		rpl, err := rDM.GetRatingPlan(rpa.RatingPlanId, skipCache, transID)
 		if err != nil || rpl == nil {
 			utils.Logger.Err(fmt.Sprintf("Error checking destination: %v", err))
 			continue
@@ -171,7 +172,7 @@ func (rpf *RatingProfile) GetRatingPlansForPrefix(cd *CallDescriptor) (err error
 			}
 		} else {
 			for _, p := range utils.SplitPrefix(cd.Destination, MIN_PREFIX_MATCH) {
-				if destIDs, err := dm.DataDB().GetReverseDestination(p, false, utils.NonTransactional); err == nil {
+				if destIDs, err := rDM.DataDB().GetReverseDestination(p, skipCache, transID); err == nil {
 					var bestWeight *float64
 					for _, dID := range destIDs {
 						if _, ok := rpl.DestinationRates[dID]; ok {
@@ -209,6 +210,7 @@ func (rpf *RatingProfile) GetRatingPlansForPrefix(cd *CallDescriptor) (err error
 				FallbackKeys:   []string{cd.GetKey(FALLBACK_SUBJECT)}})
 		}
 		if len(prefix) > 0 {
+			rps.resolveCalendars(rDM, skipCache, transID, cd.Tenant)
 			ris = append(ris, &RatingInfo{
 				MatchedSubject: rpf.Id,
 				RatingPlanId:   rpl.Id,
@@ -243,10 +245,16 @@ type TenantRatingSubject struct {
 }
 
 func RatingProfileSubjectPrefixMatching(key string) (rp *RatingProfile, err error) {
+	return ratingProfileSubjectPrefixMatching(new(CallDescriptor), key)
+}
+
+// ratingProfileSubjectPrefixMatching queries the RatingProfile on the rating data source of the CallDescriptor
+func ratingProfileSubjectPrefixMatching(cd *CallDescriptor, key string) (rp *RatingProfile, err error) {
+	rDM, skipCache, transID := cd.ratingDataManager()
 	if !rpSubjectPrefixMatching || strings.HasSuffix(key, utils.ANY) {
-		return dm.GetRatingProfile(key, false, utils.NonTransactional)
+		return rDM.GetRatingProfile(key, skipCache, transID)
 	}
-	if rp, err = dm.GetRatingProfile(key, false, utils.NonTransactional); err == nil && rp != nil { // rp nil represents cached no-result
+	if rp, err = rDM.GetRatingProfile(key, skipCache, transID); err == nil && rp != nil { // rp nil represents cached no-result
 		return
 	}
 	lastIndex := strings.LastIndex(key, utils.CONCATENATED_KEY_SEP)
@@ -254,8 +262,8 @@ func RatingProfileSubjectPrefixMatching(key string) (rp *RatingProfile, err erro
 	subject := key[lastIndex:]
 	lenSubject := len(subject)
 	for i := 1; i < lenSubject-1; i++ {
-		if rp, err = dm.GetRatingProfile(baseKey+subject[:lenSubject-i],
-			false, utils.NonTransactional); err == nil && rp != nil {
+		if rp, err = rDM.GetRatingProfile(baseKey+subject[:lenSubject-i],
+			skipCache, transID); err == nil && rp != nil {
 			return
 		}
 	}
@@ -0,0 +1,175 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// SimulatedCost is the outcome of re-rating one CDR on simulated rating data
+type SimulatedCost struct {
+	CGRID       string
+	RunID       string
+	Account     string
+	Subject     string
+	Destination string
+	Usage       time.Duration
+	OldCost     float64 // cost the CDR was rated with
+	NewCost     float64 // cost on the simulated rating data
+	Error       string  // reason the CDR could not be rated on the simulated data
+}
+
+// RatingSimulation gathers the old versus the new costs of the CDRs re-rated on simulated rating data
+type RatingSimulation struct {
+	Costs   []*SimulatedCost
+	OldCost float64 // total of the old costs, considering only the CDRs having both costs
+	NewCost float64 // total of the new costs, considering only the CDRs having both costs
+	Errors  int     // number of CDRs which could not be rated on the simulated data
+}
+
+// SimulateRating re-rates the CDRs on the rating data of the TariffPlan tpid, loaded in memory only.
+// With ratingPlanID set, the CDRs are rated on that RatingPlan instead of the RatingProfiles in the TariffPlan.
+// Accounts are not debited and neither DataDB nor the cache are modified.
+func SimulateRating(lr LoadReader, tpid, ratingPlanID, timezone string,
+	cdrs []*CDR) (rSim *RatingSimulation, err error) {
+	transID := Cache.BeginTransaction() // isolates the cache writes of the simulation, discarded at the end
+	defer Cache.RollbackTransaction(transID)
+	var simDM *DataManager
+	if simDM, err = loadSimulationRatingData(lr, tpid, ratingPlanID,
+		timezone, transID); err != nil {
+		return
+	}
+	rSim = &RatingSimulation{Costs: make([]*SimulatedCost, len(cdrs))}
+	oldTotal := utils.NewDecimalFromInt64(0)
+	newTotal := utils.NewDecimalFromInt64(0)
+	for i, cdr := range cdrs {
+		rSim.Costs[i] = simulateCDRCost(simDM, transID, ratingPlanID, cdr)
+		if rSim.Costs[i].Error != "" {
+			rSim.Errors++
+			continue
+		}
+		if cdr.Cost == -1 { // not rated before
+			continue
+		}
+		oldTotal = oldTotal.Add(utils.NewDecimalFromFloat64(rSim.Costs[i].OldCost))
+		newTotal = newTotal.Add(utils.NewDecimalFromFloat64(rSim.Costs[i].NewCost))
+	}
+	rSim.OldCost = oldTotal.Float64()
+	rSim.NewCost = newTotal.Float64()
+	return
+}
+
+// loadSimulationRatingData loads the rating data of the TariffPlan into an in-memory DataManager
+func loadSimulationRatingData(lr LoadReader, tpid, ratingPlanID, timezone,
+	transID string) (simDM *DataManager, err error) {
+	var simDB *MapStorage
+	if simDB, err = NewMapStorage(); err != nil {
+		return
+	}
+	simDM = NewDataManager(simDB)
+	tpr := NewTpReader(simDB, lr, tpid, timezone)
+	if err = tpr.LoadDestinations(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
+	if err = tpr.LoadTimings(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
+	if err = tpr.LoadCalendars(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
+	if err = tpr.LoadRates(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
+	if err = tpr.LoadDestinationRates(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
+	if err = tpr.LoadRatingPlans(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
+	if ratingPlanID == "" {
+		if err = tpr.LoadRatingProfiles(); err != nil && err.Error() != utils.NotFoundCaps {
+			return
+		}
+	} else if _, has := tpr.ratingPlans[ratingPlanID]; !has {
+		return nil, utils.ErrNotFound
+	}
+	err = nil
+	// write directly on drivers so the rating data does not reach the global cache
+	for _, dst := range tpr.destinations {
+		if err = simDB.SetDestination(dst, transID); err != nil {
+			return
+		}
+		if err = simDB.SetReverseDestination(dst, transID); err != nil {
+			return
+		}
+	}
+	for _, cal := range tpr.calendars {
+		if err = simDB.SetCalendarDrv(cal); err != nil {
+			return
+		}
+	}
+	for _, rpl := range tpr.ratingPlans {
+		if err = simDB.SetRatingPlanDrv(rpl); err != nil {
+			return
+		}
+	}
+	for _, rpf := range tpr.ratingProfiles {
+		if err = simDB.SetRatingProfileDrv(rpf); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// simulateCDRCost rates one CDR on the simulated rating data
+func simulateCDRCost(simDM *DataManager, transID, ratingPlanID string,
+	cdr *CDR) (sc *SimulatedCost) {
+	sc = &SimulatedCost{
+		CGRID:       cdr.CGRID,
+		RunID:       cdr.RunID,
+		Account:     cdr.Account,
+		Subject:     cdr.Subject,
+		Destination: cdr.Destination,
+		Usage:       cdr.Usage,
+		OldCost:     cdr.Cost,
+		NewCost:     -1,
+	}
+	cd := cdrCallDescriptor(cdr)
+	cd.ratingDM = simDM
+	cd.ratingTransID = transID
+	if ratingPlanID != "" { // the candidate RatingPlan applies to any subject
+		if err := simDM.DataDB().SetRatingProfileDrv(&RatingProfile{
+			Id: cd.GetKey(cd.Subject),
+			RatingPlanActivations: RatingPlanActivations{
+				&RatingPlanActivation{RatingPlanId: ratingPlanID}},
+		}); err != nil {
+			sc.Error = err.Error()
+			return
+		}
+	}
+	cc, err := cd.GetCost()
+	if err != nil {
+		sc.Error = err.Error()
+		return
+	}
+	sc.NewCost = cc.Cost
+	return
+}
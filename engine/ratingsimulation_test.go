@@ -0,0 +1,109 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestSimulateRating(t *testing.T) {
+	csvStor := NewStringCSVStorage(',', `DST_SIM_49,49`, ``,
+		`RT_SIM_1CNT,0,0.01,1s,1s,0s`,
+		`DR_SIM_49,DST_SIM_49,RT_SIM_1CNT,*middle,4,0,,,,,`,
+		`RP_SIM_CANDIDATE,DR_SIM_49,*any,10`,
+		`cgrates.org,call,1001,2014-01-01T00:00:00Z,RP_SIM_CANDIDATE,`,
+		``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``)
+	aTime := time.Date(2018, 8, 24, 16, 0, 26, 0, time.UTC)
+	cdrs := []*CDR{
+		&CDR{CGRID: "CDR1", RunID: utils.META_DEFAULT, ToR: utils.VOICE,
+			Tenant: "cgrates.org", Category: "call", Account: "1001", Subject: "1001",
+			Destination: "4986517174963", AnswerTime: aTime,
+			Usage: time.Duration(60 * time.Second), Cost: 1.2},
+		&CDR{CGRID: "CDR2", RunID: utils.META_DEFAULT, ToR: utils.VOICE,
+			Tenant: "cgrates.org", Category: "call", Account: "1001", Subject: "1001",
+			Destination: "1002", AnswerTime: aTime,
+			Usage: time.Duration(60 * time.Second), Cost: 0.3},
+	}
+	for _, rpID := range []string{"", "RP_SIM_CANDIDATE"} {
+		rSim, err := SimulateRating(csvStor, "", rpID, "", cdrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rSim.Costs) != 2 {
+			t.Fatalf("Unexpected costs: %s", utils.ToJSON(rSim))
+		}
+		if rSim.Costs[0].OldCost != 1.2 || rSim.Costs[0].NewCost != 0.6 ||
+			rSim.Costs[0].Error != "" {
+			t.Errorf("Unexpected simulated cost: %s", utils.ToJSON(rSim.Costs[0]))
+		}
+		if rSim.Costs[1].NewCost != -1 || rSim.Costs[1].Error == "" {
+			t.Errorf("Unexpected simulated cost: %s", utils.ToJSON(rSim.Costs[1]))
+		}
+		if rSim.OldCost != 1.2 || rSim.NewCost != 0.6 || rSim.Errors != 1 {
+			t.Errorf("Unexpected totals: %s", utils.ToJSON(rSim))
+		}
+	}
+	// the candidate rating data should not reach DataDB or the cache
+	if _, err := dm.DataDB().GetRatingPlanDrv("RP_SIM_CANDIDATE"); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, has := Cache.Get(utils.CacheRatingPlans, "RP_SIM_CANDIDATE"); has {
+		t.Error("Candidate RatingPlan in cache")
+	}
+	if _, err := SimulateRating(csvStor, "", "RP_SIM_MISSING", "", cdrs); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestSimulateRatingCalendars(t *testing.T) {
+	csvStor := NewStringCSVStorage(',', `DST_SIM_49,49`,
+		`TM_SIM_HOLIDAY,*any,*any,*any,*any,00:00:00,*holiday:CAL_SIM`,
+		`RT_SIM_1CNT,0,0.01,1s,1s,0s
+RT_SIM_FREE,0,0,1s,1s,0s`,
+		`DR_SIM_49,DST_SIM_49,RT_SIM_1CNT,*middle,4,0,,,,,
+DR_SIM_49_FREE,DST_SIM_49,RT_SIM_FREE,*middle,4,0,,,,,`,
+		`RP_SIM_HOLIDAYS,DR_SIM_49,*any,10
+RP_SIM_HOLIDAYS,DR_SIM_49_FREE,TM_SIM_HOLIDAY,20`,
+		`cgrates.org,call,1001,2014-01-01T00:00:00Z,RP_SIM_HOLIDAYS,`,
+		``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``, ``,
+		`cgrates.org,CAL_SIM,2018-08-24`)
+	cdrs := []*CDR{
+		&CDR{CGRID: "CDR_HOLIDAY", RunID: utils.META_DEFAULT, ToR: utils.VOICE,
+			Tenant: "cgrates.org", Category: "call", Account: "1001", Subject: "1001",
+			Destination: "4986517174963", AnswerTime: time.Date(2018, 8, 24, 16, 0, 26, 0, time.UTC),
+			Usage: time.Duration(60 * time.Second), Cost: 0.6},
+		&CDR{CGRID: "CDR_WORKDAY", RunID: utils.META_DEFAULT, ToR: utils.VOICE,
+			Tenant: "cgrates.org", Category: "call", Account: "1001", Subject: "1001",
+			Destination: "4986517174963", AnswerTime: time.Date(2018, 8, 27, 16, 0, 26, 0, time.UTC),
+			Usage: time.Duration(60 * time.Second), Cost: 0.6},
+	}
+	rSim, err := SimulateRating(csvStor, "", "", "", cdrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rSim.Costs[0].NewCost != 0 || rSim.Costs[0].Error != "" {
+		t.Errorf("Unexpected holiday cost: %s", utils.ToJSON(rSim.Costs[0]))
+	}
+	if rSim.Costs[1].NewCost != 0.6 || rSim.Costs[1].Error != "" {
+		t.Errorf("Unexpected workday cost: %s", utils.ToJSON(rSim.Costs[1]))
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// RequestResult is the reply of an account mutating API, remembered under the RequestID of the client
+type RequestResult struct {
+	ID       string // method, account and RequestID of the client
+	Pending  bool   // reserved by the first execution, still in progress
+	HolderID string // execution holding the reservation
+	Reply    string
+	Error    string    // error returned to the client, empty on success
+	Expiry   time.Time // retries after this time are executed again
+}
+
+// Expired checks if the result is not to be replayed anymore
+func (rr *RequestResult) Expired(now time.Time) bool {
+	return now.After(rr.Expiry)
+}
+
+// definitiveRequestErrors are replayed to the retries, the other errors could be transient
+// so the request is executed again
+var definitiveRequestErrors = []error{utils.ErrNotFound, utils.ErrAccountNotFound,
+	utils.ErrAccountDisabled, utils.ErrInsufficientCredit, utils.ErrExists,
+	utils.ErrMandatoryIeMissing, utils.ErrUnauthorizedDestination, utils.ErrMaxUsageExceeded}
+
+// isDefinitiveRequestError checks if the error is to be returned again on retries
+func isDefinitiveRequestError(err error) bool {
+	for _, dErr := range definitiveRequestErrors {
+		if utils.ErrHasPrefix(err, dErr.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessRequestOnce executes f once per method, account and reqID within ttl, retries receive the reply and error
+// of the first execution or ErrRequestInProgress while that one did not finish
+// the reservation of a crashed execution expires within the locking and reply timeouts, not ttl,
+// while the transient errors are forgotten so the retries execute the request again
+// empty reqID or ttl disabled leaves the request not idempotent
+func ProcessRequestOnce(method, acntID, reqID string, ttl time.Duration, reply *string, f func() error) (err error) {
+	if reqID == "" || ttl <= 0 {
+		return f()
+	}
+	rrID := utils.ConcatenatedKey(method, acntID, reqID)
+	pendingTTL := config.CgrConfig().GeneralCfg().LockingTimeout + config.CgrConfig().GeneralCfg().ReplyTimeout
+	if pendingTTL <= 0 || pendingTTL > ttl {
+		pendingTTL = ttl
+	}
+	rr := &RequestResult{ID: rrID, Pending: true, HolderID: utils.GenUUID(),
+		Expiry: time.Now().Add(pendingTTL)}
+	var reserved bool
+	for i := 0; i < 2; i++ { // the result could expire between reserving and reading it
+		if reserved, err = dm.ReserveRequestResult(rr, pendingTTL); err != nil {
+			return
+		}
+		if reserved {
+			break
+		}
+		prevRR, err := dm.GetRequestResult(rrID)
+		if err == utils.ErrNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if prevRR.Pending {
+			return utils.ErrRequestInProgress
+		}
+		utils.Logger.Info(fmt.Sprintf("<%s> replaying the result of request: %s", utils.RALService, rrID))
+		*reply = prevRR.Reply
+		if prevRR.Error != "" {
+			return errors.New(prevRR.Error)
+		}
+		return nil
+	}
+	if !reserved {
+		return utils.ErrRequestInProgress
+	}
+	fErr := f()
+	if fErr != nil && !isDefinitiveRequestError(fErr) {
+		if err := dm.RemoveRequestResult(rr); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> cannot remove the reservation of request: %s, error: %s",
+				utils.RALService, rrID, err.Error()))
+		}
+		return fErr
+	}
+	rr.Pending = false
+	rr.Reply = *reply
+	rr.Expiry = time.Now().Add(ttl)
+	if fErr != nil {
+		rr.Error = fErr.Error()
+	}
+	if err := dm.SetRequestResult(rr, ttl); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> cannot store the result of request: %s, error: %s",
+			utils.RALService, rrID, err.Error()))
+	}
+	return fErr
+}
@@ -0,0 +1,135 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestProcessRequestOnce(t *testing.T) {
+	var executed int
+	topup := func(reply *string) func() error {
+		return func() error {
+			executed++
+			*reply = utils.OK
+			return nil
+		}
+	}
+	var reply string
+	for i := 0; i < 2; i++ {
+		if err := ProcessRequestOnce(utils.ApierV1AddBalance, "cgrates.org:1001", "REQ_TOPUP", time.Minute, &reply, topup(&reply)); err != nil {
+			t.Error(err)
+		} else if reply != utils.OK {
+			t.Errorf("received: %s", reply)
+		}
+	}
+	if executed != 1 {
+		t.Errorf("expecting one execution, received: %d", executed)
+	}
+	reply = ""
+	if err := ProcessRequestOnce(utils.ApierV1AddBalance, "cgrates.org:1001", "", time.Minute, &reply, topup(&reply)); err != nil {
+		t.Error(err)
+	} else if executed != 2 {
+		t.Errorf("expecting execution without RequestID, received: %d", executed)
+	}
+	failed := func() error {
+		executed++
+		return errors.New("DB_DOWN")
+	}
+	for i := 0; i < 2; i++ {
+		if err := ProcessRequestOnce(utils.ApierV1AddBalance, "cgrates.org:1001", "REQ_FAILED", time.Minute, &reply, failed); err == nil ||
+			err.Error() != "DB_DOWN" {
+			t.Errorf("received: %v", err)
+		}
+	}
+	if executed != 4 {
+		t.Errorf("expecting the transient error executed again, received: %d executions", executed)
+	}
+	insufficient := func() error {
+		executed++
+		return utils.ErrInsufficientCredit
+	}
+	for i := 0; i < 2; i++ {
+		if err := ProcessRequestOnce(utils.ApierV1DebitBalance, "cgrates.org:1001", "REQ_DEBIT", time.Minute,
+			&reply, insufficient); err == nil || err.Error() != utils.ErrInsufficientCredit.Error() {
+			t.Errorf("received: %v", err)
+		}
+	}
+	if executed != 5 {
+		t.Errorf("expecting the definitive error replayed, received: %d executions", executed)
+	}
+	if err := ProcessRequestOnce(utils.ApierV1DebitBalance, "cgrates.org:1001", "REQ_TOPUP", time.Minute,
+		&reply, topup(&reply)); err != nil {
+		t.Error(err)
+	} else if executed != 6 {
+		t.Errorf("expecting execution for another method, received: %d", executed)
+	}
+	if err := ProcessRequestOnce(utils.ApierV1AddBalance, "cgrates.org:1002", "REQ_TOPUP", time.Minute,
+		&reply, topup(&reply)); err != nil {
+		t.Error(err)
+	} else if executed != 7 {
+		t.Errorf("expecting execution for another account, received: %d", executed)
+	}
+	if reserved, err := dm.ReserveRequestResult(&RequestResult{ID: "REQ_PENDING", Pending: true,
+		Expiry: time.Now().Add(time.Minute)}, time.Minute); err != nil || !reserved {
+		t.Fatalf("reserved: %v, error: %v", reserved, err)
+	}
+	if reserved, err := dm.ReserveRequestResult(&RequestResult{ID: "REQ_PENDING", Pending: true,
+		Expiry: time.Now().Add(time.Minute)}, time.Minute); err != nil || reserved {
+		t.Errorf("reserved: %v, error: %v", reserved, err)
+	}
+	if err := dm.SetRequestResult(&RequestResult{ID: utils.ConcatenatedKey(utils.ApierV1AddBalance,
+		"cgrates.org:1004", "REQ_PENDING"), Pending: true, Expiry: time.Now().Add(time.Minute)},
+		time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := ProcessRequestOnce(utils.ApierV1AddBalance, "cgrates.org:1004", "REQ_PENDING", time.Minute,
+		&reply, topup(&reply)); err != utils.ErrRequestInProgress {
+		t.Errorf("expecting: %v, received: %v", utils.ErrRequestInProgress, err)
+	}
+	rr := &RequestResult{ID: "REQ_RESERVED", Pending: true, HolderID: "exec1",
+		Expiry: time.Now().Add(time.Minute)}
+	if reserved, err := dm.ReserveRequestResult(rr, time.Minute); err != nil || !reserved {
+		t.Fatalf("reserved: %v, error: %v", reserved, err)
+	}
+	if err := dm.RemoveRequestResult(&RequestResult{ID: "REQ_RESERVED", Pending: true, HolderID: "exec2",
+		Expiry: rr.Expiry}); err != nil { // not holding it
+		t.Error(err)
+	}
+	if _, err := dm.GetRequestResult("REQ_RESERVED"); err != nil {
+		t.Error(err)
+	}
+	if err := dm.RemoveRequestResult(rr); err != nil {
+		t.Error(err)
+	}
+	if _, err := dm.GetRequestResult("REQ_RESERVED"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err := dm.SetRequestResult(&RequestResult{ID: "REQ_EXPIRED", Reply: utils.OK,
+		Expiry: time.Now().Add(-time.Second)}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dm.GetRequestResult("REQ_EXPIRED"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
@@ -187,6 +187,45 @@ func (rs *Responder) MaxDebit(arg *CallDescriptor, reply *CallCost) (err error)
 	return
 }
 
+// SplitDebit debits the usage out of the payers defined in CGRSplitBilling
+// replies with the CallCosts indexed on payer account key
+func (rs *Responder) SplitDebit(arg *CallDescriptor, reply *map[string]*CallCost) (err error) {
+	return rs.splitDebit(arg, false, reply)
+}
+
+// SplitMaxDebit debits out of the payers defined in CGRSplitBilling as much usage as their credit allows
+func (rs *Responder) SplitMaxDebit(arg *CallDescriptor, reply *map[string]*CallCost) (err error) {
+	cacheKey := utils.MAX_DEBIT_CACHE_PREFIX + arg.CgrID + arg.RunID + arg.DurationIndex.String()
+	if item, err := rs.getCache().Get(cacheKey); err == nil && item != nil {
+		if item.Value != nil {
+			*reply = *(item.Value.(*map[string]*CallCost))
+		}
+		return item.Err
+	}
+	err = rs.splitDebit(arg, true, reply)
+	rs.getCache().Cache(cacheKey, &utils.ResponseCacheItem{
+		Value: reply,
+		Err:   err,
+	})
+	return
+}
+
+func (rs *Responder) splitDebit(arg *CallDescriptor, maxDebit bool,
+	reply *map[string]*CallCost) (err error) {
+	if arg.Subject == "" {
+		arg.Subject = arg.Account
+	}
+	if !rs.usageAllowed(arg.TOR, arg.GetDuration()) {
+		return utils.ErrMaxUsageExceeded
+	}
+	ccs, err := arg.SplitDebit(maxDebit)
+	if err != nil {
+		return
+	}
+	*reply = ccs
+	return
+}
+
 func (rs *Responder) RefundIncrements(arg *CallDescriptor, reply *Account) (err error) {
 	cacheKey := utils.REFUND_INCR_CACHE_PREFIX + arg.CgrID + arg.RunID
 	if item, err := rs.getCache().Get(cacheKey); err == nil && item != nil {
@@ -280,6 +319,53 @@ func (rs *Responder) RefundRounding(arg *CallDescriptor, reply *float64) (err er
 	return
 }
 
+// DebitMinCost debits the cost missing up to the MinCost of a session, passed as the Cost of the Increments
+func (rs *Responder) DebitMinCost(arg *CallDescriptor, reply *CallCost) (err error) {
+	cacheKey := utils.DEBIT_MIN_COST_CACHE_PREFIX + arg.CgrID + arg.RunID + arg.GetAccountKey()
+	if item, err := rs.getCache().Get(cacheKey); err == nil && item != nil {
+		if item.Value != nil {
+			*reply = *(item.Value.(*CallCost))
+		}
+		return item.Err
+	}
+	if arg.Subject == "" {
+		arg.Subject = arg.Account
+	}
+	// replace user profile fields
+	if err := LoadUserProfile(arg, utils.EXTRA_FIELDS); err != nil {
+		return err
+	}
+	// replace aliases
+	if err := LoadAlias(
+		&AttrMatchingAlias{
+			Destination: arg.Destination,
+			Tenant:      arg.Tenant,
+			Category:    arg.Category,
+			Account:     arg.Account,
+			Subject:     arg.Subject,
+			Context:     utils.MetaRating,
+		}, arg, utils.EXTRA_FIELDS); err != nil && err != utils.ErrNotFound {
+		rs.getCache().Cache(cacheKey, &utils.ResponseCacheItem{
+			Err: err,
+		})
+		return err
+	}
+	r, e := arg.DebitMinCost()
+	if e != nil {
+		rs.getCache().Cache(cacheKey, &utils.ResponseCacheItem{
+			Err: e,
+		})
+		return e
+	} else if r != nil {
+		*reply = *r
+	}
+	rs.getCache().Cache(cacheKey, &utils.ResponseCacheItem{
+		Value: reply,
+		Err:   err,
+	})
+	return
+}
+
 func (rs *Responder) GetMaxSessionTime(arg *CallDescriptor, reply *time.Duration) (err error) {
 	if arg.Subject == "" {
 		arg.Subject = arg.Account
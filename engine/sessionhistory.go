@@ -0,0 +1,102 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// SessionHistoryEntry is one record in the history of a session
+type SessionHistoryEntry struct {
+	Time     time.Time
+	Type     string                 // <*initiate|*update|*terminate|*debit|*refund|*disconnect>
+	Usage    time.Duration          // requested usage for events, debited or refunded usage otherwise
+	Cost     float64                // cost of the debit
+	Balances map[string]float64     // balances touched by the debit with their value after it, indexed on AccountID:BalanceID
+	Event    map[string]interface{} // event received, populated on *initiate, *update and *terminate
+	Reason   string                 // reason for disconnect or terminate
+	Error    string                 // error out of the operation, if any
+}
+
+// NewSessionHistoryDebit builds a debit entry out of the CallCost returned by RALs
+func NewSessionHistoryDebit(entryType string, cc *CallCost) (she *SessionHistoryEntry) {
+	she = &SessionHistoryEntry{
+		Time:     time.Now(),
+		Type:     entryType,
+		Balances: make(map[string]float64),
+	}
+	if cc == nil {
+		return
+	}
+	she.Usage = cc.GetDuration()
+	she.Cost = cc.Cost
+	for _, ts := range cc.Timespans {
+		for _, incr := range ts.Increments {
+			if incr.BalanceInfo == nil {
+				continue
+			}
+			if incr.BalanceInfo.Unit != nil {
+				she.Balances[utils.ConcatenatedKey(incr.BalanceInfo.AccountID,
+					incr.BalanceInfo.Unit.ID)] = incr.BalanceInfo.Unit.Value
+			}
+			if incr.BalanceInfo.Monetary != nil {
+				she.Balances[utils.ConcatenatedKey(incr.BalanceInfo.AccountID,
+					incr.BalanceInfo.Monetary.ID)] = incr.BalanceInfo.Monetary.Value
+			}
+		}
+	}
+	return
+}
+
+// NewSessionHistory is the constructor for SessionHistory
+func NewSessionHistory(maxEntries int) *SessionHistory {
+	return &SessionHistory{MaxEntries: maxEntries}
+}
+
+// SessionHistory is a bounded log of what happened during one session run
+type SessionHistory struct {
+	MaxEntries int // maximum number of entries kept, oldest are dropped first, 0 disables history
+	Entries    []*SessionHistoryEntry
+}
+
+// Add will append a new entry, dropping the oldest ones if over MaxEntries
+func (sh *SessionHistory) Add(she *SessionHistoryEntry) {
+	if sh == nil || sh.MaxEntries <= 0 {
+		return
+	}
+	sh.Entries = append(sh.Entries, she)
+	if len(sh.Entries) > sh.MaxEntries {
+		sh.Entries = sh.Entries[len(sh.Entries)-sh.MaxEntries:]
+	}
+}
+
+// Clone returns a copy of the SessionHistory, entries are shared since they are not modified after Add
+func (sh *SessionHistory) Clone() (cln *SessionHistory) {
+	if sh == nil {
+		return
+	}
+	cln = &SessionHistory{MaxEntries: sh.MaxEntries}
+	if sh.Entries != nil {
+		cln.Entries = make([]*SessionHistoryEntry, len(sh.Entries))
+		copy(cln.Entries, sh.Entries)
+	}
+	return
+}
@@ -0,0 +1,89 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestSessionHistoryAdd(t *testing.T) {
+	var nilSH *SessionHistory
+	nilSH.Add(&SessionHistoryEntry{Type: utils.MetaInitiate}) // should not panic
+	sh := NewSessionHistory(0)
+	sh.Add(&SessionHistoryEntry{Type: utils.MetaInitiate})
+	if len(sh.Entries) != 0 {
+		t.Errorf("history disabled but received entries: %s", utils.ToJSON(sh.Entries))
+	}
+	sh = NewSessionHistory(2)
+	for _, evType := range []string{utils.MetaInitiate, utils.MetaDebit,
+		utils.MetaUpdate} {
+		sh.Add(&SessionHistoryEntry{Type: evType})
+	}
+	eEntries := []*SessionHistoryEntry{
+		{Type: utils.MetaDebit},
+		{Type: utils.MetaUpdate},
+	}
+	if !reflect.DeepEqual(eEntries, sh.Entries) {
+		t.Errorf("expecting: %s, received: %s",
+			utils.ToJSON(eEntries), utils.ToJSON(sh.Entries))
+	}
+	cln := sh.Clone()
+	sh.Add(&SessionHistoryEntry{Type: utils.MetaTerminate})
+	if !reflect.DeepEqual(eEntries, cln.Entries) {
+		t.Errorf("expecting: %s, received: %s",
+			utils.ToJSON(eEntries), utils.ToJSON(cln.Entries))
+	}
+}
+
+func TestNewSessionHistoryDebit(t *testing.T) {
+	tStart := time.Date(2018, 7, 26, 10, 0, 0, 0, time.UTC)
+	cc := &CallCost{
+		Cost: 0.6,
+		Timespans: TimeSpans{
+			{
+				TimeStart: tStart,
+				TimeEnd:   tStart.Add(time.Minute),
+				Increments: Increments{
+					{
+						Duration: time.Minute,
+						Cost:     0.6,
+						BalanceInfo: &DebitInfo{
+							Monetary:  &MonetaryInfo{ID: "MONETARY1", Value: 9.4},
+							AccountID: "cgrates.org:1001",
+						},
+					},
+				},
+			},
+		},
+	}
+	she := NewSessionHistoryDebit(utils.MetaDebit, cc)
+	if she.Type != utils.MetaDebit ||
+		she.Usage != time.Minute ||
+		she.Cost != 0.6 {
+		t.Errorf("unexpected entry: %s", utils.ToJSON(she))
+	}
+	eBlncs := map[string]float64{"cgrates.org:1001:MONETARY1": 9.4}
+	if !reflect.DeepEqual(eBlncs, she.Balances) {
+		t.Errorf("expecting: %+v, received: %+v", eBlncs, she.Balances)
+	}
+}
@@ -101,6 +101,9 @@ func (sg *SharedGroup) GetBalances(destination, category, balanceType string, ub
 			if nUb == nil || nUb.Disabled {
 				continue
 			}
+			if ub.auditor != nil { // audit the shared balances debited for ub
+				nUb.auditAs(ub.auditor.cause)
+			}
 		}
 		//sg.members = append(sg.members, nUb)
 		sb := nUb.getBalancesForPrefix(destination, category, balanceType, sg.Id)
@@ -0,0 +1,402 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// SplitPayer is one of the accounts paying for a split billed usage
+type SplitPayer struct {
+	Tenant  string
+	Account string
+	Ratio   float64 // only considered for *ratio splits
+}
+
+// AccountKey returns the tenant:account key of the payer
+func (sp *SplitPayer) AccountKey() string {
+	return utils.ConcatenatedKey(sp.Tenant, sp.Account)
+}
+
+// SplitBilling defines how the usage is charged on more accounts
+// *ratio will split the cost of the usage proportional with the ratio of each payer
+// *fallback will charge the payers in order, moving to the next one when the previous runs out of credit
+type SplitBilling struct {
+	Type   string // <*ratio|*fallback>
+	Payers []*SplitPayer
+}
+
+// NewSplitBillingFromString parses the split billing out of its string representation, ie:
+// *ratio;cgrates.org:company:0.7;cgrates.org:1001:0.3
+// *fallback;cgrates.org:1001;cgrates.org:company
+func NewSplitBillingFromString(sbStr string) (sb *SplitBilling, err error) {
+	sbSplt := strings.Split(sbStr, utils.INFIELD_SEP)
+	if len(sbSplt) < 2 {
+		return nil, fmt.Errorf("invalid split billing: <%s>", sbStr)
+	}
+	sb = &SplitBilling{Type: sbSplt[0],
+		Payers: make([]*SplitPayer, len(sbSplt)-1)}
+	for i, pyrStr := range sbSplt[1:] {
+		pyrSplt := strings.Split(pyrStr, utils.CONCATENATED_KEY_SEP)
+		switch sb.Type {
+		case utils.MetaRatio:
+			if len(pyrSplt) != 3 {
+				return nil, fmt.Errorf("invalid split billing payer: <%s>", pyrStr)
+			}
+			sb.Payers[i] = &SplitPayer{Tenant: pyrSplt[0], Account: pyrSplt[1]}
+			if sb.Payers[i].Ratio, err = strconv.ParseFloat(pyrSplt[2], 64); err != nil {
+				return nil, err
+			}
+			if sb.Payers[i].Ratio <= 0 {
+				return nil, fmt.Errorf("invalid split billing ratio: <%s>", pyrStr)
+			}
+		case utils.MetaFallback:
+			if len(pyrSplt) != 2 {
+				return nil, fmt.Errorf("invalid split billing payer: <%s>", pyrStr)
+			}
+			sb.Payers[i] = &SplitPayer{Tenant: pyrSplt[0], Account: pyrSplt[1]}
+		default:
+			return nil, fmt.Errorf("unsupported split billing type: <%s>", sb.Type)
+		}
+	}
+	return
+}
+
+// SplitBillingRunID returns the RunID under which the costs of one split payer are stored
+func SplitBillingRunID(runID, acntKey string) string {
+	return utils.ConcatenatedKey(runID, acntKey)
+}
+
+// MergeSplitCallCosts returns the CallCost covering the usage of all payers, ordered on time
+// payers sharing the same usage (*ratio) add up their costs
+// AccountSummary will be the one of the first payer
+func MergeSplitCallCosts(ccs map[string]*CallCost) (cc *CallCost) {
+	sortedCCs := make([]*CallCost, 0, len(ccs))
+	for _, pyrCC := range ccs {
+		sortedCCs = append(sortedCCs, pyrCC)
+	}
+	sort.Slice(sortedCCs, func(i, j int) bool {
+		return sortedCCs[i].GetStartTime().Before(sortedCCs[j].GetStartTime())
+	})
+	cc = new(CallCost)
+	for i, pyrCC := range sortedCCs {
+		if i == 0 {
+			*cc = *pyrCC
+			cc.Timespans = append(TimeSpans{}, pyrCC.Timespans...)
+			continue
+		}
+		if pyrCC.GetStartTime().Equal(cc.GetStartTime()) {
+			cc.Cost = utils.NewDecimalFromFloat64(cc.Cost).
+				Add(utils.NewDecimalFromFloat64(pyrCC.Cost)).Float64()
+			continue
+		}
+		cc.Merge(pyrCC)
+	}
+	return
+}
+
+// splitUsage computes the usage each payer will be charged for
+// for *ratio the parts only serve to limit the total usage, the cost being split afterwards
+// maxDebit will limit the usage to the credit available on accounts
+func (sb *SplitBilling) splitUsage(pcds []*CallDescriptor, acnts []*Account,
+	total time.Duration, maxDebit bool) (usages []time.Duration, err error) {
+	usages = make([]time.Duration, len(sb.Payers))
+	switch sb.Type {
+	case utils.MetaRatio:
+		var sumRatio float64
+		for _, pyr := range sb.Payers {
+			sumRatio += pyr.Ratio
+		}
+		ratioUsages := func(usage time.Duration) {
+			var assigned time.Duration
+			for i, pyr := range sb.Payers {
+				if i == len(sb.Payers)-1 { // last one takes the rest so we do not lose nanoseconds
+					usages[i] = usage - assigned
+					break
+				}
+				usages[i] = time.Duration(float64(usage) * pyr.Ratio / sumRatio)
+				assigned += usages[i]
+			}
+		}
+		ratioUsages(total)
+		if !maxDebit {
+			return
+		}
+		// limit the total usage so no payer goes over its available credit
+		allowed := total
+		for i, pcd := range pcds {
+			if usages[i] == 0 {
+				continue
+			}
+			pcd.TimeEnd = pcd.TimeStart.Add(usages[i])
+			var maxUsage time.Duration
+			if maxUsage, err = pcd.getMaxSessionDuration(acnts[i]); err != nil {
+				return
+			}
+			if maxUsage < 0 || maxUsage >= usages[i] { // postpaid or enough credit
+				continue
+			}
+			if pyrAllowed := time.Duration(float64(maxUsage) * sumRatio / sb.Payers[i].Ratio); pyrAllowed < allowed {
+				allowed = pyrAllowed
+			}
+		}
+		if allowed < total {
+			ratioUsages(allowed)
+		}
+	case utils.MetaFallback:
+		remaining := total
+		for i, pcd := range pcds {
+			if remaining <= 0 {
+				break
+			}
+			if i == len(pcds)-1 && !maxDebit { // last payer takes what is left
+				usages[i] = remaining
+				break
+			}
+			pcd.TimeStart = pcd.TimeEnd.Add(-remaining)
+			var maxUsage time.Duration
+			if maxUsage, err = pcd.getMaxSessionDuration(acnts[i]); err != nil {
+				return
+			}
+			if maxUsage < 0 || maxUsage > remaining { // postpaid account covers it all
+				maxUsage = remaining
+			}
+			usages[i] = maxUsage
+			remaining -= maxUsage
+		}
+	default:
+		err = fmt.Errorf("unsupported split billing type: <%s>", sb.Type)
+	}
+	return
+}
+
+// splitDebit has no locks
+func (cd *CallDescriptor) splitDebit(sb *SplitBilling, pcds []*CallDescriptor,
+	acnts []*Account, maxDebit bool) (ccs map[string]*CallCost, err error) {
+	total := cd.GetDuration()
+	for _, pcd := range pcds {
+		pcd.TimeStart, pcd.TimeEnd = cd.TimeStart, cd.TimeEnd
+	}
+	var usages []time.Duration
+	if usages, err = sb.splitUsage(pcds, acnts, total, maxDebit); err != nil {
+		return
+	}
+	if cd.ForceDuration {
+		var sumUsage time.Duration
+		for _, usage := range usages {
+			sumUsage += usage
+		}
+		if sumUsage < total {
+			return nil, utils.ErrInsufficientCredit
+		}
+	}
+	if sb.Type == utils.MetaRatio {
+		var sumUsage time.Duration
+		for _, usage := range usages {
+			sumUsage += usage
+		}
+		return cd.splitCost(sb, pcds, acnts, sumUsage)
+	}
+	ccs = make(map[string]*CallCost)
+	tStart := cd.TimeStart
+	for i, pcd := range pcds {
+		if usages[i] == 0 {
+			continue
+		}
+		pcd.TimeStart = tStart
+		pcd.TimeEnd = tStart.Add(usages[i])
+		pcd.DurationIndex = cd.DurationIndex - cd.TimeEnd.Sub(pcd.TimeEnd)
+		pcd.LoopIndex = cd.LoopIndex
+		if tStart != cd.TimeStart {
+			pcd.LoopIndex++ // connect fee is charged only on the first part
+		}
+		var cc *CallCost
+		if cc, err = pcd.debit(acnts[i], cd.DryRun, !cd.DenyNegativeAccount); err != nil {
+			if !cd.DryRun { // do not leave the split half charged
+				cd.refundSplitDebits(ccs)
+			}
+			return nil, err
+		}
+		cc.AccountSummary = pcd.AccountSummary()
+		ccs[sb.Payers[i].AccountKey()] = cc
+		tStart = pcd.TimeEnd
+	}
+	return
+}
+
+// splitCost rates the usage once and debits its cost on the *ratio payers, proportional with their ratio
+// the last payer takes the rest so the parts always sum back to the rated cost
+// has no locks
+func (cd *CallDescriptor) splitCost(sb *SplitBilling, pcds []*CallDescriptor,
+	acnts []*Account, usage time.Duration) (ccs map[string]*CallCost, err error) {
+	ccs = make(map[string]*CallCost)
+	if usage == 0 {
+		return
+	}
+	rcd := cd.Clone()
+	rcd.TimeEnd = rcd.TimeStart.Add(usage)
+	rcd.DurationIndex = cd.DurationIndex - cd.TimeEnd.Sub(rcd.TimeEnd)
+	var rcc *CallCost
+	if rcc, err = rcd.GetCost(); err != nil {
+		return nil, err
+	}
+	if len(rcc.Timespans) == 0 || rcc.Timespans[0].RateInterval == nil { // no currency to debit the cost in
+		return nil, utils.ErrRatingPlanNotFound
+	}
+	var sumRatio float64
+	for _, pyr := range sb.Payers {
+		sumRatio += pyr.Ratio
+	}
+	roundingDecimals, roundingMethod := rcc.GetLongestRounding()
+	total := utils.NewDecimalFromFloat64(rcc.Cost)
+	assigned := utils.NewDecimalFromInt64(0)
+	for i, pcd := range pcds {
+		share := total.Sub(assigned)
+		if i != len(pcds)-1 {
+			share = total.Mul(utils.NewDecimalFromFloat64(sb.Payers[i].Ratio)).
+				Div(utils.NewDecimalFromFloat64(sumRatio)).Round(roundingDecimals, roundingMethod)
+			assigned = assigned.Add(share)
+		}
+		ts := &TimeSpan{
+			TimeStart:      rcd.TimeStart,
+			TimeEnd:        rcd.TimeEnd,
+			CompressFactor: 1,
+			RateInterval:   rcc.Timespans[0].RateInterval,
+			Increments: Increments{ // carries the usage, the cost goes on the debit increment
+				&Increment{Duration: usage, CompressFactor: 1, paid: true}},
+		}
+		pcc := pcd.CreateCallCost()
+		pcc.Timespans = TimeSpans{ts}
+		if err = cd.debitSplitShare(acnts[i], pcd, pcc, share.Float64()); err != nil {
+			if !cd.DryRun { // do not leave the split half charged
+				cd.refundSplitDebits(ccs)
+			}
+			return nil, err
+		}
+		pcc.updateCost()
+		pcc.AccountSummary = acnts[i].AsAccountSummary()
+		ccs[sb.Payers[i].AccountKey()] = pcc
+	}
+	return
+}
+
+// debitSplitShare debits the share of one *ratio payer out of its monetary balances
+// on DryRun the debit is done on a copy of the account
+func (cd *CallDescriptor) debitSplitShare(acnt *Account, pcd *CallDescriptor,
+	pcc *CallCost, share float64) (err error) {
+	if cd.DryRun {
+		acnt = acnt.Clone()
+	} else {
+		prevCause := acnt.auditAs(&auditCause{cause: utils.MetaDebit, cgrID: cd.CgrID})
+		defer acnt.endAudit(prevCause)
+	}
+	moneyBalances := acnt.getAlldBalancesForPrefix(pcd.Destination, pcd.Category, utils.MONETARY)
+	if err = acnt.debitMinCost(pcc, moneyBalances, share,
+		!cd.DenyNegativeAccount, !cd.DryRun); err != nil {
+		return
+	}
+	if !cd.DryRun {
+		moneyBalances.SaveDirtyBalances(acnt)
+		setAuditedAccount(acnt)
+	}
+	return
+}
+
+// refundSplitDebits gives back to the payers what was debited within a failed split
+// has no locks, the payers are expected to be still locked
+func (cd *CallDescriptor) refundSplitDebits(ccs map[string]*CallCost) {
+	for acntKey, cc := range ccs {
+		rcd := cc.CreateCallDescriptor()
+		rcd.CgrID, rcd.RunID = cd.CgrID, cd.RunID
+		for _, ts := range cc.Timespans {
+			for _, incr := range ts.Increments {
+				if incr.BalanceInfo == nil ||
+					(incr.BalanceInfo.Unit == nil && incr.BalanceInfo.Monetary == nil) {
+					continue // nothing debited out of balances
+				}
+				rcd.Increments = append(rcd.Increments, incr)
+			}
+		}
+		rcd.Increments.Decompress()
+		if _, err := rcd.refundIncrements(); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> failed refunding split payer <%s>, error: %s",
+					utils.RALService, acntKey, err.Error()))
+		}
+	}
+}
+
+// SplitDebit charges the usage on the payers defined by the CGRSplitBilling extra field
+// all payers are locked together so the split happens in one step
+// returns the CallCosts indexed on account key of the payers
+func (cd *CallDescriptor) SplitDebit(maxDebit bool) (ccs map[string]*CallCost, err error) {
+	sbStr, has := cd.ExtraFields[utils.CGRSplitBilling]
+	if !has {
+		return nil, utils.NewErrMandatoryIeMissing(utils.CGRSplitBilling)
+	}
+	var sb *SplitBilling
+	if sb, err = NewSplitBillingFromString(sbStr); err != nil {
+		return
+	}
+	if cd.TOR == "" {
+		cd.TOR = utils.VOICE
+	}
+	cd.tierUsages = nil // computed for each payer
+	pyrLkIDs := make(utils.StringMap)
+	for _, pyr := range sb.Payers {
+		pyrLkIDs[utils.ACCOUNT_PREFIX+pyr.AccountKey()] = true
+	}
+	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+		pcds := make([]*CallDescriptor, len(sb.Payers))
+		acnts := make([]*Account, len(sb.Payers))
+		shrdLkIDs := make(utils.StringMap)
+		for i, pyr := range sb.Payers {
+			pcds[i] = cd.Clone()
+			pcds[i].Tenant, pcds[i].Account = pyr.Tenant, pyr.Account
+			pcds[i].ExtraFields = cd.ExtraFields
+			pcds[i].DenyNegativeAccount = cd.DenyNegativeAccount
+			if acnts[i], err = pcds[i].getAccount(); err != nil {
+				return
+			}
+			acntIDs, err := acnts[i].GetUniqueSharedGroupMembers(pcds[i])
+			if err != nil {
+				return nil, err
+			}
+			for acntID := range acntIDs {
+				if lkID := utils.ACCOUNT_PREFIX + acntID; !pyrLkIDs[lkID] {
+					shrdLkIDs[lkID] = true
+				}
+			}
+		}
+		_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
+			ccs, err = cd.splitDebit(sb, pcds, acnts, maxDebit)
+			return
+		}, config.CgrConfig().GeneralCfg().LockingTimeout, shrdLkIDs.Slice()...)
+		return
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, pyrLkIDs.Slice()...)
+	return
+}
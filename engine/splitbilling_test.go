@@ -0,0 +1,162 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestNewSplitBillingFromString(t *testing.T) {
+	eSB := &SplitBilling{Type: utils.MetaRatio,
+		Payers: []*SplitPayer{
+			{Tenant: "cgrates.org", Account: "company", Ratio: 0.7},
+			{Tenant: "cgrates.org", Account: "1001", Ratio: 0.3},
+		}}
+	if sb, err := NewSplitBillingFromString("*ratio;cgrates.org:company:0.7;cgrates.org:1001:0.3"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eSB, sb) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eSB), utils.ToJSON(sb))
+	}
+	eSB = &SplitBilling{Type: utils.MetaFallback,
+		Payers: []*SplitPayer{
+			{Tenant: "cgrates.org", Account: "1001"},
+			{Tenant: "itsyscom.com", Account: "company"},
+		}}
+	if sb, err := NewSplitBillingFromString("*fallback;cgrates.org:1001;itsyscom.com:company"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eSB, sb) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eSB), utils.ToJSON(sb))
+	}
+	for _, sbStr := range []string{"*ratio", "*ratio;cgrates.org:1001",
+		"*ratio;cgrates.org:1001:-1", "*fallback;cgrates.org:1001:0.3",
+		"*unsupported;cgrates.org:1001"} {
+		if _, err := NewSplitBillingFromString(sbStr); err == nil {
+			t.Errorf("expecting error for: <%s>", sbStr)
+		}
+	}
+}
+
+func TestSplitDebit(t *testing.T) {
+	for _, acntID := range []string{"split_company", "split_employee", "split_empty"} {
+		value := 10.0
+		if acntID == "split_empty" {
+			value = 0
+		}
+		if err := dm.DataDB().SetAccount(&Account{ID: "cgrates.org:" + acntID,
+			BalanceMap: map[string]Balances{utils.MONETARY: {
+				&Balance{Uuid: utils.GenUUID(), Value: value, Weight: 10}}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cd := &CallDescriptor{
+		Category:    "call",
+		Tenant:      "cgrates.org",
+		Subject:     "dy",
+		Account:     "split_employee",
+		Destination: "0723123113",
+		TimeStart:   time.Date(2016, 3, 4, 13, 50, 0, 0, time.UTC),
+		TimeEnd:     time.Date(2016, 3, 4, 13, 52, 0, 0, time.UTC),
+		ExtraFields: map[string]string{
+			utils.CGRSplitBilling: "*ratio;cgrates.org:split_company:0.5;cgrates.org:split_employee:0.5"},
+	}
+	rcc, err := cd.Clone().GetCost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ccs, err := cd.SplitDebit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ccs) != 2 {
+		t.Fatalf("unexpected costs: %s", utils.ToJSON(ccs))
+	}
+	sumCost := utils.NewDecimalFromInt64(0)
+	for _, acntID := range []string{"cgrates.org:split_company", "cgrates.org:split_employee"} {
+		if ccs[acntID].GetDuration() != 2*time.Minute {
+			t.Errorf("account: %s, unexpected duration: %v", acntID, ccs[acntID].GetDuration())
+		}
+		if ccs[acntID].GetStartTime() != cd.TimeStart {
+			t.Errorf("account: %s, unexpected start time: %v", acntID, ccs[acntID].GetStartTime())
+		}
+		if acnt, err := dm.DataDB().GetAccount(acntID); err != nil {
+			t.Error(err)
+		} else if blncVal := acnt.BalanceMap[utils.MONETARY][0].GetValue(); blncVal !=
+			utils.NewDecimalFromInt64(10).Sub(utils.NewDecimalFromFloat64(ccs[acntID].Cost)).Float64() {
+			t.Errorf("account: %s, balance: %v not debited with cost: %v", acntID, blncVal, ccs[acntID].Cost)
+		}
+		sumCost = sumCost.Add(utils.NewDecimalFromFloat64(ccs[acntID].Cost))
+	}
+	if sumCost.Float64() != rcc.Cost {
+		t.Errorf("split costs: %v not summing to the rated cost: %v", sumCost.Float64(), rcc.Cost)
+	}
+	if cc := MergeSplitCallCosts(ccs); cc.GetDuration() != 2*time.Minute {
+		t.Errorf("unexpected merged duration: %v", cc.GetDuration())
+	} else if cc.Cost != rcc.Cost {
+		t.Errorf("expecting merged cost: %v, received: %v", rcc.Cost, cc.Cost)
+	}
+	// empty account first, all usage should go on the fallback one
+	cd.TimeStart = time.Date(2016, 3, 4, 14, 50, 0, 0, time.UTC)
+	cd.TimeEnd = time.Date(2016, 3, 4, 14, 51, 0, 0, time.UTC)
+	cd.ExtraFields[utils.CGRSplitBilling] = "*fallback;cgrates.org:split_empty;cgrates.org:split_company"
+	if ccs, err = cd.SplitDebit(true); err != nil {
+		t.Fatal(err)
+	}
+	if len(ccs) != 1 {
+		t.Fatalf("unexpected costs: %s", utils.ToJSON(ccs))
+	}
+	if ccs["cgrates.org:split_company"].GetDuration() != time.Minute {
+		t.Errorf("unexpected costs: %s", utils.ToJSON(ccs))
+	}
+	cd.ExtraFields[utils.CGRSplitBilling] = "*fallback;cgrates.org:split_missing;cgrates.org:split_company"
+	if _, err = cd.SplitDebit(true); err != utils.ErrAccountNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrAccountNotFound, err)
+	}
+}
+
+func TestRefundSplitDebits(t *testing.T) {
+	blncUUID := utils.GenUUID()
+	if err := dm.DataDB().SetAccount(&Account{ID: "cgrates.org:split_refunded",
+		BalanceMap: map[string]Balances{utils.MONETARY: {
+			&Balance{Uuid: blncUUID, Value: 8, Weight: 10}}}}); err != nil {
+		t.Fatal(err)
+	}
+	cd := &CallDescriptor{CgrID: "split_refund", RunID: utils.META_DEFAULT}
+	ccs := map[string]*CallCost{
+		"cgrates.org:split_refunded": {
+			Category: "call", Tenant: "cgrates.org", Account: "split_refunded",
+			Subject: "split_refunded", TOR: utils.VOICE,
+			Timespans: TimeSpans{&TimeSpan{Increments: Increments{
+				&Increment{Duration: time.Minute, Cost: 1, CompressFactor: 2,
+					BalanceInfo: &DebitInfo{AccountID: "cgrates.org:split_refunded",
+						Monetary: &MonetaryInfo{UUID: blncUUID}}},
+				&Increment{Duration: time.Minute, Cost: 0, CompressFactor: 1}, // free, not refunded
+			}}},
+		},
+	}
+	cd.refundSplitDebits(ccs)
+	if acnt, err := dm.DataDB().GetAccount("cgrates.org:split_refunded"); err != nil {
+		t.Error(err)
+	} else if blncVal := acnt.BalanceMap[utils.MONETARY][0].GetValue(); blncVal != 10 {
+		t.Errorf("expecting balance refunded to 10, received: %v", blncVal)
+	}
+}
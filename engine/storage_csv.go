@@ -38,7 +38,8 @@ type CSVStorage struct {
 	accountactionsFn, derivedChargersFn,
 	usersFn, aliasesFn, resProfilesFn, statsFn, thresholdsFn,
 	filterFn, suppProfilesFn, attributeProfilesFn,
-	chargerProfilesFn, dispatcherProfilesFn string
+	chargerProfilesFn, dispatcherProfilesFn, exchangeRatesFn,
+	taxProfilesFn, calendarsFn string
 }
 
 func NewFileCSVStorage(sep rune,
@@ -47,7 +48,8 @@ func NewFileCSVStorage(sep rune,
 	actionsFn, actiontimingsFn, actiontriggersFn, accountactionsFn,
 	derivedChargersFn, usersFn, aliasesFn,
 	resProfilesFn, statsFn, thresholdsFn, filterFn, suppProfilesFn,
-	attributeProfilesFn, chargerProfilesFn, dispatcherProfilesFn string) *CSVStorage {
+	attributeProfilesFn, chargerProfilesFn, dispatcherProfilesFn,
+	exchangeRatesFn, taxProfilesFn, calendarsFn string) *CSVStorage {
 	return &CSVStorage{
 		sep:                      sep,
 		readerFunc:               openFileCSVStorage,
@@ -73,6 +75,9 @@ func NewFileCSVStorage(sep rune,
 		attributeProfilesFn:      attributeProfilesFn,
 		chargerProfilesFn:        chargerProfilesFn,
 		dispatcherProfilesFn:     dispatcherProfilesFn,
+		exchangeRatesFn:          exchangeRatesFn,
+		taxProfilesFn:            taxProfilesFn,
+		calendarsFn:              calendarsFn,
 	}
 }
 
@@ -84,7 +89,8 @@ func NewStringCSVStorage(sep rune,
 	aliasesFn, resProfilesFn, statsFn,
 	thresholdsFn, filterFn, suppProfilesFn,
 	attributeProfilesFn, chargerProfilesFn,
-	dispatcherProfilesFn string) *CSVStorage {
+	dispatcherProfilesFn, exchangeRatesFn, taxProfilesFn,
+	calendarsFn string) *CSVStorage {
 	c := NewFileCSVStorage(sep, destinationsFn, timingsFn,
 		ratesFn, destinationratesFn, destinationratetimingsFn,
 		ratingprofilesFn, sharedgroupsFn, actionsFn,
@@ -92,7 +98,8 @@ func NewStringCSVStorage(sep rune,
 		accountactionsFn, derivedChargersFn,
 		usersFn, aliasesFn, resProfilesFn,
 		statsFn, thresholdsFn, filterFn, suppProfilesFn,
-		attributeProfilesFn, chargerProfilesFn, dispatcherProfilesFn)
+		attributeProfilesFn, chargerProfilesFn, dispatcherProfilesFn,
+		exchangeRatesFn, taxProfilesFn, calendarsFn)
 	c.readerFunc = openStringCSVStorage
 	return c
 }
@@ -122,7 +129,7 @@ func openStringCSVStorage(data string, comma rune,
 }
 
 func (csvs *CSVStorage) GetTPTimings(tpid, id string) ([]*utils.ApierTPTiming, error) {
-	csvReader, fp, err := csvs.readerFunc(csvs.timingsFn, csvs.sep, getColumnCount(TpTiming{}))
+	csvReader, fp, err := csvs.readerFunc(csvs.timingsFn, csvs.sep, getFieldsPerRecord(TpTiming{}))
 	if err != nil {
 		//log.Print("Could not load timings file: ", err)
 		// allow writing of the other values
@@ -210,7 +217,7 @@ func (csvs *CSVStorage) GetTPRates(tpid, id string) ([]*utils.TPRate, error) {
 }
 
 func (csvs *CSVStorage) GetTPDestinationRates(tpid, id string, p *utils.Paginator) ([]*utils.TPDestinationRate, error) {
-	csvReader, fp, err := csvs.readerFunc(csvs.destinationratesFn, csvs.sep, getColumnCount(TpDestinationRate{}))
+	csvReader, fp, err := csvs.readerFunc(csvs.destinationratesFn, csvs.sep, getFieldsPerRecord(TpDestinationRate{}))
 	if err != nil {
 		//log.Print("Could not load destination_rates file: ", err)
 		// allow writing of the other values
@@ -341,7 +348,7 @@ func (csvs *CSVStorage) GetTPSharedGroups(tpid, id string) ([]*utils.TPSharedGro
 }
 
 func (csvs *CSVStorage) GetTPActions(tpid, id string) ([]*utils.TPActions, error) {
-	csvReader, fp, err := csvs.readerFunc(csvs.actionsFn, csvs.sep, getColumnCount(TpAction{}))
+	csvReader, fp, err := csvs.readerFunc(csvs.actionsFn, csvs.sep, getFieldsPerRecord(TpAction{}))
 	if err != nil {
 		//log.Print("Could not load action file: ", err)
 		// allow writing of the other values
@@ -433,7 +440,7 @@ func (csvs *CSVStorage) GetTPActionTriggers(tpid, id string) ([]*utils.TPActionT
 }
 
 func (csvs *CSVStorage) GetTPAccountActions(filter *utils.TPAccountActions) ([]*utils.TPAccountActions, error) {
-	csvReader, fp, err := csvs.readerFunc(csvs.accountactionsFn, csvs.sep, getColumnCount(TpAccountAction{}))
+	csvReader, fp, err := csvs.readerFunc(csvs.accountactionsFn, csvs.sep, getFieldsPerRecord(TpAccountAction{}))
 	if err != nil {
 		//log.Print("Could not load account actions file: ", err)
 		// allow writing of the other values
@@ -793,6 +800,87 @@ func (csvs *CSVStorage) GetTPDispatchers(tpid, tenant, id string) ([]*utils.TPDi
 	return tpDPPs.AsTPDispatchers(), nil
 }
 
+func (csvs *CSVStorage) GetTPExchangeRates(tpid string) ([]*utils.TPExchangeRate, error) {
+	csvReader, fp, err := csvs.readerFunc(csvs.exchangeRatesFn, csvs.sep, getColumnCount(TpExchangeRate{}))
+	if err != nil {
+		// allow writing of the other values
+		return nil, nil
+	}
+	if fp != nil {
+		defer fp.Close()
+	}
+	var tpExrs TpExchangeRates
+	for record, err := csvReader.Read(); err != io.EOF; record, err = csvReader.Read() {
+		if err != nil {
+			log.Printf("bad line in %s, %s\n", csvs.exchangeRatesFn, err.Error())
+			return nil, err
+		}
+		if exr, err := csvLoad(TpExchangeRate{}, record); err != nil {
+			log.Print("error loading exchange rate: ", err)
+			return nil, err
+		} else {
+			exr := exr.(TpExchangeRate)
+			exr.Tpid = tpid
+			tpExrs = append(tpExrs, exr)
+		}
+	}
+	return tpExrs.AsTPExchangeRates(), nil
+}
+
+func (csvs *CSVStorage) GetTPTaxProfiles(tpid, tenant, id string) ([]*utils.TPTaxProfile, error) {
+	csvReader, fp, err := csvs.readerFunc(csvs.taxProfilesFn, csvs.sep, getColumnCount(TpTaxProfile{}))
+	if err != nil {
+		// allow writing of the other values
+		return nil, nil
+	}
+	if fp != nil {
+		defer fp.Close()
+	}
+	var tpTxps TpTaxProfiles
+	for record, err := csvReader.Read(); err != io.EOF; record, err = csvReader.Read() {
+		if err != nil {
+			log.Printf("bad line in %s, %s\n", csvs.taxProfilesFn, err.Error())
+			return nil, err
+		}
+		if txp, err := csvLoad(TpTaxProfile{}, record); err != nil {
+			log.Print("error loading tpTaxProfile: ", err)
+			return nil, err
+		} else {
+			txp := txp.(TpTaxProfile)
+			txp.Tpid = tpid
+			tpTxps = append(tpTxps, &txp)
+		}
+	}
+	return tpTxps.AsTPTaxProfiles(), nil
+}
+
+func (csvs *CSVStorage) GetTPCalendars(tpid, tenant, id string) ([]*utils.TPCalendar, error) {
+	csvReader, fp, err := csvs.readerFunc(csvs.calendarsFn, csvs.sep, getColumnCount(TpCalendar{}))
+	if err != nil {
+		// allow writing of the other values
+		return nil, nil
+	}
+	if fp != nil {
+		defer fp.Close()
+	}
+	var tpCals TpCalendars
+	for record, err := csvReader.Read(); err != io.EOF; record, err = csvReader.Read() {
+		if err != nil {
+			log.Printf("bad line in %s, %s\n", csvs.calendarsFn, err.Error())
+			return nil, err
+		}
+		if cal, err := csvLoad(TpCalendar{}, record); err != nil {
+			log.Print("error loading tpCalendar: ", err)
+			return nil, err
+		} else {
+			cal := cal.(TpCalendar)
+			cal.Tpid = tpid
+			tpCals = append(tpCals, &cal)
+		}
+	}
+	return tpCals.AsTPCalendars(), nil
+}
+
 func (csvs *CSVStorage) GetTpIds(colName string) ([]string, error) {
 	return nil, utils.ErrNotImplemented
 }
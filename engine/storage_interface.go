@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 	"github.com/mongodb/mongo-go-driver/bson"
@@ -143,6 +144,35 @@ type DataDB interface {
 	GetDispatcherProfileDrv(string, string) (*DispatcherProfile, error)
 	SetDispatcherProfileDrv(*DispatcherProfile) error
 	RemoveDispatcherProfileDrv(string, string) error
+	GetNodeSessionsDrv(string) (*NodeSessions, error)
+	SetNodeSessionsDrv(*NodeSessions) error
+	RemoveNodeSessionsDrv(string) error
+	ClaimNodeSessionsDrv(string, time.Time) error
+	GetExchangeRateDrv(string, string) (*ExchangeRate, error)
+	SetExchangeRateDrv(*ExchangeRate) error
+	RemoveExchangeRateDrv(string, string) error
+	GetTaxProfileDrv(string, string) (*TaxProfile, error)
+	SetTaxProfileDrv(*TaxProfile) error
+	RemoveTaxProfileDrv(string, string) error
+	GetCalendarDrv(string, string) (*Calendar, error)
+	SetCalendarDrv(*Calendar) error
+	RemoveCalendarDrv(string, string) error
+	GetLeaseDrv(string) (*Lease, error)
+	AcquireLeaseDrv(string, string, time.Duration) (bool, error)
+	ReleaseLeaseDrv(string, string) error
+	GetLastExecutionDrv(string) (time.Time, error)
+	SetLastExecutionDrv(string, time.Time) error
+	GetExecutionCheckpointDrv(string) (*ExecutionCheckpoint, error)
+	SetExecutionCheckpointDrv(string, *ExecutionCheckpoint) error
+	RemoveExecutionCheckpointDrv(string) error
+	GetExecutionHistoryDrv(int, int) ([]*ExecutionRecord, error)
+	AddExecutionRecordsDrv([]*ExecutionRecord, int) error
+	GetRequestResultDrv(string) (*RequestResult, error)
+	SetRequestResultDrv(*RequestResult, time.Duration) error
+	ReserveRequestResultDrv(*RequestResult, time.Duration) (bool, error)
+	RemoveRequestResultDrv(*RequestResult) error
+	GetCDRDedupRecordDrv(string) (*CDRDedupRecord, error)
+	SetCDRDedupRecordDrv(*CDRDedupRecord, time.Duration) error
 }
 
 type StorDB interface {
@@ -158,6 +188,10 @@ type CdrStorage interface {
 	GetSMCosts(cgrid, runid, originHost, originIDPrfx string) ([]*SMCost, error)
 	RemoveSMCost(*SMCost) error
 	GetCDRs(*utils.CDRsFilter, bool) ([]*CDR, int64, error)
+	SetAccountAudits([]*AccountAudit) error
+	GetAccountAudits(*AccountAuditFilter) ([]*AccountAudit, error)
+	SetAccountSnapshot(*AccountSnapshot) error
+	GetAccountSnapshot(tenant, account string, before time.Time) (*AccountSnapshot, error)
 }
 
 type LoadStorage interface {
@@ -193,6 +227,9 @@ type LoadReader interface {
 	GetTPAttributes(string, string, string) ([]*utils.TPAttributeProfile, error)
 	GetTPChargers(string, string, string) ([]*utils.TPChargerProfile, error)
 	GetTPDispatchers(string, string, string) ([]*utils.TPDispatcherProfile, error)
+	GetTPExchangeRates(string) ([]*utils.TPExchangeRate, error)
+	GetTPTaxProfiles(string, string, string) ([]*utils.TPTaxProfile, error)
+	GetTPCalendars(string, string, string) ([]*utils.TPCalendar, error)
 }
 
 type LoadWriter interface {
@@ -219,6 +256,9 @@ type LoadWriter interface {
 	SetTPAttributes([]*utils.TPAttributeProfile) error
 	SetTPChargers([]*utils.TPChargerProfile) error
 	SetTPDispatchers([]*utils.TPDispatcherProfile) error
+	SetTPExchangeRates([]*utils.TPExchangeRate) error
+	SetTPTaxProfiles([]*utils.TPTaxProfile) error
+	SetTPCalendars([]*utils.TPCalendar) error
 }
 
 // NewMarshaler returns the marshaler type selected by mrshlerStr
@@ -25,6 +25,7 @@ import (
 	"io/ioutil"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
@@ -548,6 +549,8 @@ func (ms *MapStorage) SetAccount(ub *Account) (err error) {
 			ac.UnitCounters = ub.UnitCounters
 			ac.AllowNegative = ub.AllowNegative
 			ac.Disabled = ub.Disabled
+			ac.DebitPolicy = ub.DebitPolicy
+			ac.Subscriptions = ub.Subscriptions
 			ub = ac
 		}
 	}
@@ -1640,6 +1643,384 @@ func (ms *MapStorage) RemoveVersions(vrs Versions) (err error) {
 	return nil
 }
 
+func (ms *MapStorage) GetNodeSessionsDrv(nodeID string) (ns *NodeSessions, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.NodeSessionsPrefix+nodeID]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &ns)
+	return
+}
+
+func (ms *MapStorage) SetNodeSessionsDrv(ns *NodeSessions) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(ns)
+	if err != nil {
+		return err
+	}
+	ms.dict[utils.NodeSessionsPrefix+ns.NodeID] = result
+	return
+}
+
+func (ms *MapStorage) RemoveNodeSessionsDrv(nodeID string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	key := utils.NodeSessionsPrefix + nodeID
+	if _, has := ms.dict[key]; !has {
+		return utils.ErrNotFound
+	}
+	delete(ms.dict, key)
+	return
+}
+
+func (ms *MapStorage) ClaimNodeSessionsDrv(nodeID string, heartbeat time.Time) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	key := utils.NodeSessionsPrefix + nodeID
+	values, has := ms.dict[key]
+	if !has {
+		return utils.ErrNotFound
+	}
+	var ns *NodeSessions
+	if err = ms.ms.Unmarshal(values, &ns); err != nil {
+		return
+	}
+	if !ns.Heartbeat.Equal(heartbeat) {
+		return utils.ErrNotFound
+	}
+	delete(ms.dict, key)
+	return
+}
+
+func (ms *MapStorage) GetExchangeRateDrv(fromCurrency, toCurrency string) (exr *ExchangeRate, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.ExchangeRatePrefix+utils.ConcatenatedKey(fromCurrency, toCurrency)]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &exr)
+	return
+}
+
+func (ms *MapStorage) SetExchangeRateDrv(exr *ExchangeRate) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(exr)
+	if err != nil {
+		return err
+	}
+	ms.dict[utils.ExchangeRatePrefix+exr.ID()] = result
+	return
+}
+
+func (ms *MapStorage) RemoveExchangeRateDrv(fromCurrency, toCurrency string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.dict, utils.ExchangeRatePrefix+utils.ConcatenatedKey(fromCurrency, toCurrency))
+	return
+}
+
+func (ms *MapStorage) GetTaxProfileDrv(tenant, id string) (txp *TaxProfile, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.TaxProfilePrefix+utils.ConcatenatedKey(tenant, id)]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &txp)
+	return
+}
+
+func (ms *MapStorage) SetTaxProfileDrv(txp *TaxProfile) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(txp)
+	if err != nil {
+		return err
+	}
+	ms.dict[utils.TaxProfilePrefix+txp.TenantID()] = result
+	return
+}
+
+func (ms *MapStorage) RemoveTaxProfileDrv(tenant, id string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.dict, utils.TaxProfilePrefix+utils.ConcatenatedKey(tenant, id))
+	return
+}
+
+func (ms *MapStorage) GetCalendarDrv(tenant, id string) (cal *Calendar, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.CalendarPrefix+utils.ConcatenatedKey(tenant, id)]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &cal)
+	return
+}
+
+func (ms *MapStorage) SetCalendarDrv(cal *Calendar) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(cal)
+	if err != nil {
+		return err
+	}
+	ms.dict[utils.CalendarPrefix+cal.TenantID()] = result
+	return
+}
+
+func (ms *MapStorage) RemoveCalendarDrv(tenant, id string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.dict, utils.CalendarPrefix+utils.ConcatenatedKey(tenant, id))
+	return
+}
+
+// getLease is not locking, callers should do it
+func (ms *MapStorage) getLease(leaseID string) (ls *Lease, err error) {
+	values, ok := ms.dict[utils.LeasePrefix+leaseID]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &ls)
+	return
+}
+
+func (ms *MapStorage) GetLeaseDrv(leaseID string) (ls *Lease, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	if ls, err = ms.getLease(leaseID); err != nil {
+		return
+	}
+	if ls.Expired(time.Now()) {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+func (ms *MapStorage) AcquireLeaseDrv(leaseID, holderID string, ttl time.Duration) (acquired bool, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	now := time.Now()
+	if ls, err := ms.getLease(leaseID); err == nil &&
+		ls.HolderID != holderID && !ls.Expired(now) {
+		return false, nil
+	}
+	result, err := ms.ms.Marshal(&Lease{ID: leaseID, HolderID: holderID, Expiry: now.Add(ttl)})
+	if err != nil {
+		return
+	}
+	ms.dict[utils.LeasePrefix+leaseID] = result
+	return true, nil
+}
+
+func (ms *MapStorage) GetLastExecutionDrv(execKey string) (execTime time.Time, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.LastExecutionPrefix+execKey]
+	if !ok {
+		return execTime, utils.ErrNotFound
+	}
+	err = execTime.UnmarshalText(values)
+	return
+}
+
+func (ms *MapStorage) SetLastExecutionDrv(execKey string, execTime time.Time) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := execTime.MarshalText()
+	if err != nil {
+		return
+	}
+	ms.dict[utils.LastExecutionPrefix+execKey] = result
+	return
+}
+
+func (ms *MapStorage) GetExecutionCheckpointDrv(execKey string) (chk *ExecutionCheckpoint, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.ExecutionCheckpointPrefix+execKey]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &chk)
+	return
+}
+
+func (ms *MapStorage) SetExecutionCheckpointDrv(execKey string, chk *ExecutionCheckpoint) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(chk)
+	if err != nil {
+		return
+	}
+	ms.dict[utils.ExecutionCheckpointPrefix+execKey] = result
+	return
+}
+
+func (ms *MapStorage) RemoveExecutionCheckpointDrv(execKey string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.dict, utils.ExecutionCheckpointPrefix+execKey)
+	return
+}
+
+func (ms *MapStorage) GetExecutionHistoryDrv(offset, limit int) (recs []*ExecutionRecord, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.ExecutionHistoryKey]
+	if !ok {
+		return
+	}
+	if err = ms.ms.Unmarshal(values, &recs); err != nil {
+		return nil, err
+	}
+	if offset >= len(recs) {
+		return nil, nil
+	}
+	recs = recs[offset:]
+	if limit > 0 && limit < len(recs) {
+		recs = recs[:limit]
+	}
+	return
+}
+
+func (ms *MapStorage) AddExecutionRecordsDrv(recs []*ExecutionRecord, histSize int) (err error) {
+	if histSize <= 0 || len(recs) == 0 {
+		return
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var hist []*ExecutionRecord
+	if values, ok := ms.dict[utils.ExecutionHistoryKey]; ok {
+		if err = ms.ms.Unmarshal(values, &hist); err != nil {
+			return
+		}
+	}
+	newHist := make([]*ExecutionRecord, 0, len(recs)+len(hist))
+	for i := len(recs) - 1; i >= 0; i-- { // newest first
+		newHist = append(newHist, recs[i])
+	}
+	newHist = append(newHist, hist...)
+	if len(newHist) > histSize {
+		newHist = newHist[:histSize]
+	}
+	result, err := ms.ms.Marshal(newHist)
+	if err != nil {
+		return
+	}
+	ms.dict[utils.ExecutionHistoryKey] = result
+	return
+}
+
+func (ms *MapStorage) GetRequestResultDrv(reqID string) (rr *RequestResult, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.RequestResultPrefix+reqID]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	if err = ms.ms.Unmarshal(values, &rr); err != nil {
+		return
+	}
+	if rr.Expired(time.Now()) {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+// SetRequestResultDrv keeps the expiry within the result, checked on read
+func (ms *MapStorage) SetRequestResultDrv(rr *RequestResult, ttl time.Duration) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(rr)
+	if err != nil {
+		return
+	}
+	ms.dict[utils.RequestResultPrefix+rr.ID] = result
+	return
+}
+
+func (ms *MapStorage) ReserveRequestResultDrv(rr *RequestResult, ttl time.Duration) (reserved bool, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if values, has := ms.dict[utils.RequestResultPrefix+rr.ID]; has {
+		var prevRR *RequestResult
+		if err = ms.ms.Unmarshal(values, &prevRR); err != nil {
+			return
+		}
+		if !prevRR.Expired(time.Now()) {
+			return
+		}
+	}
+	result, err := ms.ms.Marshal(rr)
+	if err != nil {
+		return
+	}
+	ms.dict[utils.RequestResultPrefix+rr.ID] = result
+	return true, nil
+}
+
+func (ms *MapStorage) RemoveRequestResultDrv(rr *RequestResult) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	values, has := ms.dict[utils.RequestResultPrefix+rr.ID]
+	if !has {
+		return
+	}
+	var prevRR *RequestResult
+	if err = ms.ms.Unmarshal(values, &prevRR); err != nil {
+		return
+	}
+	if prevRR.HolderID == rr.HolderID {
+		delete(ms.dict, utils.RequestResultPrefix+rr.ID)
+	}
+	return
+}
+
+func (ms *MapStorage) GetCDRDedupRecordDrv(id string) (rec *CDRDedupRecord, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.CDRDedupPrefix+id]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	if err = ms.ms.Unmarshal(values, &rec); err != nil {
+		return
+	}
+	if rec.Expired(time.Now()) {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+// SetCDRDedupRecordDrv keeps the expiry within the record, checked on read
+func (ms *MapStorage) SetCDRDedupRecordDrv(rec *CDRDedupRecord, ttl time.Duration) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(rec)
+	if err != nil {
+		return
+	}
+	ms.dict[utils.CDRDedupPrefix+rec.ID] = result
+	return
+}
+
+func (ms *MapStorage) ReleaseLeaseDrv(leaseID, holderID string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ls, err := ms.getLease(leaseID); err == nil && ls.HolderID == holderID {
+		delete(ms.dict, utils.LeasePrefix+leaseID)
+	}
+	return
+}
+
 func (ms *MapStorage) GetStorageType() string {
 	return utils.MAPSTOR
 }
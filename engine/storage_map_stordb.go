@@ -19,6 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"time"
+
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -97,6 +99,15 @@ func (ms *MapStorage) GetTPChargers(tpid, tenant, id string) (attrs []*utils.TPC
 func (ms *MapStorage) GetTPDispatchers(tpid, tenant, id string) (attrs []*utils.TPDispatcherProfile, err error) {
 	return nil, utils.ErrNotImplemented
 }
+func (ms *MapStorage) GetTPExchangeRates(tpid string) (exrs []*utils.TPExchangeRate, err error) {
+	return nil, utils.ErrNotImplemented
+}
+func (ms *MapStorage) GetTPTaxProfiles(tpid, tenant, id string) (txps []*utils.TPTaxProfile, err error) {
+	return nil, utils.ErrNotImplemented
+}
+func (ms *MapStorage) GetTPCalendars(tpid, tenant, id string) (cals []*utils.TPCalendar, err error) {
+	return nil, utils.ErrNotImplemented
+}
 
 //implement LoadWriter interface
 func (ms *MapStorage) RemTpData(table, tpid string, args map[string]string) (err error) {
@@ -170,6 +181,15 @@ func (ms *MapStorage) SetTPChargers(cpps []*utils.TPChargerProfile) (err error)
 func (ms *MapStorage) SetTPDispatchers(dpps []*utils.TPDispatcherProfile) (err error) {
 	return utils.ErrNotImplemented
 }
+func (ms *MapStorage) SetTPExchangeRates(exrs []*utils.TPExchangeRate) (err error) {
+	return utils.ErrNotImplemented
+}
+func (ms *MapStorage) SetTPTaxProfiles(txps []*utils.TPTaxProfile) (err error) {
+	return utils.ErrNotImplemented
+}
+func (ms *MapStorage) SetTPCalendars(cals []*utils.TPCalendar) (err error) {
+	return utils.ErrNotImplemented
+}
 
 //implement CdrStorage interface
 func (ms *MapStorage) SetCDR(cdr *CDR, allowUpdate bool) (err error) {
@@ -185,3 +205,104 @@ func (ms *MapStorage) GetCDRs(filter *utils.CDRsFilter, remove bool) (cdrs []*CD
 func (ms *MapStorage) GetSMCosts(cgrid, runid, originHost, originIDPrfx string) (smCosts []*SMCost, err error) {
 	return nil, utils.ErrNotImplemented
 }
+
+// getAccountAudits is not locking, callers should do it
+func (ms *MapStorage) getAccountAudits() (audits []*AccountAudit, err error) {
+	if values, has := ms.dict[utils.AccountAuditsTBL]; has {
+		err = ms.ms.Unmarshal(values, &audits)
+	}
+	return
+}
+
+func (ms *MapStorage) SetAccountAudits(audits []*AccountAudit) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	stored, err := ms.getAccountAudits()
+	if err != nil {
+		return
+	}
+	result, err := ms.ms.Marshal(append(stored, audits...))
+	if err != nil {
+		return
+	}
+	ms.dict[utils.AccountAuditsTBL] = result
+	return
+}
+
+func (ms *MapStorage) SetAccountSnapshot(snp *AccountSnapshot) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	key := utils.ConcatenatedKey(utils.AccountSnapshotsTBL, snp.Tenant, snp.Account)
+	var snps []*AccountSnapshot
+	if values, has := ms.dict[key]; has {
+		if err = ms.ms.Unmarshal(values, &snps); err != nil {
+			return
+		}
+	}
+	result, err := ms.ms.Marshal(append(snps, snp))
+	if err != nil {
+		return
+	}
+	ms.dict[key] = result
+	return
+}
+
+func (ms *MapStorage) GetAccountSnapshot(tenant, account string, before time.Time) (snp *AccountSnapshot, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, has := ms.dict[utils.ConcatenatedKey(utils.AccountSnapshotsTBL, tenant, account)]
+	if !has {
+		return nil, utils.ErrNotFound
+	}
+	var snps []*AccountSnapshot
+	if err = ms.ms.Unmarshal(values, &snps); err != nil {
+		return
+	}
+	for _, s := range snps {
+		if s.Timestamp.After(before) ||
+			(snp != nil && s.Timestamp.Before(snp.Timestamp)) {
+			continue
+		}
+		snp = s
+	}
+	if snp == nil {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+func (ms *MapStorage) GetAccountAudits(fltr *AccountAuditFilter) (audits []*AccountAudit, err error) {
+	ms.mu.RLock()
+	stored, err := ms.getAccountAudits()
+	ms.mu.RUnlock()
+	if err != nil {
+		return
+	}
+	for _, aud := range stored {
+		if (fltr.Tenant != "" && aud.Tenant != fltr.Tenant) ||
+			(fltr.Account != "" && aud.Account != fltr.Account) ||
+			(fltr.BalanceID != "" && aud.BalanceID != fltr.BalanceID) ||
+			(fltr.Cause != "" && aud.Cause != fltr.Cause) ||
+			(fltr.ActionsID != "" && aud.ActionsID != fltr.ActionsID) ||
+			(fltr.CGRID != "" && aud.CGRID != fltr.CGRID) ||
+			(fltr.TimeStart != nil && aud.Timestamp.Before(*fltr.TimeStart)) ||
+			(fltr.TimeEnd != nil && !fltr.TimeEnd.IsZero() && !aud.Timestamp.Before(*fltr.TimeEnd)) {
+			continue
+		}
+		audits = append(audits, aud)
+	}
+	if fltr.Paginator.Offset != nil {
+		if *fltr.Paginator.Offset >= len(audits) {
+			audits = nil
+		} else {
+			audits = audits[*fltr.Paginator.Offset:]
+		}
+	}
+	if fltr.Paginator.Limit != nil && *fltr.Paginator.Limit < len(audits) {
+		audits = audits[:*fltr.Paginator.Limit]
+	}
+	if len(audits) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
@@ -76,6 +76,16 @@ const (
 	ColCDRs  = "cdrs"
 	colCpp   = "charger_profiles"
 	colDpp   = "dispatcher_profiles"
+	colNss   = "node_sessions"
+	colExr   = "exchange_rates"
+	colTxp   = "tax_profiles"
+	colCal   = "calendars"
+	colLea   = "leases"
+	colLex   = "last_executions"
+	colChk   = "execution_checkpoints"
+	colExh   = "execution_history"
+	colRqr   = "request_results"
+	colCdd   = "cdr_dedups"
 )
 
 var (
@@ -192,6 +202,9 @@ func NewMongoStorage(host, port, db, user, pass, storageType string, cdrsIndexes
 	}); err != nil {
 		return nil, err
 	}
+	if err = ms.ensureRequiredIndexes(); err != nil {
+		return nil, err
+	}
 	ms.cnter = utils.NewCounter(time.Now().UnixNano(), 0)
 	return
 }
@@ -237,11 +250,58 @@ func (ms *MongoStorage) GetContext() context.Context {
 	return ms.ctx
 }
 
+// ensureRequiredIndexes creates the indexes the storage relies on for correctness or for querying the collections added later
+// unlike EnsureIndexes, these are created on the existing databases too
+func (ms *MongoStorage) ensureRequiredIndexes() (err error) {
+	if ms.storageType == utils.DataDB {
+		// AcquireLeaseDrv and ReserveRequestResultDrv rely on the unique id to fail for the existing ones
+		for _, col := range []string{colLea, colRqr, colCdd} {
+			if err = ms.EnusureIndex(col, true, "id"); err != nil {
+				return
+			}
+		}
+		for _, col := range []string{colRqr, colCdd} {
+			if err = ms.ensureTTLIndex(col, "expiry"); err != nil {
+				return
+			}
+		}
+		// GetExecutionHistoryDrv pages the records in this order
+		if err = ms.EnusureIndex(colExh, false, "record.executiontime", "_id"); err != nil {
+			return
+		}
+	}
+	if ms.storageType == utils.StorDB {
+		if err = ms.EnusureIndex(utils.AccountAuditsTBL, false, "tenant",
+			"account", "timestamp"); err != nil {
+			return
+		}
+		if err = ms.EnusureIndex(utils.AccountAuditsTBL, false, "cgrid"); err != nil {
+			return
+		}
+		if err = ms.EnusureIndex(utils.AccountSnapshotsTBL, false, "tenant",
+			"account", "timestamp"); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// ensureTTLIndex has the documents removed once the time within key passed
+func (ms *MongoStorage) ensureTTLIndex(colName, key string) error {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) error {
+		_, err := ms.getCol(colName).Indexes().CreateOne(sctx, mongo.IndexModel{
+			Keys:    bsonx.Doc{}.Append(key, bsonx.Int32(1)),
+			Options: options.Index().SetExpireAfterSeconds(0),
+		})
+		return err
+	})
+}
+
 // EnsureIndexes creates db indexes
 func (ms *MongoStorage) EnsureIndexes() (err error) {
 	if ms.storageType == utils.DataDB {
 		for _, col := range []string{colAct, colApl, colAAp, colAtr,
-			colDcs, colRpl, colDst, colRds, colAls, colUsr, colLht} {
+			colDcs, colRpl, colDst, colRds, colAls, colUsr, colLht, colLex, colChk} {
 			if err = ms.EnusureIndex(col, true, "key"); err != nil {
 				return
 			}
@@ -252,7 +312,7 @@ func (ms *MongoStorage) EnsureIndexes() (err error) {
 				return
 			}
 		}
-		for _, col := range []string{colRpf, colShg, colAcc} {
+		for _, col := range []string{colRpf, colShg, colAcc, colLea, colRqr, colCdd} {
 			if err = ms.EnusureIndex(col, true, "id"); err != nil {
 				return
 			}
@@ -641,6 +701,12 @@ func (ms *MongoStorage) GetKeysForPrefix(prefix string) (result []string, err er
 			result, err = ms.getField2(sctx, colCpp, utils.ChargerProfilePrefix, subject, tntID)
 		case utils.DispatcherProfilePrefix:
 			result, err = ms.getField2(sctx, colDpp, utils.DispatcherProfilePrefix, subject, tntID)
+		case utils.NodeSessionsPrefix:
+			result, err = ms.getField(sctx, colNss, utils.NodeSessionsPrefix, subject, "nodeid")
+		case utils.TaxProfilePrefix:
+			result, err = ms.getField2(sctx, colTxp, utils.TaxProfilePrefix, subject, tntID)
+		case utils.CalendarPrefix:
+			result, err = ms.getField2(sctx, colCal, utils.CalendarPrefix, subject, tntID)
 		default:
 			err = fmt.Errorf("unsupported prefix in GetKeysForPrefix: %s", prefix)
 		}
@@ -1117,6 +1183,8 @@ func (ms *MongoStorage) SetAccount(acc *Account) error {
 			ac.UnitCounters = acc.UnitCounters
 			ac.AllowNegative = acc.AllowNegative
 			ac.Disabled = acc.Disabled
+			ac.DebitPolicy = acc.DebitPolicy
+			ac.Subscriptions = acc.Subscriptions
 			acc = ac
 		}
 	}
@@ -2494,3 +2562,441 @@ func (ms *MongoStorage) RemoveDispatcherProfileDrv(tenant, id string) (err error
 		return err
 	})
 }
+
+func (ms *MongoStorage) GetNodeSessionsDrv(nodeID string) (ns *NodeSessions, err error) {
+	ns = new(NodeSessions)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colNss).FindOne(sctx, bson.M{"nodeid": nodeID})
+		if err := cur.Decode(ns); err != nil {
+			ns = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (ms *MongoStorage) SetNodeSessionsDrv(ns *NodeSessions) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colNss).UpdateOne(sctx, bson.M{"nodeid": ns.NodeID},
+			bson.M{"$set": ns},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemoveNodeSessionsDrv(nodeID string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(colNss).DeleteOne(sctx, bson.M{"nodeid": nodeID})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
+
+// ClaimNodeSessionsDrv removes the NodeSessions only if the heartbeat was not refreshed in the meantime
+func (ms *MongoStorage) ClaimNodeSessionsDrv(nodeID string, heartbeat time.Time) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(colNss).DeleteOne(sctx, bson.M{"nodeid": nodeID, "heartbeat": heartbeat})
+		if err != nil {
+			return err
+		}
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (ms *MongoStorage) GetExchangeRateDrv(fromCurrency, toCurrency string) (exr *ExchangeRate, err error) {
+	exr = new(ExchangeRate)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colExr).FindOne(sctx,
+			bson.M{"fromcurrency": fromCurrency, "tocurrency": toCurrency})
+		if err := cur.Decode(exr); err != nil {
+			exr = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (ms *MongoStorage) SetExchangeRateDrv(exr *ExchangeRate) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colExr).UpdateOne(sctx,
+			bson.M{"fromcurrency": exr.FromCurrency, "tocurrency": exr.ToCurrency},
+			bson.M{"$set": exr},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemoveExchangeRateDrv(fromCurrency, toCurrency string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(colExr).DeleteOne(sctx,
+			bson.M{"fromcurrency": fromCurrency, "tocurrency": toCurrency})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
+
+func (ms *MongoStorage) GetTaxProfileDrv(tenant, id string) (txp *TaxProfile, err error) {
+	txp = new(TaxProfile)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colTxp).FindOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if err := cur.Decode(txp); err != nil {
+			txp = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (ms *MongoStorage) SetTaxProfileDrv(txp *TaxProfile) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colTxp).UpdateOne(sctx, bson.M{"tenant": txp.Tenant, "id": txp.ID},
+			bson.M{"$set": txp},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemoveTaxProfileDrv(tenant, id string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(colTxp).DeleteOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
+
+func (ms *MongoStorage) GetCalendarDrv(tenant, id string) (cal *Calendar, err error) {
+	cal = new(Calendar)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colCal).FindOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if err := cur.Decode(cal); err != nil {
+			cal = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (ms *MongoStorage) SetCalendarDrv(cal *Calendar) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colCal).UpdateOne(sctx, bson.M{"tenant": cal.Tenant, "id": cal.ID},
+			bson.M{"$set": cal},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemoveCalendarDrv(tenant, id string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		dr, err := ms.getCol(colCal).DeleteOne(sctx, bson.M{"tenant": tenant, "id": id})
+		if dr.DeletedCount == 0 {
+			return utils.ErrNotFound
+		}
+		return err
+	})
+}
+
+func (ms *MongoStorage) GetLeaseDrv(leaseID string) (ls *Lease, err error) {
+	ls = new(Lease)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colLea).FindOne(sctx, bson.M{"id": leaseID})
+		if err := cur.Decode(ls); err != nil {
+			ls = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		if ls.Expired(time.Now()) {
+			ls = nil
+			return utils.ErrNotFound
+		}
+		return nil
+	})
+	return
+}
+
+// AcquireLeaseDrv relies on the unique index on id, the upsert fails if another holder has the lease
+func (ms *MongoStorage) AcquireLeaseDrv(leaseID, holderID string, ttl time.Duration) (acquired bool, err error) {
+	now := time.Now()
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colLea).UpdateOne(sctx,
+			bson.M{"id": leaseID, "$or": []bson.M{
+				{"holderid": holderID},
+				{"expiry": bson.M{"$lt": now}}}},
+			bson.M{"$set": &Lease{ID: leaseID, HolderID: holderID, Expiry: now.Add(ttl)}},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "E11000") { // duplicate key, held by someone else
+			return false, nil
+		}
+		return
+	}
+	return true, nil
+}
+
+func (ms *MongoStorage) ReleaseLeaseDrv(leaseID, holderID string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colLea).DeleteOne(sctx, bson.M{"id": leaseID, "holderid": holderID})
+		return err
+	})
+}
+
+func (ms *MongoStorage) GetLastExecutionDrv(execKey string) (execTime time.Time, err error) {
+	var kv struct {
+		Key   string
+		Value time.Time
+	}
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colLex).FindOne(sctx, bson.M{"key": execKey})
+		if err := cur.Decode(&kv); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return kv.Value, err
+}
+
+func (ms *MongoStorage) SetLastExecutionDrv(execKey string, execTime time.Time) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colLex).UpdateOne(sctx, bson.M{"key": execKey},
+			bson.M{"$set": struct {
+				Key   string
+				Value time.Time
+			}{Key: execKey, Value: execTime}},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) GetExecutionCheckpointDrv(execKey string) (chk *ExecutionCheckpoint, err error) {
+	var kv struct {
+		Key   string
+		Value *ExecutionCheckpoint
+	}
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colChk).FindOne(sctx, bson.M{"key": execKey})
+		if err := cur.Decode(&kv); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	return kv.Value, err
+}
+
+func (ms *MongoStorage) SetExecutionCheckpointDrv(execKey string, chk *ExecutionCheckpoint) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colChk).UpdateOne(sctx, bson.M{"key": execKey},
+			bson.M{"$set": struct {
+				Key   string
+				Value *ExecutionCheckpoint
+			}{Key: execKey, Value: chk}},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemoveExecutionCheckpointDrv(execKey string) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colChk).DeleteOne(sctx, bson.M{"key": execKey})
+		return err
+	})
+}
+
+// execHistSort orders the execution records newest first, the ones inserted together in reverse
+var execHistSort = bsonx.Doc{}.Append("record.executiontime", bsonx.Int32(-1)).Append("_id", bsonx.Int32(-1))
+
+// GetExecutionHistoryDrv reads limit records, all if 0, after skipping offset of the newest ones
+func (ms *MongoStorage) GetExecutionHistoryDrv(offset, limit int) (recs []*ExecutionRecord, err error) {
+	fop := options.Find().SetSort(execHistSort).SetSkip(int64(offset))
+	if limit > 0 {
+		fop = fop.SetLimit(int64(limit))
+	}
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(colExh).Find(sctx, bson.D{}, fop)
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var v struct {
+				ID     primitive.ObjectID `bson:"_id"`
+				Record *ExecutionRecord
+			}
+			if err = cur.Decode(&v); err != nil {
+				cur.Close(sctx)
+				return err
+			}
+			recs = append(recs, v.Record)
+		}
+		return cur.Close(sctx)
+	})
+	return
+}
+
+// AddExecutionRecordsDrv stores each record in its own document, removing the ones older than the newest histSize
+func (ms *MongoStorage) AddExecutionRecordsDrv(recs []*ExecutionRecord, histSize int) (err error) {
+	if histSize <= 0 || len(recs) == 0 {
+		return
+	}
+	docs := make([]interface{}, len(recs))
+	for i, rec := range recs { // increasing ids keep the insertion order for the same execution time
+		docs[i] = bson.M{"_id": primitive.NewObjectID(), "record": rec}
+	}
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		if _, err = ms.getCol(colExh).InsertMany(sctx, docs); err != nil {
+			return
+		}
+		cur, err := ms.getCol(colExh).Find(sctx, bson.D{},
+			options.Find().SetSort(execHistSort).SetSkip(int64(histSize)).SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return
+		}
+		var oldIDs []primitive.ObjectID
+		for cur.Next(sctx) {
+			var v struct {
+				ID primitive.ObjectID `bson:"_id"`
+			}
+			if err = cur.Decode(&v); err != nil {
+				cur.Close(sctx)
+				return
+			}
+			oldIDs = append(oldIDs, v.ID)
+		}
+		if err = cur.Close(sctx); err != nil || len(oldIDs) == 0 {
+			return
+		}
+		_, err = ms.getCol(colExh).DeleteMany(sctx, bson.M{"_id": bson.M{"$in": oldIDs}})
+		return
+	})
+}
+
+func (ms *MongoStorage) GetRequestResultDrv(reqID string) (rr *RequestResult, err error) {
+	rr = new(RequestResult)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colRqr).FindOne(sctx, bson.M{"id": reqID})
+		if err := cur.Decode(rr); err != nil {
+			rr = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		if rr.Expired(time.Now()) {
+			rr = nil
+			return utils.ErrNotFound
+		}
+		return nil
+	})
+	return
+}
+
+// SetRequestResultDrv keeps the expiry within the result, the expired ones being removed by the TTL index
+func (ms *MongoStorage) SetRequestResultDrv(rr *RequestResult, ttl time.Duration) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colRqr).UpdateOne(sctx, bson.M{"id": rr.ID},
+			bson.M{"$set": rr},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+// ReserveRequestResultDrv relies on the unique id to fail the insert of an existing result,
+// replacing the expired one not yet removed by the TTL index
+func (ms *MongoStorage) ReserveRequestResultDrv(rr *RequestResult, ttl time.Duration) (reserved bool, err error) {
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		if _, err = ms.getCol(colRqr).InsertOne(sctx, rr); err == nil {
+			reserved = true
+			return
+		}
+		if !strings.Contains(err.Error(), "E11000") { // duplicate key
+			return
+		}
+		res, err := ms.getCol(colRqr).UpdateOne(sctx,
+			bson.M{"id": rr.ID, "expiry": bson.M{"$lt": time.Now()}},
+			bson.M{"$set": rr})
+		if err != nil {
+			return
+		}
+		reserved = res.MatchedCount != 0
+		return
+	})
+	return
+}
+
+func (ms *MongoStorage) RemoveRequestResultDrv(rr *RequestResult) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colRqr).DeleteOne(sctx, bson.M{"id": rr.ID, "holderid": rr.HolderID})
+		return err
+	})
+}
+
+func (ms *MongoStorage) GetCDRDedupRecordDrv(id string) (rec *CDRDedupRecord, err error) {
+	rec = new(CDRDedupRecord)
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(colCdd).FindOne(sctx, bson.M{"id": id})
+		if err := cur.Decode(rec); err != nil {
+			rec = nil
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		if rec.Expired(time.Now()) {
+			rec = nil
+			return utils.ErrNotFound
+		}
+		return nil
+	})
+	return
+}
+
+// SetCDRDedupRecordDrv keeps the expiry within the record, the expired ones being removed by the TTL index
+func (ms *MongoStorage) SetCDRDedupRecordDrv(rec *CDRDedupRecord, ttl time.Duration) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(colCdd).UpdateOne(sctx, bson.M{"id": rec.ID},
+			bson.M{"$set": rec},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
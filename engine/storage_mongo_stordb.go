@@ -1115,6 +1115,90 @@ func (ms *MongoStorage) GetSMCosts(cgrid, runid, originHost, originIDPrefix stri
 	return smcs, err
 }
 
+func (ms *MongoStorage) SetAccountAudits(audits []*AccountAudit) (err error) {
+	docs := make([]interface{}, len(audits))
+	for i, aud := range audits {
+		docs[i] = aud
+	}
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(utils.AccountAuditsTBL).InsertMany(sctx, docs)
+		return err
+	})
+}
+
+// GetAccountAudits returns the audits matching the filter, ordered on Timestamp
+func (ms *MongoStorage) GetAccountAudits(fltr *AccountAuditFilter) (audits []*AccountAudit, err error) {
+	filter := bson.M{}
+	for fldName, fldVal := range map[string]string{"tenant": fltr.Tenant, "account": fltr.Account,
+		"balanceid": fltr.BalanceID, "cause": fltr.Cause, "actionsid": fltr.ActionsID, "cgrid": fltr.CGRID} {
+		if fldVal != "" {
+			filter[fldName] = fldVal
+		}
+	}
+	tmFltr := bson.M{}
+	if fltr.TimeStart != nil && !fltr.TimeStart.IsZero() {
+		tmFltr["$gte"] = fltr.TimeStart
+	}
+	if fltr.TimeEnd != nil && !fltr.TimeEnd.IsZero() {
+		tmFltr["$lt"] = fltr.TimeEnd
+	}
+	if len(tmFltr) != 0 {
+		filter["timestamp"] = tmFltr
+	}
+	fop := options.Find().SetSort(bson.M{"timestamp": 1})
+	if fltr.Paginator.Limit != nil {
+		fop = fop.SetLimit(int64(*fltr.Paginator.Limit))
+	}
+	if fltr.Paginator.Offset != nil {
+		fop = fop.SetSkip(int64(*fltr.Paginator.Offset))
+	}
+	err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(utils.AccountAuditsTBL).Find(sctx, filter, fop)
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var aud AccountAudit
+			if err := cur.Decode(&aud); err != nil {
+				return err
+			}
+			audits = append(audits, &aud)
+		}
+		if len(audits) == 0 {
+			return utils.ErrNotFound
+		}
+		return cur.Close(sctx)
+	})
+	return
+}
+
+func (ms *MongoStorage) SetAccountSnapshot(snp *AccountSnapshot) (err error) {
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(utils.AccountSnapshotsTBL).InsertOne(sctx, snp)
+		return err
+	})
+}
+
+// GetAccountSnapshot returns the latest snapshot of the account taken not later than before
+func (ms *MongoStorage) GetAccountSnapshot(tenant, account string, before time.Time) (snp *AccountSnapshot, err error) {
+	fop := options.FindOne().SetSort(bson.M{"timestamp": -1})
+	if err = ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(utils.AccountSnapshotsTBL).FindOne(sctx,
+			bson.M{"tenant": tenant, "account": account, "timestamp": bson.M{"$lte": before}}, fop)
+		snp = new(AccountSnapshot)
+		if err := cur.Decode(snp); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return
+}
+
 func (ms *MongoStorage) SetCDR(cdr *CDR, allowUpdate bool) (err error) {
 	if cdr.OrderID == 0 {
 		cdr.OrderID = ms.cnter.Next()
@@ -1661,6 +1745,144 @@ func (ms *MongoStorage) SetTPDispatchers(tpDPPs []*utils.TPDispatcherProfile) (e
 	})
 }
 
+func (ms *MongoStorage) GetTPExchangeRates(tpid string) ([]*utils.TPExchangeRate, error) {
+	var results []*utils.TPExchangeRate
+	err := ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(utils.TBLTPExchangeRates).Find(sctx, bson.M{"tpid": tpid})
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var tp utils.TPExchangeRate
+			err := cur.Decode(&tp)
+			if err != nil {
+				return err
+			}
+			results = append(results, &tp)
+		}
+		if len(results) == 0 {
+			return utils.ErrNotFound
+		}
+		return cur.Close(sctx)
+	})
+	return results, err
+}
+
+func (ms *MongoStorage) SetTPExchangeRates(tpExrs []*utils.TPExchangeRate) (err error) {
+	if len(tpExrs) == 0 {
+		return
+	}
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		for _, tp := range tpExrs {
+			_, err = ms.getCol(utils.TBLTPExchangeRates).UpdateOne(sctx,
+				bson.M{"tpid": tp.TPid, "fromcurrency": tp.FromCurrency, "tocurrency": tp.ToCurrency},
+				bson.M{"$set": tp},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (ms *MongoStorage) GetTPTaxProfiles(tpid, tenant, id string) ([]*utils.TPTaxProfile, error) {
+	filter := bson.M{"tpid": tpid}
+	if id != "" {
+		filter["id"] = id
+	}
+	if tenant != "" {
+		filter["tenant"] = tenant
+	}
+	var results []*utils.TPTaxProfile
+	err := ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(utils.TBLTPTaxProfiles).Find(sctx, filter)
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var tp utils.TPTaxProfile
+			err := cur.Decode(&tp)
+			if err != nil {
+				return err
+			}
+			results = append(results, &tp)
+		}
+		if len(results) == 0 {
+			return utils.ErrNotFound
+		}
+		return cur.Close(sctx)
+	})
+	return results, err
+}
+
+func (ms *MongoStorage) GetTPCalendars(tpid, tenant, id string) ([]*utils.TPCalendar, error) {
+	filter := bson.M{"tpid": tpid}
+	if id != "" {
+		filter["id"] = id
+	}
+	if tenant != "" {
+		filter["tenant"] = tenant
+	}
+	var results []*utils.TPCalendar
+	err := ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		cur, err := ms.getCol(utils.TBLTPCalendars).Find(sctx, filter)
+		if err != nil {
+			return err
+		}
+		for cur.Next(sctx) {
+			var tp utils.TPCalendar
+			err := cur.Decode(&tp)
+			if err != nil {
+				return err
+			}
+			results = append(results, &tp)
+		}
+		if len(results) == 0 {
+			return utils.ErrNotFound
+		}
+		return cur.Close(sctx)
+	})
+	return results, err
+}
+
+func (ms *MongoStorage) SetTPTaxProfiles(tpTxps []*utils.TPTaxProfile) (err error) {
+	if len(tpTxps) == 0 {
+		return
+	}
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		for _, tp := range tpTxps {
+			_, err = ms.getCol(utils.TBLTPTaxProfiles).UpdateOne(sctx, bson.M{"tpid": tp.TPid, "id": tp.ID},
+				bson.M{"$set": tp},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (ms *MongoStorage) SetTPCalendars(tpCals []*utils.TPCalendar) (err error) {
+	if len(tpCals) == 0 {
+		return
+	}
+	return ms.client.UseSession(ms.ctx, func(sctx mongo.SessionContext) (err error) {
+		for _, tp := range tpCals {
+			_, err = ms.getCol(utils.TBLTPCalendars).UpdateOne(sctx, bson.M{"tpid": tp.TPid, "id": tp.ID},
+				bson.M{"$set": tp},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (ms *MongoStorage) GetVersions(itm string) (vrs Versions, err error) {
 	fop := options.FindOne()
 	if itm != "" {
@@ -27,6 +27,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/guardian"
@@ -718,6 +719,8 @@ func (rs *RedisStorage) SetAccount(ub *Account) (err error) {
 			ac.UnitCounters = ub.UnitCounters
 			ac.AllowNegative = ub.AllowNegative
 			ac.Disabled = ub.Disabled
+			ac.DebitPolicy = ub.DebitPolicy
+			ac.Subscriptions = ub.Subscriptions
 			ub = ac
 		}
 	}
@@ -1804,6 +1807,343 @@ func (rs *RedisStorage) RemoveDispatcherProfileDrv(tenant, id string) (err error
 	return
 }
 
+func (rs *RedisStorage) GetNodeSessionsDrv(nodeID string) (ns *NodeSessions, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.NodeSessionsPrefix+nodeID).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	if err = rs.ms.Unmarshal(values, &ns); err != nil {
+		return
+	}
+	return
+}
+
+func (rs *RedisStorage) SetNodeSessionsDrv(ns *NodeSessions) (err error) {
+	result, err := rs.ms.Marshal(ns)
+	if err != nil {
+		return err
+	}
+	return rs.Cmd("SET", utils.NodeSessionsPrefix+ns.NodeID, result).Err
+}
+
+func (rs *RedisStorage) RemoveNodeSessionsDrv(nodeID string) (err error) {
+	var n int
+	if n, err = rs.Cmd("DEL", utils.NodeSessionsPrefix+nodeID).Int(); err != nil {
+		return
+	}
+	if n == 0 {
+		err = utils.ErrNotFound
+	}
+	return
+}
+
+// ClaimNodeSessionsDrv removes the NodeSessions only if the heartbeat was not refreshed in the meantime,
+// comparing the stored value with the one checked, atomically
+func (rs *RedisStorage) ClaimNodeSessionsDrv(nodeID string, heartbeat time.Time) (err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.NodeSessionsPrefix+nodeID).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	var ns *NodeSessions
+	if err = rs.ms.Unmarshal(values, &ns); err != nil {
+		return
+	}
+	if !ns.Heartbeat.Equal(heartbeat) {
+		return utils.ErrNotFound
+	}
+	var n int
+	if n, err = rs.Cmd("EVAL", redisRemoveUnchangedScript, 1,
+		utils.NodeSessionsPrefix+nodeID, values).Int(); err != nil {
+		return
+	}
+	if n == 0 {
+		err = utils.ErrNotFound
+	}
+	return
+}
+
+func (rs *RedisStorage) GetExchangeRateDrv(fromCurrency, toCurrency string) (exr *ExchangeRate, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.ExchangeRatePrefix+
+		utils.ConcatenatedKey(fromCurrency, toCurrency)).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	if err = rs.ms.Unmarshal(values, &exr); err != nil {
+		return
+	}
+	return
+}
+
+func (rs *RedisStorage) SetExchangeRateDrv(exr *ExchangeRate) (err error) {
+	result, err := rs.ms.Marshal(exr)
+	if err != nil {
+		return err
+	}
+	return rs.Cmd("SET", utils.ExchangeRatePrefix+exr.ID(), result).Err
+}
+
+func (rs *RedisStorage) RemoveExchangeRateDrv(fromCurrency, toCurrency string) (err error) {
+	return rs.Cmd("DEL", utils.ExchangeRatePrefix+
+		utils.ConcatenatedKey(fromCurrency, toCurrency)).Err
+}
+
+func (rs *RedisStorage) GetTaxProfileDrv(tenant, id string) (txp *TaxProfile, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.TaxProfilePrefix+
+		utils.ConcatenatedKey(tenant, id)).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	if err = rs.ms.Unmarshal(values, &txp); err != nil {
+		return
+	}
+	return
+}
+
+func (rs *RedisStorage) SetTaxProfileDrv(txp *TaxProfile) (err error) {
+	result, err := rs.ms.Marshal(txp)
+	if err != nil {
+		return err
+	}
+	return rs.Cmd("SET", utils.TaxProfilePrefix+txp.TenantID(), result).Err
+}
+
+func (rs *RedisStorage) RemoveTaxProfileDrv(tenant, id string) (err error) {
+	return rs.Cmd("DEL", utils.TaxProfilePrefix+
+		utils.ConcatenatedKey(tenant, id)).Err
+}
+
+func (rs *RedisStorage) GetCalendarDrv(tenant, id string) (cal *Calendar, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.CalendarPrefix+
+		utils.ConcatenatedKey(tenant, id)).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	if err = rs.ms.Unmarshal(values, &cal); err != nil {
+		return
+	}
+	return
+}
+
+func (rs *RedisStorage) SetCalendarDrv(cal *Calendar) (err error) {
+	result, err := rs.ms.Marshal(cal)
+	if err != nil {
+		return err
+	}
+	return rs.Cmd("SET", utils.CalendarPrefix+cal.TenantID(), result).Err
+}
+
+func (rs *RedisStorage) RemoveCalendarDrv(tenant, id string) (err error) {
+	return rs.Cmd("DEL", utils.CalendarPrefix+
+		utils.ConcatenatedKey(tenant, id)).Err
+}
+
+const (
+	// set the holder of the lease if free or already owned, atomically
+	redisAcquireLeaseScript = `local crnt = redis.call('GET', KEYS[1])
+if crnt == false or crnt == ARGV[1] then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+return 0`
+	// remove the key only if its value did not change, eg: the lease if still owned
+	redisRemoveUnchangedScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0`
+)
+
+func (rs *RedisStorage) GetLeaseDrv(leaseID string) (ls *Lease, err error) {
+	var holderID string
+	if holderID, err = rs.Cmd("GET", utils.LeasePrefix+leaseID).Str(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	var pttl int64
+	if pttl, err = rs.Cmd("PTTL", utils.LeasePrefix+leaseID).Int64(); err != nil {
+		return
+	}
+	return &Lease{ID: leaseID, HolderID: holderID,
+		Expiry: time.Now().Add(time.Duration(pttl) * time.Millisecond)}, nil
+}
+
+// AcquireLeaseDrv relies on the key expiry to free the lease
+func (rs *RedisStorage) AcquireLeaseDrv(leaseID, holderID string, ttl time.Duration) (acquired bool, err error) {
+	var n int
+	if n, err = rs.Cmd("EVAL", redisAcquireLeaseScript, 1, utils.LeasePrefix+leaseID,
+		holderID, int64(ttl/time.Millisecond)).Int(); err != nil {
+		return
+	}
+	return n == 1, nil
+}
+
+func (rs *RedisStorage) GetLastExecutionDrv(execKey string) (execTime time.Time, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.LastExecutionPrefix+execKey).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = execTime.UnmarshalText(values)
+	return
+}
+
+func (rs *RedisStorage) SetLastExecutionDrv(execKey string, execTime time.Time) (err error) {
+	result, err := execTime.MarshalText()
+	if err != nil {
+		return
+	}
+	return rs.Cmd("SET", utils.LastExecutionPrefix+execKey, result).Err
+}
+
+func (rs *RedisStorage) GetExecutionCheckpointDrv(execKey string) (chk *ExecutionCheckpoint, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.ExecutionCheckpointPrefix+execKey).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = rs.ms.Unmarshal(values, &chk)
+	return
+}
+
+func (rs *RedisStorage) SetExecutionCheckpointDrv(execKey string, chk *ExecutionCheckpoint) (err error) {
+	result, err := rs.ms.Marshal(chk)
+	if err != nil {
+		return
+	}
+	return rs.Cmd("SET", utils.ExecutionCheckpointPrefix+execKey, result).Err
+}
+
+func (rs *RedisStorage) RemoveExecutionCheckpointDrv(execKey string) (err error) {
+	return rs.Cmd("DEL", utils.ExecutionCheckpointPrefix+execKey).Err
+}
+
+func (rs *RedisStorage) GetExecutionHistoryDrv(offset, limit int) (recs []*ExecutionRecord, err error) {
+	stop := -1
+	if limit > 0 {
+		stop = offset + limit - 1
+	}
+	marshaleds, err := rs.Cmd("LRANGE", utils.ExecutionHistoryKey, offset, stop).ListBytes()
+	if err != nil {
+		return
+	}
+	recs = make([]*ExecutionRecord, len(marshaleds))
+	for idx, marshaled := range marshaleds {
+		if err = rs.ms.Unmarshal(marshaled, &recs[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (rs *RedisStorage) AddExecutionRecordsDrv(recs []*ExecutionRecord, histSize int) (err error) {
+	if histSize <= 0 || len(recs) == 0 {
+		return
+	}
+	args := make([]interface{}, len(recs)+1)
+	args[0] = utils.ExecutionHistoryKey
+	for i, rec := range recs {
+		if args[i+1], err = rs.ms.Marshal(rec); err != nil {
+			return
+		}
+	}
+	if err = rs.Cmd("LPUSH", args...).Err; err != nil {
+		return
+	}
+	return rs.Cmd("LTRIM", utils.ExecutionHistoryKey, 0, histSize-1).Err
+}
+
+func (rs *RedisStorage) GetRequestResultDrv(reqID string) (rr *RequestResult, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.RequestResultPrefix+reqID).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = rs.ms.Unmarshal(values, &rr)
+	return
+}
+
+// SetRequestResultDrv relies on the key expiry to forget the result
+func (rs *RedisStorage) SetRequestResultDrv(rr *RequestResult, ttl time.Duration) (err error) {
+	result, err := rs.ms.Marshal(rr)
+	if err != nil {
+		return
+	}
+	return rs.Cmd("SET", utils.RequestResultPrefix+rr.ID, result,
+		"PX", int64(ttl/time.Millisecond)).Err
+}
+
+// ReserveRequestResultDrv sets the key only if missing, the nil reply meaning it is already there
+func (rs *RedisStorage) ReserveRequestResultDrv(rr *RequestResult, ttl time.Duration) (reserved bool, err error) {
+	result, err := rs.ms.Marshal(rr)
+	if err != nil {
+		return
+	}
+	rpl := rs.Cmd("SET", utils.RequestResultPrefix+rr.ID, result,
+		"PX", int64(ttl/time.Millisecond), "NX")
+	if rpl.Err != nil {
+		return false, rpl.Err
+	}
+	return !rpl.IsType(redis.Nil), nil
+}
+
+// RemoveRequestResultDrv deletes the key only if it still holds the reservation as marshaled
+func (rs *RedisStorage) RemoveRequestResultDrv(rr *RequestResult) (err error) {
+	result, err := rs.ms.Marshal(rr)
+	if err != nil {
+		return
+	}
+	return rs.Cmd("EVAL", redisRemoveUnchangedScript, 1,
+		utils.RequestResultPrefix+rr.ID, result).Err
+}
+
+func (rs *RedisStorage) GetCDRDedupRecordDrv(id string) (rec *CDRDedupRecord, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.CDRDedupPrefix+id).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = rs.ms.Unmarshal(values, &rec)
+	return
+}
+
+// SetCDRDedupRecordDrv relies on the key expiry to forget the record
+func (rs *RedisStorage) SetCDRDedupRecordDrv(rec *CDRDedupRecord, ttl time.Duration) (err error) {
+	result, err := rs.ms.Marshal(rec)
+	if err != nil {
+		return
+	}
+	return rs.Cmd("SET", utils.CDRDedupPrefix+rec.ID, result,
+		"PX", int64(ttl/time.Millisecond)).Err
+}
+
+func (rs *RedisStorage) ReleaseLeaseDrv(leaseID, holderID string) (err error) {
+	return rs.Cmd("EVAL", redisRemoveUnchangedScript, 1, utils.LeasePrefix+leaseID, holderID).Err
+}
+
 func (rs *RedisStorage) GetStorageType() string {
 	return utils.REDIS
 }
@@ -127,7 +127,7 @@ func (self *SQLStorage) GetTpIds(colName string) ([]string, error) {
 	qryStr := fmt.Sprintf(" (SELECT tpid FROM %s)", colName)
 	if colName == "" {
 		qryStr = fmt.Sprintf(
-			"(SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s)",
+			"(SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s)",
 			utils.TBLTPTimings,
 			utils.TBLTPDestinations,
 			utils.TBLTPRates,
@@ -149,7 +149,10 @@ func (self *SQLStorage) GetTpIds(colName string) ([]string, error) {
 			utils.TBLTPSuppliers,
 			utils.TBLTPAttributes,
 			utils.TBLTPChargers,
-			utils.TBLTPDispatchers)
+			utils.TBLTPDispatchers,
+			utils.TBLTPExchangeRates,
+			utils.TBLTPTaxProfiles,
+			utils.TBLTPCalendars)
 	}
 	rows, err = self.Db.Query(qryStr)
 	if err != nil {
@@ -241,7 +244,8 @@ func (self *SQLStorage) RemTpData(table, tpid string, args map[string]string) er
 			utils.TBLTPActionTriggers, utils.TBLTPAccountActions,
 			utils.TBLTPDerivedChargers, utils.TBLTPAliases, utils.TBLTPUsers, utils.TBLTPResources,
 			utils.TBLTPStats, utils.TBLTPFilters, utils.TBLTPSuppliers, utils.TBLTPAttributes,
-			utils.TBLTPChargers, utils.TBLTPDispatchers} {
+			utils.TBLTPChargers, utils.TBLTPDispatchers, utils.TBLTPExchangeRates,
+			utils.TBLTPTaxProfiles, utils.TBLTPCalendars} {
 			if err := tx.Table(tblName).Where("tpid = ?", tpid).Delete(nil).Error; err != nil {
 				tx.Rollback()
 				return err
@@ -741,6 +745,72 @@ func (self *SQLStorage) SetTPDispatchers(tpDPPs []*utils.TPDispatcherProfile) er
 	return nil
 }
 
+func (self *SQLStorage) SetTPExchangeRates(tpExrs []*utils.TPExchangeRate) error {
+	if len(tpExrs) == 0 {
+		return nil
+	}
+	tx := self.db.Begin()
+	for _, tpExr := range tpExrs {
+		// Remove previous
+		if err := tx.Where(&TpExchangeRate{Tpid: tpExr.TPid, FromCurrency: tpExr.FromCurrency,
+			ToCurrency: tpExr.ToCurrency}).Delete(TpExchangeRate{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		mdl := APItoModelExchangeRate(tpExr)
+		if err := tx.Save(&mdl).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (self *SQLStorage) SetTPTaxProfiles(tpTxps []*utils.TPTaxProfile) error {
+	if len(tpTxps) == 0 {
+		return nil
+	}
+	tx := self.db.Begin()
+	for _, txp := range tpTxps {
+		// Remove previous
+		if err := tx.Where(&TpTaxProfile{Tpid: txp.TPid, ID: txp.ID}).Delete(TpTaxProfile{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, mst := range APItoModelTPTaxProfile(txp) {
+			if err := tx.Save(&mst).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (self *SQLStorage) SetTPCalendars(tpCals []*utils.TPCalendar) error {
+	if len(tpCals) == 0 {
+		return nil
+	}
+	tx := self.db.Begin()
+	for _, cal := range tpCals {
+		// Remove previous
+		if err := tx.Where(&TpCalendar{Tpid: cal.TPid, ID: cal.ID}).Delete(TpCalendar{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, mst := range APItoModelTPCalendar(cal) {
+			if err := tx.Save(&mst).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
 func (self *SQLStorage) SetSMCost(smc *SMCost) error {
 	if smc.CostDetails == nil {
 		return nil
@@ -756,6 +826,9 @@ func (self *SQLStorage) SetSMCost(smc *SMCost) error {
 		Usage:       smc.Usage.Nanoseconds(),
 		CreatedAt:   time.Now(),
 	}
+	if len(smc.History) != 0 {
+		cd.History = utils.ToJSON(smc.History)
+	}
 	if tx.Save(cd).Error != nil { // Check further since error does not properly reflect duplicates here (sql: no rows in result set)
 		tx.Rollback()
 		return tx.Error
@@ -779,6 +852,112 @@ func (self *SQLStorage) RemoveSMCost(smc *SMCost) error {
 	return nil
 }
 
+func (self *SQLStorage) SetAccountAudits(audits []*AccountAudit) error {
+	tx := self.db.Begin()
+	for _, aud := range audits {
+		if err := tx.Save(&AccountAuditSQL{
+			Tenant:      aud.Tenant,
+			Account:     aud.Account,
+			BalanceType: aud.BalanceType,
+			BalanceID:   aud.BalanceID,
+			BalanceUUID: aud.BalanceUUID,
+			ValueBefore: aud.ValueBefore,
+			ValueAfter:  aud.ValueAfter,
+			Cause:       aud.Cause,
+			ActionsID:   aud.ActionsID,
+			Cgrid:       aud.CGRID,
+			Timestamp:   aud.Timestamp,
+		}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+// GetAccountAudits returns the audits matching the filter, ordered on Timestamp
+func (self *SQLStorage) GetAccountAudits(fltr *AccountAuditFilter) (audits []*AccountAudit, err error) {
+	q := self.db.Table(utils.AccountAuditsTBL).Select("*").Where(&AccountAuditSQL{
+		Tenant:    fltr.Tenant,
+		Account:   fltr.Account,
+		BalanceID: fltr.BalanceID,
+		Cause:     fltr.Cause,
+		ActionsID: fltr.ActionsID,
+		Cgrid:     fltr.CGRID,
+	})
+	if fltr.TimeStart != nil && !fltr.TimeStart.IsZero() {
+		q = q.Where("timestamp >= ?", fltr.TimeStart)
+	}
+	if fltr.TimeEnd != nil && !fltr.TimeEnd.IsZero() {
+		q = q.Where("timestamp < ?", fltr.TimeEnd)
+	}
+	q = q.Order("timestamp, id")
+	if fltr.Paginator.Limit != nil {
+		q = q.Limit(*fltr.Paginator.Limit)
+	}
+	if fltr.Paginator.Offset != nil {
+		q = q.Offset(*fltr.Paginator.Offset)
+	}
+	results := make([]*AccountAuditSQL, 0)
+	if err = q.Find(&results).Error; err != nil {
+		return
+	}
+	for _, result := range results {
+		audits = append(audits, &AccountAudit{
+			Tenant:      result.Tenant,
+			Account:     result.Account,
+			BalanceType: result.BalanceType,
+			BalanceID:   result.BalanceID,
+			BalanceUUID: result.BalanceUUID,
+			ValueBefore: result.ValueBefore,
+			ValueAfter:  result.ValueAfter,
+			Cause:       result.Cause,
+			ActionsID:   result.ActionsID,
+			CGRID:       result.Cgrid,
+			Timestamp:   result.Timestamp,
+		})
+	}
+	if len(audits) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+func (self *SQLStorage) SetAccountSnapshot(snp *AccountSnapshot) error {
+	blncs, err := json.Marshal(snp.BalanceMap)
+	if err != nil {
+		return err
+	}
+	return self.db.Save(&AccountSnapshotSQL{
+		Tenant:    snp.Tenant,
+		Account:   snp.Account,
+		Timestamp: snp.Timestamp,
+		Balances:  string(blncs),
+	}).Error
+}
+
+// GetAccountSnapshot returns the latest snapshot of the account taken not later than before
+func (self *SQLStorage) GetAccountSnapshot(tenant, account string, before time.Time) (*AccountSnapshot, error) {
+	var result AccountSnapshotSQL
+	q := self.db.Where(&AccountSnapshotSQL{Tenant: tenant, Account: account}).
+		Where("timestamp <= ?", before).Order("timestamp desc, id desc").First(&result)
+	if q.RecordNotFound() {
+		return nil, utils.ErrNotFound
+	} else if q.Error != nil {
+		return nil, q.Error
+	}
+	snp := &AccountSnapshot{
+		Tenant:    result.Tenant,
+		Account:   result.Account,
+		Timestamp: result.Timestamp,
+	}
+	if err := json.Unmarshal([]byte(result.Balances), &snp.BalanceMap); err != nil {
+		return nil, err
+	}
+	return snp, nil
+}
+
 // GetSMCosts is used to retrieve one or multiple SMCosts based on filter
 func (self *SQLStorage) GetSMCosts(cgrid, runid, originHost, originIDPrefix string) ([]*SMCost, error) {
 	var smCosts []*SMCost
@@ -816,6 +995,11 @@ func (self *SQLStorage) GetSMCosts(cgrid, runid, originHost, originIDPrefix stri
 		if err := json.Unmarshal([]byte(result.CostDetails), smc.CostDetails); err != nil {
 			return nil, err
 		}
+		if len(result.History) != 0 {
+			if err := json.Unmarshal([]byte(result.History), &smc.History); err != nil {
+				return nil, err
+			}
+		}
 		smCosts = append(smCosts, smc)
 	}
 	if len(smCosts) == 0 {
@@ -1657,6 +1841,56 @@ func (self *SQLStorage) GetTPDispatchers(tpid, tenant, id string) ([]*utils.TPDi
 	return arls, nil
 }
 
+func (self *SQLStorage) GetTPExchangeRates(tpid string) ([]*utils.TPExchangeRate, error) {
+	var tpExrs TpExchangeRates
+	if err := self.db.Where("tpid = ?", tpid).Find(&tpExrs).Error; err != nil {
+		return nil, err
+	}
+	exrs := tpExrs.AsTPExchangeRates()
+	if len(exrs) == 0 {
+		return exrs, utils.ErrNotFound
+	}
+	return exrs, nil
+}
+
+func (self *SQLStorage) GetTPTaxProfiles(tpid, tenant, id string) ([]*utils.TPTaxProfile, error) {
+	var txps TpTaxProfiles
+	q := self.db.Where("tpid = ?", tpid)
+	if len(id) != 0 {
+		q = q.Where("id = ?", id)
+	}
+	if len(tenant) != 0 {
+		q = q.Where("tenant = ?", tenant)
+	}
+	if err := q.Find(&txps).Error; err != nil {
+		return nil, err
+	}
+	tpTxps := txps.AsTPTaxProfiles()
+	if len(tpTxps) == 0 {
+		return tpTxps, utils.ErrNotFound
+	}
+	return tpTxps, nil
+}
+
+func (self *SQLStorage) GetTPCalendars(tpid, tenant, id string) ([]*utils.TPCalendar, error) {
+	var cals TpCalendars
+	q := self.db.Where("tpid = ?", tpid)
+	if len(id) != 0 {
+		q = q.Where("id = ?", id)
+	}
+	if len(tenant) != 0 {
+		q = q.Where("tenant = ?", tenant)
+	}
+	if err := q.Find(&cals).Error; err != nil {
+		return nil, err
+	}
+	tpCals := cals.AsTPCalendars()
+	if len(tpCals) == 0 {
+		return tpCals, utils.ErrNotFound
+	}
+	return tpCals, nil
+}
+
 // GetVersions returns slice of all versions or a specific version if tag is specified
 func (self *SQLStorage) GetVersions(itm string) (vrs Versions, err error) {
 	q := self.db.Model(&TBLVersion{})
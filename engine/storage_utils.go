@@ -164,6 +164,7 @@ type SMCost struct {
 	CostSource  string
 	Usage       time.Duration
 	CostDetails *EventCost
+	History     []*SessionHistoryEntry
 }
 
 type AttrCDRSStoreSMCost struct {
@@ -184,4 +185,5 @@ type V2SMCost struct {
 	CostSource  string
 	Usage       time.Duration
 	CostDetails *EventCost
+	History     []*SessionHistoryEntry
 }
@@ -0,0 +1,303 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// Subscription is a recurring fee charged on an account once per billing cycle
+// Cycles are charged in advance, the first and the last ones being prorated
+type Subscription struct {
+	PlanID          string
+	Price           float64   // price of one full billing cycle
+	BillingCycle    string    // <*daily|*weekly|*monthly|*yearly>
+	AnniversaryDate time.Time // cycles start on this date, defaults to StartTime
+	StartTime       time.Time
+	StopTime        time.Time // zero while the subscription is active
+	ChargedUntil    time.Time // end of the last charged interval
+}
+
+// Clone returns a copy of the subscription
+func (sub *Subscription) Clone() *Subscription {
+	clned := *sub
+	return &clned
+}
+
+// IsBillingCycle checks if the cycle is one of the supported subscription billing cycles
+func IsBillingCycle(cycle string) bool {
+	switch cycle {
+	case utils.MetaDaily, utils.MetaWeekly, utils.MetaMonthly, utils.MetaYearly:
+		return true
+	}
+	return false
+}
+
+// cycleStart returns the start of the cycle with index n, counted from the anniversary date
+// monthly and yearly anniversaries falling after the end of the month are moved on its last day
+func (sub *Subscription) cycleStart(n int) time.Time {
+	anniv := sub.AnniversaryDate
+	if anniv.IsZero() {
+		anniv = sub.StartTime
+	}
+	switch sub.BillingCycle {
+	case utils.MetaDaily:
+		return anniv.AddDate(0, 0, n)
+	case utils.MetaWeekly:
+		return anniv.AddDate(0, 0, 7*n)
+	case utils.MetaYearly:
+		n *= 12
+	}
+	monthStart := time.Date(anniv.Year(), anniv.Month()+time.Month(n), 1,
+		anniv.Hour(), anniv.Minute(), anniv.Second(), anniv.Nanosecond(), anniv.Location())
+	day := anniv.Day()
+	if lastDay := monthStart.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return monthStart.AddDate(0, 0, day-1)
+}
+
+// cycleIndex returns the index of the cycle containing t
+func (sub *Subscription) cycleIndex(t time.Time) (n int) {
+	anniv := sub.cycleStart(0)
+	switch sub.BillingCycle {
+	case utils.MetaDaily:
+		n = int(t.Sub(anniv) / (24 * time.Hour))
+	case utils.MetaWeekly:
+		n = int(t.Sub(anniv) / (7 * 24 * time.Hour))
+	case utils.MetaMonthly:
+		n = (t.Year()-anniv.Year())*12 + int(t.Month()-anniv.Month())
+	case utils.MetaYearly:
+		n = t.Year() - anniv.Year()
+	}
+	// the estimation above can be off by one on DST changes or partial months
+	for sub.cycleStart(n).After(t) {
+		n--
+	}
+	for !sub.cycleStart(n + 1).After(t) {
+		n++
+	}
+	return
+}
+
+// SubscriptionCharge is the fee of a subscription for one interval within a billing cycle
+type SubscriptionCharge struct {
+	PlanID    string
+	StartTime time.Time
+	EndTime   time.Time
+	Amount    float64 // negative for refunds
+}
+
+// prorate splits the interval on billing cycles, charging each part proportionally with its duration
+func (sub *Subscription) prorate(start, end time.Time) (chrgs []*SubscriptionCharge) {
+	price := utils.NewDecimalFromFloat64(sub.Price)
+	for start.Before(end) {
+		n := sub.cycleIndex(start)
+		cycleStart, cycleEnd := sub.cycleStart(n), sub.cycleStart(n+1)
+		chrgEnd := cycleEnd
+		if end.Before(chrgEnd) {
+			chrgEnd = end
+		}
+		amount := price
+		if chrgEnd.Sub(start) != cycleEnd.Sub(cycleStart) { // partial cycle
+			amount = price.Mul(utils.NewDecimalFromInt64(int64(chrgEnd.Sub(start)))).
+				Div(utils.NewDecimalFromInt64(int64(cycleEnd.Sub(cycleStart))))
+		}
+		chrgs = append(chrgs, &SubscriptionCharge{
+			PlanID:    sub.PlanID,
+			StartTime: start,
+			EndTime:   chrgEnd,
+			Amount:    amount.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64(),
+		})
+		start = chrgEnd
+	}
+	return
+}
+
+// chargedFrom returns the time the next charge starts from
+func (sub *Subscription) chargedFrom() time.Time {
+	if sub.ChargedUntil.IsZero() {
+		return sub.StartTime
+	}
+	return sub.ChargedUntil
+}
+
+// isRefunding checks if the subscription was stopped within the charged interval
+func (sub *Subscription) isRefunding() bool {
+	return !sub.StopTime.IsZero() && sub.StopTime.Before(sub.chargedFrom())
+}
+
+// DueCharges returns the charges needed to bring the subscription up to date at now
+// Cycles started until now are charged in full, stopping refunds the remainder of the charged cycle
+func (sub *Subscription) DueCharges(now time.Time) (chrgs []*SubscriptionCharge) {
+	from := sub.chargedFrom()
+	if sub.isRefunding() { // refund the time charged after stop
+		for _, chrg := range sub.prorate(sub.StopTime, from) {
+			chrg.Amount = -chrg.Amount
+			chrgs = append(chrgs, chrg)
+		}
+		return
+	}
+	for !from.After(now) &&
+		(sub.StopTime.IsZero() || from.Before(sub.StopTime)) {
+		until := sub.cycleStart(sub.cycleIndex(from) + 1)
+		if !sub.StopTime.IsZero() && sub.StopTime.Before(until) {
+			until = sub.StopTime
+		}
+		chrgs = append(chrgs, sub.prorate(from, until)...)
+		from = until
+	}
+	return
+}
+
+// chargeSubscriptions debits the due subscription fees out of the *default monetary balance
+// returns one action for each charge so they can be logged as CDRs
+func (acc *Account) chargeSubscriptions(now time.Time) (chrgActs Actions, err error) {
+	for planID, sub := range acc.Subscriptions {
+		refund := sub.isRefunding()
+		for _, chrg := range sub.DueCharges(now) {
+			a := &Action{
+				Id:         planID,
+				ActionType: MetaChargeSubscriptions,
+				Balance: &BalanceFilter{
+					ID:    utils.StringPointer(utils.META_DEFAULT),
+					Type:  utils.StringPointer(utils.MONETARY),
+					Value: &utils.ValueFormula{Static: chrg.Amount},
+				},
+			}
+			if chrg.Amount != 0 {
+				if err = acc.debitBalanceAction(a, false, false); err != nil {
+					return
+				}
+			}
+			if !refund {
+				sub.ChargedUntil = chrg.EndTime
+			}
+			chrgActs = append(chrgActs, a)
+		}
+		if refund {
+			sub.ChargedUntil = sub.StopTime
+		}
+		if !sub.StopTime.IsZero() && !sub.ChargedUntil.After(sub.StopTime) &&
+			!sub.StopTime.After(now) { // stopped and settled
+			delete(acc.Subscriptions, planID)
+		}
+	}
+	return
+}
+
+// SetSubscription starts a new subscription on the account or updates the one with the same PlanID
+func (acc *Account) SetSubscription(sub *Subscription) (err error) {
+	if !IsBillingCycle(sub.BillingCycle) {
+		return fmt.Errorf("unsupported BillingCycle: %s", sub.BillingCycle)
+	}
+	if acc.Subscriptions == nil {
+		acc.Subscriptions = make(map[string]*Subscription)
+	}
+	if crnt, has := acc.Subscriptions[sub.PlanID]; has { // keep what was charged already
+		sub.ChargedUntil = crnt.ChargedUntil
+	}
+	acc.Subscriptions[sub.PlanID] = sub
+	return
+}
+
+// StopSubscription schedules the end of a subscription, the charged time after stopTime is refunded
+func (acc *Account) StopSubscription(planID string, stopTime time.Time) (err error) {
+	sub, has := acc.Subscriptions[planID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	if stopTime.Before(sub.StartTime) {
+		stopTime = sub.StartTime
+	}
+	sub.StopTime = stopTime
+	return
+}
+
+// AttachSubscriptionsPlan attaches the account to the ActionPlan charging its subscriptions and
+// queues a task charging them right away, so the first cycle is not left for the next execution.
+// The default *charge_subscriptions ActionPlan, running daily, is created together with its Actions on first use.
+func AttachSubscriptionsPlan(acntID, apID string) (err error) {
+	if apID == "" {
+		apID = MetaChargeSubscriptions
+	}
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		ap, err := dm.DataDB().GetActionPlan(apID, false, utils.NonTransactional)
+		if err == utils.ErrNotFound && apID == MetaChargeSubscriptions {
+			if err = dm.SetActions(apID, Actions{&Action{Id: apID,
+				ActionType: MetaChargeSubscriptions}}, utils.NonTransactional); err != nil {
+				return 0, err
+			}
+			ap = &ActionPlan{
+				Id: apID,
+				ActionTimings: []*ActionTiming{{
+					Uuid:      utils.GenUUID(),
+					ActionsID: apID,
+					Timing:    &RateInterval{Timing: &RITiming{StartTime: "00:00:00"}},
+				}},
+			}
+		} else if err != nil {
+			return 0, err
+		}
+		var chrgActsID string
+		for _, at := range ap.ActionTimings {
+			acts, err := dm.GetActions(at.ActionsID, false, utils.NonTransactional)
+			if err != nil {
+				return 0, err
+			}
+			for _, a := range acts {
+				if a.ActionType == MetaChargeSubscriptions {
+					chrgActsID = at.ActionsID
+				}
+			}
+		}
+		if chrgActsID == "" {
+			return 0, fmt.Errorf("ActionPlan <%s> does not charge subscriptions", apID)
+		}
+		if !ap.AccountIDs.HasKey(acntID) {
+			if ap.AccountIDs == nil {
+				ap.AccountIDs = make(utils.StringMap)
+			}
+			ap.AccountIDs[acntID] = true
+			if err = dm.DataDB().SetActionPlan(apID, ap, true, utils.NonTransactional); err != nil {
+				return 0, err
+			}
+			if err = dm.CacheDataFromDB(utils.ACTION_PLAN_PREFIX, []string{apID}, true); err != nil {
+				return 0, err
+			}
+			if err = dm.DataDB().SetAccountActionPlans(acntID, []string{apID}, false); err != nil {
+				return 0, err
+			}
+			if err = dm.CacheDataFromDB(utils.AccountActionPlansPrefix, []string{acntID}, true); err != nil {
+				return 0, err
+			}
+		}
+		return 0, dm.DataDB().PushTask(&Task{
+			Uuid:      utils.GenUUID(),
+			AccountID: acntID,
+			ActionsID: chrgActsID,
+		})
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.ACTION_PLAN_PREFIX)
+	return
+}
@@ -0,0 +1,183 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestSubscriptionCycleStart(t *testing.T) {
+	sub := &Subscription{
+		BillingCycle:    utils.MetaMonthly,
+		AnniversaryDate: time.Date(2018, time.January, 31, 0, 0, 0, 0, time.UTC),
+	}
+	if cs := sub.cycleStart(1); !cs.Equal(time.Date(2018, time.February, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("received: %v", cs)
+	}
+	if cs := sub.cycleStart(2); !cs.Equal(time.Date(2018, time.March, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("received: %v", cs)
+	}
+	if n := sub.cycleIndex(time.Date(2018, time.March, 30, 0, 0, 0, 0, time.UTC)); n != 1 {
+		t.Errorf("expecting: 1, received: %d", n)
+	}
+	sub.BillingCycle = utils.MetaWeekly
+	if n := sub.cycleIndex(time.Date(2018, time.January, 30, 0, 0, 0, 0, time.UTC)); n != -1 {
+		t.Errorf("expecting: -1, received: %d", n)
+	}
+}
+
+func TestSubscriptionDueCharges(t *testing.T) {
+	sub := &Subscription{
+		PlanID:          "PLAN1",
+		Price:           31,
+		BillingCycle:    utils.MetaMonthly,
+		AnniversaryDate: time.Date(2018, time.September, 1, 0, 0, 0, 0, time.UTC),
+		StartTime:       time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC),
+	}
+	eChrgs := []*SubscriptionCharge{
+		{PlanID: "PLAN1", StartTime: sub.StartTime,
+			EndTime: time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC), Amount: 15.5},
+	}
+	if chrgs := sub.DueCharges(sub.StartTime); !reflect.DeepEqual(eChrgs, chrgs) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eChrgs), utils.ToJSON(chrgs))
+	}
+	sub.ChargedUntil = time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC)
+	if chrgs := sub.DueCharges(time.Date(2018, time.September, 30, 0, 0, 0, 0, time.UTC)); len(chrgs) != 0 {
+		t.Errorf("received: %s", utils.ToJSON(chrgs))
+	}
+	eChrgs = []*SubscriptionCharge{
+		{PlanID: "PLAN1", StartTime: sub.ChargedUntil,
+			EndTime: time.Date(2018, time.November, 1, 0, 0, 0, 0, time.UTC), Amount: 31},
+	}
+	if chrgs := sub.DueCharges(sub.ChargedUntil); !reflect.DeepEqual(eChrgs, chrgs) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eChrgs), utils.ToJSON(chrgs))
+	}
+	sub.ChargedUntil = time.Date(2018, time.November, 1, 0, 0, 0, 0, time.UTC)
+	sub.StopTime = time.Date(2018, time.October, 11, 0, 0, 0, 0, time.UTC)
+	eChrgs = []*SubscriptionCharge{
+		{PlanID: "PLAN1", StartTime: sub.StopTime, EndTime: sub.ChargedUntil, Amount: -21},
+	}
+	if chrgs := sub.DueCharges(sub.StopTime); !reflect.DeepEqual(eChrgs, chrgs) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eChrgs), utils.ToJSON(chrgs))
+	}
+}
+
+func TestAccountChargeSubscriptions(t *testing.T) {
+	acc := &Account{
+		ID: "cgrates.org:subscr",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: Balances{&Balance{ID: utils.META_DEFAULT, Value: 100}},
+		},
+	}
+	if err := acc.SetSubscription(&Subscription{PlanID: "PLAN1", Price: 31,
+		BillingCycle: "*hourly"}); err == nil {
+		t.Error("expecting error for unsupported BillingCycle")
+	}
+	start := time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC)
+	if err := acc.SetSubscription(&Subscription{PlanID: "PLAN1", Price: 31,
+		BillingCycle:    utils.MetaMonthly,
+		AnniversaryDate: time.Date(2018, time.September, 1, 0, 0, 0, 0, time.UTC),
+		StartTime:       start}); err != nil {
+		t.Error(err)
+	}
+	chrgActs, err := acc.chargeSubscriptions(start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chrgActs) != 1 || chrgActs[0].Id != "PLAN1" ||
+		chrgActs[0].ActionType != MetaChargeSubscriptions ||
+		chrgActs[0].Balance.GetValue() != 15.5 {
+		t.Errorf("received: %s", utils.ToJSON(chrgActs))
+	}
+	if val := acc.BalanceMap[utils.MONETARY][0].GetValue(); val != 84.5 {
+		t.Errorf("expecting: 84.5, received: %v", val)
+	}
+	if err := acc.StopSubscription("PLAN2", start); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	// stop in the middle of the charged interval, refund the rest
+	stop := time.Date(2018, time.September, 26, 0, 0, 0, 0, time.UTC)
+	if err := acc.StopSubscription("PLAN1", stop); err != nil {
+		t.Error(err)
+	}
+	if chrgActs, err = acc.chargeSubscriptions(stop); err != nil {
+		t.Fatal(err)
+	}
+	if len(chrgActs) != 1 || chrgActs[0].Balance.GetValue() != -5.166667 {
+		t.Errorf("received: %s", utils.ToJSON(chrgActs))
+	}
+	if val := acc.BalanceMap[utils.MONETARY][0].GetValue(); val != 89.666667 {
+		t.Errorf("expecting: 89.666667, received: %v", val)
+	}
+	if _, has := acc.Subscriptions["PLAN1"]; has {
+		t.Error("settled subscription not removed")
+	}
+	if logActs := cdrLogActions(Actions{&Action{ActionType: MetaChargeSubscriptions,
+		charges: chrgActs}}); !reflect.DeepEqual(chrgActs, logActs) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(chrgActs), utils.ToJSON(logActs))
+	}
+}
+
+func TestAttachSubscriptionsPlan(t *testing.T) {
+	// clean previous unused tasks
+	for task, _ := dm.DataDB().PopTask(); task != nil; task, _ = dm.DataDB().PopTask() {
+	}
+	if err := AttachSubscriptionsPlan("cgrates.org:sub_attached", ""); err != nil {
+		t.Fatal(err)
+	}
+	ap, err := dm.DataDB().GetActionPlan(MetaChargeSubscriptions, true, utils.NonTransactional)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ap.AccountIDs.HasKey("cgrates.org:sub_attached") || len(ap.ActionTimings) != 1 ||
+		ap.ActionTimings[0].ActionsID != MetaChargeSubscriptions {
+		t.Errorf("unexpected ActionPlan: %s", utils.ToJSON(ap))
+	}
+	if apIDs, err := dm.DataDB().GetAccountActionPlans("cgrates.org:sub_attached",
+		true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !utils.IsSliceMember(apIDs, MetaChargeSubscriptions) {
+		t.Errorf("ActionPlan not attached to the account: %v", apIDs)
+	}
+	if task, err := dm.DataDB().PopTask(); err != nil {
+		t.Error(err)
+	} else if task == nil || task.AccountID != "cgrates.org:sub_attached" ||
+		task.ActionsID != MetaChargeSubscriptions {
+		t.Errorf("unexpected task: %s", utils.ToJSON(task))
+	}
+	// the plan is reused for other accounts
+	if err := AttachSubscriptionsPlan("cgrates.org:sub_attached2", MetaChargeSubscriptions); err != nil {
+		t.Fatal(err)
+	}
+	if ap, err = dm.DataDB().GetActionPlan(MetaChargeSubscriptions, true, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	} else if len(ap.AccountIDs) != 2 || len(ap.ActionTimings) != 1 {
+		t.Errorf("unexpected ActionPlan: %s", utils.ToJSON(ap))
+	}
+	if _, err := dm.DataDB().PopTask(); err != nil {
+		t.Error(err)
+	}
+	if err := AttachSubscriptionsPlan("cgrates.org:sub_attached", "AP_MISSING"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
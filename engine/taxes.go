@@ -0,0 +1,167 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// TaxProfile defines one tax applied on the cost of the matching events
+type TaxProfile struct {
+	Tenant             string
+	ID                 string
+	FilterIDs          []string                  // jurisdiction of the tax, eg: destinations or account attributes
+	ActivationInterval *utils.ActivationInterval // Activation interval
+	Rate               float64                   // percentage out of the taxable amount
+	Inclusive          bool                      // the tax is already contained into the cost
+	Compound           bool                      // the tax applies also on top of the taxes with higher Weight
+	Weight             float64                   // higher Weight is applied first
+}
+
+func (txp *TaxProfile) TenantID() string {
+	return utils.ConcatenatedKey(txp.Tenant, txp.ID)
+}
+
+// TaxProfiles is a sortable list of TaxProfiles
+type TaxProfiles []*TaxProfile
+
+// Sort is part of sort interface, sort based on Weight
+func (txps TaxProfiles) Sort() {
+	sort.Slice(txps, func(i, j int) bool { return txps[i].Weight > txps[j].Weight })
+}
+
+// ComputeTaxes returns one TaxLine for each of the taxes applied on cost
+// Inclusive taxes are extracted out of the cost, exclusive ones come on top of it
+func (txps TaxProfiles) ComputeTaxes(cost float64) (txLns []*TaxLine) {
+	if len(txps) == 0 {
+		return
+	}
+	// compute the taxable amount and the tax out of a net amount of 1
+	one := utils.NewDecimalFromInt64(1)
+	hundred := utils.NewDecimalFromInt64(100)
+	bases := make([]*utils.Decimal, len(txps))
+	taxes := make([]*utils.Decimal, len(txps))
+	taxed := one      // net amount plus the taxes applied so far
+	inclFactor := one // net amount plus the inclusive taxes
+	for i, txp := range txps {
+		bases[i] = one
+		if txp.Compound {
+			bases[i] = taxed
+		}
+		taxes[i] = bases[i].Mul(utils.NewDecimalFromFloat64(txp.Rate)).Div(hundred)
+		taxed = taxed.Add(taxes[i])
+		if txp.Inclusive {
+			inclFactor = inclFactor.Add(taxes[i])
+		}
+	}
+	net := utils.NewDecimalFromFloat64(cost).Div(inclFactor)
+	txLns = make([]*TaxLine, len(txps))
+	for i, txp := range txps {
+		txLns[i] = &TaxLine{
+			TaxProfileID: txp.ID,
+			Rate:         txp.Rate,
+			Inclusive:    txp.Inclusive,
+			Compound:     txp.Compound,
+			TaxableAmount: net.Mul(bases[i]).
+				Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64(),
+			Amount: net.Mul(taxes[i]).
+				Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64(),
+		}
+	}
+	return
+}
+
+// TaxLine is one tax charged on an event
+type TaxLine struct {
+	TaxProfileID  string
+	Rate          float64
+	Inclusive     bool
+	Compound      bool
+	TaxableAmount float64 // amount the Rate was applied on
+	Amount        float64 // tax amount
+}
+
+// TaxLines is the list of taxes charged on an event
+type TaxLines []*TaxLine
+
+// Total returns the sum of the tax amounts, optionally only for the exclusive taxes
+func (txLns TaxLines) Total(exclusiveOnly bool) float64 {
+	total := utils.NewDecimalFromInt64(0)
+	for _, txLn := range txLns {
+		if exclusiveOnly && txLn.Inclusive {
+			continue
+		}
+		total = total.Add(utils.NewDecimalFromFloat64(txLn.Amount))
+	}
+	return total.Float64()
+}
+
+// matchingTaxProfilesForEvent returns the TaxProfiles of the tenant matching the event, ordered by Weight
+func matchingTaxProfilesForEvent(dm *DataManager, filterS *FilterS, tenant string,
+	atTime time.Time, ev map[string]interface{}, indexedSelects bool) (txps TaxProfiles, err error) {
+	txpIDs, err := MatchingItemIDsForEvent(ev, nil, nil,
+		dm, utils.CacheTaxFilterIndexes, tenant, indexedSelects)
+	if err != nil {
+		if err == utils.ErrNotFound { // no taxes for the event
+			err = nil
+		}
+		return
+	}
+	evNm := config.NewNavigableMap(ev)
+	for txpID := range txpIDs {
+		txp, err := dm.GetTaxProfile(tenant, txpID, true, true, utils.NonTransactional)
+		if err != nil {
+			if err == utils.ErrNotFound {
+				continue
+			}
+			return nil, err
+		}
+		if txp.ActivationInterval != nil &&
+			!txp.ActivationInterval.IsActiveAtTime(atTime) { // not active
+			continue
+		}
+		if pass, err := filterS.Pass(tenant, txp.FilterIDs, evNm); err != nil {
+			return nil, err
+		} else if !pass {
+			continue
+		}
+		txps = append(txps, txp)
+	}
+	txps.Sort()
+	return
+}
+
+// TaxCDR populates the TaxLines of a rated CDR out of the TaxProfiles matching it
+func TaxCDR(dm *DataManager, filterS *FilterS, cdr *CDR, indexedSelects bool) (err error) {
+	cdr.TaxLines = nil
+	if cdr.Cost <= 0 { // not rated or free
+		return
+	}
+	var txps TaxProfiles
+	if txps, err = matchingTaxProfilesForEvent(dm, filterS, cdr.Tenant,
+		cdr.AnswerTime, cdr.AsMapStringIface(), indexedSelects); err != nil {
+		return
+	}
+	cdr.TaxLines = txps.ComputeTaxes(cdr.Cost)
+	return
+}
@@ -0,0 +1,127 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestTaxProfilesSort(t *testing.T) {
+	txps := TaxProfiles{
+		&TaxProfile{ID: "TX1", Weight: 10},
+		&TaxProfile{ID: "TX2", Weight: 20},
+		&TaxProfile{ID: "TX3", Weight: 5},
+	}
+	txps.Sort()
+	if txps[0].ID != "TX2" || txps[1].ID != "TX1" || txps[2].ID != "TX3" {
+		t.Errorf("Wrong order: %s", utils.ToJSON(txps))
+	}
+}
+
+func TestTaxProfilesComputeTaxesExclusive(t *testing.T) {
+	txps := TaxProfiles{&TaxProfile{ID: "VAT", Rate: 19}}
+	eTxLns := []*TaxLine{
+		&TaxLine{TaxProfileID: "VAT", Rate: 19, TaxableAmount: 10, Amount: 1.9},
+	}
+	if txLns := txps.ComputeTaxes(10); !reflect.DeepEqual(eTxLns, txLns) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eTxLns), utils.ToJSON(txLns))
+	}
+}
+
+func TestTaxProfilesComputeTaxesCompound(t *testing.T) {
+	txps := TaxProfiles{
+		&TaxProfile{ID: "GST", Rate: 5, Weight: 20},
+		&TaxProfile{ID: "QST", Rate: 9.975, Compound: true, Weight: 10},
+	}
+	eTxLns := []*TaxLine{
+		&TaxLine{TaxProfileID: "GST", Rate: 5, TaxableAmount: 100, Amount: 5},
+		&TaxLine{TaxProfileID: "QST", Rate: 9.975, Compound: true,
+			TaxableAmount: 105, Amount: 10.47375},
+	}
+	txLns := txps.ComputeTaxes(100)
+	if !reflect.DeepEqual(eTxLns, txLns) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eTxLns), utils.ToJSON(txLns))
+	}
+	if total := TaxLines(txLns).Total(false); total != 15.47375 {
+		t.Errorf("Unexpected total: %v", total)
+	}
+}
+
+func TestTaxProfilesComputeTaxesInclusive(t *testing.T) {
+	txps := TaxProfiles{&TaxProfile{ID: "VAT", Rate: 20, Inclusive: true}}
+	eTxLns := []*TaxLine{
+		&TaxLine{TaxProfileID: "VAT", Rate: 20, Inclusive: true,
+			TaxableAmount: 10, Amount: 2},
+	}
+	txLns := txps.ComputeTaxes(12)
+	if !reflect.DeepEqual(eTxLns, txLns) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eTxLns), utils.ToJSON(txLns))
+	}
+	if total := TaxLines(txLns).Total(true); total != 0 {
+		t.Errorf("Unexpected exclusive total: %v", total)
+	}
+}
+
+func TestTaxCDRIndexed(t *testing.T) {
+	data, _ := NewMapStorage()
+	dmTax := NewDataManager(data)
+	defaultCfg, err := config.NewDefaultCGRConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fltrS := &FilterS{dm: dmTax, cfg: defaultCfg}
+	if err := dmTax.SetFilter(&Filter{Tenant: "cgrates.org", ID: "FLTR_TAX_QC",
+		Rules: []*FilterRule{{Type: MetaString, FieldName: utils.Subject,
+			Values: []string{"1001"}}}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, txp := range []*TaxProfile{
+		&TaxProfile{Tenant: "cgrates.org", ID: "GST", Rate: 5, Weight: 20},
+		&TaxProfile{Tenant: "cgrates.org", ID: "QST", FilterIDs: []string{"FLTR_TAX_QC"},
+			Rate: 10, Weight: 10},
+	} {
+		if err := dmTax.SetTaxProfile(txp, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cdr := &CDR{Tenant: "cgrates.org", Subject: "1001", Cost: 10,
+		AnswerTime: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if err := TaxCDR(dmTax, fltrS, cdr, true); err != nil {
+		t.Fatal(err)
+	}
+	eTxLns := TaxLines{
+		&TaxLine{TaxProfileID: "GST", Rate: 5, TaxableAmount: 10, Amount: 0.5},
+		&TaxLine{TaxProfileID: "QST", Rate: 10, TaxableAmount: 10, Amount: 1},
+	}
+	if !reflect.DeepEqual(eTxLns, cdr.TaxLines) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eTxLns), utils.ToJSON(cdr.TaxLines))
+	}
+	cdr.Subject = "1002"
+	if err := TaxCDR(dmTax, fltrS, cdr, true); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(eTxLns[:1], cdr.TaxLines) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eTxLns[:1]), utils.ToJSON(cdr.TaxLines))
+	}
+}
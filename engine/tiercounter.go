@@ -0,0 +1,145 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// TierCounter keeps the usage of an account within one billing period, used by tiered rates
+type TierCounter struct {
+	PeriodStart time.Time
+	Usage       time.Duration
+}
+
+// tierCounterKey returns the key of the counter within Account.TierCounters
+func tierCounterKey(tor, period string) string {
+	return utils.ConcatenatedKey(tor, period)
+}
+
+// TierUsage returns the usage of the account within the period containing t
+func (acc *Account) TierUsage(tor, period string, t time.Time) time.Duration {
+	tc, has := acc.TierCounters[tierCounterKey(tor, period)]
+	if !has {
+		return 0
+	}
+	if pStart, err := utils.GetPeriodStart(t, period); err != nil ||
+		!tc.PeriodStart.Equal(pStart) { // counter belongs to a previous period
+		return 0
+	}
+	return tc.Usage
+}
+
+// addTierUsage increases the counter of the period containing t, resetting it when a new period starts
+func (acc *Account) addTierUsage(tor, period string, t time.Time, usage time.Duration) {
+	pStart, err := utils.GetPeriodStart(t, period)
+	if err != nil {
+		return
+	}
+	if acc.TierCounters == nil {
+		acc.TierCounters = make(map[string]*TierCounter)
+	}
+	tcKey := tierCounterKey(tor, period)
+	if tc, has := acc.TierCounters[tcKey]; !has || tc.PeriodStart.Before(pStart) {
+		acc.TierCounters[tcKey] = &TierCounter{PeriodStart: pStart}
+	} else if tc.PeriodStart.After(pStart) {
+		return // late usage, its period is already closed
+	}
+	acc.TierCounters[tcKey].Usage += usage
+}
+
+// countTierUsage adds to the counters the usage of the timespans rated by tiered rates
+func (acc *Account) countTierUsage(cc *CallCost) {
+	for _, ts := range cc.Timespans {
+		if ts.RateInterval == nil || ts.RateInterval.Rating == nil ||
+			ts.RateInterval.Rating.TierPeriod == "" {
+			continue
+		}
+		acc.addTierUsage(cc.TOR, ts.RateInterval.Rating.TierPeriod,
+			ts.TimeStart, ts.GetDuration())
+		ts.SetTierPeriod()
+	}
+}
+
+// SetTierPeriod marks the increments with the tier counter their usage was counted on, consulted on refunds
+func (ts *TimeSpan) SetTierPeriod() {
+	if ts.RateInterval == nil || ts.RateInterval.Rating == nil ||
+		ts.RateInterval.Rating.TierPeriod == "" {
+		return
+	}
+	pStart, err := utils.GetPeriodStart(ts.TimeStart, ts.RateInterval.Rating.TierPeriod)
+	if err != nil {
+		return
+	}
+	for _, incr := range ts.Increments {
+		incr.TierPeriod = ts.RateInterval.Rating.TierPeriod
+		incr.TierPeriodStart = pStart
+	}
+}
+
+// refundTierUsage decreases the counter the refunded increment was counted on
+// nothing is refunded if the counter moved meanwhile to a new period
+func (acc *Account) refundTierUsage(tor string, incr *Increment) {
+	if incr.TierPeriod == "" {
+		return
+	}
+	tc, has := acc.TierCounters[tierCounterKey(tor, incr.TierPeriod)]
+	if !has || !tc.PeriodStart.Equal(incr.TierPeriodStart) {
+		return
+	}
+	if tc.Usage -= incr.Duration; tc.Usage < 0 {
+		tc.Usage = 0
+	}
+}
+
+// tierUsages returns the usage within the current billing periods, indexed on period
+func (acc *Account) tierUsages(tor string, t time.Time) (tus map[string]time.Duration) {
+	tus = make(map[string]time.Duration)
+	for _, period := range []string{utils.MetaDaily, utils.MetaWeekly,
+		utils.MetaMonthly, utils.MetaYearly} {
+		if usage := acc.TierUsage(tor, period, t); usage != 0 {
+			tus[period] = usage
+		}
+	}
+	return
+}
+
+// setTierUsages caches the usage of the account within billing periods, consulted by tiered rates
+// the usage already debited out of this call is excluded since it is part of the DurationIndex
+func (cd *CallDescriptor) setTierUsages(acnt *Account) {
+	cd.tierUsages = make(map[string]time.Duration)
+	if acnt == nil {
+		return
+	}
+	callUsage := cd.DurationIndex - cd.GetDuration()
+	if callUsage < 0 {
+		callUsage = 0
+	}
+	tor := cd.TOR
+	if tor == "" {
+		tor = utils.VOICE
+	}
+	for period, usage := range acnt.tierUsages(tor, cd.TimeStart) {
+		if usage -= callUsage; usage > 0 {
+			cd.tierUsages[period] = usage
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAccountTierUsage(t *testing.T) {
+	acc := &Account{ID: "cgrates.org:tiered"}
+	oct := time.Date(2018, time.October, 10, 10, 0, 0, 0, time.UTC)
+	acc.addTierUsage(utils.VOICE, utils.MetaMonthly, oct, time.Minute)
+	acc.addTierUsage(utils.VOICE, utils.MetaMonthly, oct.Add(time.Hour), 2*time.Minute)
+	if usage := acc.TierUsage(utils.VOICE, utils.MetaMonthly, oct); usage != 3*time.Minute {
+		t.Errorf("expecting: %v, received: %v", 3*time.Minute, usage)
+	}
+	if usage := acc.TierUsage(utils.DATA, utils.MetaMonthly, oct); usage != 0 {
+		t.Errorf("expecting: 0, received: %v", usage)
+	}
+	nov := time.Date(2018, time.November, 1, 0, 0, 1, 0, time.UTC)
+	if usage := acc.TierUsage(utils.VOICE, utils.MetaMonthly, nov); usage != 0 {
+		t.Errorf("expecting: 0, received: %v", usage)
+	}
+	acc.addTierUsage(utils.VOICE, utils.MetaMonthly, nov, time.Minute)
+	acc.addTierUsage(utils.VOICE, utils.MetaMonthly, oct, time.Hour) // period closed, not counted
+	if usage := acc.TierUsage(utils.VOICE, utils.MetaMonthly, nov); usage != time.Minute {
+		t.Errorf("expecting: %v, received: %v", time.Minute, usage)
+	}
+	octStart := time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC)
+	novStart := time.Date(2018, time.November, 1, 0, 0, 0, 0, time.UTC)
+	// usage counted within the previous period does not touch the current one
+	acc.refundTierUsage(utils.VOICE, &Increment{Duration: time.Minute,
+		TierPeriod: utils.MetaMonthly, TierPeriodStart: octStart})
+	if usage := acc.TierUsage(utils.VOICE, utils.MetaMonthly, nov); usage != time.Minute {
+		t.Errorf("expecting: %v, received: %v", time.Minute, usage)
+	}
+	// untiered usage
+	acc.refundTierUsage(utils.VOICE, &Increment{Duration: time.Minute})
+	if usage := acc.TierUsage(utils.VOICE, utils.MetaMonthly, nov); usage != time.Minute {
+		t.Errorf("expecting: %v, received: %v", time.Minute, usage)
+	}
+	acc.refundTierUsage(utils.VOICE, &Increment{Duration: 2 * time.Minute,
+		TierPeriod: utils.MetaMonthly, TierPeriodStart: novStart})
+	if usage := acc.TierUsage(utils.VOICE, utils.MetaMonthly, nov); usage != 0 {
+		t.Errorf("expecting: 0, received: %v", usage)
+	}
+}
+
+func TestTSSetTierPeriod(t *testing.T) {
+	ts := &TimeSpan{TimeStart: time.Date(2018, time.October, 10, 10, 0, 0, 0, time.UTC),
+		RateInterval: &RateInterval{Rating: &RIRate{TierPeriod: utils.MetaMonthly}},
+		Increments:   Increments{&Increment{Duration: time.Minute}}}
+	ts.SetTierPeriod()
+	if incr := ts.Increments[0]; incr.TierPeriod != utils.MetaMonthly ||
+		!incr.TierPeriodStart.Equal(time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected increment: %+v", incr)
+	}
+}
+
+func TestTSSplitByTieredRates(t *testing.T) {
+	i := &RateInterval{
+		Timing: &RITiming{},
+		Rating: &RIRate{
+			TierPeriod: utils.MetaMonthly,
+			Rates: RateGroups{
+				&Rate{
+					GroupIntervalStart: 0,
+					Value:              0.02,
+					RateIncrement:      time.Second,
+					RateUnit:           time.Minute},
+				&Rate{
+					GroupIntervalStart: 1000 * time.Minute,
+					Value:              0.015,
+					RateIncrement:      time.Second,
+					RateUnit:           time.Minute,
+				}}},
+	}
+	t1 := time.Date(2018, time.October, 10, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	ts := &TimeSpan{TimeStart: t1, TimeEnd: t2, DurationIndex: time.Minute,
+		ratingInfo: &RatingInfo{},
+		tierUsages: map[string]time.Duration{utils.MetaMonthly: 1000*time.Minute - 20*time.Second}}
+	nts := ts.SplitByRateInterval(i, false)
+	if nts == nil {
+		t.Fatal("timespan not split")
+	}
+	if splitTime := t1.Add(20 * time.Second); !ts.TimeEnd.Equal(splitTime) || !nts.TimeStart.Equal(splitTime) {
+		t.Errorf("wrong split: %v, %v", ts.TimeEnd, nts.TimeStart)
+	}
+	if rate, _, _ := ts.RateInterval.GetRateParameters(ts.GetGroupStart()); rate != 0.02 {
+		t.Errorf("wrong rate for first tier: %v", rate)
+	}
+	if rate, _, _ := nts.RateInterval.GetRateParameters(nts.GetGroupStart()); rate != 0.015 {
+		t.Errorf("wrong rate for second tier: %v", rate)
+	}
+	// untiered rates keep applying GroupIntervalStart on the call usage
+	i.Rating.TierPeriod = ""
+	ts = &TimeSpan{TimeStart: t1, TimeEnd: t2, DurationIndex: time.Minute,
+		ratingInfo: &RatingInfo{},
+		tierUsages: map[string]time.Duration{utils.MetaMonthly: 1000*time.Minute - 20*time.Second}}
+	if nts = ts.SplitByRateInterval(i, false); nts != nil {
+		t.Errorf("unexpected split: %+v", nts)
+	}
+}
@@ -38,14 +38,17 @@ type TimeSpan struct {
 	MatchedSubject, MatchedPrefix, MatchedDestId, RatingPlanId string
 	CompressFactor                                             int
 	ratingInfo                                                 *RatingInfo
+	tierUsages                                                 map[string]time.Duration // account usage within billing periods, indexed on period
 }
 
 type Increment struct {
-	Duration       time.Duration
-	Cost           float64
-	BalanceInfo    *DebitInfo // need more than one for units with cost
-	CompressFactor int
-	paid           bool
+	Duration        time.Duration
+	Cost            float64
+	BalanceInfo     *DebitInfo // need more than one for units with cost
+	CompressFactor  int
+	TierPeriod      string    // billing period of the tier counter the usage was counted on
+	TierPeriodStart time.Time // start of the period the tier counter was in when counting the usage
+	paid            bool
 }
 
 // Holds information about the balance that made a specific payment
@@ -79,6 +82,7 @@ type MonetaryInfo struct {
 	ID           string
 	Value        float64
 	RateInterval *RateInterval
+	ExchangeRate float64 // applied on the cost when the balance currency differs, 0 for none
 }
 
 func (mi *MonetaryInfo) Clone() *MonetaryInfo {
@@ -94,6 +98,7 @@ func (mi *MonetaryInfo) Equal(other *MonetaryInfo) bool {
 		return false
 	}
 	return mi.UUID == other.UUID &&
+		mi.ExchangeRate == other.ExchangeRate &&
 		reflect.DeepEqual(mi.RateInterval, other.RateInterval)
 }
 
@@ -280,8 +285,10 @@ func (tss *TimeSpans) Merge() { // Merge whenever possible
 
 func (incr *Increment) Clone() *Increment {
 	nInc := &Increment{
-		Duration: incr.Duration,
-		Cost:     incr.Cost,
+		Duration:        incr.Duration,
+		Cost:            incr.Cost,
+		TierPeriod:      incr.TierPeriod,
+		TierPeriodStart: incr.TierPeriodStart,
 	}
 	if incr.BalanceInfo != nil {
 		nInc.BalanceInfo = incr.BalanceInfo.Clone()
@@ -292,6 +299,8 @@ func (incr *Increment) Clone() *Increment {
 func (incr *Increment) Equal(other *Increment) bool {
 	return incr.Duration == other.Duration &&
 		incr.Cost == other.Cost &&
+		incr.TierPeriod == other.TierPeriod &&
+		incr.TierPeriodStart.Equal(other.TierPeriodStart) &&
 		((incr.BalanceInfo == nil && other.BalanceInfo == nil) || incr.BalanceInfo.Equal(other.BalanceInfo))
 }
 
@@ -303,7 +312,12 @@ func (incr *Increment) GetCompressFactor() int {
 }
 
 func (incr *Increment) GetCost() float64 {
-	return float64(incr.GetCompressFactor()) * incr.Cost
+	return incr.getDecimalCost().Float64()
+}
+
+// getDecimalCost returns the cost of all compressed increments as Decimal
+func (incr *Increment) getDecimalCost() *utils.Decimal {
+	return utils.NewDecimalFromFloat64(incr.Cost).Mul(utils.NewDecimalFromInt64(int64(incr.GetCompressFactor())))
 }
 
 type Increments []*Increment
@@ -386,11 +400,11 @@ func (incs Increments) SharingSignature(other Increments) bool {
 }
 
 func (incs Increments) GetTotalCost() float64 {
-	cost := 0.0
+	cost := utils.NewDecimalFromInt64(0)
 	for _, increment := range incs {
-		cost += increment.GetCost()
+		cost = cost.Add(increment.getDecimalCost())
 	}
-	return utils.Round(cost, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	return cost.Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE).Float64()
 }
 
 func (incs Increments) Length() (length int) {
@@ -438,7 +452,8 @@ func (ts *TimeSpan) CalculateCost() float64 {
 		}
 		return ts.RateInterval.GetCost(ts.GetDuration(), ts.GetGroupStart())
 	} else {
-		return ts.Increments.GetTotalCost() * float64(ts.GetCompressFactor())
+		return utils.NewDecimalFromFloat64(ts.Increments.GetTotalCost()).
+			Mul(utils.NewDecimalFromInt64(int64(ts.GetCompressFactor()))).Float64()
 	}
 }
 
@@ -461,18 +476,19 @@ func (ts *TimeSpan) createIncrementsSlice() {
 	// because ts cost is rounded
 	//incrementCost := rate / rateUnit.Seconds() * rateIncrement.Seconds()
 	nbIncrements := int(ts.GetDuration() / rateIncrement)
-	incrementCost := ts.CalculateCost() / float64(nbIncrements)
-	incrementCost = utils.Round(incrementCost, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	incrementCost := utils.NewDecimalFromFloat64(ts.CalculateCost()).
+		Div(utils.NewDecimalFromInt64(int64(nbIncrements))).
+		Round(globalRoundingDecimals, utils.ROUNDING_MIDDLE)
 	for s := 0; s < nbIncrements; s++ {
 		inc := &Increment{
 			Duration:    rateIncrement,
-			Cost:        incrementCost,
+			Cost:        incrementCost.Float64(),
 			BalanceInfo: &DebitInfo{},
 		}
 		ts.Increments = append(ts.Increments, inc)
 	}
 	// put the rounded cost back in timespan
-	ts.Cost = incrementCost * float64(nbIncrements)
+	ts.Cost = incrementCost.Mul(utils.NewDecimalFromInt64(int64(nbIncrements))).Float64()
 }
 
 // returns whether the timespan has all increments marked as paid and if not
@@ -506,10 +522,11 @@ func (ts *TimeSpan) SplitByRateInterval(i *RateInterval, data bool) (nts *TimeSp
 	if i.Rating != nil {
 		i.Rating.Rates.Sort()
 		for _, rate := range i.Rating.Rates {
-			if ts.GetGroupStart() < rate.GroupIntervalStart && ts.GetGroupEnd() > rate.GroupIntervalStart {
+			grpStart := ts.groupStart(i)
+			if grpStart < rate.GroupIntervalStart && ts.groupEnd(i) > rate.GroupIntervalStart {
 				//log.Print("Splitting")
 				ts.SetRateInterval(i)
-				splitTime := ts.TimeStart.Add(rate.GroupIntervalStart - ts.GetGroupStart())
+				splitTime := ts.TimeStart.Add(rate.GroupIntervalStart - grpStart)
 				nts = &TimeSpan{
 					TimeStart: splitTime,
 					TimeEnd:   ts.TimeEnd,
@@ -668,15 +685,33 @@ func (ts *TimeSpan) SplitByDay() (newTs *TimeSpan) {
 
 // Returns the starting time of this timespan
 func (ts *TimeSpan) GetGroupStart() time.Duration {
+	return ts.groupStart(ts.RateInterval)
+}
+
+func (ts *TimeSpan) GetGroupEnd() time.Duration {
+	return ts.groupEnd(ts.RateInterval)
+}
+
+// tierUsage returns the usage consumed within the billing period if the interval has tiered rates
+func (ts *TimeSpan) tierUsage(ri *RateInterval) time.Duration {
+	if ri == nil || ri.Rating == nil || ri.Rating.TierPeriod == "" {
+		return 0
+	}
+	return ts.tierUsages[ri.Rating.TierPeriod]
+}
+
+// groupStart returns the start of the timespan within the rate groups of the interval
+func (ts *TimeSpan) groupStart(ri *RateInterval) time.Duration {
 	s := ts.DurationIndex - ts.GetDuration()
 	if s < 0 {
 		s = 0
 	}
-	return s
+	return s + ts.tierUsage(ri)
 }
 
-func (ts *TimeSpan) GetGroupEnd() time.Duration {
-	return ts.DurationIndex
+// groupEnd returns the end of the timespan within the rate groups of the interval
+func (ts *TimeSpan) groupEnd(ri *RateInterval) time.Duration {
+	return ts.DurationIndex + ts.tierUsage(ri)
 }
 
 // sets the DurationIndex attribute to reflect new timespan
@@ -689,6 +724,7 @@ func (ts *TimeSpan) SetNewDurationIndex(nts *TimeSpan) {
 }
 
 func (nts *TimeSpan) copyRatingInfo(ts *TimeSpan) {
+	nts.tierUsages = ts.tierUsages
 	if ts.ratingInfo == nil {
 		return
 	}
@@ -63,6 +63,9 @@ var fileHandlers = map[string]func(*TPCSVImporter, string) error{
 	utils.AttributesCsv:         (*TPCSVImporter).importAttributeProfiles,
 	utils.ChargersCsv:           (*TPCSVImporter).importChargerProfiles,
 	utils.DispatchersCsv:        (*TPCSVImporter).importDispatcherProfiles,
+	utils.ExchangeRatesCsv:      (*TPCSVImporter).importExchangeRates,
+	utils.TaxProfilesCsv:        (*TPCSVImporter).importTaxProfiles,
+	utils.CalendarsCsv:          (*TPCSVImporter).importCalendars,
 }
 
 func (self *TPCSVImporter) Run() error {
@@ -89,6 +92,9 @@ func (self *TPCSVImporter) Run() error {
 		path.Join(self.DirPath, utils.AttributesCsv),
 		path.Join(self.DirPath, utils.ChargersCsv),
 		path.Join(self.DirPath, utils.DispatchersCsv),
+		path.Join(self.DirPath, utils.ExchangeRatesCsv),
+		path.Join(self.DirPath, utils.TaxProfilesCsv),
+		path.Join(self.DirPath, utils.CalendarsCsv),
 	)
 	files, _ := ioutil.ReadDir(self.DirPath)
 	for _, f := range files {
@@ -415,3 +421,36 @@ func (self *TPCSVImporter) importDispatcherProfiles(fn string) error {
 	}
 	return self.StorDb.SetTPDispatchers(dpps)
 }
+
+func (self *TPCSVImporter) importExchangeRates(fn string) error {
+	if self.Verbose {
+		log.Printf("Processing file: <%s> ", fn)
+	}
+	exrs, err := self.csvr.GetTPExchangeRates(self.TPid)
+	if err != nil {
+		return err
+	}
+	return self.StorDb.SetTPExchangeRates(exrs)
+}
+
+func (self *TPCSVImporter) importTaxProfiles(fn string) error {
+	if self.Verbose {
+		log.Printf("Processing file: <%s> ", fn)
+	}
+	txps, err := self.csvr.GetTPTaxProfiles(self.TPid, "", "")
+	if err != nil {
+		return err
+	}
+	return self.StorDb.SetTPTaxProfiles(txps)
+}
+
+func (self *TPCSVImporter) importCalendars(fn string) error {
+	if self.Verbose {
+		log.Printf("Processing file: <%s> ", fn)
+	}
+	cals, err := self.csvr.GetTPCalendars(self.TPid, "", "")
+	if err != nil {
+		return err
+	}
+	return self.StorDb.SetTPCalendars(cals)
+}
@@ -58,6 +58,9 @@ type TpReader struct {
 	attributeProfiles  map[utils.TenantID]*utils.TPAttributeProfile
 	chargerProfiles    map[utils.TenantID]*utils.TPChargerProfile
 	dispatcherProfiles map[utils.TenantID]*utils.TPDispatcherProfile
+	exchangeRates      map[string]*ExchangeRate
+	taxProfiles        map[utils.TenantID]*TaxProfile
+	calendars          map[utils.TenantID]*Calendar
 	resources          []*utils.TenantID // IDs of resources which need creation based on resourceProfiles
 	statQueues         []*utils.TenantID // IDs of statQueues which need creation based on statQueueProfiles
 	thresholds         []*utils.TenantID // IDs of thresholds which need creation based on thresholdProfiles
@@ -140,6 +143,9 @@ func (tpr *TpReader) Init() {
 	tpr.attributeProfiles = make(map[utils.TenantID]*utils.TPAttributeProfile)
 	tpr.chargerProfiles = make(map[utils.TenantID]*utils.TPChargerProfile)
 	tpr.dispatcherProfiles = make(map[utils.TenantID]*utils.TPDispatcherProfile)
+	tpr.exchangeRates = make(map[string]*ExchangeRate)
+	tpr.taxProfiles = make(map[utils.TenantID]*TaxProfile)
+	tpr.calendars = make(map[utils.TenantID]*Calendar)
 	tpr.filters = make(map[utils.TenantID]*utils.TPFilterProfile)
 	tpr.revDests = make(map[string][]string)
 	tpr.revAliases = make(map[string][]string)
@@ -508,6 +514,8 @@ func (tpr *TpReader) LoadActions() (err error) {
 				ExtraParameters:  tpact.ExtraParameters,
 				ExpirationString: tpact.ExpiryTime,
 				Filter:           tpact.Filter,
+				FilterIDs:        tpact.FilterIDs,
+				FailurePolicy:    tpact.FailurePolicy,
 				Balance:          &BalanceFilter{},
 			}
 			if tpact.BalanceId != "" && tpact.BalanceId != utils.ANY {
@@ -744,6 +752,9 @@ func (tpr *TpReader) LoadAccountActionsFiltered(qriedAA *utils.TPAccountActions)
 	}
 	for _, accountAction := range storAas {
 		id := accountAction.KeyId()
+		if !IsDebitPolicy(accountAction.DebitPolicy) {
+			return fmt.Errorf("unsupported debit policy %s for account %s", accountAction.DebitPolicy, id)
+		}
 		var actionIDs []string // collects action ids
 		// action timings
 		if accountAction.ActionPlanId != "" {
@@ -960,6 +971,8 @@ func (tpr *TpReader) LoadAccountActionsFiltered(qriedAA *utils.TPAccountActions)
 						ExtraParameters:  tpact.ExtraParameters,
 						ExpirationString: tpact.ExpiryTime,
 						Filter:           tpact.Filter,
+						FilterIDs:        tpact.FilterIDs,
+						FailurePolicy:    tpact.FailurePolicy,
 						Balance:          &BalanceFilter{},
 					}
 					if tpact.BalanceId != "" && tpact.BalanceId != utils.ANY {
@@ -1050,6 +1063,7 @@ func (tpr *TpReader) LoadAccountActionsFiltered(qriedAA *utils.TPAccountActions)
 			}
 		}
 		ub.ActionTriggers = actionTriggers
+		ub.DebitPolicy = accountAction.DebitPolicy
 		// init counters
 		ub.InitCounters()
 		if err := tpr.dm.DataDB().SetAccount(ub); err != nil {
@@ -1081,11 +1095,15 @@ func (tpr *TpReader) LoadAccountActions() (err error) {
 				return fmt.Errorf("could not get action triggers for tag %s", aa.ActionTriggersId)
 			}
 		}
+		if !IsDebitPolicy(aa.DebitPolicy) {
+			return fmt.Errorf("unsupported debit policy %s for account %s", aa.DebitPolicy, aaKeyID)
+		}
 		ub := &Account{
 			ID:             aaKeyID,
 			ActionTriggers: aTriggers,
 			AllowNegative:  aa.AllowNegative,
 			Disabled:       aa.Disabled,
+			DebitPolicy:    aa.DebitPolicy,
 		}
 		ub.InitCounters()
 		tpr.accountActions[aaKeyID] = ub
@@ -1463,6 +1481,56 @@ func (tpr *TpReader) LoadDispatcherProfiles() error {
 	return tpr.LoadDispatcherProfilesFiltered("")
 }
 
+func (tpr *TpReader) LoadExchangeRates() (err error) {
+	tps, err := tpr.lr.GetTPExchangeRates(tpr.tpid)
+	if err != nil {
+		return err
+	}
+	for _, tp := range tps {
+		exr := APItoExchangeRate(tp)
+		tpr.exchangeRates[exr.ID()] = exr
+	}
+	return nil
+}
+
+func (tpr *TpReader) LoadTaxProfilesFiltered(tag string) (err error) {
+	tps, err := tpr.lr.GetTPTaxProfiles(tpr.tpid, "", tag)
+	if err != nil {
+		return err
+	}
+	for _, tp := range tps {
+		txp, err := APItoTaxProfile(tp, tpr.timezone)
+		if err != nil {
+			return err
+		}
+		tpr.taxProfiles[utils.TenantID{Tenant: txp.Tenant, ID: txp.ID}] = txp
+	}
+	return nil
+}
+
+func (tpr *TpReader) LoadTaxProfiles() error {
+	return tpr.LoadTaxProfilesFiltered("")
+}
+
+func (tpr *TpReader) LoadCalendarsFiltered(tag string) (err error) {
+	tps, err := tpr.lr.GetTPCalendars(tpr.tpid, "", tag)
+	if err != nil {
+		return err
+	}
+	for _, tp := range tps {
+		cal, err := APItoCalendar(tp)
+		if err != nil {
+			return err
+		}
+		tpr.calendars[utils.TenantID{Tenant: cal.Tenant, ID: cal.ID}] = cal
+	}
+	return nil
+}
+
+func (tpr *TpReader) LoadCalendars() error {
+	return tpr.LoadCalendarsFiltered("")
+}
+
 func (tpr *TpReader) LoadAll() (err error) {
 	if err = tpr.LoadDestinations(); err != nil && err.Error() != utils.NotFoundCaps {
 		return
@@ -1530,6 +1598,15 @@ func (tpr *TpReader) LoadAll() (err error) {
 	if err = tpr.LoadDispatcherProfiles(); err != nil && err.Error() != utils.NotFoundCaps {
 		return
 	}
+	if err = tpr.LoadExchangeRates(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
+	if err = tpr.LoadTaxProfiles(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
+	if err = tpr.LoadCalendars(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
 	return nil
 }
 
@@ -1911,6 +1988,42 @@ func (tpr *TpReader) WriteToDatabase(flush, verbose, disable_reverse bool) (err
 		}
 	}
 
+	if verbose {
+		log.Print("ExchangeRates:")
+	}
+	for k, exr := range tpr.exchangeRates {
+		if err = tpr.dm.SetExchangeRate(exr); err != nil {
+			return err
+		}
+		if verbose {
+			log.Print("\t", k)
+		}
+	}
+
+	if verbose {
+		log.Print("TaxProfiles:")
+	}
+	for _, txp := range tpr.taxProfiles {
+		if err = tpr.dm.SetTaxProfile(txp, true); err != nil {
+			return err
+		}
+		if verbose {
+			log.Print("\t", txp.TenantID())
+		}
+	}
+
+	if verbose {
+		log.Print("Calendars:")
+	}
+	for _, cal := range tpr.calendars {
+		if err = tpr.dm.SetCalendar(cal); err != nil {
+			return err
+		}
+		if verbose {
+			log.Print("\t", cal.TenantID())
+		}
+	}
+
 	if verbose {
 		log.Print("Timings:")
 	}
@@ -2020,6 +2133,12 @@ func (tpr *TpReader) ShowStatistics() {
 	log.Print("ChargerProfiles: ", len(tpr.chargerProfiles))
 	// Dispatcher profiles
 	log.Print("DispatcherProfiles: ", len(tpr.dispatcherProfiles))
+	// Exchange rates
+	log.Print("ExchangeRates: ", len(tpr.exchangeRates))
+	// Tax profiles
+	log.Print("TaxProfiles: ", len(tpr.taxProfiles))
+	// Calendars
+	log.Print("Calendars: ", len(tpr.calendars))
 }
 
 // Returns the identities loaded for a specific category, useful for cache reloads
@@ -2193,6 +2312,22 @@ func (tpr *TpReader) GetLoadedIds(categ string) ([]string, error) {
 			i++
 		}
 		return keys, nil
+	case utils.TaxProfilePrefix:
+		keys := make([]string, len(tpr.taxProfiles))
+		i := 0
+		for k := range tpr.taxProfiles {
+			keys[i] = k.TenantID()
+			i++
+		}
+		return keys, nil
+	case utils.CalendarPrefix:
+		keys := make([]string, len(tpr.calendars))
+		i := 0
+		for k := range tpr.calendars {
+			keys[i] = k.TenantID()
+			i++
+		}
+		return keys, nil
 	}
 	return nil, errors.New("Unsupported load category")
 }
@@ -2472,6 +2607,42 @@ func (tpr *TpReader) RemoveFromDatabase(verbose, disable_reverse bool) (err erro
 		}
 	}
 
+	if verbose {
+		log.Print("ExchangeRates:")
+	}
+	for k, exr := range tpr.exchangeRates {
+		if err = tpr.dm.RemoveExchangeRate(exr.FromCurrency, exr.ToCurrency); err != nil {
+			return err
+		}
+		if verbose {
+			log.Print("\t", k)
+		}
+	}
+
+	if verbose {
+		log.Print("TaxProfiles:")
+	}
+	for _, txp := range tpr.taxProfiles {
+		if err = tpr.dm.RemoveTaxProfile(txp.Tenant, txp.ID, utils.NonTransactional, false); err != nil {
+			return err
+		}
+		if verbose {
+			log.Print("\t", txp.TenantID())
+		}
+	}
+
+	if verbose {
+		log.Print("Calendars:")
+	}
+	for _, cal := range tpr.calendars {
+		if err = tpr.dm.RemoveCalendar(cal.Tenant, cal.ID, utils.NonTransactional); err != nil {
+			return err
+		}
+		if verbose {
+			log.Print("\t", cal.TenantID())
+		}
+	}
+
 	if verbose {
 		log.Print("Timings:")
 	}
@@ -35,8 +35,14 @@ var (
 		utils.Thresholds:     "cgr-migrator -migrate=*thresholds",
 	}
 	storDBVers = map[string]string{
-		utils.CostDetails:   "cgr-migrator -migrate=*cost_details",
-		utils.SessionSCosts: "cgr-migrator -migrate=*sessions_costs",
+		utils.CostDetails:        "cgr-migrator -migrate=*cost_details",
+		utils.SessionSCosts:      "cgr-migrator -migrate=*sessions_costs",
+		utils.AccountAudits:      "cgr-migrator -migrate=*account_audits",
+		utils.AccountSnapshots:   "cgr-migrator -migrate=*account_snapshots",
+		utils.TpDestinationRates: "cgr-migrator -migrate=*tp_destination_rates",
+		utils.TpTiming:           "cgr-migrator -migrate=*tp_timing",
+		utils.TpAccountActionsV:  "cgr-migrator -migrate=*tp_account_actions",
+		utils.TpActions:          "cgr-migrator -migrate=*tp_actions",
 	}
 	allVers map[string]string // init will fill this with a merge of data+stor
 )
@@ -165,15 +171,17 @@ func CurrentDataDBVersions() Versions {
 func CurrentStorDBVersions() Versions {
 	return Versions{
 		utils.CostDetails:        2,
-		utils.SessionSCosts:      3,
-		utils.CDRs:               2,
+		utils.SessionSCosts:      4,
+		utils.CDRs:               3,
+		utils.AccountAudits:      1,
+		utils.AccountSnapshots:   1,
 		utils.TpRatingPlans:      1,
 		utils.TpFilters:          1,
-		utils.TpDestinationRates: 1,
+		utils.TpDestinationRates: 3,
 		utils.TpActionTriggers:   1,
-		utils.TpAccountActionsV:  1,
+		utils.TpAccountActionsV:  2,
 		utils.TpActionPlans:      1,
-		utils.TpActions:          1,
+		utils.TpActions:          2,
 		utils.TpDerivedCharges:   1,
 		utils.TpThresholds:       1,
 		utils.TpSuppliers:        1,
@@ -182,7 +190,7 @@ func CurrentStorDBVersions() Versions {
 		utils.TpRatingProfiles:   1,
 		utils.TpResources:        1,
 		utils.TpRates:            1,
-		utils.TpTiming:           1,
+		utils.TpTiming:           2,
 		utils.TpResource:         1,
 		utils.TpAliases:          1,
 		utils.TpUsers:            1,
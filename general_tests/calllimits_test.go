@@ -0,0 +1,202 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package general_tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestCallLimitsSetStorage(t *testing.T) {
+	data, _ := engine.NewMapStorageJson()
+	dataDB = engine.NewDataManager(data)
+	engine.SetDataStorage(dataDB)
+}
+
+func TestCallLimitsLoadCsvTp(t *testing.T) {
+	timings := `ALWAYS,*any,*any,*any,*any,00:00:00,`
+	dests := `DST_LIMITS,+49`
+	rates := `RT_1CNT_LIMITS,0,0.01,1s,1s,0s`
+	destinationRates := `DR_LIMITS,DST_LIMITS,RT_1CNT_LIMITS,*middle,4,0,,,,0.5,5s
+DR_CAP,DST_LIMITS,RT_1CNT_LIMITS,*middle,4,0.3,*free,,,,`
+	ratingPlans := `RP_LIMITS,DR_LIMITS,ALWAYS,10
+RP_CAP,DR_CAP,ALWAYS,10`
+	ratingProfiles := `cgrates.org,call,limits,2012-01-01T00:00:00Z,RP_LIMITS,
+cgrates.org,call,capped,2012-01-01T00:00:00Z,RP_CAP,`
+	csvr := engine.NewTpReader(dataDB.DataDB(), engine.NewStringCSVStorage(',', dests, timings, rates, destinationRates, ratingPlans, ratingProfiles,
+		"", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", ""), "", "")
+	if err := csvr.LoadTimings(); err != nil {
+		t.Fatal(err)
+	}
+	if err := csvr.LoadDestinations(); err != nil {
+		t.Fatal(err)
+	}
+	if err := csvr.LoadRates(); err != nil {
+		t.Fatal(err)
+	}
+	if err := csvr.LoadDestinationRates(); err != nil {
+		t.Fatal(err)
+	}
+	if err := csvr.LoadRatingPlans(); err != nil {
+		t.Fatal(err)
+	}
+	if err := csvr.LoadRatingProfiles(); err != nil {
+		t.Fatal(err)
+	}
+	csvr.WriteToDatabase(false, false, false)
+	engine.Cache.Clear(nil)
+	for _, acntID := range []string{"limits", "capped"} {
+		if err := dataDB.DataDB().SetAccount(&engine.Account{
+			ID: utils.ConcatenatedKey("cgrates.org", acntID),
+			BalanceMap: map[string]engine.Balances{
+				utils.MONETARY: engine.Balances{
+					&engine.Balance{Uuid: acntID, Value: 10, Weight: 10}}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCallLimitsGetCost(t *testing.T) {
+	tStart := time.Date(2018, 8, 24, 16, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		subject string
+		usage   time.Duration
+		cost    float64
+	}{
+		{"limits", 3 * time.Second, 0},    // below FreeUsage
+		{"limits", 20 * time.Second, 0.5}, // MinCost
+		{"limits", time.Minute, 0.6},
+		{"capped", time.Minute, 0.3}, // MaxCost with *free strategy
+	} {
+		cd := &engine.CallDescriptor{
+			Category:    "call",
+			Tenant:      "cgrates.org",
+			Subject:     tc.subject,
+			Account:     tc.subject,
+			Destination: "+4986517174963",
+			TimeStart:   tStart,
+			TimeEnd:     tStart.Add(tc.usage),
+		}
+		cc, err := cd.GetCost()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cc.Cost != tc.cost {
+			t.Errorf("%s for %v, expecting cost: %v, received: %v", tc.subject, tc.usage, tc.cost, cc.Cost)
+		}
+		if ecCost := engine.NewEventCostFromCallCost(cc, "", "").GetCost(); ecCost != tc.cost {
+			t.Errorf("%s for %v, expecting EventCost: %v, received: %v", tc.subject, tc.usage, tc.cost, ecCost)
+		}
+	}
+}
+
+func TestCallLimitsDebit(t *testing.T) {
+	tStart := time.Date(2018, 8, 24, 16, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		subject string
+		usage   time.Duration
+		cost    float64
+		balance float64
+	}{
+		{"limits", 3 * time.Second, 0, 10},
+		{"limits", 20 * time.Second, 0.5, 9.5},
+		{"capped", time.Minute, 0.3, 9.7},
+	} {
+		cd := &engine.CallDescriptor{
+			Category:    "call",
+			Tenant:      "cgrates.org",
+			Subject:     tc.subject,
+			Account:     tc.subject,
+			Destination: "+4986517174963",
+			TimeStart:   tStart,
+			TimeEnd:     tStart.Add(tc.usage),
+		}
+		cc, err := cd.Debit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cc.Cost != tc.cost {
+			t.Errorf("%s for %v, expecting cost: %v, received: %v", tc.subject, tc.usage, tc.cost, cc.Cost)
+		}
+		if ecCost := engine.NewEventCostFromCallCost(cc, "", "").GetCost(); ecCost != tc.cost {
+			t.Errorf("%s for %v, expecting EventCost: %v, received: %v", tc.subject, tc.usage, tc.cost, ecCost)
+		}
+		acnt, err := dataDB.DataDB().GetAccount(utils.ConcatenatedKey("cgrates.org", tc.subject))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blnc := acnt.BalanceMap[utils.MONETARY].GetTotalValue(); blnc != tc.balance {
+			t.Errorf("%s for %v, expecting balance: %v, received: %v", tc.subject, tc.usage, tc.balance, blnc)
+		}
+	}
+}
+
+func TestCallLimitsDeferred(t *testing.T) {
+	tStart := time.Date(2018, 8, 24, 16, 0, 0, 0, time.UTC)
+	cd := &engine.CallDescriptor{
+		Category:        "call",
+		Tenant:          "cgrates.org",
+		Subject:         "limits",
+		Account:         "limits",
+		Destination:     "+4986517174963",
+		TimeStart:       tStart,
+		TimeEnd:         tStart.Add(3 * time.Second),
+		DeferCallLimits: true,
+	}
+	cc, err := cd.Debit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cc.Cost != 0.03 { // below FreeUsage but charged, limits are left to the caller
+		t.Errorf("expecting cost: %v, received: %v", 0.03, cc.Cost)
+	}
+	ec := engine.NewEventCostFromCallCost(cc, "", "")
+	if minCost, freeUsage := ec.CallLimits(); minCost != 0.5 || freeUsage != 5*time.Second {
+		t.Errorf("received MinCost: %v, FreeUsage: %v", minCost, freeUsage)
+	}
+	mcCD := cd.Clone()
+	mcCD.TimeEnd = mcCD.TimeStart
+	mcCD.Increments = engine.Increments{&engine.Increment{Cost: 0.47}}
+	mcCC, err := mcCD.DebitMinCost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mcCC.Cost != 0.47 {
+		t.Errorf("expecting cost: %v, received: %v", 0.47, mcCC.Cost)
+	}
+	ec.Merge(engine.NewEventCostFromCallCost(mcCC, "", ""))
+	if ecCost := ec.GetCost(); ecCost != 0.5 {
+		t.Errorf("expecting EventCost: %v, received: %v", 0.5, ecCost)
+	}
+	acnt, err := dataDB.DataDB().GetAccount(utils.ConcatenatedKey("cgrates.org", "limits"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blnc := utils.Round(acnt.BalanceMap[utils.MONETARY].GetTotalValue(),
+		4, utils.ROUNDING_MIDDLE); blnc != 9 {
+		t.Errorf("expecting balance: %v, received: %v", 9, blnc)
+	}
+	ec.ResetCost()
+	if ecCost := ec.GetCost(); ecCost != 0 {
+		t.Errorf("expecting EventCost: %v, received: %v", 0, ecCost)
+	}
+}
@@ -67,7 +67,7 @@ TOPUP10_AT,TOPUP10_AC1,ASAP,10`
 	csvr := engine.NewTpReader(dataDB2.DataDB(), engine.NewStringCSVStorage(',', destinations, timings,
 		rates, destinationRates, ratingPlans, ratingProfiles, sharedGroups, actions, actionPlans,
 		actionTriggers, accountActions, derivedCharges, users, aliases, resLimits,
-		stats, thresholds, filters, suppliers, aliasProfiles, chargerProfiles, ``), "", "")
+		stats, thresholds, filters, suppliers, aliasProfiles, chargerProfiles, ``, ``, ``, ``), "", "")
 	if err := csvr.LoadDestinations(); err != nil {
 		t.Fatal(err)
 	}
@@ -113,7 +113,7 @@ TOPUP10_AT,TOPUP10_AC1,ASAP,10`
 	engine.Cache.Clear(nil)
 	dataDB2.LoadDataDBCache(nil, nil, nil, nil, nil, nil,
 		nil, nil, nil, nil, nil, nil, nil, nil, nil,
-		nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	if cachedDests := len(engine.Cache.GetItemIDs(utils.CacheDestinations, "")); cachedDests != 0 {
 		t.Error("Wrong number of cached destinations found", cachedDests)
@@ -0,0 +1,130 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func (m *Migrator) migrateCurrentAccountAudits() (err error) {
+	if m.sameStorDB { // no move
+		return
+	}
+	audits, err := m.storDBIn.StorDB().GetAccountAudits(new(engine.AccountAuditFilter))
+	if err != nil {
+		return err
+	}
+	if len(audits) == 0 {
+		return
+	}
+	if m.dryRun != true {
+		if err = m.storDBOut.StorDB().SetAccountAudits(audits); err != nil {
+			return err
+		}
+		m.stats[utils.AccountAudits] += len(audits)
+	}
+	return
+}
+
+func (m *Migrator) migrateAccountAudits() (err error) {
+	var vrs engine.Versions
+	current := engine.CurrentStorDBVersions()
+	vrs, err = m.storDBOut.StorDB().GetVersions("")
+	if err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when querying OutStorDB for versions", err.Error()))
+	} else if len(vrs) == 0 {
+		return utils.NewCGRError(utils.Migrator,
+			utils.MandatoryIEMissingCaps,
+			utils.UndefinedVersion,
+			"version number is not defined for AccountAudits model")
+	}
+	switch vrs[utils.AccountAudits] {
+	case 0:
+		if err := m.migrateV0AccountAudits(); err != nil {
+			return err
+		}
+	case current[utils.AccountAudits]:
+		if err := m.migrateCurrentAccountAudits(); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// v1AccountAuditsTable is the table introduced with the account audits
+var v1AccountAuditsTable = &sqlTable{
+	Name: utils.AccountAuditsTBL,
+	MySQL: []string{`CREATE TABLE account_audits (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  tenant varchar(64) NOT NULL,
+  account varchar(128) NOT NULL,
+  balance_type varchar(24) NOT NULL,
+  balance_id varchar(64) NOT NULL,
+  balance_uuid varchar(64) NOT NULL,
+  value_before DECIMAL(20,4) NOT NULL,
+  value_after DECIMAL(20,4) NOT NULL,
+  cause varchar(64) NOT NULL,
+  actions_id varchar(64) NOT NULL,
+  cgrid varchar(40) NOT NULL,
+  ` + "`timestamp`" + ` TIMESTAMP(6) NULL,
+  PRIMARY KEY (` + "`id`" + `),
+  KEY account_timestamp_idx (tenant, account, ` + "`timestamp`" + `),
+  KEY cgrid_idx (cgrid)
+);`},
+	Postgres: []string{`CREATE TABLE account_audits (
+  id SERIAL PRIMARY KEY,
+  tenant VARCHAR(64) NOT NULL,
+  account VARCHAR(128) NOT NULL,
+  balance_type VARCHAR(24) NOT NULL,
+  balance_id VARCHAR(64) NOT NULL,
+  balance_uuid VARCHAR(64) NOT NULL,
+  value_before NUMERIC(20,4) NOT NULL,
+  value_after NUMERIC(20,4) NOT NULL,
+  cause VARCHAR(64) NOT NULL,
+  actions_id VARCHAR(64) NOT NULL,
+  cgrid VARCHAR(40) NOT NULL,
+  timestamp TIMESTAMP WITH TIME ZONE
+);`,
+		"CREATE INDEX account_timestamp_audits_idx ON account_audits (tenant, account, timestamp);",
+		"CREATE INDEX cgrid_audits_idx ON account_audits (cgrid);"},
+}
+
+// migrateV0AccountAudits creates the account_audits table within the StorDBs predating it
+func (m *Migrator) migrateV0AccountAudits() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBOut.createTable(v1AccountAuditsTable); err != nil {
+		return
+	}
+	vrs := engine.Versions{utils.AccountAudits: engine.CurrentStorDBVersions()[utils.AccountAudits]}
+	if err = m.storDBOut.StorDB().SetVersions(vrs, false); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when updating AccountAudits version into StorDB", err.Error()))
+	}
+	return
+}
@@ -0,0 +1,89 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func (m *Migrator) migrateAccountSnapshots() (err error) {
+	var vrs engine.Versions
+	vrs, err = m.storDBOut.StorDB().GetVersions("")
+	if err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when querying OutStorDB for versions", err.Error()))
+	} else if len(vrs) == 0 {
+		return utils.NewCGRError(utils.Migrator,
+			utils.MandatoryIEMissingCaps,
+			utils.UndefinedVersion,
+			"version number is not defined for AccountSnapshots model")
+	}
+	switch vrs[utils.AccountSnapshots] {
+	case 0:
+		if err := m.migrateV0AccountSnapshots(); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// v1AccountSnapshotsTable is the table introduced with the account snapshots
+var v1AccountSnapshotsTable = &sqlTable{
+	Name: utils.AccountSnapshotsTBL,
+	MySQL: []string{`CREATE TABLE account_snapshots (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  tenant varchar(64) NOT NULL,
+  account varchar(128) NOT NULL,
+  ` + "`timestamp`" + ` TIMESTAMP(6) NULL,
+  balances MEDIUMTEXT NOT NULL,
+  PRIMARY KEY (` + "`id`" + `),
+  KEY account_timestamp_idx (tenant, account, ` + "`timestamp`" + `)
+);`},
+	Postgres: []string{`CREATE TABLE account_snapshots (
+  id SERIAL PRIMARY KEY,
+  tenant VARCHAR(64) NOT NULL,
+  account VARCHAR(128) NOT NULL,
+  timestamp TIMESTAMP WITH TIME ZONE,
+  balances TEXT NOT NULL
+);`,
+		"CREATE INDEX account_timestamp_snapshots_idx ON account_snapshots (tenant, account, timestamp);"},
+}
+
+// migrateV0AccountSnapshots creates the account_snapshots table within the StorDBs predating it
+func (m *Migrator) migrateV0AccountSnapshots() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBOut.createTable(v1AccountSnapshotsTable); err != nil {
+		return
+	}
+	vrs := engine.Versions{utils.AccountSnapshots: engine.CurrentStorDBVersions()[utils.AccountSnapshots]}
+	if err = m.storDBOut.StorDB().SetVersions(vrs, false); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when updating AccountSnapshots version into StorDB", err.Error()))
+	}
+	return
+}
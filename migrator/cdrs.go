@@ -63,6 +63,10 @@ func (m *Migrator) migrateCDRs() (err error) {
 		if err := m.migrateV1CDRs(); err != nil {
 			return err
 		}
+	case 2:
+		if err := m.migrateV2CDRs(); err != nil {
+			return err
+		}
 	case current[utils.CDRs]:
 		if err := m.migrateCurrentCDRs(); err != nil {
 			return err
@@ -72,6 +76,12 @@ func (m *Migrator) migrateCDRs() (err error) {
 }
 
 func (m *Migrator) migrateV1CDRs() (err error) {
+	if m.dryRun != true {
+		// the CDRs are written back with the current model
+		if err = m.storDBOut.addColumns(utils.CDRsTBL, v3CDRsColumns); err != nil {
+			return err
+		}
+	}
 	var v1CDR *v1Cdrs
 	for {
 		v1CDR, err = m.storDBIn.getV1CDR()
@@ -104,6 +114,31 @@ func (m *Migrator) migrateV1CDRs() (err error) {
 	return
 }
 
+// v3CDRsColumns are the columns added with the taxes
+var v3CDRsColumns = []*sqlColumn{
+	{Name: "tax_lines", MySQL: "TEXT", Postgres: "TEXT"},
+}
+
+func (m *Migrator) migrateV2CDRs() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBOut.addColumns(utils.CDRsTBL, v3CDRsColumns); err != nil {
+		return
+	}
+	if err = m.migrateCurrentCDRs(); err != nil {
+		return
+	}
+	vrs := engine.Versions{utils.CDRs: engine.CurrentStorDBVersions()[utils.CDRs]}
+	if err = m.storDBOut.StorDB().SetVersions(vrs, false); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when updating CDRs version into StorDB", err.Error()))
+	}
+	return
+}
+
 type v1Cdrs struct {
 	CGRID       string
 	RunID       string
@@ -172,7 +172,7 @@ func testCdrITMigrateAndMove(t *testing.T) {
 	}
 	if vrs, err := cdrMigrator.storDBOut.StorDB().GetVersions(""); err != nil {
 		t.Error(err)
-	} else if vrs[utils.CDRs] != 2 {
+	} else if vrs[utils.CDRs] != 3 {
 		t.Errorf("Unexpected version returned: %d", vrs[utils.CDRs])
 	}
 }
@@ -90,6 +90,10 @@ func (m *Migrator) Migrate(taskIDs []string) (err error, stats map[string]int) {
 			err = m.migrateCDRs()
 		case utils.MetaSessionsCosts:
 			err = m.migrateSessionSCosts()
+		case utils.MetaAccountAudits:
+			err = m.migrateAccountAudits()
+		case utils.MetaAccountSnapshots:
+			err = m.migrateAccountSnapshots()
 		// case utils.MetaCostDetails:
 		// 	err = m.migrateCostDetails()
 		case utils.MetaAccounts:
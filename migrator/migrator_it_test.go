@@ -924,8 +924,8 @@ func testMigratorSessionsCosts(t *testing.T) {
 		err, _ = mig.Migrate([]string{utils.MetaSessionsCosts})
 		if vrs, err := mig.OutStorDB().GetVersions(utils.SessionSCosts); err != nil {
 			t.Error(err)
-		} else if vrs[utils.SessionSCosts] != 3 {
-			t.Errorf("Expecting: 3, received: %+v", vrs[utils.SessionSCosts])
+		} else if vrs[utils.SessionSCosts] != 4 {
+			t.Errorf("Expecting: 4, received: %+v", vrs[utils.SessionSCosts])
 		}
 	}
 }
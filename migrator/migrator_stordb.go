@@ -30,5 +30,21 @@ type MigratorStorDB interface {
 	getV2SMCost() (v2Cost *v2SessionsCost, err error)
 	setV2SMCost(v2Cost *v2SessionsCost) (err error)
 	remV2SMCost(v2Cost *v2SessionsCost) (err error)
+	addColumns(table string, cols []*sqlColumn) (err error)
+	createTable(tbl *sqlTable) (err error)
 	StorDB() engine.StorDB
 }
+
+// sqlColumn is a column added to an existing StorDB table
+type sqlColumn struct {
+	Name     string
+	MySQL    string // column definition used with MySQL
+	Postgres string // column definition used with PostgreSQL
+}
+
+// sqlTable is a table added to an existing StorDB
+type sqlTable struct {
+	Name     string
+	MySQL    []string // statements creating the table and its indexes with MySQL
+	Postgres []string // statements creating the table and its indexes with PostgreSQL
+}
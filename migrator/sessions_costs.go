@@ -70,6 +70,10 @@ func (m *Migrator) migrateSessionSCosts() (err error) {
 		if err := m.migrateV2SessionSCosts(); err != nil {
 			return err
 		}
+	case 3:
+		if err := m.migrateV3SessionSCosts(); err != nil {
+			return err
+		}
 	case current[utils.SessionSCosts]:
 		if err := m.migrateCurrentSessionSCost(); err != nil {
 			return err
@@ -95,6 +99,12 @@ func (m *Migrator) migrateV1SessionSCosts() (err error) {
 }
 
 func (m *Migrator) migrateV2SessionSCosts() (err error) {
+	if m.dryRun != true {
+		// the costs are written back with the current model
+		if err = m.storDBOut.addColumns(utils.SessionsCostsTBL, v4SessionSCostsColumns); err != nil {
+			return err
+		}
+	}
 	var v2Cost *v2SessionsCost
 	for {
 		v2Cost, err = m.storDBIn.getV2SMCost()
@@ -130,6 +140,31 @@ func (m *Migrator) migrateV2SessionSCosts() (err error) {
 	return
 }
 
+// v4SessionSCostsColumns are the columns added with the session history
+var v4SessionSCostsColumns = []*sqlColumn{
+	{Name: "history", MySQL: "MEDIUMTEXT", Postgres: "TEXT"},
+}
+
+func (m *Migrator) migrateV3SessionSCosts() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBOut.addColumns(utils.SessionsCostsTBL, v4SessionSCostsColumns); err != nil {
+		return
+	}
+	if err = m.migrateCurrentSessionSCost(); err != nil {
+		return
+	}
+	vrs := engine.Versions{utils.SessionSCosts: engine.CurrentStorDBVersions()[utils.SessionSCosts]}
+	if err = m.storDBOut.StorDB().SetVersions(vrs, false); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when updating SessionSCosts version into StorDB", err.Error()))
+	}
+	return
+}
+
 type v2SessionsCost struct {
 	CGRID       string
 	RunID       string
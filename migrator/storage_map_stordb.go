@@ -76,3 +76,13 @@ func (mpMig *mapStorDBMigrator) setV2SMCost(v2Cost *v2SessionsCost) (err error)
 func (mpMig *mapStorDBMigrator) remV2SMCost(v2Cost *v2SessionsCost) (err error) {
 	return utils.ErrNotImplemented
 }
+
+// addColumns has nothing to do since documents are schemaless
+func (mpMig *mapStorDBMigrator) addColumns(table string, cols []*sqlColumn) (err error) {
+	return
+}
+
+// createTable has nothing to do since the collections are created on first write
+func (mpMig *mapStorDBMigrator) createTable(tbl *sqlTable) (err error) {
+	return
+}
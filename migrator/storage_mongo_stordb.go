@@ -122,3 +122,13 @@ func (v1ms *mongoStorDBMigrator) remV2SMCost(v2Cost *v2SessionsCost) (err error)
 	_, err = v1ms.mgoDB.DB().Collection(utils.SessionsCostsTBL).DeleteMany(v1ms.mgoDB.GetContext(), bson.D{})
 	return
 }
+
+// addColumns has nothing to do since documents are schemaless
+func (v1ms *mongoStorDBMigrator) addColumns(table string, cols []*sqlColumn) (err error) {
+	return
+}
+
+// createTable has nothing to do since the collections are created on first write and their indexes on connect
+func (v1ms *mongoStorDBMigrator) createTable(tbl *sqlTable) (err error) {
+	return
+}
@@ -164,3 +164,38 @@ func (mgSQL *migratorSQL) remV2SMCost(v2Cost *v2SessionsCost) (err error) {
 	return nil
 
 }
+
+// addColumns alters table, adding the columns introduced by newer versions
+func (mgSQL *migratorSQL) addColumns(table string, cols []*sqlColumn) (err error) {
+	gormDB := mgSQL.sqlStorage.ExportGormDB()
+	for _, col := range cols {
+		if gormDB.Dialect().HasColumn(table, col.Name) { // already migrated
+			continue
+		}
+		qry := fmt.Sprintf("ALTER TABLE %s ADD COLUMN `%s` %s;", table, col.Name, col.MySQL)
+		if mgSQL.StorDB().GetStorageType() == utils.POSTGRES {
+			qry = fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, col.Name, col.Postgres)
+		}
+		if _, err = mgSQL.sqlStorage.Db.Exec(qry); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// createTable creates the table introduced by newer versions, together with its indexes
+func (mgSQL *migratorSQL) createTable(tbl *sqlTable) (err error) {
+	if mgSQL.sqlStorage.ExportGormDB().HasTable(tbl.Name) { // already migrated
+		return
+	}
+	qrys := tbl.MySQL
+	if mgSQL.StorDB().GetStorageType() == utils.POSTGRES {
+		qrys = tbl.Postgres
+	}
+	for _, qry := range qrys {
+		if _, err = mgSQL.sqlStorage.Db.Exec(qry); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -77,6 +77,37 @@ func (m *Migrator) migrateTPaccountacction() (err error) {
 			return err
 		}
 		return
+	case 1:
+		if err := m.migrateV1TPaccountActions(); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// v2TPaccountActionsColumns are the columns added with the DebitPolicy
+var v2TPaccountActionsColumns = []*sqlColumn{
+	{Name: "debit_policy", MySQL: "varchar(24) NOT NULL DEFAULT ''", Postgres: "VARCHAR(24) NOT NULL DEFAULT ''"},
+}
+
+func (m *Migrator) migrateV1TPaccountActions() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBOut.addColumns(utils.TBLTPAccountActions, v2TPaccountActionsColumns); err != nil {
+		return
+	}
+	if !m.sameStorDB {
+		if err = m.migrateCurrentTPaccountAcction(); err != nil {
+			return
+		}
+	}
+	vrs := engine.Versions{utils.TpAccountActionsV: engine.CurrentStorDBVersions()[utils.TpAccountActionsV]}
+	if err = m.storDBOut.StorDB().SetVersions(vrs, false); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when updating TpAccountActions version into StorDB", err.Error()))
 	}
 	return
 }
@@ -82,6 +82,38 @@ func (m *Migrator) migrateTPactions() (err error) {
 			return err
 		}
 		return
+	case 1:
+		if err := m.migrateV1TPactions(); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// v2TPactionsColumns are the columns added with the FilterIDs and FailurePolicy
+var v2TPactionsColumns = []*sqlColumn{
+	{Name: "filter_ids", MySQL: "varchar(64) NOT NULL DEFAULT ''", Postgres: "VARCHAR(64) NOT NULL DEFAULT ''"},
+	{Name: "failure_policy", MySQL: "varchar(24) NOT NULL DEFAULT ''", Postgres: "VARCHAR(24) NOT NULL DEFAULT ''"},
+}
+
+func (m *Migrator) migrateV1TPactions() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBOut.addColumns(utils.TBLTPActions, v2TPactionsColumns); err != nil {
+		return
+	}
+	if !m.sameStorDB {
+		if err = m.migrateCurrentTPactions(); err != nil {
+			return
+		}
+	}
+	vrs := engine.Versions{utils.TpActions: engine.CurrentStorDBVersions()[utils.TpActions]}
+	if err = m.storDBOut.StorDB().SetVersions(vrs, false); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when updating TpActions version into StorDB", err.Error()))
 	}
 	return
 }
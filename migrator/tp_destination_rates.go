@@ -82,6 +82,58 @@ func (m *Migrator) migrateTPdestinationrates() (err error) {
 			return err
 		}
 		return
+	case 1:
+		if err := m.migrateV1TPdestinationrates(); err != nil {
+			return err
+		}
+	case 2:
+		if err := m.migrateV2TPdestinationrates(); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// v2TPDestinationRatesColumns are the columns added with TierPeriod and Currency
+var v2TPDestinationRatesColumns = []*sqlColumn{
+	{Name: "tier_period", MySQL: "varchar(16) NOT NULL DEFAULT ''", Postgres: "VARCHAR(16) NOT NULL DEFAULT ''"},
+	{Name: "currency", MySQL: "varchar(3) NOT NULL DEFAULT ''", Postgres: "VARCHAR(3) NOT NULL DEFAULT ''"},
+}
+
+// v3TPDestinationRatesColumns are the columns added with MinCost and FreeUsage
+var v3TPDestinationRatesColumns = []*sqlColumn{
+	{Name: "min_cost", MySQL: "decimal(7,4) NOT NULL DEFAULT 0", Postgres: "NUMERIC(7,4) NOT NULL DEFAULT 0"},
+	{Name: "free_usage", MySQL: "varchar(32) NOT NULL DEFAULT ''", Postgres: "VARCHAR(32) NOT NULL DEFAULT ''"},
+}
+
+func (m *Migrator) migrateV1TPdestinationrates() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBOut.addColumns(utils.TBLTPDestinationRates, v2TPDestinationRatesColumns); err != nil {
+		return
+	}
+	return m.migrateV2TPdestinationrates()
+}
+
+func (m *Migrator) migrateV2TPdestinationrates() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBOut.addColumns(utils.TBLTPDestinationRates, v3TPDestinationRatesColumns); err != nil {
+		return
+	}
+	if !m.sameStorDB {
+		if err = m.migrateCurrentTPdestinationrates(); err != nil {
+			return
+		}
+	}
+	vrs := engine.Versions{utils.TpDestinationRates: engine.CurrentStorDBVersions()[utils.TpDestinationRates]}
+	if err = m.storDBOut.StorDB().SetVersions(vrs, false); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when updating TpDestinationRates version into StorDB", err.Error()))
 	}
 	return
 }
@@ -85,6 +85,37 @@ func (m *Migrator) migrateTpTimings() (err error) {
 			return err
 		}
 		return
+	case 1:
+		if err := m.migrateV1TpTimings(); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// v2TpTimingsColumns are the columns added with the Holidays filter
+var v2TpTimingsColumns = []*sqlColumn{
+	{Name: "holidays", MySQL: "varchar(64) NOT NULL DEFAULT ''", Postgres: "VARCHAR(64) NOT NULL DEFAULT ''"},
+}
+
+func (m *Migrator) migrateV1TpTimings() (err error) {
+	if m.dryRun {
+		return
+	}
+	if err = m.storDBOut.addColumns(utils.TBLTPTimings, v2TpTimingsColumns); err != nil {
+		return
+	}
+	if !m.sameStorDB {
+		if err = m.migrateCurrentTPTiming(); err != nil {
+			return
+		}
+	}
+	vrs := engine.Versions{utils.TpTiming: engine.CurrentStorDBVersions()[utils.TpTiming]}
+	if err = m.storDBOut.StorDB().SetVersions(vrs, false); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when updating TpTiming version into StorDB", err.Error()))
 	}
 	return
 }
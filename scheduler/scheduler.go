@@ -25,6 +25,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
 )
@@ -40,17 +41,188 @@ type Scheduler struct {
 	actSucessChan, actFailedChan    chan *engine.Action           // ActionPlan will pass actions via these channels
 	aSMux, aFMux                    sync.RWMutex                  // protect schedStats
 	actSuccessStats, actFailedStats map[string]map[time.Time]bool // keep here stats regarding executed actions, map[actionType]map[execTime]bool
+	nodeID                          string                        // holder ID within the scheduler lease
+	leaseTTL                        time.Duration                 // when not 0 only the node holding the lease executes the actions
+	leader                          bool                          // holding the lease
+	leaseExpiry                     time.Time                     // the lease is held at most until, as seen by this node
+	recovered                       bool                          // missed executions recovered within the current leadership term
+	leaseMux                        sync.RWMutex                  // protect leader, leaseExpiry and recovered
+	stopLease                       chan struct{}
+	catchupPolicy                   string                        // executions missed while down: <*skip|*run_once|*run_all>
+	historySize                     int                           // maximum number of executions kept in history
+	execOpts                        engine.ExecuteOpts            // workers and spread of the account executions
+	checkpointInterval              int                           // processed accounts between checkpoints, 0 to disable
+	running                         map[string]*ExecutionProgress // executions in progress, indexed on ActionTiming execution key
+	recovering                      map[string]bool               // ActionTimings with recoveries in progress, indexed on execution key
+	rMux                            sync.RWMutex                  // protect running and recovering
+	rolloverInterval                time.Duration                 // interval to roll over the expired balances, 0 to disable
+	stopRollover                    chan struct{}
+}
+
+const (
+	// schedulerLeaseID identifies the lease the schedulers sharing a DataDB compete for
+	schedulerLeaseID = "scheduler"
+	// catchUpLimit is the maximum number of missed start times considered at once for an ActionTiming
+	catchUpLimit = 1000
+	// historyPageSize is the number of execution records read at once out of DataDB
+	historyPageSize = 1000
+)
+
+// ExecutionProgress reports on an ActionTiming being executed
+type ExecutionProgress struct {
+	ActionPlanID  string
+	ActionsID     string
+	ScheduledTime time.Time
+	StartTime     time.Time
+	Total         int // accounts to process
+	Processed     int // accounts processed, in the order of their IDs
 }
 
 func NewScheduler(dm *engine.DataManager) *Scheduler {
 	s := &Scheduler{
-		restartLoop: make(chan bool),
-		dm:          dm,
+		restartLoop:   make(chan bool),
+		dm:            dm,
+		nodeID:        config.CgrConfig().GeneralCfg().NodeID,
+		leaseTTL:      config.CgrConfig().SchedulerCfg().LeaseTTL,
+		catchupPolicy: config.CgrConfig().SchedulerCfg().CatchupPolicy,
+		historySize:   config.CgrConfig().SchedulerCfg().ExecutionHistorySize,
+		execOpts: engine.ExecuteOpts{
+			Workers: config.CgrConfig().SchedulerCfg().ExecutionWorkers,
+			Spread:  config.CgrConfig().SchedulerCfg().ExecutionSpread,
+		},
+		checkpointInterval: config.CgrConfig().SchedulerCfg().CheckpointInterval,
+		running:            make(map[string]*ExecutionProgress),
+		recovering:         make(map[string]bool),
+		rolloverInterval:   config.CgrConfig().SchedulerCfg().RolloverInterval,
+	}
+	if s.leaseTTL != 0 {
+		if s.nodeID == "" {
+			s.nodeID = utils.GenUUID()
+		}
+		s.stopLease = make(chan struct{})
+		s.renewLease()
+		go s.leaseLoop()
 	}
 	s.Reload()
+	if s.rolloverInterval != 0 {
+		s.stopRollover = make(chan struct{})
+		go s.rolloverLoop()
+	}
 	return s
 }
 
+// isLeader checks if this node is allowed to execute the actions
+func (s *Scheduler) isLeader() bool {
+	if s.leaseTTL == 0 {
+		return true
+	}
+	s.leaseMux.RLock()
+	defer s.leaseMux.RUnlock()
+	return s.leader
+}
+
+// holdsLease returns utils.ErrLeaseLost once the lease could be taken over by another node,
+// checked before each action so an execution outliving the lease is not repeated by the new leader
+func (s *Scheduler) holdsLease() error {
+	if s.leaseTTL == 0 {
+		return nil
+	}
+	s.leaseMux.RLock()
+	defer s.leaseMux.RUnlock()
+	if !s.leader || !time.Now().Before(s.leaseExpiry) {
+		return utils.ErrLeaseLost
+	}
+	return nil
+}
+
+// renewLease acquires or renews the scheduler lease, returns true if the leadership was just taken over
+func (s *Scheduler) renewLease() (takenOver bool) {
+	reqTime := time.Now() // the lease TTL counts from before the request reached DataDB
+	acquired, err := s.dm.AcquireLease(schedulerLeaseID, s.nodeID, s.leaseTTL)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot acquire lease: %v", err))
+		acquired = false // step down, the lease could expire meanwhile
+	}
+	s.leaseMux.Lock()
+	takenOver = acquired && !s.leader
+	lost := !acquired && s.leader
+	s.leader = acquired
+	if acquired {
+		s.leaseExpiry = reqTime.Add(s.leaseTTL)
+	}
+	if takenOver || lost { // new leadership term
+		s.recovered = false
+	}
+	s.leaseMux.Unlock()
+	if takenOver {
+		utils.Logger.Info(fmt.Sprintf("<Scheduler> Node %s is leading, executing the actions", s.nodeID))
+	} else if lost {
+		utils.Logger.Warning(fmt.Sprintf("<Scheduler> Node %s lost the lead, standing by", s.nodeID))
+	}
+	return
+}
+
+// startRecovery returns true once per leadership term, when the missed executions should be recovered
+func (s *Scheduler) startRecovery() bool {
+	s.leaseMux.Lock()
+	defer s.leaseMux.Unlock()
+	if s.recovered || (s.leaseTTL != 0 && !s.leader) {
+		return false
+	}
+	s.recovered = true
+	return true
+}
+
+// leaseLoop renews the lease often enough for the leader to keep it
+// while the standby nodes take over within the lease TTL
+func (s *Scheduler) leaseLoop() {
+	tkr := time.NewTicker(s.leaseTTL / 3)
+	defer tkr.Stop()
+	for {
+		select {
+		case <-s.stopLease:
+			return
+		case <-tkr.C:
+			if s.renewLease() {
+				s.Reload() // the queue could have changed while standing by
+			} else if s.isLeader() {
+				s.executeTasks() // tasks pushed through other nodes
+			}
+		}
+	}
+}
+
+// rolloverLoop rolls over the expired balances of the accounts at each interval
+func (s *Scheduler) rolloverLoop() {
+	tkr := time.NewTicker(s.rolloverInterval)
+	defer tkr.Stop()
+	for {
+		select {
+		case <-s.stopRollover:
+			return
+		case <-tkr.C:
+			if s.isLeader() {
+				s.rolloverBalances()
+			}
+		}
+	}
+}
+
+// rolloverBalances moves the unused value of the expired balances into new ones for all the accounts
+func (s *Scheduler) rolloverBalances() {
+	acntKeys, err := s.dm.DataDB().GetKeysForPrefix(utils.ACCOUNT_PREFIX)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot get accounts to roll over: %v", err))
+		return
+	}
+	for _, acntKey := range acntKeys {
+		acntID := strings.TrimPrefix(acntKey, utils.ACCOUNT_PREFIX)
+		if err := engine.RolloverAccountBalances(acntID); err != nil && err != utils.ErrNotFound {
+			utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot roll over balances of account %s: %v", acntID, err))
+		}
+	}
+}
+
 func (s *Scheduler) updateActStats(act *engine.Action, isFailed bool) {
 	mux := s.aSMux
 	statsMp := s.actSuccessStats
@@ -96,7 +268,9 @@ func (s *Scheduler) Loop() {
 		now := time.Now()
 		start := a0.GetNextStartTime(now)
 		if start.Equal(now) || start.Before(now) {
-			go a0.Execute(s.actSucessChan, s.actFailedChan)
+			if s.isLeader() {
+				go s.execute(a0, start, "", false)
+			}
 			// if after execute the next start time is in the past then
 			// do not add it to the queue
 			a0.ResetStartTimeCache()
@@ -131,17 +305,21 @@ func (s *Scheduler) Reload() {
 	s.restart()
 }
 
-func (s *Scheduler) loadActionPlans() {
-	s.Lock()
-	defer s.Unlock()
+// executeTasks executes the tasks pending in DataDB
+func (s *Scheduler) executeTasks() {
 	// limit the number of concurrent tasks
 	limit := make(chan bool, 10)
-	// execute existing tasks
 	for {
 		task, err := s.dm.DataDB().PopTask()
 		if err != nil || task == nil {
 			break
 		}
+		if task.ActionPlanID != "" {
+			if err := s.queueActionPlan(task.ActionPlanID); err != nil {
+				utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot queue action plan %s: %v", task.ActionPlanID, err))
+			}
+			continue
+		}
 		limit <- true
 		go func() {
 			utils.Logger.Info(fmt.Sprintf("<Scheduler> executing task %s on account %s", task.ActionsID, task.AccountID))
@@ -149,6 +327,14 @@ func (s *Scheduler) loadActionPlans() {
 			<-limit
 		}()
 	}
+}
+
+func (s *Scheduler) loadActionPlans() {
+	if s.isLeader() { // standby nodes leave the tasks for the leader
+		s.executeTasks() // not locked, the actions executed could queue ActionPlans
+	}
+	s.Lock()
+	defer s.Unlock()
 
 	actionPlans, err := s.dm.DataDB().GetAllActionPlans()
 	if err != nil && err != utils.ErrNotFound {
@@ -157,6 +343,7 @@ func (s *Scheduler) loadActionPlans() {
 	utils.Logger.Info(fmt.Sprintf("<Scheduler> processing %d action plans", len(actionPlans)))
 	// recreate the queue
 	s.queue = engine.ActionTimingPriorityList{}
+	recoverExecs := s.startRecovery() // not on each reload, the replays would be repeated
 	for _, actionPlan := range actionPlans {
 		if actionPlan == nil {
 			continue
@@ -170,12 +357,15 @@ func (s *Scheduler) loadActionPlans() {
 				continue
 			}
 			now := time.Now()
+			at.SetAccountIDs(actionPlan.AccountIDs) // copy the accounts
+			at.SetActionPlanID(actionPlan.Id)
+			if recoverExecs {
+				s.recoverExecutions(at, now)
+			}
 			if at.GetNextStartTime(now).Before(now) {
 				// the task is obsolete, do not add it to the queue
 				continue
 			}
-			at.SetAccountIDs(actionPlan.AccountIDs) // copy the accounts
-			at.SetActionPlanID(actionPlan.Id)
 			s.queue = append(s.queue, at)
 
 		}
@@ -184,6 +374,294 @@ func (s *Scheduler) loadActionPlans() {
 	utils.Logger.Info(fmt.Sprintf("<Scheduler> queued %d action plans", len(s.queue)))
 }
 
+// QueueActionPlan adds the ActionTimings of the ActionPlan to the queue, sparing
+// the reload of all the ActionPlans for the ones created one by one (eg: by actions)
+// standby nodes leave it as task in DataDB for the leader to queue
+func (s *Scheduler) QueueActionPlan(apID string) (err error) {
+	if !s.isLeader() {
+		return s.dm.DataDB().PushTask(&engine.Task{Uuid: utils.GenUUID(), ActionPlanID: apID})
+	}
+	return s.queueActionPlan(apID)
+}
+
+// queueActionPlan adds the ActionTimings of the ActionPlan to the queue of this node
+func (s *Scheduler) queueActionPlan(apID string) (err error) {
+	ap, err := s.dm.DataDB().GetActionPlan(apID, true, utils.NonTransactional)
+	if err != nil {
+		return
+	}
+	s.Lock()
+	now := time.Now()
+	for _, at := range ap.ActionTimings {
+		if at.Timing == nil || at.IsASAP() {
+			continue
+		}
+		at.SetAccountIDs(ap.AccountIDs)
+		at.SetActionPlanID(ap.Id)
+		if at.GetNextStartTime(now).Before(now) {
+			continue
+		}
+		s.queue = append(s.queue, at)
+	}
+	sort.Sort(s.queue)
+	s.Unlock()
+	s.restart()
+	return
+}
+
+// execute runs the ActionTiming due at schedTime, storing it as last execution before
+// so a failing node does not lead to executing it twice
+// resumeAfter is the last account processed by an interrupted execution, empty for a new one
+func (s *Scheduler) execute(at *engine.ActionTiming, schedTime time.Time, resumeAfter string, catchUp bool) {
+	execKey := at.ExecutionKey()
+	execTime := time.Now()
+	prg := &ExecutionProgress{
+		ActionPlanID:  at.GetActionPlanID(),
+		ActionsID:     at.ActionsID,
+		ScheduledTime: schedTime,
+		StartTime:     execTime,
+		Total:         len(at.GetAccountIDs()),
+	}
+	s.rMux.Lock()
+	if _, has := s.running[execKey]; has {
+		s.rMux.Unlock()
+		utils.Logger.Warning(fmt.Sprintf("<Scheduler> Previous execution of %s still running, skipping the one at %v",
+			at.ActionsID, schedTime))
+		return
+	}
+	s.running[execKey] = prg
+	s.rMux.Unlock()
+	defer func() {
+		s.rMux.Lock()
+		delete(s.running, execKey)
+		s.rMux.Unlock()
+	}()
+	if resumeAfter == "" {
+		if err := s.dm.SetLastExecution(execKey, schedTime); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot store last execution of %s: %v", at.ActionsID, err))
+		}
+	}
+	opts := s.execOpts
+	opts.After = resumeAfter
+	var lastChk int
+	opts.OnProgress = func(processed int, lastAccID string) {
+		s.rMux.Lock()
+		prg.Processed = processed
+		s.rMux.Unlock()
+		if lastChk == 0 { // first progress, count from the accounts processed before resuming
+			lastChk = processed - 1
+		}
+		if s.checkpointInterval <= 0 || processed-lastChk < s.checkpointInterval ||
+			processed == prg.Total {
+			return
+		}
+		lastChk = processed
+		if err := s.dm.SetExecutionCheckpoint(execKey,
+			&engine.ExecutionCheckpoint{ScheduledTime: schedTime,
+				LastAccountID: lastAccID, Processed: processed}); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot store checkpoint of %s: %v", at.ActionsID, err))
+			return
+		}
+		utils.Logger.Info(fmt.Sprintf("<Scheduler> Execution of %s at %v processed %d out of %d accounts",
+			at.ActionsID, schedTime, processed, prg.Total))
+	}
+	opts.Proceed = s.holdsLease
+	accErrs, err := at.ExecuteWithOpts(s.actSucessChan, s.actFailedChan, &opts)
+	if err == utils.ErrLeaseLost { // the new leader resumes from the last checkpoint
+		utils.Logger.Warning(fmt.Sprintf("<Scheduler> Lost the lease while executing %s at %v, aborted",
+			at.ActionsID, schedTime))
+	} else if s.checkpointInterval > 0 {
+		if err := s.dm.RemoveExecutionCheckpoint(execKey); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot remove checkpoint of %s: %v", at.ActionsID, err))
+		}
+	}
+	if s.historySize <= 0 {
+		return
+	}
+	recs := make([]*engine.ExecutionRecord, 0, len(accErrs))
+	for accID, err := range accErrs {
+		if err == utils.ErrLeaseLost { // left unchanged for the new leader
+			continue
+		}
+		rec := &engine.ExecutionRecord{
+			ActionPlanID:     at.GetActionPlanID(),
+			ActionTimingUUID: at.Uuid,
+			ActionsID:        at.ActionsID,
+			AccountID:        accID,
+			ScheduledTime:    schedTime,
+			ExecutionTime:    execTime,
+			CatchUp:          catchUp,
+		}
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		recs = append(recs, rec)
+	}
+	if err := s.dm.AddExecutionRecords(recs, s.historySize); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot store execution history of %s: %v", at.ActionsID, err))
+	}
+}
+
+// GetExecutionProgress returns the executions in progress
+func (s *Scheduler) GetExecutionProgress() (prgs []*ExecutionProgress) {
+	s.rMux.RLock()
+	for _, prg := range s.running {
+		prgCpy := *prg
+		prgs = append(prgs, &prgCpy)
+	}
+	s.rMux.RUnlock()
+	sort.Slice(prgs, func(i, j int) bool { return prgs[i].StartTime.Before(prgs[j].StartTime) })
+	return
+}
+
+// recoverExecutions resumes the interrupted execution of the ActionTiming and catches up on the missed ones
+func (s *Scheduler) recoverExecutions(at *engine.ActionTiming, now time.Time) {
+	chk := s.checkpoint(at)
+	var sts []time.Time
+	if s.catchupPolicy != utils.MetaSkip {
+		sts = s.missedStartTimes(at, now)
+	}
+	if chk == nil && len(sts) == 0 {
+		return
+	}
+	execKey := at.ExecutionKey()
+	s.rMux.Lock()
+	if s.recovering[execKey] { // still replaying since a previous leadership term
+		s.rMux.Unlock()
+		return
+	}
+	s.recovering[execKey] = true
+	s.rMux.Unlock()
+	go func() {
+		defer func() {
+			s.rMux.Lock()
+			delete(s.recovering, execKey)
+			s.rMux.Unlock()
+		}()
+		if chk != nil {
+			utils.Logger.Info(fmt.Sprintf("<Scheduler> Resuming execution of %s at %v after account %s (%d processed)",
+				at.ActionsID, chk.ScheduledTime, chk.LastAccountID, chk.Processed))
+			s.execute(at, chk.ScheduledTime, chk.LastAccountID, false)
+		}
+		for _, st := range sts {
+			s.execute(at, st, "", true) // stores st as last execution before replaying it
+		}
+	}()
+}
+
+// checkpoint returns the checkpoint of the interrupted execution of the ActionTiming, nil if none
+func (s *Scheduler) checkpoint(at *engine.ActionTiming) (chk *engine.ExecutionCheckpoint) {
+	execKey := at.ExecutionKey()
+	s.rMux.RLock()
+	_, running := s.running[execKey]
+	s.rMux.RUnlock()
+	if running {
+		return
+	}
+	chk, err := s.dm.GetExecutionCheckpoint(execKey)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot get checkpoint of %s: %v", at.ActionsID, err))
+		}
+		return nil
+	}
+	return
+}
+
+// missedStartTimes returns the start times of the ActionTiming to catch up on based on the catchup policy
+func (s *Scheduler) missedStartTimes(at *engine.ActionTiming, now time.Time) (sts []time.Time) {
+	lastExec, err := s.dm.GetLastExecution(at.ExecutionKey())
+	if err != nil {
+		if err != utils.ErrNotFound {
+			utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot get last execution of %s: %v", at.ActionsID, err))
+		}
+		return
+	}
+	if sts = at.MissedStartTimes(lastExec, now, catchUpLimit); len(sts) == 0 {
+		return
+	}
+	if s.catchupPolicy == utils.MetaRunOnce {
+		for len(sts) == catchUpLimit { // only the latest one is needed
+			nextSts := at.MissedStartTimes(sts[len(sts)-1], now, catchUpLimit)
+			if len(nextSts) == 0 {
+				break
+			}
+			sts = nextSts
+		}
+		sts = sts[len(sts)-1:]
+	} else if len(sts) == catchUpLimit {
+		utils.Logger.Warning(fmt.Sprintf("<Scheduler> Catching up on the first %d missed executions of %s",
+			catchUpLimit, at.ActionsID))
+	}
+	utils.Logger.Info(fmt.Sprintf("<Scheduler> Catching up on %d missed executions of %s", len(sts), at.ActionsID))
+	return
+}
+
+// ArgsGetExecutionHistory filters the executions returned by GetExecutionHistory
+type ArgsGetExecutionHistory struct {
+	Tenant, Account    *string
+	ActionPlanID       *string
+	TimeStart, TimeEnd *time.Time // Filter based on execution time
+	utils.Paginator
+}
+
+// GetExecutionHistory returns the executions journaled in DataDB, newest first
+// the history is read in pages, up to the records within the paginator
+func (s *Scheduler) GetExecutionHistory(fltr ArgsGetExecutionHistory) (recs []*engine.ExecutionRecord, err error) {
+	var offset int // matching records to skip
+	if fltr.Paginator.Offset != nil {
+		offset = *fltr.Paginator.Offset
+	}
+	for page := 0; ; page++ {
+		var hist []*engine.ExecutionRecord
+		if hist, err = s.dm.GetExecutionHistory(page*historyPageSize, historyPageSize); err != nil {
+			return nil, err
+		}
+		for _, rec := range hist {
+			if !fltr.matches(rec) {
+				continue
+			}
+			if offset > 0 {
+				offset--
+				continue
+			}
+			recs = append(recs, rec)
+			if fltr.Paginator.Limit != nil && len(recs) == *fltr.Paginator.Limit {
+				return
+			}
+		}
+		if len(hist) < historyPageSize {
+			return
+		}
+	}
+}
+
+// matches checks the execution record against the filters
+func (fltr *ArgsGetExecutionHistory) matches(rec *engine.ExecutionRecord) bool {
+	if fltr.ActionPlanID != nil && *fltr.ActionPlanID != rec.ActionPlanID {
+		return false
+	}
+	if fltr.TimeStart != nil && !fltr.TimeStart.IsZero() && rec.ExecutionTime.Before(*fltr.TimeStart) {
+		return false
+	}
+	if fltr.TimeEnd != nil && !fltr.TimeEnd.IsZero() && !rec.ExecutionTime.Before(*fltr.TimeEnd) {
+		return false
+	}
+	if fltr.Tenant != nil || fltr.Account != nil {
+		split := strings.Split(rec.AccountID, utils.CONCATENATED_KEY_SEP)
+		if len(split) != 2 {
+			return false // accountless or malformed account id
+		}
+		if fltr.Tenant != nil && *fltr.Tenant != split[0] {
+			return false
+		}
+		if fltr.Account != nil && *fltr.Account != split[1] {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *Scheduler) restart() {
 	if s.schedulerStarted {
 		s.restartLoop <- true
@@ -254,6 +732,17 @@ func (s *Scheduler) GetScheduledActions(fltr ArgsGetScheduledActions) (schedActi
 }
 
 func (s *Scheduler) Shutdown() {
+	if s.stopLease != nil {
+		close(s.stopLease)
+		if s.isLeader() { // let the standby nodes take over right away
+			if err := s.dm.ReleaseLease(schedulerLeaseID, s.nodeID); err != nil {
+				utils.Logger.Warning(fmt.Sprintf("<Scheduler> Cannot release lease: %v", err))
+			}
+		}
+	}
+	if s.stopRollover != nil {
+		close(s.stopRollover)
+	}
 	s.schedulerStarted = false // disable loop on next run
 	s.restartLoop <- true      // cancel waiting tasks
 	if s.timer != nil {
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
 )
 
 func TestSchedulerUpdateActStats(t *testing.T) {
@@ -45,3 +46,163 @@ func TestSchedulerUpdateActStats(t *testing.T) {
 		t.Errorf("Wrong stats: %+v", sched.actSuccessStats)
 	}
 }
+
+func TestSchedulerRenewLease(t *testing.T) {
+	data, _ := engine.NewMapStorage()
+	dm := engine.NewDataManager(data)
+	sched1 := &Scheduler{dm: dm, nodeID: "node1", leaseTTL: 50 * time.Millisecond}
+	sched2 := &Scheduler{dm: dm, nodeID: "node2", leaseTTL: 50 * time.Millisecond}
+	if !sched1.renewLease() || !sched1.isLeader() {
+		t.Error("node1 should lead")
+	}
+	if sched2.renewLease() || sched2.isLeader() {
+		t.Error("node2 should stand by")
+	}
+	if sched1.renewLease() || !sched1.isLeader() { // renewed, not taken over again
+		t.Error("node1 should keep the lead")
+	}
+	if err := sched1.holdsLease(); err != nil {
+		t.Error(err)
+	}
+	time.Sleep(60 * time.Millisecond) // node1 did not renew in time
+	// expired before the renewal notices
+	if err := sched1.holdsLease(); err != utils.ErrLeaseLost {
+		t.Errorf("expecting: %v, received: %v", utils.ErrLeaseLost, err)
+	}
+	if !sched2.renewLease() || !sched2.isLeader() {
+		t.Error("node2 should take over")
+	}
+	if sched1.renewLease() || sched1.isLeader() {
+		t.Error("node1 should stand by")
+	}
+	if err := dm.ReleaseLease(schedulerLeaseID, "node1"); err != nil { // not holding it
+		t.Error(err)
+	}
+	if ls, err := dm.GetLease(schedulerLeaseID); err != nil {
+		t.Error(err)
+	} else if ls.HolderID != "node2" {
+		t.Errorf("expecting: node2, received: %s", ls.HolderID)
+	}
+	if err := dm.ReleaseLease(schedulerLeaseID, "node2"); err != nil {
+		t.Error(err)
+	}
+	if !sched1.renewLease() {
+		t.Error("node1 should take over the released lease")
+	}
+}
+
+func TestSchedulerGetExecutionHistory(t *testing.T) {
+	now := time.Now()
+	data, _ := engine.NewMapStorage()
+	sched := &Scheduler{dm: engine.NewDataManager(data), historySize: 3}
+	if err := sched.dm.AddExecutionRecords([]*engine.ExecutionRecord{
+		{ActionPlanID: "AP0", AccountID: "cgrates.org:1001", ExecutionTime: now.Add(-4 * time.Minute)},
+		{ActionPlanID: "AP1", AccountID: "cgrates.org:1001", ExecutionTime: now.Add(-3 * time.Minute)},
+	}, sched.historySize); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.dm.AddExecutionRecords([]*engine.ExecutionRecord{
+		{ActionPlanID: "AP1", AccountID: "cgrates.org:1002", ExecutionTime: now.Add(-2 * time.Minute), Error: "ACCOUNT_DISABLED"},
+		{ActionPlanID: "AP2", AccountID: "", ExecutionTime: now.Add(-time.Minute), CatchUp: true},
+	}, sched.historySize); err != nil {
+		t.Fatal(err)
+	}
+	if recs, err := sched.GetExecutionHistory(ArgsGetExecutionHistory{}); err != nil {
+		t.Error(err)
+	} else if len(recs) != 3 || recs[0].ActionPlanID != "AP2" || recs[2].ActionPlanID != "AP1" {
+		t.Errorf("received: %s", utils.ToJSON(recs))
+	}
+	if recs, err := sched.GetExecutionHistory(ArgsGetExecutionHistory{
+		ActionPlanID: utils.StringPointer("AP1"), Account: utils.StringPointer("1002")}); err != nil {
+		t.Error(err)
+	} else if len(recs) != 1 || recs[0].Error != "ACCOUNT_DISABLED" {
+		t.Errorf("received: %s", utils.ToJSON(recs))
+	}
+	tStart := now.Add(-150 * time.Second)
+	if recs, err := sched.GetExecutionHistory(ArgsGetExecutionHistory{TimeStart: &tStart,
+		Paginator: utils.Paginator{Limit: utils.IntPointer(1)}}); err != nil {
+		t.Error(err)
+	} else if len(recs) != 1 || recs[0].ActionPlanID != "AP2" {
+		t.Errorf("received: %s", utils.ToJSON(recs))
+	}
+	if recs, err := sched.GetExecutionHistory(ArgsGetExecutionHistory{
+		Paginator: utils.Paginator{Offset: utils.IntPointer(1), Limit: utils.IntPointer(1)}}); err != nil {
+		t.Error(err)
+	} else if len(recs) != 1 || recs[0].AccountID != "cgrates.org:1002" {
+		t.Errorf("received: %s", utils.ToJSON(recs))
+	}
+	if recs, err := sched.dm.GetExecutionHistory(2, 5); err != nil {
+		t.Error(err)
+	} else if len(recs) != 1 || recs[0].ActionPlanID != "AP1" || recs[0].AccountID != "cgrates.org:1001" {
+		t.Errorf("received: %s", utils.ToJSON(recs))
+	}
+	if recs, err := sched.GetExecutionHistory(ArgsGetExecutionHistory{
+		Tenant: utils.StringPointer("itsyscom.com")}); err != nil {
+		t.Error(err)
+	} else if len(recs) != 0 {
+		t.Errorf("received: %s", utils.ToJSON(recs))
+	}
+}
+
+func TestSchedulerStartRecovery(t *testing.T) {
+	data, _ := engine.NewMapStorage()
+	sched := &Scheduler{dm: engine.NewDataManager(data), nodeID: "node1", leaseTTL: time.Minute}
+	if sched.startRecovery() {
+		t.Error("recovering while standing by")
+	}
+	if !sched.renewLease() {
+		t.Fatal("node1 should lead")
+	}
+	if !sched.startRecovery() {
+		t.Error("not recovering when taking over")
+	}
+	if sched.startRecovery() { // reloads within the same term
+		t.Error("recovering twice within the same term")
+	}
+}
+
+func TestSchedulerQueueActionPlan(t *testing.T) {
+	data, _ := engine.NewMapStorage()
+	sched := &Scheduler{dm: engine.NewDataManager(data)}
+	schedTime := time.Now().AddDate(0, 0, 30)
+	ap := &engine.ActionPlan{Id: "AP_ONE_OFF",
+		AccountIDs: utils.StringMap{"cgrates.org:1001": true},
+		ActionTimings: []*engine.ActionTiming{{
+			Uuid: "AT_ONE_OFF",
+			Timing: &engine.RateInterval{
+				Timing: &engine.RITiming{
+					Years:     utils.Years{schedTime.Year()},
+					Months:    utils.Months{schedTime.Month()},
+					MonthDays: utils.MonthDays{schedTime.Day()},
+					StartTime: schedTime.Format("15:04:05"),
+				},
+			},
+			ActionsID: "ACT_ONE_OFF",
+		}},
+	}
+	if err := data.SetActionPlan(ap.Id, ap, true, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.QueueActionPlan(ap.Id); err != nil {
+		t.Fatal(err)
+	}
+	if len(sched.queue) != 1 || sched.queue[0].GetActionPlanID() != ap.Id ||
+		!sched.queue[0].GetAccountIDs()["cgrates.org:1001"] {
+		t.Errorf("received: %s", utils.ToJSON(sched.queue))
+	}
+	if err := sched.QueueActionPlan("AP_MISSING"); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	standby := &Scheduler{dm: sched.dm, nodeID: "node2", leaseTTL: time.Minute}
+	if err := standby.QueueActionPlan(ap.Id); err != nil {
+		t.Fatal(err)
+	}
+	if len(standby.queue) != 0 {
+		t.Errorf("queued on the standby node: %s", utils.ToJSON(standby.queue))
+	}
+	sched.queue = nil
+	sched.executeTasks() // leader queueing the tasks pushed by the standby nodes
+	if len(sched.queue) != 1 || sched.queue[0].GetActionPlanID() != ap.Id {
+		t.Errorf("received: %s", utils.ToJSON(sched.queue))
+	}
+}
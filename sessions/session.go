@@ -48,6 +48,8 @@ type SMGSession struct {
 	EventStart *engine.SafEvent       // Event which started the session
 	CD         *engine.CallDescriptor // initial CD used for debits, updated on each debit
 	EventCost  *engine.EventCost
+	SplitCosts map[string]*engine.EventCost // costs of each payer on split billed sessions, indexed on account key
+	History    *engine.SessionHistory       // events and debits received during the session
 
 	ExtraDuration time.Duration // keeps the current duration debited on top of what has been asked
 	LastUsage     time.Duration // last requested Duration
@@ -62,11 +64,65 @@ func (s *SMGSession) Clone() *SMGSession {
 		EventStart:    s.EventStart.Clone(),
 		CD:            s.CD.Clone(),
 		EventCost:     s.EventCost.Clone(),
+		SplitCosts:    s.cloneSplitCosts(),
+		History:       s.History.Clone(),
 		ExtraDuration: s.ExtraDuration, LastUsage: s.LastUsage,
 		LastDebit: s.LastDebit, TotalUsage: s.TotalUsage,
 	}
 }
 
+func (s *SMGSession) cloneSplitCosts() (cln map[string]*engine.EventCost) {
+	if s.SplitCosts == nil {
+		return
+	}
+	cln = make(map[string]*engine.EventCost)
+	for acntKey, ec := range s.SplitCosts {
+		cln[acntKey] = ec.Clone()
+	}
+	return
+}
+
+// splitBilled returns true if the session is charged on more accounts
+func (s *SMGSession) splitBilled() bool {
+	return s.CD != nil && s.CD.ExtraFields[utils.CGRSplitBilling] != ""
+}
+
+// callDebit sends the debit request to RALs
+// on split billed sessions the costs of each payer are also kept apart
+func (s *SMGSession) callDebit(maxDebit bool) (cc *engine.CallCost, err error) {
+	cc = new(engine.CallCost)
+	if !s.splitBilled() {
+		method := "Responder.Debit"
+		if maxDebit {
+			method = "Responder.MaxDebit"
+		}
+		err = s.rals.Call(method, s.CD, cc)
+		return
+	}
+	method := "Responder.SplitDebit"
+	if maxDebit {
+		method = "Responder.SplitMaxDebit"
+	}
+	var ccs map[string]*engine.CallCost
+	if err = s.rals.Call(method, s.CD, &ccs); err != nil || len(ccs) == 0 {
+		return
+	}
+	if s.SplitCosts == nil {
+		s.SplitCosts = make(map[string]*engine.EventCost)
+	}
+	for acntKey, pyrCC := range ccs {
+		ec := engine.NewEventCostFromCallCost(pyrCC, s.CGRID,
+			engine.SplitBillingRunID(s.RunID, acntKey))
+		if s.SplitCosts[acntKey] == nil {
+			s.SplitCosts[acntKey] = ec
+		} else {
+			s.SplitCosts[acntKey].Merge(ec)
+		}
+	}
+	cc = engine.MergeSplitCallCosts(ccs)
+	return
+}
+
 type SessionID struct {
 	OriginHost string
 	OriginID   string
@@ -137,8 +193,13 @@ func (self *SMGSession) debit(dur time.Duration, lastUsed *time.Duration) (time.
 	}
 	self.CD.TimeEnd = self.CD.TimeStart.Add(dur)
 	self.CD.DurationIndex += dur
-	cc := &engine.CallCost{}
-	if err := self.rals.Call("Responder.MaxDebit", self.CD, cc); err != nil || cc.GetDuration() == 0 {
+	cc, err := self.callDebit(true)
+	dbtEntry := engine.NewSessionHistoryDebit(utils.MetaDebit, cc)
+	if err != nil {
+		dbtEntry.Error = err.Error()
+	}
+	self.History.Add(dbtEntry)
+	if err != nil || cc.GetDuration() == 0 {
 		self.LastUsage = 0
 		self.LastDebit = 0
 		return 0, err
@@ -184,16 +245,25 @@ func (self *SMGSession) disconnectSession(reason string) error {
 	if self.clientProto == 0 { // competibility with OpenSIPS
 		servMethod = "SMGClientV1.DisconnectSession"
 	}
+	dscEntry := &engine.SessionHistoryEntry{Time: time.Now(),
+		Type: utils.MetaDisconnect, Usage: self.TotalUsage, Reason: reason}
+	defer func() {
+		self.Lock()
+		self.History.Add(dscEntry)
+		self.Unlock()
+	}()
 	if err := self.clntConn.Call(servMethod,
 		utils.AttrDisconnectSession{EventStart: self.EventStart.AsMapInterface(),
 			Reason: reason},
 		&reply); err != nil {
 		if err != utils.ErrNotImplemented {
+			dscEntry.Error = err.Error()
 			return err
 		}
 		err = nil
 	} else if reply != utils.OK {
-		return errors.New(fmt.Sprintf("Unexpected disconnect reply: %s", reply))
+		dscEntry.Error = fmt.Sprintf("Unexpected disconnect reply: %s", reply)
+		return errors.New(dscEntry.Error)
 	}
 	return nil
 }
@@ -205,21 +275,105 @@ func (self *SMGSession) close(usage time.Duration) (err error) {
 	if self.EventCost == nil {
 		return
 	}
+	minCost, freeUsage := self.EventCost.CallLimits()
+	if self.CD.DeferCallLimits && freeUsage > 0 && usage < freeUsage { // call shorter than the FreeUsage, give back everything
+		return self.refundAll()
+	}
 	if notCharged := usage - self.EventCost.GetUsage(); notCharged > 0 { // we did not charge enough, make a manual debit here
 		if self.CD.LoopIndex > 0 {
 			self.CD.TimeStart = self.CD.TimeEnd
 		}
 		self.CD.TimeEnd = self.CD.TimeStart.Add(notCharged)
 		self.CD.DurationIndex += notCharged
-		cc := &engine.CallCost{}
-		if err = self.rals.Call("Responder.Debit", self.CD, cc); err == nil {
+		var cc *engine.CallCost
+		cc, err = self.callDebit(false)
+		dbtEntry := engine.NewSessionHistoryDebit(utils.MetaDebit, cc)
+		if err != nil {
+			dbtEntry.Error = err.Error()
+		} else {
 			self.EventCost.Merge(
 				engine.NewEventCostFromCallCost(cc, self.CGRID, self.RunID))
 		}
+		self.History.Add(dbtEntry)
 	} else if notCharged < 0 { // charged too much, try refund
 		err = self.refund(usage)
 	}
+	if err == nil && self.CD.DeferCallLimits && minCost > self.EventCost.GetCost() {
+		err = self.debitMinCost(utils.NewDecimalFromFloat64(minCost).
+			Sub(utils.NewDecimalFromFloat64(self.EventCost.GetCost())).Float64())
+	}
+	return
+}
+
+// refundAll gives back everything charged on the session, keeping the charged usage with zero cost
+func (self *SMGSession) refundAll() (err error) {
+	if !self.splitBilled() {
+		var acnt engine.Account
+		if acnt, err = self.refundEventCost(self.EventCost.Clone(),
+			self.CD.Tenant, self.CD.Account, self.RunID); err != nil {
+			return
+		}
+		self.EventCost.ResetCost()
+		if acnt.ID != "" {
+			self.EventCost.AccountSummary = acnt.AsAccountSummary()
+		}
+		return
+	}
+	sb, err := engine.NewSplitBillingFromString(self.CD.ExtraFields[utils.CGRSplitBilling])
+	if err != nil {
+		return
+	}
+	for _, pyr := range sb.Payers {
+		acntKey := pyr.AccountKey()
+		ec, has := self.SplitCosts[acntKey]
+		if !has {
+			continue
+		}
+		acnt, err := self.refundEventCost(ec.Clone(), pyr.Tenant, pyr.Account,
+			engine.SplitBillingRunID(self.RunID, acntKey))
+		if err != nil {
+			return err
+		}
+		ec.ResetCost()
+		if acnt.ID != "" {
+			ec.AccountSummary = acnt.AsAccountSummary()
+		}
+	}
+	self.EventCost.ResetCost()
+	return
+}
 
+// debitMinCost charges the session account with the cost missing up to the MinCost
+func (self *SMGSession) debitMinCost(minCostDiff float64) (err error) {
+	cd := self.CD.Clone()
+	cd.TimeStart = self.EventCost.StartTime
+	cd.TimeEnd = cd.TimeStart
+	cd.Increments = engine.Increments{&engine.Increment{Cost: minCostDiff}}
+	cc := new(engine.CallCost)
+	err = self.rals.Call("Responder.DebitMinCost", cd, cc)
+	dbtEntry := engine.NewSessionHistoryDebit(utils.MetaDebit, cc)
+	if err != nil {
+		dbtEntry.Error = err.Error()
+	}
+	self.History.Add(dbtEntry)
+	if err != nil {
+		return
+	}
+	ec := engine.NewEventCostFromCallCost(cc, self.CGRID, self.RunID)
+	if self.splitBilled() {
+		acntKey := self.CD.GetAccountKey()
+		splitEC := engine.NewEventCostFromCallCost(cc, self.CGRID,
+			engine.SplitBillingRunID(self.RunID, acntKey))
+		if self.SplitCosts == nil {
+			self.SplitCosts = make(map[string]*engine.EventCost)
+		}
+		if self.SplitCosts[acntKey] == nil {
+			self.SplitCosts[acntKey] = splitEC
+		} else {
+			self.SplitCosts[acntKey].Merge(splitEC)
+		}
+	}
+	self.EventCost.Merge(ec)
 	return
 }
 
@@ -229,6 +383,9 @@ func (self *SMGSession) refund(usage time.Duration) (err error) {
 	if self.EventCost == nil {
 		return
 	}
+	if self.splitBilled() {
+		return self.refundSplit(usage)
+	}
 	srplsEC, err := self.EventCost.Trim(usage)
 	if err != nil {
 		return err
@@ -236,10 +393,75 @@ func (self *SMGSession) refund(usage time.Duration) (err error) {
 	if srplsEC == nil {
 		return
 	}
+	acnt, err := self.refundEventCost(srplsEC, self.CD.Tenant, self.CD.Account, self.RunID)
+	if acnt.ID != "" { // Account info updated, update also cached AccountSummary
+		self.EventCost.AccountSummary = acnt.AsAccountSummary()
+	}
+	return
+}
 
+// refundSplit refunds the surplus on split billed sessions
+// each payer is refunded in proportion with its share out of the charged usage
+func (self *SMGSession) refundSplit(usage time.Duration) (err error) {
+	srplsUsage := self.EventCost.GetUsage() - usage
+	if _, err = self.EventCost.Trim(usage); err != nil {
+		return
+	}
+	sb, err := engine.NewSplitBillingFromString(self.CD.ExtraFields[utils.CGRSplitBilling])
+	if err != nil {
+		return
+	}
+	var pyrs []*engine.SplitPayer
+	var totalUsage time.Duration
+	for _, pyr := range sb.Payers {
+		if ec, has := self.SplitCosts[pyr.AccountKey()]; has {
+			pyrs = append(pyrs, pyr)
+			totalUsage += ec.GetUsage()
+		}
+	}
+	if totalUsage == 0 {
+		return
+	}
+	rfndLeft := srplsUsage
+	for i, pyr := range pyrs {
+		acntKey := pyr.AccountKey()
+		ec := self.SplitCosts[acntKey]
+		pyrUsage := ec.GetUsage()
+		rfndUsage := time.Duration(float64(srplsUsage) * float64(pyrUsage) / float64(totalUsage))
+		if i == len(pyrs)-1 { // last one takes the rest so we do not lose nanoseconds
+			rfndUsage = rfndLeft
+		}
+		rfndUsage = utils.MinDuration(rfndUsage, pyrUsage)
+		rfndLeft -= rfndUsage
+		if rfndUsage <= 0 {
+			continue
+		}
+		srplsEC, err := ec.Trim(pyrUsage - rfndUsage)
+		if err != nil {
+			return err
+		}
+		if srplsEC == nil {
+			continue
+		}
+		acnt, err := self.refundEventCost(srplsEC, pyr.Tenant, pyr.Account,
+			engine.SplitBillingRunID(self.RunID, acntKey))
+		if err != nil {
+			return err
+		}
+		if acnt.ID != "" {
+			ec.AccountSummary = acnt.AsAccountSummary()
+		}
+	}
+	return
+}
+
+// refundEventCost sends the increments of the surplus EventCost to RALs for refund
+func (self *SMGSession) refundEventCost(srplsEC *engine.EventCost,
+	tnt, acntID, runID string) (acnt engine.Account, err error) {
 	cc := srplsEC.AsCallCost()
 	var incrmts engine.Increments
 	for _, tmspn := range cc.Timespans {
+		tmspn.SetTierPeriod() // so the tier counters can be refunded
 		for _, incr := range tmspn.Increments {
 			if incr.BalanceInfo == nil ||
 				(incr.BalanceInfo.Unit == nil &&
@@ -251,20 +473,21 @@ func (self *SMGSession) refund(usage time.Duration) (err error) {
 	}
 	cd := &engine.CallDescriptor{
 		CgrID:       self.CGRID,
-		RunID:       self.RunID,
+		RunID:       runID,
 		Category:    self.CD.Category,
-		Tenant:      self.CD.Tenant,
+		Tenant:      tnt,
 		Subject:     self.CD.Subject,
-		Account:     self.CD.Account,
+		Account:     acntID,
 		Destination: self.CD.Destination,
 		TOR:         self.CD.TOR,
 		Increments:  incrmts,
 	}
-	var acnt engine.Account
 	err = self.rals.Call("Responder.RefundIncrements", cd, &acnt)
-	if acnt.ID != "" { // Account info updated, update also cached AccountSummary
-		self.EventCost.AccountSummary = acnt.AsAccountSummary()
+	rfndEntry := engine.NewSessionHistoryDebit(utils.MetaRefund, cc)
+	if err != nil {
+		rfndEntry.Error = err.Error()
 	}
+	self.History.Add(rfndEntry)
 	return
 }
 
@@ -275,6 +498,9 @@ func (self *SMGSession) storeSMCost() error {
 	}
 	self.Lock()
 	self.Unlock()
+	if self.splitBilled() {
+		return self.storeSplitSMCosts()
+	}
 	smCost := &engine.V2SMCost{
 		CGRID:       self.CGRID,
 		CostSource:  utils.MetaSessionS,
@@ -284,6 +510,9 @@ func (self *SMGSession) storeSMCost() error {
 		Usage:       self.TotalUsage,
 		CostDetails: self.EventCost,
 	}
+	if self.History != nil {
+		smCost.History = self.History.Entries
+	}
 	var reply string
 	if err := self.cdrsrv.Call("CdrsV2.StoreSMCost",
 		engine.ArgsV2CDRSStoreSMCost{Cost: smCost,
@@ -297,6 +526,44 @@ func (self *SMGSession) storeSMCost() error {
 	return nil
 }
 
+// storeSplitSMCosts stores one SMCost for each payer of a split billed session
+// the RunID of each SMCost is composed out of session RunID and account key of the payer
+func (self *SMGSession) storeSplitSMCosts() error {
+	for acntKey, ec := range self.SplitCosts {
+		smCost := &engine.V2SMCost{
+			CGRID:       self.CGRID,
+			CostSource:  utils.MetaSessionS,
+			RunID:       engine.SplitBillingRunID(self.RunID, acntKey),
+			OriginHost:  self.EventStart.GetStringIgnoreErrors(utils.OriginHost),
+			OriginID:    self.EventStart.GetStringIgnoreErrors(utils.OriginID),
+			Usage:       ec.GetUsage(),
+			CostDetails: ec,
+		}
+		if self.History != nil {
+			smCost.History = self.History.Entries
+		}
+		var reply string
+		if err := self.cdrsrv.Call("CdrsV2.StoreSMCost",
+			engine.ArgsV2CDRSStoreSMCost{Cost: smCost,
+				CheckDuplicate: true}, &reply); err != nil && err != utils.ErrExists {
+			return err
+		}
+	}
+	return nil
+}
+
+// AsFilterEvent returns the data used when matching the session on FilterS filters
+// Usage is overwritten with the usage of the session so far
+func (self *SMGSession) AsFilterEvent() (ev engine.MapEvent) {
+	self.RLock()
+	ev = self.EventStart.Clone().MapEvent()
+	ev[utils.CGRID] = self.CGRID
+	ev[utils.RunID] = self.RunID
+	ev[utils.Usage] = self.TotalUsage
+	self.RUnlock()
+	return
+}
+
 func (self *SMGSession) AsActiveSession(timezone string) *ActiveSession {
 	self.RLock()
 	defer self.RUnlock()
@@ -329,6 +596,63 @@ func (self *SMGSession) AsActiveSession(timezone string) *ActiveSession {
 	return aSession
 }
 
+// AsSessionCost returns the charging status of the session so far
+func (self *SMGSession) AsSessionCost() (sc *SessionCost) {
+	self.Lock() // EventCost caches computed values
+	defer self.Unlock()
+	sc = &SessionCost{
+		BalancesConsumed:  make(map[string]float64),
+		BalancesRemaining: make(map[string]float64),
+	}
+	if self.EventCost == nil {
+		return
+	}
+	sc.Cost = self.EventCost.GetCost()
+	blncIDs := make(map[string]string) // map[BalanceUUID]BalanceID
+	blncVals := make(map[string]float64)
+	if self.EventCost.AccountSummary != nil {
+		for _, bs := range self.EventCost.AccountSummary.BalanceSummaries {
+			blncIDs[bs.UUID] = bs.ID
+			blncVals[bs.UUID] = bs.Value
+		}
+	}
+	for blncUUID, units := range self.EventCost.BalancesConsumed() {
+		blncID := blncUUID
+		if id, has := blncIDs[blncUUID]; has && id != "" {
+			blncID = id
+		}
+		sc.BalancesConsumed[blncID] += units
+		if val, has := blncVals[blncUUID]; has {
+			sc.BalancesRemaining[blncID] = val
+		}
+	}
+	return
+}
+
+// SessionCost is the charging status of a session, returned to agents on request
+type SessionCost struct {
+	Cost              float64            // cost accumulated so far
+	BalancesConsumed  map[string]float64 // units consumed out of each balance, indexed on BalanceID
+	BalancesRemaining map[string]float64 // value left on the balances consumed, indexed on BalanceID
+}
+
+// AsMapInterface is used when building the NavigableMap out of replies
+func (sc *SessionCost) AsMapInterface() map[string]interface{} {
+	blncsCnsmd := make(map[string]interface{})
+	for blncID, val := range sc.BalancesConsumed {
+		blncsCnsmd[blncID] = val
+	}
+	blncsRmng := make(map[string]interface{})
+	for blncID, val := range sc.BalancesRemaining {
+		blncsRmng[blncID] = val
+	}
+	return map[string]interface{}{
+		utils.Cost:              sc.Cost,
+		utils.BalancesConsumed:  blncsCnsmd,
+		utils.BalancesRemaining: blncsRmng,
+	}
+}
+
 // Will be used when displaying active sessions via RPC
 type ActiveSession struct {
 	CGRID         string
@@ -19,6 +19,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package sessions
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -68,6 +69,7 @@ type SMGReplicationConn struct {
 
 func NewSMGeneric(cgrCfg *config.CGRConfig, rals, resS, thdS,
 	statS, splS, attrS, cdrsrv, chargerS rpcclient.RpcClientConnection,
+	filterS *engine.FilterS, dm *engine.DataManager,
 	smgReplConns []*SMGReplicationConn, timezone string) *SMGeneric {
 	ssIdxCfg := cgrCfg.SessionSCfg().SessionIndexes
 	ssIdxCfg[utils.OriginID] = true // Make sure we have indexing for OriginID since it is a requirement on prefix searching
@@ -105,6 +107,8 @@ func NewSMGeneric(cgrCfg *config.CGRConfig, rals, resS, thdS,
 		splS:               splS,
 		attrS:              attrS,
 		cdrsrv:             cdrsrv,
+		filterS:            filterS,
+		dm:                 dm,
 		smgReplConns:       smgReplConns,
 		Timezone:           timezone,
 		biJsonConns:        make(map[*rpc2.Client]struct{}),
@@ -129,6 +133,10 @@ type SMGeneric struct {
 	splS               rpcclient.RpcClientConnection // SupplierS connections
 	attrS              rpcclient.RpcClientConnection // AttributeS connections
 	cdrsrv             rpcclient.RpcClientConnection // CDR server connections
+	filterS            *engine.FilterS               // used to filter sessions on FilterS filters
+	dm                 *engine.DataManager           // used for sessions replication via DataDB
+	dataDBReplStop     chan struct{}                 // stops the replication via DataDB
+	dataDBReplDone     chan struct{}                 // closed once the replication via DataDB stopped
 	smgReplConns       []*SMGReplicationConn         // list of connections where we will replicate our session data
 	Timezone           string
 	intBiJSONConns     []rpcclient.RpcClientConnection
@@ -272,6 +280,8 @@ func (smg *SMGeneric) ttlTerminate(s *SMGSession, tmtr *smgSessionTerminator) {
 	for _, s := range aSessions[s.CGRID] {
 		s.debit(debitUsage, tmtr.ttlLastUsed)
 	}
+	smg.recordSessionHistory(s.CGRID, &engine.SessionHistoryEntry{Time: time.Now(),
+		Type: utils.MetaTerminate, Usage: s.TotalUsage, Reason: utils.SessionTTL})
 	smg.sessionEnd(s.CGRID, s.TotalUsage)
 	cdr, err := s.EventStart.AsCDR(smg.cgrCfg, s.Tenant, smg.Timezone)
 	if err != nil {
@@ -525,7 +535,8 @@ func (smg *SMGeneric) v1ForkSessions(tnt string, evStart *engine.SafEvent,
 			ResourceID: resourceID, EventStart: evStart,
 			RunID: utils.META_NONE, Timezone: smg.Timezone,
 			rals: smg.rals, cdrsrv: smg.cdrsrv,
-			clntConn: clntConn}}
+			clntConn: clntConn,
+			History:  engine.NewSessionHistory(smg.cgrCfg.SessionSCfg().SessionHistorySize)}}
 	handledSessions := []string{utils.META_PREPAID}
 	if handlePseudo {
 		handledSessions = append(handledSessions, utils.META_PSEUDOPREPAID)
@@ -540,7 +551,8 @@ func (smg *SMGeneric) v1ForkSessions(tnt string, evStart *engine.SafEvent,
 				RunID: sessionRun.DerivedCharger.RunID, Timezone: smg.Timezone,
 				rals: smg.rals, cdrsrv: smg.cdrsrv,
 				CD: sessionRun.CallDescriptor, clntConn: clntConn,
-				clientProto: smg.cgrCfg.SessionSCfg().ClientProtocol})
+				clientProto: smg.cgrCfg.SessionSCfg().ClientProtocol,
+				History:     engine.NewSessionHistory(smg.cgrCfg.SessionSCfg().SessionHistorySize)})
 	}
 	if len(ss) == 0 { //  we have no *prepaid session to work with
 		return noneSession, nil
@@ -568,7 +580,8 @@ func (smg *SMGeneric) v2ForkSessions(tnt string, evStart *engine.SafEvent,
 		{CGRID: cgrID, ResourceID: resourceID, EventStart: evStart,
 			RunID: utils.META_NONE, Timezone: smg.Timezone,
 			rals: smg.rals, cdrsrv: smg.cdrsrv,
-			clntConn: clntConn}}
+			clntConn: clntConn,
+			History:  engine.NewSessionHistory(smg.cgrCfg.SessionSCfg().SessionHistorySize)}}
 	handledSessions := []string{utils.META_PREPAID}
 	if handlePseudo {
 		handledSessions = append(handledSessions, utils.META_PSEUDOPREPAID)
@@ -603,7 +616,8 @@ func (smg *SMGeneric) v2ForkSessions(tnt string, evStart *engine.SafEvent,
 				Timezone:   smg.Timezone,
 				rals:       smg.rals, cdrsrv: smg.cdrsrv,
 				CD: cd, clntConn: clntConn,
-				clientProto: smg.cgrCfg.SessionSCfg().ClientProtocol})
+				clientProto: smg.cgrCfg.SessionSCfg().ClientProtocol,
+				History:     engine.NewSessionHistory(smg.cgrCfg.SessionSCfg().SessionHistorySize)})
 	}
 	if len(ss) == 0 { //  we have no *prepaid session to work with
 		return noneSession, nil
@@ -626,6 +640,9 @@ func (smg *SMGeneric) sessionStart(tnt, cgrID string, evStart *engine.SafEvent,
 	}
 	stopDebitChan := make(chan struct{})
 	for _, s := range ss {
+		if s.CD != nil { // debited in chunks, MinCost and FreeUsage are applied when the session closes
+			s.CD.DeferCallLimits = true
+		}
 		smg.recordASession(s)
 		if s.RunID != utils.META_NONE &&
 			dbtItval != 0 {
@@ -824,6 +841,109 @@ func (smg *SMGeneric) replicateSessionsWithID(cgrID string, passiveSessions bool
 	return
 }
 
+// storeNodeSessions saves the sessions of this node in DataDB, refreshing also the heartbeat
+func (smg *SMGeneric) storeNodeSessions() (err error) {
+	ns := &engine.NodeSessions{
+		NodeID:    smg.cgrCfg.GeneralCfg().NodeID,
+		Heartbeat: time.Now(),
+		Sessions:  make(map[string][]byte),
+	}
+	for _, passiveSessions := range []bool{true, false} { // active sessions overwrite the passive ones
+		for cgrID, ss := range smg.getSessions("", passiveSessions) {
+			ssCln := make([]*SMGSession, len(ss))
+			for i, s := range ss {
+				s.RLock()
+				ssCln[i] = s.Clone()
+				s.RUnlock()
+			}
+			if ns.Sessions[cgrID], err = json.Marshal(ssCln); err != nil {
+				return
+			}
+		}
+	}
+	return smg.dm.SetNodeSessions(ns)
+}
+
+// restoreNodeSessions sets the sessions out of NodeSessions as passive ones
+// sessions already active on this node are ignored
+func (smg *SMGeneric) restoreNodeSessions(ns *engine.NodeSessions) {
+	for cgrID, ssData := range ns.Sessions {
+		if len(smg.getSessions(cgrID, false)) != 0 {
+			continue
+		}
+		var ss []*SMGSession
+		if err := json.Unmarshal(ssData, &ss); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> cannot restore session with CGRID: <%s> of node <%s>, error: %s",
+					utils.SessionS, cgrID, ns.NodeID, err.Error()))
+			continue
+		}
+		if err := smg.setPassiveSessions(cgrID, ss); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> cannot restore session with CGRID: <%s> of node <%s>, error: %s",
+					utils.SessionS, cgrID, ns.NodeID, err.Error()))
+		}
+	}
+}
+
+// takeoverNodeSessions takes over, as passive, the sessions of the nodes with expired heartbeat
+// removing the NodeSessions out of DataDB, if not refreshed since read, is used as claim
+// so only one node will take them over
+func (smg *SMGeneric) takeoverNodeSessions() (err error) {
+	nodeIDs, err := smg.dm.GetNodeSessionsIDs()
+	if err != nil {
+		return
+	}
+	for _, nodeID := range nodeIDs {
+		if nodeID == smg.cgrCfg.GeneralCfg().NodeID {
+			continue
+		}
+		ns, err := smg.dm.GetNodeSessions(nodeID)
+		if err != nil {
+			if err == utils.ErrNotFound { // taken over in the meantime
+				continue
+			}
+			return err
+		}
+		if !ns.Expired(smg.cgrCfg.SessionSCfg().DataDBReplicationTTL) {
+			continue
+		}
+		if err = smg.dm.ClaimNodeSessions(nodeID, ns.Heartbeat); err != nil {
+			if err == utils.ErrNotFound { // claimed by another node or node back alive
+				continue
+			}
+			return err
+		}
+		smg.restoreNodeSessions(ns)
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> took over %d sessions of node <%s>",
+				utils.SessionS, len(ns.Sessions), nodeID))
+	}
+	return
+}
+
+// dataDBReplication periodically stores the sessions of this node and takes over the ones of failed nodes
+func (smg *SMGeneric) dataDBReplication() {
+	defer close(smg.dataDBReplDone)
+	for {
+		if err := smg.storeNodeSessions(); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s storing sessions in DataDB",
+					utils.SessionS, err.Error()))
+		}
+		if err := smg.takeoverNodeSessions(); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s taking over sessions of other nodes",
+					utils.SessionS, err.Error()))
+		}
+		select {
+		case <-smg.dataDBReplStop:
+			return
+		case <-time.After(smg.cgrCfg.SessionSCfg().DataDBReplicationInterval):
+		}
+	}
+}
+
 // getSessions is used to return in a thread-safe manner active or passive sessions
 func (smg *SMGeneric) getSessions(cgrID string, passiveSessions bool) (aSS map[string][]*SMGSession) {
 	ssMux := &smg.aSessionsMux
@@ -908,8 +1028,49 @@ func (smg *SMGeneric) passiveToActive(cgrID string) (pSessions map[string][]*SMG
 	return
 }
 
+// recordSessionHistory adds the entry to the history of all runs of a session
+// falls back on passive sessions, returns false if no session was found
+func (smg *SMGeneric) recordSessionHistory(cgrID string, she *engine.SessionHistoryEntry) bool {
+	ss := smg.getSessions(cgrID, false)
+	if len(ss) == 0 {
+		if ss = smg.getSessions(cgrID, true); len(ss) == 0 {
+			return false
+		}
+	}
+	for _, s := range ss[cgrID] {
+		s.Lock()
+		s.History.Add(she)
+		s.Unlock()
+	}
+	return true
+}
+
+// sessionCosts returns the charging status of the active session runs, indexed on RunID
+func (smg *SMGeneric) sessionCosts(cgrID string) (sCosts map[string]*SessionCost) {
+	ss := smg.getSessions(cgrID, false)
+	sCosts = make(map[string]*SessionCost)
+	for _, s := range ss[cgrID] {
+		sCosts[s.RunID] = s.AsSessionCost()
+	}
+	return
+}
+
+// sessionCostsAsMapInterface is used when building NavigableMaps out of replies
+func sessionCostsAsMapInterface(sCosts map[string]*SessionCost) (mp map[string]interface{}) {
+	mp = make(map[string]interface{})
+	for runID, sc := range sCosts {
+		mp[runID] = sc.AsMapInterface()
+	}
+	return
+}
+
 // asActiveSessions returns sessions from either active or passive table as []*ActiveSession
-func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSessions bool) (aSessions []*ActiveSession, counter int, err error) {
+// tenant and filterIDs are used for further filtering via FilterS, filterIDs can be inline filters
+func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, tenant string, filterIDs []string,
+	count, passiveSessions bool) (aSessions []*ActiveSession, counter int, err error) {
+	if len(filterIDs) != 0 && smg.filterS == nil {
+		return nil, 0, utils.NewErrNotConnected(utils.FilterS)
+	}
 	aSessions = make([]*ActiveSession, 0) // Make sure we return at least empty list and not nil
 	// Check first based on indexes so we can downsize the list of matching sessions
 	matchingSessionIDs, checkedFilters := smg.getSessionIDsMatchingIndexes(fltrs, passiveSessions)
@@ -955,6 +1116,21 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 			i++
 		}
 	}
+	if len(filterIDs) != 0 {
+		if tenant == "" {
+			tenant = smg.cgrCfg.GeneralCfg().DefaultTenant
+		}
+		for i := 0; i < len(remainingSessions); {
+			if pass, err := smg.filterS.Pass(tenant, filterIDs,
+				remainingSessions[i].AsFilterEvent()); err != nil {
+				return nil, 0, err
+			} else if !pass {
+				remainingSessions = append(remainingSessions[:i], remainingSessions[i+1:]...)
+				continue
+			}
+			i++
+		}
+	}
 	if count {
 		return nil, len(remainingSessions), nil
 	}
@@ -1026,6 +1202,9 @@ func (smg *SMGeneric) InitiateSession(tnt string, ev *engine.SafEvent,
 			smg.sessionEnd(cgrID, 0)
 			return
 		}
+		smg.recordSessionHistory(cgrID, &engine.SessionHistoryEntry{Time: time.Now(),
+			Type: utils.MetaInitiate, Usage: ev.GetDurationIgnoreErrors(utils.Usage),
+			Event: ev.AsMapInterface()})
 		if dbtItval != 0 { // Session handled by debit loop
 			maxUsage = time.Duration(-1)
 			return
@@ -1051,7 +1230,14 @@ func (smg *SMGeneric) UpdateSession(tnt string, ev *engine.SafEvent,
 		}
 		defer smg.responseCache.Cache(cacheKey,
 			&utils.ResponseCacheItem{Value: maxUsage, Err: err})
+		updEntry := &engine.SessionHistoryEntry{Time: time.Now(),
+			Type: utils.MetaUpdate, Usage: ev.GetDurationIgnoreErrors(utils.Usage),
+			Event: ev.AsMapInterface()}
+		recorded := smg.recordSessionHistory(cgrID, updEntry)
 		maxUsage, err = smg.sessionUpdate(tnt, cgrID, ev, clnt, resourceID, dbtItval)
+		if !recorded { // session was created out of this update
+			smg.recordSessionHistory(cgrID, updEntry)
+		}
 		if err != nil {
 			smg.sessionEnd(cgrID, 0)
 		}
@@ -1125,6 +1311,8 @@ func (smg *SMGeneric) TerminateSession(tnt string, ev *engine.SafEvent,
 			if errUsage != nil {
 				usage = s.TotalUsage - s.LastUsage + lastUsed
 			}
+			smg.recordSessionHistory(sessionID, &engine.SessionHistoryEntry{Time: time.Now(),
+				Type: utils.MetaTerminate, Usage: usage, Event: ev.AsMapInterface()})
 			if errSEnd := smg.sessionEnd(sessionID, usage); errSEnd != nil {
 				err = errSEnd // Last error will be the one returned as API result
 			}
@@ -1228,14 +1416,35 @@ func (smg *SMGeneric) Connect() error {
 
 		}()
 	}
+	if smg.dm != nil && smg.cgrCfg.SessionSCfg().DataDBReplicationInterval != 0 {
+		// restore the sessions stored before a restart of this node
+		if ns, err := smg.dm.GetNodeSessions(smg.cgrCfg.GeneralCfg().NodeID); err == nil {
+			smg.restoreNodeSessions(ns)
+		} else if err != utils.ErrNotFound {
+			return err
+		}
+		smg.dataDBReplStop = make(chan struct{})
+		smg.dataDBReplDone = make(chan struct{})
+		go smg.dataDBReplication()
+	}
 	return nil
 }
 
 // System shutdown
 func (smg *SMGeneric) Shutdown() error {
+	if smg.dataDBReplStop != nil { // make sure the replication does not store the sessions back
+		close(smg.dataDBReplStop)
+		<-smg.dataDBReplDone
+	}
 	for ssId := range smg.getSessions("", false) { // Force sessions shutdown
 		smg.sessionEnd(ssId, time.Duration(smg.cgrCfg.MaxCallDuration))
 	}
+	if smg.dataDBReplStop != nil {
+		if err := smg.dm.RemoveNodeSessions(smg.cgrCfg.GeneralCfg().NodeID); err != nil &&
+			err != utils.ErrNotFound {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1467,7 +1676,7 @@ func (smg *SMGeneric) BiRPCV1GetActiveSessions(clnt rpcclient.RpcClientConnectio
 			fltr[fldName] = utils.META_NONE
 		}
 	}
-	aSessions, _, err := smg.asActiveSessions(fltr, false, false)
+	aSessions, _, err := smg.asActiveSessions(fltr, "", nil, false, false)
 	if err != nil {
 		return utils.NewErrServerError(err)
 	} else if len(aSessions) == 0 {
@@ -1484,7 +1693,7 @@ func (smg *SMGeneric) BiRPCV1GetActiveSessionsCount(clnt rpcclient.RpcClientConn
 			fltr[fldName] = utils.META_NONE
 		}
 	}
-	if _, count, err := smg.asActiveSessions(fltr, true, false); err != nil {
+	if _, count, err := smg.asActiveSessions(fltr, "", nil, true, false); err != nil {
 		return err
 	} else {
 		*reply = count
@@ -1499,7 +1708,7 @@ func (smg *SMGeneric) BiRPCV1GetPassiveSessions(clnt rpcclient.RpcClientConnecti
 			fltr[fldName] = utils.META_NONE
 		}
 	}
-	aSessions, _, err := smg.asActiveSessions(fltr, false, true)
+	aSessions, _, err := smg.asActiveSessions(fltr, "", nil, false, true)
 	if err != nil {
 		return utils.NewErrServerError(err)
 	} else if len(aSessions) == 0 {
@@ -1516,7 +1725,7 @@ func (smg *SMGeneric) BiRPCV1GetPassiveSessionsCount(clnt rpcclient.RpcClientCon
 			fltr[fldName] = utils.META_NONE
 		}
 	}
-	if _, count, err := smg.asActiveSessions(fltr, true, true); err != nil {
+	if _, count, err := smg.asActiveSessions(fltr, "", nil, true, true); err != nil {
 		return err
 	} else {
 		*reply = count
@@ -1524,6 +1733,72 @@ func (smg *SMGeneric) BiRPCV1GetPassiveSessionsCount(clnt rpcclient.RpcClientCon
 	return nil
 }
 
+// BiRPCV1GetSessionHistory returns the history of a session, indexed on RunID
+// sessions which are not anymore active are queried out of the stored SMCosts
+func (smg *SMGeneric) BiRPCV1GetSessionHistory(clnt rpcclient.RpcClientConnection,
+	cgrID string, reply *map[string][]*engine.SessionHistoryEntry) (err error) {
+	if cgrID == "" {
+		return utils.NewErrMandatoryIeMissing(utils.CGRID)
+	}
+	sHistory := make(map[string][]*engine.SessionHistoryEntry)
+	ss := smg.getSessions(cgrID, false)
+	if len(ss) == 0 {
+		ss = smg.getSessions(cgrID, true)
+	}
+	for _, s := range ss[cgrID] {
+		s.RLock()
+		if s.History != nil {
+			sHistory[s.RunID] = s.History.Clone().Entries
+		}
+		s.RUnlock()
+	}
+	if len(ss) == 0 && smg.cdrsrv != nil {
+		var smCosts []*engine.SMCost
+		if err = smg.cdrsrv.Call(utils.CdrsV1GetSMCosts,
+			engine.ArgsGetSMCosts{CGRID: cgrID}, &smCosts); err != nil {
+			if err.Error() == utils.ErrNotFound.Error() {
+				return utils.ErrNotFound
+			}
+			return utils.NewErrServerError(err)
+		}
+		for _, smCost := range smCosts {
+			sHistory[smCost.RunID] = smCost.History
+		}
+	}
+	if len(sHistory) == 0 {
+		return utils.ErrNotFound
+	}
+	*reply = sHistory
+	return
+}
+
+// ArgsFilterSessions selects sessions based on field values and FilterS filters
+type ArgsFilterSessions struct {
+	Tenant    string            // tenant of the filters, defaults to general default_tenant
+	Filters   map[string]string // field filters, matched on equality
+	FilterIDs []string          // FilterS filter IDs or inline filters (ie: *gte:Usage:1h)
+	DryRun    bool              // only list the matching sessions, without acting on them
+}
+
+// BiRPCV1GetActiveSessionsWithFilters returns the active sessions matching args
+func (smg *SMGeneric) BiRPCV1GetActiveSessionsWithFilters(clnt rpcclient.RpcClientConnection,
+	args *ArgsFilterSessions, reply *[]*ActiveSession) error {
+	for fldName, fldVal := range args.Filters {
+		if fldVal == "" {
+			args.Filters[fldName] = utils.META_NONE
+		}
+	}
+	aSessions, _, err := smg.asActiveSessions(args.Filters, args.Tenant,
+		args.FilterIDs, false, false)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	} else if len(aSessions) == 0 {
+		return utils.ErrNotFound
+	}
+	*reply = aSessions
+	return nil
+}
+
 type ArgsSetPassiveSessions struct {
 	CGRID    string
 	Sessions []*SMGSession
@@ -1842,6 +2117,7 @@ type V1InitSessionArgs struct {
 	GetAttributes     bool
 	AllocateResources bool
 	InitSession       bool
+	GetSessionCosts   bool // return the cost and balances consumed so far, together with InitSession
 	ProcessThresholds bool
 	ProcessStats      bool
 	utils.CGREvent
@@ -1851,6 +2127,7 @@ type V1InitSessionReply struct {
 	Attributes         *engine.AttrSProcessEventReply
 	ResourceAllocation *string
 	MaxUsage           *time.Duration
+	SessionCosts       map[string]*SessionCost // indexed on RunID
 	ThresholdIDs       *[]string
 	StatQueueIDs       *[]string
 }
@@ -1875,6 +2152,9 @@ func (v1Rply *V1InitSessionReply) AsNavigableMap(
 		if v1Rply.MaxUsage != nil {
 			cgrReply[utils.CapMaxUsage] = *v1Rply.MaxUsage
 		}
+		if v1Rply.SessionCosts != nil {
+			cgrReply[utils.CapSessionCosts] = sessionCostsAsMapInterface(v1Rply.SessionCosts)
+		}
 		if v1Rply.ThresholdIDs != nil {
 			cgrReply[utils.CapThresholds] = *v1Rply.ThresholdIDs
 		}
@@ -1962,6 +2242,9 @@ func (smg *SMGeneric) BiRPCv1InitiateSession(clnt rpcclient.RpcClientConnection,
 		} else {
 			rply.MaxUsage = &maxUsage
 		}
+		if args.GetSessionCosts {
+			rply.SessionCosts = smg.sessionCosts(GetSetCGRID(ev))
+		}
 	}
 	if args.ProcessThresholds {
 		if smg.thdS == nil {
@@ -2045,14 +2328,16 @@ func NewV1UpdateSessionArgs(attrs, acnts bool,
 }
 
 type V1UpdateSessionArgs struct {
-	GetAttributes bool
-	UpdateSession bool
+	GetAttributes   bool
+	UpdateSession   bool
+	GetSessionCosts bool // return the cost and balances consumed so far, together with UpdateSession
 	utils.CGREvent
 }
 
 type V1UpdateSessionReply struct {
-	Attributes *engine.AttrSProcessEventReply
-	MaxUsage   *time.Duration
+	Attributes   *engine.AttrSProcessEventReply
+	MaxUsage     *time.Duration
+	SessionCosts map[string]*SessionCost // indexed on RunID
 }
 
 // AsNavigableMap is part of engine.NavigableMapper interface
@@ -2072,6 +2357,9 @@ func (v1Rply *V1UpdateSessionReply) AsNavigableMap(
 		if v1Rply.MaxUsage != nil {
 			cgrReply[utils.CapMaxUsage] = *v1Rply.MaxUsage
 		}
+		if v1Rply.SessionCosts != nil {
+			cgrReply[utils.CapSessionCosts] = sessionCostsAsMapInterface(v1Rply.SessionCosts)
+		}
 	}
 	return config.NewNavigableMap(cgrReply), nil
 }
@@ -2128,6 +2416,9 @@ func (smg *SMGeneric) BiRPCv1UpdateSession(clnt rpcclient.RpcClientConnection,
 		} else {
 			rply.MaxUsage = &maxUsage
 		}
+		if args.GetSessionCosts {
+			rply.SessionCosts = smg.sessionCosts(GetSetCGRID(ev))
+		}
 	}
 	return
 }
@@ -2449,18 +2740,40 @@ func (smg *SMGeneric) BiRPCv1SyncSessions(clnt rpcclient.RpcClientConnection,
 
 func (smg *SMGeneric) BiRPCV1ForceDisconnect(clnt rpcclient.RpcClientConnection,
 	fltr map[string]string, reply *string) error {
-	for fldName, fldVal := range fltr {
+	var cgrIDs []string
+	if err := smg.BiRPCV1ForceDisconnectWithFilters(clnt,
+		&ArgsFilterSessions{Filters: fltr}, &cgrIDs); err != nil {
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// BiRPCV1ForceDisconnectWithFilters disconnects the active sessions matching args
+// and returns the CGRIDs of the sessions affected, with DryRun the sessions are only listed
+func (smg *SMGeneric) BiRPCV1ForceDisconnectWithFilters(clnt rpcclient.RpcClientConnection,
+	args *ArgsFilterSessions, reply *[]string) error {
+	for fldName, fldVal := range args.Filters {
 		if fldVal == "" {
-			fltr[fldName] = utils.META_NONE
+			args.Filters[fldName] = utils.META_NONE
 		}
 	}
-	aSessions, _, err := smg.asActiveSessions(fltr, false, false)
+	aSessions, _, err := smg.asActiveSessions(args.Filters, args.Tenant,
+		args.FilterIDs, false, false)
 	if err != nil {
 		return utils.NewErrServerError(err)
 	} else if len(aSessions) == 0 {
 		return utils.ErrNotFound
 	}
+	cgrIDs := make([]string, 0, len(aSessions))
 	for _, aSession := range aSessions {
+		if utils.IsSliceMember(cgrIDs, aSession.CGRID) { // one CGRID per all runs
+			continue
+		}
+		cgrIDs = append(cgrIDs, aSession.CGRID)
+		if args.DryRun {
+			continue
+		}
 		sessions := smg.getSessions(aSession.CGRID, false)
 		if len(sessions[aSession.CGRID]) == 0 {
 			continue
@@ -2470,7 +2783,7 @@ func (smg *SMGeneric) BiRPCV1ForceDisconnect(clnt rpcclient.RpcClientConnection,
 		}
 		smg.ttlTerminate(sessions[aSession.CGRID][0], terminator)
 	}
-	*reply = utils.OK
+	*reply = cgrIDs
 	return nil
 }
 
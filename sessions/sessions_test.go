@@ -332,7 +332,84 @@ func TestV1UpdateSessionReplyAsNavigableMap(t *testing.T) {
 	if rply, _ := v1UpdtRpl.AsNavigableMap(nil); !reflect.DeepEqual(expected, rply) {
 		t.Errorf("Expecting \n%+v\n, received: \n%+v", expected, rply)
 	}
+	v1UpdtRpl.SessionCosts = map[string]*SessionCost{
+		utils.META_DEFAULT: &SessionCost{
+			Cost:              0.6,
+			BalancesConsumed:  map[string]float64{"MONETARY": 0.6},
+			BalancesRemaining: map[string]float64{"MONETARY": 9.4},
+		},
+	}
+	expected.Set([]string{utils.CapSessionCosts},
+		map[string]interface{}{
+			utils.META_DEFAULT: map[string]interface{}{
+				utils.Cost:              0.6,
+				utils.BalancesConsumed:  map[string]interface{}{"MONETARY": 0.6},
+				utils.BalancesRemaining: map[string]interface{}{"MONETARY": 9.4},
+			},
+		}, false, false)
+	if rply, _ := v1UpdtRpl.AsNavigableMap(nil); !reflect.DeepEqual(expected, rply) {
+		t.Errorf("Expecting \n%+v\n, received: \n%+v", expected, rply)
+	}
 }
+
+func TestSMGSessionAsSessionCost(t *testing.T) {
+	s := &SMGSession{CGRID: "CGRID1", RunID: utils.META_DEFAULT}
+	eSc := &SessionCost{
+		BalancesConsumed:  map[string]float64{},
+		BalancesRemaining: map[string]float64{},
+	}
+	if sc := s.AsSessionCost(); !reflect.DeepEqual(eSc, sc) {
+		t.Errorf("Expecting: %+v, received: %+v", eSc, sc)
+	}
+	s.EventCost = &engine.EventCost{
+		CGRID: "CGRID1",
+		RunID: utils.META_DEFAULT,
+		Charges: []*engine.ChargingInterval{
+			&engine.ChargingInterval{
+				RatingID: "RATING1",
+				Increments: []*engine.ChargingIncrement{
+					&engine.ChargingIncrement{
+						Usage:          time.Duration(1 * time.Second),
+						Cost:           0.01,
+						AccountingID:   "ACCOUNTING1",
+						CompressFactor: 60,
+					},
+				},
+				CompressFactor: 1,
+			},
+		},
+		AccountSummary: &engine.AccountSummary{
+			Tenant: "cgrates.org",
+			ID:     "1001",
+			BalanceSummaries: []*engine.BalanceSummary{
+				&engine.BalanceSummary{
+					UUID:  "BALANCE_UUID1",
+					ID:    "MONETARY",
+					Type:  utils.MONETARY,
+					Value: 9.4},
+			},
+		},
+		Accounting: engine.Accounting{
+			"ACCOUNTING1": &engine.BalanceCharge{
+				AccountID:   "cgrates.org:1001",
+				BalanceUUID: "BALANCE_UUID1",
+				Units:       0.01,
+			},
+		},
+	}
+	eSc = &SessionCost{
+		Cost:              0.6,
+		BalancesConsumed:  map[string]float64{"MONETARY": 0.6},
+		BalancesRemaining: map[string]float64{"MONETARY": 9.4},
+	}
+	sc := s.AsSessionCost()
+	sc.BalancesConsumed["MONETARY"] = utils.Round(sc.BalancesConsumed["MONETARY"],
+		5, utils.ROUNDING_MIDDLE)
+	if !reflect.DeepEqual(eSc, sc) {
+		t.Errorf("Expecting: %+v, received: %+v", eSc, sc)
+	}
+}
+
 func TestV1ProcessEventReplyAsNavigableMap(t *testing.T) {
 	v1PrcEvRpl := new(V1ProcessEventReply)
 	expected := config.NewNavigableMap(map[string]interface{}{})
@@ -20,6 +20,7 @@ package sessions
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
@@ -39,7 +40,7 @@ func init() {
 }
 
 func TestSMGSessionIndexing(t *testing.T) {
-	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, "UTC")
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "UTC")
 	smGev := engine.NewSafEvent(map[string]interface{}{
 		utils.EVENT_NAME:       "TEST_EVENT",
 		utils.ToR:              "*voice",
@@ -407,7 +408,7 @@ func TestSMGSessionIndexing(t *testing.T) {
 }
 
 func TestSMGActiveSessions(t *testing.T) {
-	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, "UTC")
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "UTC")
 	smGev1 := engine.NewSafEvent(map[string]interface{}{
 		utils.EVENT_NAME:       "TEST_EVENT",
 		utils.ToR:              "*voice",
@@ -461,60 +462,130 @@ func TestSMGActiveSessions(t *testing.T) {
 		RunID:      utils.META_DEFAULT,
 		EventStart: smGev2,
 	})
-	if aSessions, _, err := smg.asActiveSessions(nil, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(nil, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 2 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{}, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{}, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 2 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.Tenant: "noTenant"}, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.Tenant: "noTenant"}, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 0 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.Tenant: "itsyscom.com"}, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.Tenant: "itsyscom.com"}, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 1 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "222", utils.Tenant: "itsyscom.com"}, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "222", utils.Tenant: "itsyscom.com"}, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 1 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "222", utils.Tenant: "NoTenant.com"}, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "222", utils.Tenant: "NoTenant.com"}, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 0 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.ToR: "*voice"}, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.ToR: "*voice"}, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 2 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{"Extra3": utils.MetaEmpty}, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{"Extra3": utils.MetaEmpty}, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 1 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.SUPPLIER: "supplier2"}, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.SUPPLIER: "supplier2"}, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 1 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "222", utils.Tenant: "itsyscom.com", utils.SUPPLIER: "supplier2", "Extra1": "Value1"}, false, false); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "222", utils.Tenant: "itsyscom.com", utils.SUPPLIER: "supplier2", "Extra1": "Value1"}, "", nil, false, false); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 1 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
 }
 
+func TestSMGActiveSessionsWithFilters(t *testing.T) {
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil,
+		engine.NewFilterS(smgCfg, nil, nil), nil, nil, "UTC")
+	smGev1 := engine.NewSafEvent(map[string]interface{}{
+		utils.ToR:         utils.VOICE,
+		utils.OriginID:    "111",
+		utils.Account:     "account1",
+		utils.Destination: "+4986517174963",
+		utils.Tenant:      "cgrates.org",
+		utils.OriginHost:  "127.0.0.1",
+	})
+	smg.recordASession(&SMGSession{
+		CGRID:      GetSetCGRID(smGev1),
+		RunID:      utils.META_DEFAULT,
+		EventStart: smGev1,
+		TotalUsage: 2 * time.Hour,
+	})
+	smGev2 := engine.NewSafEvent(map[string]interface{}{
+		utils.ToR:         utils.VOICE,
+		utils.OriginID:    "222",
+		utils.Account:     "account2",
+		utils.Destination: "+4986517174963",
+		utils.Tenant:      "cgrates.org",
+		utils.OriginHost:  "127.0.0.1",
+	})
+	smg.recordASession(&SMGSession{
+		CGRID:      GetSetCGRID(smGev2),
+		RunID:      utils.META_DEFAULT,
+		EventStart: smGev2,
+		TotalUsage: 10 * time.Minute,
+	})
+	if aSessions, _, err := smg.asActiveSessions(nil, "cgrates.org",
+		[]string{"*prefix:Destination:+49"}, false, false); err != nil {
+		t.Error(err)
+	} else if len(aSessions) != 2 {
+		t.Errorf("Received sessions: %+v", aSessions)
+	}
+	if aSessions, _, err := smg.asActiveSessions(nil, "cgrates.org",
+		[]string{"*prefix:Destination:+49", "*gte:Usage:1h"}, false, false); err != nil {
+		t.Error(err)
+	} else if len(aSessions) != 1 || aSessions[0].Account != "account1" {
+		t.Errorf("Received sessions: %+v", aSessions)
+	}
+	if _, count, err := smg.asActiveSessions(map[string]string{utils.Account: "account2"},
+		"cgrates.org", []string{"*gte:Usage:1h"}, true, false); err != nil {
+		t.Error(err)
+	} else if count != 0 {
+		t.Errorf("Received count: %d", count)
+	}
+	var cgrIDs []string
+	if err := smg.BiRPCV1ForceDisconnectWithFilters(nil, &ArgsFilterSessions{
+		Tenant:    "cgrates.org",
+		FilterIDs: []string{"*gte:Usage:1h"},
+		DryRun:    true,
+	}, &cgrIDs); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{GetSetCGRID(smGev1)}, cgrIDs) {
+		t.Errorf("Received CGRIDs: %+v", cgrIDs)
+	}
+	if aSessions := smg.getSessions("", false); len(aSessions) != 2 { // dry run should not disconnect
+		t.Errorf("Active sessions: %+v", aSessions)
+	}
+	smgNoFltrS := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "UTC")
+	if _, _, err := smgNoFltrS.asActiveSessions(nil, "cgrates.org",
+		[]string{"*gte:Usage:1h"}, false, false); err == nil ||
+		err.Error() != utils.NewErrNotConnected(utils.FilterS).Error() {
+		t.Errorf("Expecting not connected error, received: %v", err)
+	}
+}
+
 func TestGetPassiveSessions(t *testing.T) {
-	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, "UTC")
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "UTC")
 	if pSS := smg.getSessions("", true); len(pSS) != 0 {
 		t.Errorf("PassiveSessions: %+v", pSS)
 	}
@@ -595,54 +666,112 @@ func TestGetPassiveSessions(t *testing.T) {
 		t.Errorf("PassiveSessions: %+v", pSS)
 	}
 
-	if aSessions, _, err := smg.asActiveSessions(nil, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(nil, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 3 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{}, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{}, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 3 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.Tenant: "noTenant"}, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.Tenant: "noTenant"}, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 0 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.Tenant: "cgrates.org"}, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.Tenant: "cgrates.org"}, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 3 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "23456", utils.Tenant: "cgrates.org"}, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "23456", utils.Tenant: "cgrates.org"}, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 1 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "404", utils.Tenant: "cgrates.org"}, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "404", utils.Tenant: "cgrates.org"}, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 0 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.ToR: "*voice"}, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.ToR: "*voice"}, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 3 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{"Extra3": ""}, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{"Extra3": ""}, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 2 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.SUPPLIER: "supplier2"}, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.SUPPLIER: "supplier2"}, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 1 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
-	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "23456", utils.Tenant: "cgrates.org", "Extra3": "e1", "Extra1": "Value2"}, false, true); err != nil {
+	if aSessions, _, err := smg.asActiveSessions(map[string]string{utils.OriginID: "23456", utils.Tenant: "cgrates.org", "Extra3": "e1", "Extra1": "Value2"}, "", nil, false, true); err != nil {
 		t.Error(err)
 	} else if len(aSessions) != 1 {
 		t.Errorf("Received sessions: %+v", aSessions)
 	}
 }
+
+func TestSMGDataDBReplication(t *testing.T) {
+	data, _ := engine.NewMapStorage()
+	dm := engine.NewDataManager(data)
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, dm, nil, "UTC")
+	smGev := engine.NewSafEvent(map[string]interface{}{
+		utils.ToR:         utils.VOICE,
+		utils.OriginID:    "111",
+		utils.Account:     "account1",
+		utils.Destination: "+4986517174963",
+		utils.Tenant:      "cgrates.org",
+		utils.OriginHost:  "127.0.0.1",
+	})
+	cgrID := GetSetCGRID(smGev)
+	smg.recordASession(&SMGSession{
+		CGRID:      cgrID,
+		RunID:      utils.META_DEFAULT,
+		EventStart: smGev,
+	})
+	if err := smg.storeNodeSessions(); err != nil {
+		t.Error(err)
+	}
+	ns, err := dm.GetNodeSessions(smgCfg.GeneralCfg().NodeID)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, has := ns.Sessions[cgrID]; !has {
+		t.Errorf("Sessions not stored: %+v", ns)
+	}
+	// sessions of other nodes
+	ns.NodeID = "EXPIRED_NODE"
+	ns.Heartbeat = time.Now().Add(-time.Hour)
+	if err := dm.SetNodeSessions(ns); err != nil {
+		t.Error(err)
+	}
+	if err := dm.SetNodeSessions(&engine.NodeSessions{NodeID: "ALIVE_NODE",
+		Heartbeat: time.Now(), Sessions: ns.Sessions}); err != nil {
+		t.Error(err)
+	}
+	smg2 := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, dm, nil, "UTC")
+	if err := smg2.takeoverNodeSessions(); err != nil {
+		t.Error(err)
+	}
+	if pSS := smg2.getSessions(cgrID, true); len(pSS[cgrID]) != 1 {
+		t.Errorf("PassiveSessions: %+v", pSS)
+	} else if pSS[cgrID][0].EventStart.GetStringIgnoreErrors(utils.Account) != "account1" {
+		t.Errorf("Unexpected session: %+v", pSS[cgrID][0])
+	}
+	if _, err := dm.GetNodeSessions("EXPIRED_NODE"); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dm.GetNodeSessions("ALIVE_NODE"); err != nil {
+		t.Error(err)
+	}
+	// heartbeat refreshed since read, claim should fail
+	if err := dm.ClaimNodeSessions("ALIVE_NODE", ns.Heartbeat); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
@@ -151,6 +151,15 @@ type DestinationRate struct {
 	RoundingDecimals int
 	MaxCost          float64
 	MaxCostStrategy  string
+	TierPeriod       string  // <""|*daily|*weekly|*monthly|*yearly>, apply GroupIntervalStart on the account usage within the period
+	Currency         string  // ISO 4217 code of the rates, empty for the default currency
+	MinCost          float64 // minimum cost charged per call
+	FreeUsage        string  // calls shorter than this usage are not charged
+}
+
+// FreeUsageDuration returns the FreeUsage parsed as duration
+func (dr *DestinationRate) FreeUsageDuration() (time.Duration, error) {
+	return ParseDurationWithNanosecs(dr.FreeUsage)
 }
 
 type ApierTPTiming struct {
@@ -161,16 +170,19 @@ type ApierTPTiming struct {
 	MonthDays string // semicolon separated list of month's days this timing is valid on, *any supported
 	WeekDays  string // semicolon separated list of week day names this timing is valid on *any supported
 	Time      string // String representing the time this timing starts on
+	Holidays  string // <*holiday|*not_holiday>:<CalendarID>, restricts the timing on the holidays of the tenant Calendar
 }
 
 type TPTiming struct {
-	ID        string
-	Years     Years
-	Months    Months
-	MonthDays MonthDays
-	WeekDays  WeekDays
-	StartTime string
-	EndTime   string
+	ID            string
+	Years         Years
+	Months        Months
+	MonthDays     MonthDays
+	WeekDays      WeekDays
+	StartTime     string
+	EndTime       string
+	CalendarID    string // Calendar listing the holidays
+	HolidayFilter string // <*holiday|*not_holiday>
 }
 
 func NewTiming(timingInfo ...string) (rt *TPTiming) {
@@ -338,7 +350,9 @@ type TPAction struct {
 	ExtraParameters string
 	BalanceBlocker  string
 	BalanceDisabled string
-	Weight          float64 // Action's weight
+	FilterIDs       []string // FilterS filters checked before the action
+	FailurePolicy   string   // <*stop_on_failure|*continue>
+	Weight          float64  // Action's weight
 }
 
 type TPSharedGroups struct {
@@ -561,6 +575,7 @@ type TPAccountActions struct {
 	ActionTriggersId string // Id of ActionTriggers profile to use
 	AllowNegative    bool
 	Disabled         bool
+	DebitPolicy      string // order of debiting the balances, empty for ordering on Weight
 }
 
 // Returns the id used in some nosql dbs (eg: redis)
@@ -623,6 +638,8 @@ type ArgsCache struct {
 	AttributeProfileIDs   *[]string
 	ChargerProfileIDs     *[]string
 	DispatcherProfileIDs  *[]string
+	TaxProfileIDs         *[]string
+	CalendarIDs           *[]string
 }
 
 // Data used to do remote cache reloads via api
@@ -666,6 +683,8 @@ type CacheStats struct {
 	AttributeProfiles   int
 	ChargerProfiles     int
 	DispatcherProfiles  int
+	TaxProfiles         int
+	Calendars           int
 }
 
 type AttrExpFileCdrs struct {
@@ -1154,6 +1173,7 @@ type AttrExecuteAction struct {
 	Tenant    string
 	Account   string
 	ActionsId string
+	RequestID string // optional, retries with the same RequestID receive the first result
 }
 
 type AttrSetAccount struct {
@@ -1163,13 +1183,16 @@ type AttrSetAccount struct {
 	ActionTriggersId string
 	AllowNegative    *bool
 	Disabled         *bool
+	DebitPolicy      *string
 	ReloadScheduler  bool
+	RequestID        string // optional, retries with the same RequestID receive the first result
 }
 
 type AttrRemoveAccount struct {
 	Tenant          string
 	Account         string
 	ReloadScheduler bool
+	RequestID       string // optional, retries with the same RequestID receive the first result
 }
 
 type AttrGetSMASessions struct {
@@ -1205,6 +1228,39 @@ type AttrSetBalance struct {
 	SharedGroups   *string
 	Blocker        *bool
 	Disabled       *bool
+	Currency       *string
+	Rollover       *RolloverPolicy
+	RequestID      string // optional, retries with the same RequestID receive the first result
+}
+
+// RolloverPolicy moves the unused value of a balance into a new balance when it expires
+type RolloverPolicy struct {
+	Percent      float64 // percentage out of the unused value which is rolled over, 0 rolls over the whole value
+	MaxValue     float64 // cap of the rolled over value, 0 for no cap
+	MaxRollovers int     // number of consecutive rollovers, 0 for unlimited
+	Expiry       string  // validity of the new balance, counted from the old expiry: <*daily|*weekly|*monthly|*yearly|duration>
+}
+
+// NextExpiry returns the expiry of the balance created out of one expiring at expTime
+func (rp *RolloverPolicy) NextExpiry(expTime time.Time) (time.Time, error) {
+	switch rp.Expiry {
+	case MetaDaily:
+		return expTime.AddDate(0, 0, 1), nil
+	case MetaWeekly:
+		return expTime.AddDate(0, 0, 7), nil
+	case MetaMonthly:
+		return expTime.AddDate(0, 1, 0), nil
+	case MetaYearly:
+		return expTime.AddDate(1, 0, 0), nil
+	}
+	dur, err := ParseDurationWithNanosecs(rp.Expiry)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if dur <= 0 {
+		return time.Time{}, fmt.Errorf("invalid rollover expiry: <%s>", rp.Expiry)
+	}
+	return expTime.Add(dur), nil
 }
 
 type TPResource struct {
@@ -1413,3 +1469,32 @@ type TPDispatcherProfile struct {
 	Weight             float64
 	Conns              []*TPDispatcherConns
 }
+
+// TPExchangeRate is used to convert amounts between currencies
+type TPExchangeRate struct {
+	TPid         string
+	FromCurrency string
+	ToCurrency   string
+	Rate         float64 // units of ToCurrency for one unit of FromCurrency
+}
+
+// TPTaxProfile defines one tax applied on the cost of the matching CDRs
+type TPTaxProfile struct {
+	TPid               string
+	Tenant             string
+	ID                 string
+	FilterIDs          []string
+	ActivationInterval *TPActivationInterval // Time when this tax becomes active and expires
+	Rate               float64               // percentage out of the taxable amount
+	Inclusive          bool                  // the tax is already contained into the cost
+	Compound           bool                  // the tax applies also on top of the previous taxes
+	Weight             float64
+}
+
+// TPCalendar is a named list of holidays, referenced by the timings
+type TPCalendar struct {
+	TPid     string
+	Tenant   string
+	ID       string
+	Holidays []string // dates in 2006-01-02 format or 01-02 for yearly recurring ones
+}
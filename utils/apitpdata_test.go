@@ -20,6 +20,7 @@ package utils
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestNewDTCSFromRPKey(t *testing.T) {
@@ -59,3 +60,23 @@ func TestPaginatorPaginateStringSlice(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", eOut, rcv)
 	}
 }
+
+func TestRolloverPolicyNextExpiry(t *testing.T) {
+	expTime := time.Date(2018, time.January, 31, 0, 0, 0, 0, time.UTC)
+	rp := &RolloverPolicy{Expiry: MetaMonthly}
+	if rcv, err := rp.NextExpiry(expTime); err != nil {
+		t.Error(err)
+	} else if eOut := time.Date(2018, time.March, 3, 0, 0, 0, 0, time.UTC); !rcv.Equal(eOut) {
+		t.Errorf("Expecting: %v, received: %v", eOut, rcv)
+	}
+	rp.Expiry = "48h"
+	if rcv, err := rp.NextExpiry(expTime); err != nil {
+		t.Error(err)
+	} else if eOut := expTime.Add(48 * time.Hour); !rcv.Equal(eOut) {
+		t.Errorf("Expecting: %v, received: %v", eOut, rcv)
+	}
+	rp.Expiry = ""
+	if _, err := rp.NextExpiry(expTime); err == nil {
+		t.Error("Expecting error for missing expiry")
+	}
+}
@@ -28,14 +28,15 @@ var (
 
 	GitLastLog                  string // If set, it will be processed as part of versioning
 	PosterTransportContentTypes = map[string]string{
-		MetaHTTPjsonCDR: CONTENT_JSON,
-		MetaHTTPjsonMap: CONTENT_JSON,
-		MetaHTTPjson:    CONTENT_JSON,
-		META_HTTP_POST:  CONTENT_FORM,
-		MetaAMQPjsonCDR: CONTENT_JSON,
-		MetaAMQPjsonMap: CONTENT_JSON,
-		MetaAWSjsonMap:  CONTENT_JSON,
-		MetaSQSjsonMap:  CONTENT_JSON,
+		MetaHTTPjsonCDR:     CONTENT_JSON,
+		MetaHTTPjsonMap:     CONTENT_JSON,
+		MetaHTTPjson:        CONTENT_JSON,
+		MetaHTTPjsonHeaders: CONTENT_JSON,
+		META_HTTP_POST:      CONTENT_FORM,
+		MetaAMQPjsonCDR:     CONTENT_JSON,
+		MetaAMQPjsonMap:     CONTENT_JSON,
+		MetaAWSjsonMap:      CONTENT_JSON,
+		MetaSQSjsonMap:      CONTENT_JSON,
 	}
 	CDREFileSuffixes = map[string]string{
 		MetaHTTPjsonCDR: JSNSuffix,
@@ -74,6 +75,8 @@ var (
 		CacheAttributeProfiles:       AttributeProfilePrefix,
 		CacheChargerProfiles:         ChargerProfilePrefix,
 		CacheDispatcherProfiles:      DispatcherProfilePrefix,
+		CacheTaxProfiles:             TaxProfilePrefix,
+		CacheCalendars:               CalendarPrefix,
 		CacheResourceFilterIndexes:   ResourceFilterIndexes,
 		CacheStatFilterIndexes:       StatFilterIndexes,
 		CacheThresholdFilterIndexes:  ThresholdFilterIndexes,
@@ -81,6 +84,7 @@ var (
 		CacheAttributeFilterIndexes:  AttributeFilterIndexes,
 		CacheChargerFilterIndexes:    ChargerFilterIndexes,
 		CacheDispatcherFilterIndexes: DispatcherFilterIndexes,
+		CacheTaxFilterIndexes:        TaxFilterIndexes,
 	}
 	CachePrefixToInstance map[string]string // will be built on init
 	PrefixToIndexCache    = map[string]string{
@@ -91,6 +95,7 @@ var (
 		AttributeProfilePrefix:  CacheAttributeFilterIndexes,
 		ChargerProfilePrefix:    CacheChargerFilterIndexes,
 		DispatcherProfilePrefix: CacheDispatcherFilterIndexes,
+		TaxProfilePrefix:        CacheTaxFilterIndexes,
 	}
 	CacheIndexesToPrefix map[string]string // will be built on init
 )
@@ -258,7 +263,17 @@ const (
 	DispatcherProfilePrefix       = "dpp_"
 	ThresholdProfilePrefix        = "thp_"
 	StatQueuePrefix               = "stq_"
+	NodeSessionsPrefix            = "nss_"
+	ExchangeRatePrefix            = "exr_"
+	TaxProfilePrefix              = "txp_"
+	CalendarPrefix                = "cal_"
+	LeasePrefix                   = "lea_"
+	LastExecutionPrefix           = "lex_"
+	ExecutionCheckpointPrefix     = "chk_"
+	RequestResultPrefix           = "rqr_"
+	CDRDedupPrefix                = "cdd_"
 	LOADINST_KEY                  = "load_history"
+	ExecutionHistoryKey           = "execution_history"
 	SESSION_MANAGER_SOURCE        = "SMR"
 	MEDIATOR_SOURCE               = "MED"
 	CDRS_SOURCE                   = "CDRS"
@@ -273,6 +288,7 @@ const (
 	META_HANDLER                  = "*handler"
 	META_HTTP_POST                = "*http_post"
 	MetaHTTPjson                  = "*http_json"
+	MetaHTTPjsonHeaders           = "*http_json_headers"
 	MetaHTTPjsonCDR               = "*http_json_cdr"
 	META_HTTP_JSONRPC             = "*http_jsonrpc"
 	MetaHTTPjsonMap               = "*http_json_map"
@@ -289,6 +305,7 @@ const (
 	MAX_DEBIT_CACHE_PREFIX        = "MAX_DEBIT_"
 	REFUND_INCR_CACHE_PREFIX      = "REFUND_INCR_"
 	REFUND_ROUND_CACHE_PREFIX     = "REFUND_ROUND_"
+	DEBIT_MIN_COST_CACHE_PREFIX   = "DEBIT_MIN_COST_"
 	GET_SESS_RUNS_CACHE_PREFIX    = "GET_SESS_RUNS_"
 	GET_DERIV_MAX_SESS_TIME       = "GET_DERIV_MAX_SESS_TIME_"
 	LOG_CALL_COST_CACHE_PREFIX    = "LOG_CALL_COSTS_"
@@ -381,6 +398,8 @@ const (
 	MetaScheduler                = "*scheduler"
 	MetaCostDetails              = "*cost_details"
 	MetaSessionsCosts            = "*sessions_costs"
+	MetaAccountAudits            = "*account_audits"
+	MetaAccountSnapshots         = "*account_snapshots"
 	MetaAccounts                 = "*accounts"
 	MetaActionPlans              = "*action_plans"
 	MetaActionTriggers           = "*action_triggers"
@@ -422,6 +441,18 @@ const (
 	SharedGroups                 = "SharedGroups"
 	MetaEveryMinute              = "*every_minute"
 	MetaHourly                   = "*hourly"
+	MetaHoliday                  = "*holiday"
+	MetaNotHoliday               = "*not_holiday"
+	MetaExpiresFirst             = "*expires_first"
+	MetaSmallestFirst            = "*smallest_first"
+	MetaFIFOTopup                = "*fifo_topup"
+	MetaRollover                 = "*rollover"
+	MetaSkip                     = "*skip"
+	MetaRunOnce                  = "*run_once"
+	MetaRunAll                   = "*run_all"
+	MetaStopOnFailure            = "*stop_on_failure"
+	MetaContinue                 = "*continue"
+	MetaAccount                  = "*account"
 	ID                           = "ID"
 	Thresholds                   = "Thresholds"
 	Suppliers                    = "Suppliers"
@@ -459,6 +490,8 @@ const (
 	MetaNow                      = "*now"
 	SessionsCosts                = "SessionsCosts"
 	SessionSCosts                = "SessionSCosts"
+	AccountAudits                = "AccountAudits"
+	AccountSnapshots             = "AccountSnapshots"
 	Timing                       = "Timing"
 	RQF                          = "RQF"
 	Resource                     = "Resource"
@@ -514,6 +547,7 @@ const (
 	MetaEventCost                = "*event_cost"
 	MetaSuppliersEventCost       = "*suppliers_event_cost"
 	MetaSuppliersIgnoreErrors    = "*suppliers_ignore_errors"
+	MetaSessionCosts             = "*session_costs"
 	Freeswitch                   = "freeswitch"
 	Kamailio                     = "kamailio"
 	Opensips                     = "opensips"
@@ -531,6 +565,13 @@ const (
 	MetaInitiate                 = "*initiate"
 	MetaUpdate                   = "*update"
 	MetaTerminate                = "*terminate"
+	MetaDebit                    = "*debit"
+	MetaRefund                   = "*refund"
+	MetaTrigger                  = "*trigger"
+	MetaDrop                     = "*drop"
+	MetaReplace                  = "*replace"
+	MetaKeepLongest              = "*keep_longest"
+	MetaDisconnect               = "*disconnect"
 	MetaEvent                    = "*event"
 	MetaDryRun                   = "*dryrun"
 	Event                        = "Event"
@@ -557,6 +598,13 @@ const (
 	Local                        = "local"
 	TCP                          = "tcp"
 	CGRDebitInterval             = "CGRDebitInterval"
+	CGRSplitBilling              = "CGRSplitBilling"
+	MetaRatio                    = "*ratio"
+	MetaFallback                 = "*fallback"
+	MetaDaily                    = "*daily"
+	MetaWeekly                   = "*weekly"
+	MetaMonthly                  = "*monthly"
+	MetaYearly                   = "*yearly"
 	MetaAsr                      = "*asr"
 	Version                      = "Version"
 )
@@ -648,6 +696,9 @@ const (
 	CapThresholdHits        = "ThresholdHits"
 	CapThresholds           = "Thresholds"
 	CapStatQueues           = "StatQueues"
+	CapSessionCosts         = "SessionCosts"
+	BalancesConsumed        = "BalancesConsumed"
+	BalancesRemaining       = "BalancesRemaining"
 )
 
 const (
@@ -692,6 +743,7 @@ const (
 	AttributeSv1  = "AttributeSv1"
 	SessionSv1    = "SessionSv1"
 	ChargerSv1    = "ChargerSv1"
+	SchedulerSv1  = "SchedulerSv1"
 	MetaAuth      = "*auth"
 	APIKey        = "APIKey"
 	APIMethods    = "APIMethods"
@@ -701,14 +753,30 @@ const (
 
 // ApierV1 APIs
 const (
-	ApierV1ComputeFilterIndexes = "ApierV1.ComputeFilterIndexes"
-	ApierV1ReloadCache          = "ApierV1.ReloadCache"
-	ApierV1ReloadScheduler      = "ApierV1.ReloadScheduler"
-	ApierV1Ping                 = "ApierV1.Ping"
+	ApierV1ComputeFilterIndexes        = "ApierV1.ComputeFilterIndexes"
+	ApierV1ReloadCache                 = "ApierV1.ReloadCache"
+	ApierV1ReloadScheduler             = "ApierV1.ReloadScheduler"
+	ApierV1Ping                        = "ApierV1.Ping"
+	ApierV1ExecuteAction               = "ApierV1.ExecuteAction"
+	ApierV1AddBalance                  = "ApierV1.AddBalance"
+	ApierV1DebitBalance                = "ApierV1.DebitBalance"
+	ApierV1SetBalance                  = "ApierV1.SetBalance"
+	ApierV1RemoveBalances              = "ApierV1.RemoveBalances"
+	ApierV1GetAccountAudits            = "ApierV1.GetAccountAudits"
+	ApierV1SetAccount                  = "ApierV1.SetAccount"
+	ApierV1RemoveAccount               = "ApierV1.RemoveAccount"
+	ApierV1AddAccountActionTriggers    = "ApierV1.AddAccountActionTriggers"
+	ApierV1RemoveAccountActionTriggers = "ApierV1.RemoveAccountActionTriggers"
+	ApierV1ResetAccountActionTriggers  = "ApierV1.ResetAccountActionTriggers"
+	ApierV1SetAccountActionTriggers    = "ApierV1.SetAccountActionTriggers"
+	ApierV1SetAccountSubscription      = "ApierV1.SetAccountSubscription"
+	ApierV1StopAccountSubscription     = "ApierV1.StopAccountSubscription"
 )
 
 const (
 	ApierV2LoadTariffPlanFromFolder = "ApierV2.LoadTariffPlanFromFolder"
+	ApierV2SetAccount               = "ApierV2.SetAccount"
+	ApierV2SetAccountActionTriggers = "ApierV2.SetAccountActionTriggers"
 )
 
 // UserS APIs
@@ -736,6 +804,13 @@ const (
 	ChargerSv1ProcessEvent        = "ChargerSv1.ProcessEvent"
 )
 
+// SchedulerS APIs
+const (
+	SchedulerSv1GetExecutionHistory  = "SchedulerSv1.GetExecutionHistory"
+	SchedulerSv1GetExecutionProgress = "SchedulerSv1.GetExecutionProgress"
+	SchedulerSv1QueueActionPlan      = "SchedulerSv1.QueueActionPlan"
+)
+
 // ThresholdS APIs
 const (
 	ThresholdSv1ProcessEvent          = "ThresholdSv1.ProcessEvent"
@@ -766,25 +841,28 @@ const (
 
 // SessionS APIs
 const (
-	SessionSv1AuthorizeEvent             = "SessionSv1.AuthorizeEvent"
-	SessionSv1AuthorizeEventWithDigest   = "SessionSv1.AuthorizeEventWithDigest"
-	SessionSv1InitiateSession            = "SessionSv1.InitiateSession"
-	SessionSv1InitiateSessionWithDigest  = "SessionSv1.InitiateSessionWithDigest"
-	SessionSv1UpdateSession              = "SessionSv1.UpdateSession"
-	SessionSv1SyncSessions               = "SessionSv1.SyncSessions"
-	SessionSv1TerminateSession           = "SessionSv1.TerminateSession"
-	SessionSv1ProcessCDR                 = "SessionSv1.ProcessCDR"
-	SessionSv1ProcessEvent               = "SessionSv1.ProcessEvent"
-	SessionSv1DisconnectSession          = "SessionSv1.DisconnectSession"
-	SessionSv1GetActiveSessions          = "SessionSv1.GetActiveSessions"
-	SessionSv1ForceDisconnect            = "SessionSv1.ForceDisconnect"
-	SessionSv1GetPassiveSessions         = "SessionSv1.GetPassiveSessions"
-	SMGenericV1InitiateSession           = "SMGenericV1.InitiateSession"
-	SMGenericV2InitiateSession           = "SMGenericV2.InitiateSession"
-	SMGenericV2UpdateSession             = "SMGenericV2.UpdateSession"
-	SessionSv1Ping                       = "SessionSv1.Ping"
-	SessionSv1GetActiveSessionIDs        = "SessionSv1.GetActiveSessionIDs"
-	SessionSv1RegisterInternalBiJSONConn = "SessionSv1.RegisterInternalBiJSONConn"
+	SessionSv1AuthorizeEvent               = "SessionSv1.AuthorizeEvent"
+	SessionSv1AuthorizeEventWithDigest     = "SessionSv1.AuthorizeEventWithDigest"
+	SessionSv1InitiateSession              = "SessionSv1.InitiateSession"
+	SessionSv1InitiateSessionWithDigest    = "SessionSv1.InitiateSessionWithDigest"
+	SessionSv1UpdateSession                = "SessionSv1.UpdateSession"
+	SessionSv1SyncSessions                 = "SessionSv1.SyncSessions"
+	SessionSv1TerminateSession             = "SessionSv1.TerminateSession"
+	SessionSv1ProcessCDR                   = "SessionSv1.ProcessCDR"
+	SessionSv1ProcessEvent                 = "SessionSv1.ProcessEvent"
+	SessionSv1DisconnectSession            = "SessionSv1.DisconnectSession"
+	SessionSv1GetActiveSessions            = "SessionSv1.GetActiveSessions"
+	SessionSv1ForceDisconnect              = "SessionSv1.ForceDisconnect"
+	SessionSv1ForceDisconnectWithFilters   = "SessionSv1.ForceDisconnectWithFilters"
+	SessionSv1GetActiveSessionsWithFilters = "SessionSv1.GetActiveSessionsWithFilters"
+	SessionSv1GetPassiveSessions           = "SessionSv1.GetPassiveSessions"
+	SessionSv1GetSessionHistory            = "SessionSv1.GetSessionHistory"
+	SMGenericV1InitiateSession             = "SMGenericV1.InitiateSession"
+	SMGenericV2InitiateSession             = "SMGenericV2.InitiateSession"
+	SMGenericV2UpdateSession               = "SMGenericV2.UpdateSession"
+	SessionSv1Ping                         = "SessionSv1.Ping"
+	SessionSv1GetActiveSessionIDs          = "SessionSv1.GetActiveSessionIDs"
+	SessionSv1RegisterInternalBiJSONConn   = "SessionSv1.RegisterInternalBiJSONConn"
 )
 
 // DispatcherS APIs
@@ -819,10 +897,12 @@ const (
 
 // Cdrs APIs
 const (
-	CdrsV1CountCDRs  = "CdrsV1.CountCDRs"
-	CdrsV1GetCDRs    = "CdrsV1.GetCDRs"
-	CdrsV2ProcessCDR = "CdrsV2.ProcessCDR"
-	CdrsV2RateCDRs   = "CdrsV2.RateCDRs"
+	CdrsV1CountCDRs     = "CdrsV1.CountCDRs"
+	CdrsV1GetCDRs       = "CdrsV1.GetCDRs"
+	CdrsV1GetSMCosts    = "CdrsV1.GetSMCosts"
+	CdrsV1GetDedupStats = "CdrsV1.GetDedupStats"
+	CdrsV2ProcessCDR    = "CdrsV2.ProcessCDR"
+	CdrsV2RateCDRs      = "CdrsV2.RateCDRs"
 )
 
 // Scheduler
@@ -878,6 +958,9 @@ const (
 	AttributesCsv         = "Attributes.csv"
 	ChargersCsv           = "Chargers.csv"
 	DispatchersCsv        = "Dispatchers.csv"
+	ExchangeRatesCsv      = "ExchangeRates.csv"
+	TaxProfilesCsv        = "TaxProfiles.csv"
+	CalendarsCsv          = "Calendars.csv"
 )
 
 // Table Name
@@ -901,6 +984,8 @@ const (
 	TBLTPThresholds       = "tp_thresholds"
 	TBLTPFilters          = "tp_filters"
 	SessionsCostsTBL      = "sessions_costs"
+	AccountAuditsTBL      = "account_audits"
+	AccountSnapshotsTBL   = "account_snapshots"
 	CDRsTBL               = "cdrs"
 	TBLTPSuppliers        = "tp_suppliers"
 	TBLTPAttributes       = "tp_attributes"
@@ -908,6 +993,9 @@ const (
 	TBLVersions           = "versions"
 	OldSMCosts            = "sm_costs"
 	TBLTPDispatchers      = "tp_dispatchers"
+	TBLTPExchangeRates    = "tp_exchange_rates"
+	TBLTPTaxProfiles      = "tp_tax_profiles"
+	TBLTPCalendars        = "tp_calendars"
 )
 
 // Cache Name
@@ -946,6 +1034,9 @@ const (
 	CacheAttributeFilterIndexes  = "attribute_filter_indexes"
 	CacheChargerFilterIndexes    = "charger_filter_indexes"
 	CacheDispatcherFilterIndexes = "dispatcher_filter_indexes"
+	CacheTaxProfiles             = "tax_profiles"
+	CacheTaxFilterIndexes        = "tax_filter_indexes"
+	CacheCalendars               = "calendars"
 	CacheDiameterMessages        = "diameter_messages"
 	MetaPrecaching               = "*precaching"
 	MetaReady                    = "*ready"
@@ -960,6 +1051,7 @@ const (
 	AttributeFilterIndexes  = "afi_"
 	ChargerFilterIndexes    = "cfi_"
 	DispatcherFilterIndexes = "dfi_"
+	TaxFilterIndexes        = "xfi_"
 )
 
 // Agents
@@ -116,12 +116,16 @@ func UUIDSha1Prefix() string {
 }
 
 // Round return rounded version of x with prec precision.
+// Negative values are rounded like their absolute value, same as Decimal.Round
 //
 // Special cases are:
 //	Round(±0) = ±0
 //	Round(±Inf) = ±Inf
 //	Round(NaN) = NaN
 func Round(x float64, prec int, method string) float64 {
+	if x < 0 {
+		return -Round(-x, prec, method)
+	}
 	var rounder float64
 	maxPrec := 7 // define a max precison to cut float errors
 	if maxPrec < prec {
@@ -237,6 +241,11 @@ func ParseTimeDetectLayout(tmStr string, timezone string) (time.Time, error) {
 		return time.Now(), nil
 	case strings.HasPrefix(tmStr, "+"):
 		tmStr = strings.TrimPrefix(tmStr, "+")
+		if strings.HasSuffix(tmStr, "d") { // days, not supported by time.ParseDuration
+			if days, err := strconv.Atoi(strings.TrimSuffix(tmStr, "d")); err == nil {
+				return time.Now().AddDate(0, 0, days), nil
+			}
+		}
 		if tmStrTmp, err := time.ParseDuration(tmStr); err != nil {
 			return nilTime, err
 		} else {
@@ -617,6 +626,24 @@ func GetEndOfMonth(ref time.Time) time.Time {
 	return eom.Add(-time.Second)
 }
 
+// GetPeriodStart returns the start of the calendar period (<*daily|*weekly|*monthly|*yearly>) containing ref
+// weeks are considered starting on Monday
+func GetPeriodStart(ref time.Time, period string) (time.Time, error) {
+	year, month, day := ref.Date()
+	switch period {
+	case MetaDaily:
+		return time.Date(year, month, day, 0, 0, 0, 0, ref.Location()), nil
+	case MetaWeekly:
+		daysSinceMonday := (int(ref.Weekday()) + 6) % 7
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, ref.Location()), nil
+	case MetaMonthly:
+		return time.Date(year, month, 1, 0, 0, 0, 0, ref.Location()), nil
+	case MetaYearly:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, ref.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("unsupported period: <%s>", period)
+}
+
 // formats number in K,M,G, etc.
 func SizeFmt(num float64, suffix string) string {
 	if suffix == "" {
@@ -866,7 +893,7 @@ func NewFallbackFileNameFronString(fileName string) (ffn *FallbackFileName, err
 		return nil, fmt.Errorf("unsupported module: %s", ffn.Module)
 	}
 	fileNameWithoutModule := fileName[moduleIdx+1:]
-	for _, trspt := range []string{MetaHTTPjsonCDR, MetaHTTPjsonMap, MetaHTTPjson, META_HTTP_POST, MetaAMQPjsonCDR, MetaAMQPjsonMap, MetaAWSjsonMap, MetaSQSjsonMap} {
+	for _, trspt := range []string{MetaHTTPjsonCDR, MetaHTTPjsonMap, MetaHTTPjsonHeaders, MetaHTTPjson, META_HTTP_POST, MetaAMQPjsonCDR, MetaAMQPjsonMap, MetaAWSjsonMap, MetaSQSjsonMap} {
 		if strings.HasPrefix(fileNameWithoutModule, trspt) {
 			ffn.Transport = trspt
 			break
@@ -83,6 +83,21 @@ func TestRoundByMethodDown2(t *testing.T) {
 	}
 }
 
+func TestRoundNegative(t *testing.T) {
+	for method, expected := range map[string]float64{
+		ROUNDING_UP:     -0.13,
+		ROUNDING_DOWN:   -0.12,
+		ROUNDING_MIDDLE: -0.12,
+	} {
+		if result := Round(-0.124, 2, method); result != expected {
+			t.Errorf("method: %s, expecting: %v, received: %v", method, expected, result)
+		}
+		if result := NewDecimalFromFloat64(-0.124).Round(2, method).Float64(); result != expected {
+			t.Errorf("method: %s, expecting decimal: %v, received: %v", method, expected, result)
+		}
+	}
+}
+
 func TestParseTimeDetectLayout(t *testing.T) {
 	tmStr := "2013-12-30T15:00:01Z"
 	expectedTime := time.Date(2013, 12, 30, 15, 0, 1, 0, time.UTC)
@@ -280,6 +295,13 @@ func TestParseTimeDetectLayout(t *testing.T) {
 		t.Error("error parsing date: ", date.Sub(expected).Seconds())
 	}
 
+	expected = time.Now().AddDate(0, 0, 30)
+	if date, err := ParseTimeDetectLayout("+30d", ""); err != nil {
+		t.Error(err)
+	} else if expected.Sub(date).Seconds() > 1 || date.Sub(expected).Seconds() > 1 {
+		t.Errorf("received: %+v", date)
+	}
+
 	expected = time.Now().AddDate(0, 1, 0)
 	if date, err := ParseTimeDetectLayout("*monthly", ""); err != nil {
 		t.Error(err)
@@ -860,6 +882,17 @@ func TestFFNNewFallbackFileNameFronString(t *testing.T) {
 	} else if !reflect.DeepEqual(eFFN, ffn) {
 		t.Errorf("Expecting: %+v, received: %+v", eFFN, ffn)
 	}
+	fileName = "act>*http_post|*http_json_headers|http%3A%2F%2Flocalhost%3A2080%2Fcrm|f52cf23e-da2f-4675-b36b-e8fcc3869270.json"
+	eFFN = &FallbackFileName{Module: "act>*http_post",
+		Transport:  MetaHTTPjsonHeaders,
+		Address:    "http://localhost:2080/crm",
+		RequestID:  "f52cf23e-da2f-4675-b36b-e8fcc3869270",
+		FileSuffix: JSNSuffix}
+	if ffn, err := NewFallbackFileNameFronString(fileName); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eFFN, ffn) {
+		t.Errorf("Expecting: %+v, received: %+v", eFFN, ffn)
+	}
 }
 
 func TestFFNFallbackFileNameAsString(t *testing.T) {
@@ -874,3 +907,27 @@ func TestFFNFallbackFileNameAsString(t *testing.T) {
 		t.Errorf("Expecting: <%q>, received: <%q>", eFn, ffnStr)
 	}
 }
+
+func TestGetPeriodStart(t *testing.T) {
+	ref := time.Date(2018, time.October, 18, 10, 1, 2, 3, time.UTC) // Thursday
+	for period, ePStart := range map[string]time.Time{
+		MetaDaily:   time.Date(2018, time.October, 18, 0, 0, 0, 0, time.UTC),
+		MetaWeekly:  time.Date(2018, time.October, 15, 0, 0, 0, 0, time.UTC),
+		MetaMonthly: time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC),
+		MetaYearly:  time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if pStart, err := GetPeriodStart(ref, period); err != nil {
+			t.Error(err)
+		} else if !pStart.Equal(ePStart) {
+			t.Errorf("period: %s, expecting: %v, received: %v", period, ePStart, pStart)
+		}
+	}
+	if pStart, err := GetPeriodStart(time.Date(2018, time.October, 21, 23, 0, 0, 0, time.UTC), MetaWeekly); err != nil {
+		t.Error(err)
+	} else if eStart := time.Date(2018, time.October, 15, 0, 0, 0, 0, time.UTC); !pStart.Equal(eStart) {
+		t.Errorf("expecting: %v, received: %v", eStart, pStart)
+	}
+	if _, err := GetPeriodStart(ref, "*hourly"); err == nil {
+		t.Error("expecting error")
+	}
+}
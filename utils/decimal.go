@@ -0,0 +1,130 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// decimalPrecision is the number of decimals kept on the result of divisions,
+// protects intermediate results from growing unbounded
+var decimalPrecision = 10
+
+// SetDecimalPrecision sets the number of decimals kept on intermediate results of monetary computations
+func SetDecimalPrecision(prec int) {
+	decimalPrecision = prec
+}
+
+// NewDecimalFromFloat64 builds a Decimal out of the decimal representation of f,
+// cutting float noise beyond 15 significant digits
+func NewDecimalFromFloat64(f float64) *Decimal {
+	d := new(Decimal)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return d
+	}
+	d.r.SetString(strconv.FormatFloat(f, 'g', 15, 64))
+	return d
+}
+
+// NewDecimalFromInt64 builds a Decimal out of an integer
+func NewDecimalFromInt64(i int64) *Decimal {
+	d := new(Decimal)
+	d.r.SetInt64(i)
+	return d
+}
+
+// Decimal is an arbitrary precision number used for internal monetary computations
+type Decimal struct {
+	r big.Rat
+}
+
+// Add returns d + o
+func (d *Decimal) Add(o *Decimal) *Decimal {
+	res := new(Decimal)
+	res.r.Add(&d.r, &o.r)
+	return res
+}
+
+// Sub returns d - o
+func (d *Decimal) Sub(o *Decimal) *Decimal {
+	res := new(Decimal)
+	res.r.Sub(&d.r, &o.r)
+	return res
+}
+
+// Mul returns d * o
+func (d *Decimal) Mul(o *Decimal) *Decimal {
+	res := new(Decimal)
+	res.r.Mul(&d.r, &o.r)
+	return res
+}
+
+// Div returns d / o rounded to the configured decimal precision, division by zero returns zero
+func (d *Decimal) Div(o *Decimal) *Decimal {
+	res := new(Decimal)
+	if o.r.Sign() == 0 {
+		return res
+	}
+	res.r.Quo(&d.r, &o.r)
+	return res.Round(decimalPrecision, ROUNDING_MIDDLE)
+}
+
+// Round returns d rounded to prec decimals using one of the rounding methods <*up|*down|*middle>
+// unlike the float Round there is no tolerance for *up since the value is exact,
+// negative values are rounded like their absolute value: *up away from zero, *down towards it
+// and *middle with the half away from zero
+func (d *Decimal) Round(prec int, method string) *Decimal {
+	res := new(Decimal)
+	if prec < 0 {
+		prec = 0
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(prec)), nil)
+	num := new(big.Int).Mul(d.r.Num(), scale)
+	neg := num.Sign() < 0
+	num.Abs(num)
+	den := d.r.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	switch method {
+	case ROUNDING_UP:
+		if rem.Sign() != 0 {
+			quo.Add(quo, big.NewInt(1))
+		}
+	case ROUNDING_DOWN:
+	case ROUNDING_MIDDLE:
+		if new(big.Int).Lsh(rem, 1).Cmp(den) >= 0 {
+			quo.Add(quo, big.NewInt(1))
+		}
+	default:
+		res.r.Set(&d.r)
+		return res
+	}
+	if neg {
+		quo.Neg(quo)
+	}
+	res.r.SetFrac(quo, scale)
+	return res
+}
+
+// Float64 returns the nearest float64 value of d
+func (d *Decimal) Float64() float64 {
+	f, _ := d.r.Float64()
+	return f
+}
@@ -0,0 +1,74 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestDecimalAdd(t *testing.T) {
+	if rcv := NewDecimalFromFloat64(0.1).Add(NewDecimalFromFloat64(0.2)).Float64(); rcv != 0.3 {
+		t.Errorf("Expecting: 0.3, received: %v", rcv)
+	}
+	if rcv := NewDecimalFromFloat64(1.1).Sub(NewDecimalFromFloat64(0.3)).Float64(); rcv != 0.8 {
+		t.Errorf("Expecting: 0.8, received: %v", rcv)
+	}
+}
+
+func TestDecimalDiv(t *testing.T) {
+	if rcv := NewDecimalFromInt64(1).Div(NewDecimalFromInt64(3)).Float64(); rcv != 0.3333333333 {
+		t.Errorf("Expecting: 0.3333333333, received: %v", rcv)
+	}
+	if rcv := NewDecimalFromInt64(1).Div(NewDecimalFromInt64(0)).Float64(); rcv != 0 {
+		t.Errorf("Expecting: 0, received: %v", rcv)
+	}
+	SetDecimalPrecision(4)
+	if rcv := NewDecimalFromInt64(2).Div(NewDecimalFromInt64(3)).Float64(); rcv != 0.6667 {
+		t.Errorf("Expecting: 0.6667, received: %v", rcv)
+	}
+	SetDecimalPrecision(10)
+}
+
+func TestDecimalRound(t *testing.T) {
+	if rcv := NewDecimalFromFloat64(2.675).Round(2, ROUNDING_MIDDLE).Float64(); rcv != 2.68 {
+		t.Errorf("Expecting: 2.68, received: %v", rcv)
+	}
+	if rcv := NewDecimalFromFloat64(-1.25).Round(1, ROUNDING_MIDDLE).Float64(); rcv != -1.3 {
+		t.Errorf("Expecting: -1.3, received: %v", rcv)
+	}
+	if rcv := NewDecimalFromFloat64(-1.21).Round(1, ROUNDING_MIDDLE).Float64(); rcv != -1.2 {
+		t.Errorf("Expecting: -1.2, received: %v", rcv)
+	}
+	if rcv := NewDecimalFromFloat64(-1.21).Round(1, ROUNDING_UP).Float64(); rcv != -1.3 {
+		t.Errorf("Expecting: -1.3, received: %v", rcv)
+	}
+	if rcv := NewDecimalFromFloat64(-1.29).Round(1, ROUNDING_DOWN).Float64(); rcv != -1.2 {
+		t.Errorf("Expecting: -1.2, received: %v", rcv)
+	}
+	// exact values, no tolerance on *up
+	if rcv := NewDecimalFromFloat64(1.00000001).Round(2, ROUNDING_UP).Float64(); rcv != 1.01 {
+		t.Errorf("Expecting: 1.01, received: %v", rcv)
+	}
+	if rcv := NewDecimalFromFloat64(0.1+0.2).Round(2, ROUNDING_UP).Float64(); rcv != 0.3 {
+		t.Errorf("Expecting: 0.3, received: %v", rcv)
+	}
+	if rcv := NewDecimalFromFloat64(1.2345).Round(2, "").Float64(); rcv != 1.2345 {
+		t.Errorf("Expecting: 1.2345, received: %v", rcv)
+	}
+}
@@ -74,6 +74,9 @@ var (
 	ErrCDRCNoProfileID          = errors.New("CDRC_PROFILE_WITHOUT_ID")
 	ErrCDRCNoInDir              = errors.New("CDRC_PROFILE_WITHOUT_IN_DIR")
 	ErrNotEnoughParameters      = errors.New("NotEnoughParameters")
+	ErrRequestInProgress        = errors.New("REQUEST_IN_PROGRESS")
+	ErrAccountAuditsDisabled    = errors.New("ACCOUNT_AUDITS_DISABLED")
+	ErrLeaseLost                = errors.New("LEASE_LOST")
 	RalsErrorPrfx               = "RALS_ERROR"
 	DispatcherErrorPrefix       = "DISPATCHER_ERROR"
 )